@@ -77,7 +77,12 @@ func main() {
 	updateWalletStocksDividendHandler := handler.NewUpdateWalletStocksDividend(walletFinder, stockFinder)
 
 	// LISTENER
-	updateStockDividend := listener.NewUpdateStockDividend(stockDividendPersister, stockDividendMarketChameleonService)
+	updateStockDividend := listener.NewUpdateStockDividend(
+		stockDividendFinder,
+		stockDividendPersister,
+		stockDividendMarketChameleonService,
+		[]service.AlertSink{service.NewStdoutAlertSink()},
+	)
 	updateStockDividend.WithConcurrency(1)
 	updateStockDividend.WithSleep(5)
 	updateStockDividendYield := listener.NewUpdateStockDividendYield(stockDividendFinder, stockPersister)
@@ -118,6 +118,47 @@ func main() {
 									Name:  "file, f",
 									Usage: "csv file to import",
 								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
+							},
+						},
+						{
+							Name:      "etf",
+							Aliases:   []string{"e"},
+							Usage:     "Import the constituents of an ETF from csv file. The file is named after the ETF symbol",
+							Action:    cLine.ImportETF,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "file, f",
+									Usage: "csv file to import",
+								},
+								cli.StringFlag{
+									Name:  "etf, s",
+									Usage: "ETF symbol",
+								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
 							},
 						},
 					},
@@ -142,6 +183,76 @@ func main() {
 									Name:  "exchange, e",
 									Usage: "Exchange name",
 								},
+								cli.StringFlag{
+									Name:  "isin, i",
+									Usage: "Stock ISIN, used to match the stock in the brokers statements",
+								},
+							},
+						},
+						{
+							Name:      "crypto",
+							Aliases:   []string{"c"},
+							Usage:     "Add cryptocurrency pair priced in the quote currency. The price is read from the crypto price provider",
+							Action:    cLine.AddCryptocurrency,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "symbol, s",
+									Usage: "Cryptocurrency symbol, e.g. BTC",
+								},
+								cli.StringFlag{
+									Name:  "quote, q",
+									Usage: "Quote currency code (EUR, USD, CAD)",
+								},
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "Cryptocurrency name, by default the symbol",
+								},
+							},
+						},
+						{
+							Name:      "bond",
+							Aliases:   []string{"bd"},
+							Usage:     "Add fixed income instrument (bond, treasury bill). Its coupons are scheduled as dividends",
+							Action:    cLine.AddBond,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "symbol, s",
+									Usage: "Bond symbol, usually the ISIN",
+								},
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "Bond name, by default the symbol",
+								},
+								cli.StringFlag{
+									Name:  "exchange, e",
+									Usage: "Exchange symbol the bond is listed in",
+								},
+								cli.StringFlag{
+									Name:  "face-value, fv",
+									Usage: "Face value paid back per bond at maturity",
+								},
+								cli.StringFlag{
+									Name:  "coupon, c",
+									Usage: "Annual coupon rate, percentage of the face value. Empty for zero coupon",
+								},
+								cli.StringFlag{
+									Name:  "frequency, fq",
+									Usage: "Coupons paid per year (1, 2, 4, 12). Empty for zero coupon",
+								},
+								cli.StringFlag{
+									Name:  "issue, i",
+									Usage: "Issue date. Required for the bonds paying coupons",
+								},
+								cli.StringFlag{
+									Name:  "maturity, m",
+									Usage: "Maturity date",
+								},
+								cli.StringFlag{
+									Name:  "price, p",
+									Usage: "Clean price, percentage of the face value. Default 100",
+								},
 							},
 						},
 					},
@@ -166,6 +277,10 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "watchlist",
+									Usage: "Watchlist name",
+								},
 							},
 						},
 						{
@@ -183,6 +298,61 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "watchlist",
+									Usage: "Watchlist name",
+								},
+							},
+						},
+						{
+							Name:      "book-value",
+							Aliases:   []string{"b"},
+							Usage:     "Update stock book value per share used by the Graham number valuation",
+							Action:    cLine.UpdateBookValue,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Stock symbol(tricker) to update book value",
+								},
+								cli.StringFlag{
+									Name:  "value, v",
+									Usage: "Book value per share",
+								},
+							},
+						},
+						{
+							Name:      "bond-price",
+							Aliases:   []string{"bp"},
+							Usage:     "Update bond clean price",
+							Action:    cLine.UpdateBondPrice,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Bond symbol",
+								},
+								cli.StringFlag{
+									Name:  "price, p",
+									Usage: "Clean price, percentage of the face value",
+								},
+							},
+						},
+						{
+							Name:      "isin",
+							Aliases:   []string{"in"},
+							Usage:     "Update the ISIN the brokers statements refer the stock by",
+							Action:    cLine.UpdateStockISIN,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Stock symbol",
+								},
+								cli.StringFlag{
+									Name:  "isin, i",
+									Usage: "Stock ISIN",
+								},
 							},
 						},
 					},
@@ -206,10 +376,213 @@ func main() {
 									Name:  "exchange, e",
 									Usage: "filter by exchange",
 								},
+								cli.StringFlag{
+									Name:  "watchlist",
+									Usage: "filter by watchlist",
+								},
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "filter by stock",
+								},
+								cli.StringFlag{
+									Name:  "sort",
+									Usage: "Sort by (name, dyield, exdate) Default by name",
+								},
+								cli.StringFlag{
+									Name:  "order",
+									Usage: "Order (desc, asc) Default by desc",
+								},
+								cli.StringFlag{
+									Name:  "group",
+									Usage: "Group by (exdate) None by default",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:    "dividends",
+					Aliases: []string{"d"},
+					Usage:   "Dividend reports",
+					Subcommands: []cli.Command{
+						{
+							Name:    "changes",
+							Aliases: []string{"c"},
+							Usage:   "List the dividend changes (new, raised, cut, suspended) detected while updating the dividends",
+							Action:  cLine.ListDividendChanges,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "from",
+									Usage: "Detected since date (dd/mm/yyyy). Default last 30 days",
+								},
 								cli.StringFlag{
 									Name:  "stock, s",
 									Usage: "filter by stock",
 								},
+							},
+						},
+					},
+				},
+				{
+					Name:    "screen",
+					Aliases: []string{"sc"},
+					Usage:   "Screen the stocks with a filter expression, e.g. 'dyield > 4 and per < 15 and sector = \"UTILITIES\"'",
+					Action:  cLine.Screen,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "expr, e",
+							Usage: "Filter expression over the stock fields",
+						},
+						cli.StringFlag{
+							Name:  "name, n",
+							Usage: "Saved screen name",
+						},
+						cli.StringFlag{
+							Name:  "sort",
+							Usage: "Field to sort the stocks by (default name)",
+						},
+						cli.StringFlag{
+							Name:  "order",
+							Usage: "Order (asc, desc)",
+						},
+						cli.IntFlag{
+							Name:  "limit",
+							Usage: "Maximum number of stocks",
+						},
+						cli.BoolFlag{
+							Name:  "save",
+							Usage: "Save the screen by name",
+						},
+						cli.BoolFlag{
+							Name:  "delete",
+							Usage: "Delete the saved screen",
+						},
+						cli.BoolFlag{
+							Name:  "list",
+							Usage: "List the saved screens",
+						},
+					},
+				},
+				{
+					Name:    "alert",
+					Aliases: []string{"al"},
+					Usage:   "Manage price alert rules evaluated after updating the stocks price",
+					Subcommands: []cli.Command{
+						{
+							Name:    "add",
+							Aliases: []string{"a"},
+							Usage:   "Add alert rule to a stock or to the stocks of a watchlist",
+							Action:  cLine.AddAlertRule,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Stock symbol(tricker)",
+								},
+								cli.StringFlag{
+									Name:  "watchlist, w",
+									Usage: "Watchlist name",
+								},
+								cli.StringFlag{
+									Name:  "condition, c",
+									Usage: "Condition (price_below, below_buy_under, near_52_week_low, daily_change_above)",
+								},
+								cli.StringFlag{
+									Name:  "value, v",
+									Usage: "Price for price_below, percentage for near_52_week_low (default 5) and daily_change_above",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"d"},
+							Usage:   "Delete alert rule",
+							Action:  cLine.DeleteAlertRule,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Alert rule's id",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Usage:   "List alert rules",
+							Action:  cLine.ListAlertRules,
+						},
+					},
+				},
+				{
+					Name:    "watchlist",
+					Aliases: []string{"w"},
+					Usage:   "Manage watchlists of stocks",
+					Subcommands: []cli.Command{
+						{
+							Name:    "create",
+							Aliases: []string{"c"},
+							Usage:   "Create watchlist",
+							Action:  cLine.AddWatchlist,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "watchlist name",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"d"},
+							Usage:   "Delete watchlist",
+							Action:  cLine.DeleteWatchlist,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "watchlist name",
+								},
+							},
+						},
+						{
+							Name:    "add",
+							Aliases: []string{"a"},
+							Usage:   "Add stocks to the watchlist",
+							Action:  cLine.AddWatchlistStocks,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "watchlist name",
+								},
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Stock symbols(tricker) separated by comma",
+								},
+							},
+						},
+						{
+							Name:    "remove",
+							Aliases: []string{"r"},
+							Usage:   "Remove stocks from the watchlist",
+							Action:  cLine.RemoveWatchlistStocks,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "watchlist name",
+								},
+								cli.StringFlag{
+									Name:  "stock, s",
+									Usage: "Stock symbols(tricker) separated by comma",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Usage:   "List the stocks of the watchlist",
+							Action:  cLine.ListWatchlist,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "watchlist name",
+								},
 								cli.StringFlag{
 									Name:  "sort",
 									Usage: "Sort by (name, dyield, exdate) Default by name",
@@ -252,6 +625,22 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "broker, b",
+									Usage: "Broker name where the wallet is hold",
+								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
 							},
 						},
 						{
@@ -268,6 +657,55 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "match, m",
+									Usage: "open trade a sell without trade goes to (fifo, lifo, exact). Default fifo",
+								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
+							},
+						},
+						{
+							Name:      "broker",
+							Aliases:   []string{"b"},
+							Usage:     "Import the operations from the files exported by the wallet broker (Degiro Transactions.csv and Account.csv, Interactive Brokers flex query xml)",
+							Action:    cLine.ImportBrokerOperation,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "file, f",
+									Usage: "csv or xml file to import",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name, only the files of the wallet are imported",
+								},
+								cli.StringFlag{
+									Name:  "match, m",
+									Usage: "open trade a sell without trade goes to (fifo, lifo, exact). Default fifo",
+								},
+								cli.StringFlag{
+									Name:  "bank-account, ba",
+									Usage: "Bank account alias the deposits and withdrawals are transferred from and to",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
 							},
 						},
 						{
@@ -284,6 +722,18 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
 							},
 						},
 					},
@@ -307,6 +757,10 @@ func main() {
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
+								cli.StringFlag{
+									Name:  "group, g",
+									Usage: "Wallet group name, consolidates the wallets of the group merging the same stocks",
+								},
 								cli.StringFlag{
 									Name:  "status, st",
 									Usage: "wallet operation status (active, inactive, all) Default by active",
@@ -338,34 +792,87 @@ func main() {
 							},
 						},
 						{
-							Name:      "snapshot",
-							Aliases:   []string{"wr"},
-							Action:    cLine.ExportSnapshotWallet,
+							Name:      "wallets",
+							Aliases:   []string{"ws"},
+							Action:    cLine.ExportWallets,
 							ArgsUsage: "",
 							Flags: []cli.Flag{
 								cli.StringFlag{
-									Name:  "file, f",
-									Usage: "csv file to export",
+									Name:  "broker, b",
+									Usage: "Broker name",
 								},
+							},
+						},
+						{
+							Name:      "ledger",
+							Aliases:   []string{"l"},
+							Action:    cLine.ExportWalletLedger,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
 								cli.StringFlag{
 									Name:  "wallet, w",
 									Usage: "Wallet name",
 								},
 								cli.StringFlag{
-									Name:  "date, d",
-									Usage: "date of the report",
-								},
-								cli.StringFlag{
-									Name:  "exclude, e",
-									Usage: "exclude stock(s) separate by comma",
-								},
-								cli.StringFlag{
-									Name:  "sort",
-									Usage: "Sort by (stock, invested) Default by stock",
+									Name:  "from",
+									Usage: "date from (dd/mm/yyyy). Default since the beginning",
 								},
 								cli.StringFlag{
-									Name:  "order",
-									Usage: "Order (desc, asc) Default by desc",
+									Name:  "to",
+									Usage: "date to (dd/mm/yyyy). Default today",
+								},
+							},
+						},
+						{
+							Name:      "exposure",
+							Aliases:   []string{"ex"},
+							Usage:     "Wallet capital by sector, country and stock looking through the ETFs",
+							Action:    cLine.ExportWalletExposure,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "group, g",
+									Usage: "Wallet group name, consolidates the wallets of the group",
+								},
+							},
+						},
+						{
+							Name:      "snapshot",
+							Aliases:   []string{"wr"},
+							Action:    cLine.ExportSnapshotWallet,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "file, f",
+									Usage: "csv file to export",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "group, g",
+									Usage: "Wallet group name, consolidates the wallets of the group merging the same stocks",
+								},
+								cli.StringFlag{
+									Name:  "date, d",
+									Usage: "date of the report",
+								},
+								cli.StringFlag{
+									Name:  "exclude, e",
+									Usage: "exclude stock(s) separate by comma",
+								},
+								cli.StringFlag{
+									Name:  "sort",
+									Usage: "Sort by (stock, invested) Default by stock",
+								},
+								cli.StringFlag{
+									Name:  "order",
+									Usage: "Order (desc, asc) Default by desc",
 								},
 							},
 						},
@@ -417,7 +924,7 @@ func main() {
 										},
 										cli.StringFlag{
 											Name:  "trade, t",
-											Usage: "Operation's trade",
+											Usage: "Operation's trade. Default assigned automatically",
 										},
 										cli.StringFlag{
 											Name:  "date, d",
@@ -465,7 +972,11 @@ func main() {
 										},
 										cli.StringFlag{
 											Name:  "trade, t",
-											Usage: "Operation's trade",
+											Usage: "Operation's trade. Default assigned automatically",
+										},
+										cli.StringFlag{
+											Name:  "match, m",
+											Usage: "open trade the sell goes to when no trade is given (fifo, lifo, exact). Default fifo",
 										},
 										cli.StringFlag{
 											Name:  "date, d",
@@ -521,6 +1032,70 @@ func main() {
 										},
 									},
 								},
+								{
+									Name:      "coupon",
+									Aliases:   []string{"c"},
+									Action:    cLine.AddCoupon,
+									ArgsUsage: "Add bond coupon operation to the wallet",
+									Flags: []cli.Flag{
+										cli.StringFlag{
+											Name:  "wallet, w",
+											Usage: "Wallet name",
+										},
+										cli.StringFlag{
+											Name:  "date, d",
+											Usage: "Operation's date",
+										},
+										cli.StringFlag{
+											Name:  "stock, s",
+											Usage: "Bond symbol",
+										},
+										cli.StringFlag{
+											Name:  "value, v",
+											Usage: "Coupon value",
+										},
+									},
+								},
+								{
+									Name:      "redemption",
+									Aliases:   []string{"r"},
+									Action:    cLine.AddRedemption,
+									ArgsUsage: "Add bond redemption operation to the wallet",
+									Flags: []cli.Flag{
+										cli.StringFlag{
+											Name:  "wallet, w",
+											Usage: "Wallet name",
+										},
+										cli.StringFlag{
+											Name:  "trade, t",
+											Usage: "Trade number. Default the open trade holding the bonds",
+										},
+										cli.StringFlag{
+											Name:  "date, d",
+											Usage: "Operation's date",
+										},
+										cli.StringFlag{
+											Name:  "stock, s",
+											Usage: "Bond symbol",
+										},
+										cli.StringFlag{
+											Name:  "amount, a",
+											Usage: "Operation's bond amount",
+										},
+										cli.StringFlag{
+											Name:  "price-change, pc",
+											Usage: "Operation's price change",
+										},
+										cli.StringFlag{
+											Name:  "value, v",
+											Usage: "Operation's value. Default the face value of the bonds",
+										},
+										cli.StringFlag{
+											Name:  "commission, c",
+											Usage: "Operation's commission",
+										},
+									},
+								},
 							},
 						},
 						{
@@ -545,6 +1120,305 @@ func main() {
 						},
 					},
 				},
+				{
+					Name:    "broker",
+					Aliases: []string{"b"},
+					Usage:   "Manage brokers where the wallets are hold",
+					Subcommands: []cli.Command{
+						{
+							Name:    "add",
+							Aliases: []string{"a"},
+							Usage:   "Add broker",
+							Action:  cLine.AddBroker,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "broker name",
+								},
+								cli.StringFlag{
+									Name:  "retention, r",
+									Usage: "default dividend retention in percentage",
+								},
+								cli.StringFlag{
+									Name:  "margin, m",
+									Usage: "margin in percentage of the net capital",
+								},
+								cli.StringFlag{
+									Name:  "connectivity, c",
+									Usage: "yearly connectivity fee",
+								},
+								cli.StringFlag{
+									Name:  "format, f",
+									Usage: "import format (degiro, interactive-brokers)",
+								},
+							},
+						},
+						{
+							Name:    "commission",
+							Aliases: []string{"c"},
+							Usage:   "Add/Update broker commission for an exchange",
+							Action:  cLine.AddBrokerCommission,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "broker, b",
+									Usage: "broker name",
+								},
+								cli.StringFlag{
+									Name:  "exchange, e",
+									Usage: "exchange symbol",
+								},
+								cli.StringFlag{
+									Name:  "base",
+									Usage: "base commission",
+								},
+								cli.StringFlag{
+									Name:  "extra",
+									Usage: "extra commission",
+								},
+								cli.StringFlag{
+									Name:  "extra-currency",
+									Usage: "extra commission currency. Default the exchange currency",
+								},
+								cli.StringFlag{
+									Name:  "apply",
+									Usage: "how the extra commission is applied (PER_STOCK, INVESTED_PERCENTAGE)",
+								},
+								cli.StringFlag{
+									Name:  "maximum",
+									Usage: "maximum commission",
+								},
+								cli.StringFlag{
+									Name:  "change",
+									Usage: "price change commission",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Usage:   "List brokers",
+							Action:  cLine.ListBrokers,
+						},
+					},
+				},
+				{
+					Name:    "group",
+					Aliases: []string{"g"},
+					Usage:   "Manage groups of wallets reported together",
+					Subcommands: []cli.Command{
+						{
+							Name:    "create",
+							Aliases: []string{"c"},
+							Usage:   "Create wallet group",
+							Action:  cLine.AddWalletGroup,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "wallet group name",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet names separated by comma",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"d"},
+							Usage:   "Delete wallet group",
+							Action:  cLine.DeleteWalletGroup,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "wallet group name",
+								},
+							},
+						},
+						{
+							Name:    "add",
+							Aliases: []string{"a"},
+							Usage:   "Add wallets to the wallet group",
+							Action:  cLine.AddWalletGroupWallets,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "wallet group name",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet names separated by comma",
+								},
+							},
+						},
+						{
+							Name:    "remove",
+							Aliases: []string{"r"},
+							Usage:   "Remove wallets from the wallet group",
+							Action:  cLine.RemoveWalletGroupWallets,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "wallet group name",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet names separated by comma",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Usage:   "List wallet groups",
+							Action:  cLine.ListWalletGroups,
+						},
+					},
+				},
+				{
+					Name:    "operation",
+					Aliases: []string{"o"},
+					Usage:   "Manage wallet's operations",
+					Subcommands: []cli.Command{
+						{
+							Name:    "edit",
+							Aliases: []string{"e"},
+							Usage:   "Edit operation. Only the given values are changed",
+							Action:  cLine.EditOperation,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Operation's id",
+								},
+								cli.StringFlag{
+									Name:  "date, d",
+									Usage: "Operation's date",
+								},
+								cli.StringFlag{
+									Name:  "amount, a",
+									Usage: "Operation's stock amount",
+								},
+								cli.StringFlag{
+									Name:  "price, p",
+									Usage: "Operation's price",
+								},
+								cli.StringFlag{
+									Name:  "price-change, pc",
+									Usage: "Operation's price change",
+								},
+								cli.StringFlag{
+									Name:  "price-change-commission, pcc",
+									Usage: "Operation's price change commission",
+								},
+								cli.StringFlag{
+									Name:  "value, v",
+									Usage: "Operation's value",
+								},
+								cli.StringFlag{
+									Name:  "commission, c",
+									Usage: "Operation's commission",
+								},
+							},
+						},
+						{
+							Name:    "delete",
+							Aliases: []string{"d"},
+							Usage:   "Delete operation",
+							Action:  cLine.DeleteOperation,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "id",
+									Usage: "Operation's id",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:    "trade",
+					Aliases: []string{"t"},
+					Usage:   "Trade journal and statistics",
+					Subcommands: []cli.Command{
+						{
+							Name:    "journal",
+							Aliases: []string{"j"},
+							Usage:   "Write the trade notes, strategy tags and planned exit targets",
+							Action:  cLine.TradeJournal,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "trade, t",
+									Usage: "Trade number",
+								},
+								cli.StringFlag{
+									Name:  "note, n",
+									Usage: "Why the trade was entered",
+								},
+								cli.StringSliceFlag{
+									Name:  "tag",
+									Usage: "Strategy tag. Can be repeated, replaces the existing tags",
+								},
+								cli.StringFlag{
+									Name:  "target",
+									Usage: "Planned exit price",
+								},
+								cli.StringFlag{
+									Name:  "stop-loss",
+									Usage: "Planned stop loss price",
+								},
+							},
+						},
+						{
+							Name:    "stats",
+							Aliases: []string{"s"},
+							Usage:   "Statistics of the closed trades",
+							Action:  cLine.TradeStats,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+								cli.StringFlag{
+									Name:  "tag",
+									Usage: "Only trades tagged with",
+								},
+								cli.StringFlag{
+									Name:  "from",
+									Usage: "Trades closed from date",
+								},
+								cli.StringFlag{
+									Name:  "to",
+									Usage: "Trades closed until date. Default today",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name:   "rebuild",
+					Usage:  "Rebuild wallet replaying its transfers and operations",
+					Action: cLine.RebuildWallet,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "wallet, w",
+							Usage: "wallet to rebuild",
+						},
+						cli.BoolFlag{
+							Name:  "apply",
+							Usage: "store the rebuilt wallet. Default only the differences are reported",
+						},
+					},
+				},
 				{
 					Name:    "reload",
 					Aliases: []string{"r"},
@@ -562,8 +1436,97 @@ func main() {
 		{
 			Name:    "banking",
 			Aliases: []string{"a"},
-			Usage:   "Add/Update banking values (transfers)",
+			Usage:   "Add/Update banking values (accounts, transfers)",
 			Subcommands: []cli.Command{
+				{
+					Name:    "account",
+					Aliases: []string{"a"},
+					Usage:   "Manage bank accounts",
+					Subcommands: []cli.Command{
+						{
+							Name:    "add",
+							Aliases: []string{"a"},
+							Usage:   "Add bank account",
+							Action:  cLine.AddBankAccount,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "alias, a",
+									Usage: "bank account alias, used to refer the account in the transfers",
+								},
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "bank account name. Default the alias",
+								},
+								cli.StringFlag{
+									Name:  "account-no, no",
+									Usage: "account number",
+								},
+								cli.StringFlag{
+									Name:  "account-no-type, nt",
+									Usage: "account number type (iban, hash). Default iban",
+								},
+								cli.StringFlag{
+									Name:  "type, t",
+									Usage: "account type (checking, savings, brokerage, external). Default checking",
+								},
+							},
+						},
+						{
+							Name:    "edit",
+							Aliases: []string{"e"},
+							Usage:   "Edit bank account",
+							Action:  cLine.EditBankAccount,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "alias, a",
+									Usage: "bank account alias",
+								},
+								cli.StringFlag{
+									Name:  "new-alias",
+									Usage: "new bank account alias",
+								},
+								cli.StringFlag{
+									Name:  "name, n",
+									Usage: "bank account name",
+								},
+								cli.StringFlag{
+									Name:  "account-no, no",
+									Usage: "account number",
+								},
+								cli.StringFlag{
+									Name:  "account-no-type, nt",
+									Usage: "account number type (iban, hash)",
+								},
+								cli.StringFlag{
+									Name:  "type, t",
+									Usage: "account type (checking, savings, brokerage, external)",
+								},
+							},
+						},
+						{
+							Name:    "link",
+							Aliases: []string{"k"},
+							Usage:   "Link bank account to a wallet",
+							Action:  cLine.LinkBankAccount,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "alias, a",
+									Usage: "bank account alias",
+								},
+								cli.StringFlag{
+									Name:  "wallet, w",
+									Usage: "Wallet name",
+								},
+							},
+						},
+						{
+							Name:    "list",
+							Aliases: []string{"l"},
+							Usage:   "List bank accounts",
+							Action:  cLine.ListBankAccounts,
+						},
+					},
+				},
 				{
 					Name:    "import",
 					Aliases: []string{"i"},
@@ -579,6 +1542,43 @@ func main() {
 									Name:  "file, f",
 									Usage: "csv file to import",
 								},
+								cli.StringFlag{
+									Name:  "mapping, p",
+									Usage: "mapping profile the csv file is read with, by default the one named after the import type if any",
+								},
+								cli.IntFlag{
+									Name:  "legacy-rows",
+									Usage: "rows the file had when it was imported, before its rows were told apart, if edited since",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
+							},
+						},
+						{
+							Name:      "statement",
+							Aliases:   []string{"s"},
+							Usage:     "Import the transfers between bank and broker accounts from bank statements (CAMT.053, MT940, OFX)",
+							Action:    cLine.ImportStatement,
+							ArgsUsage: "",
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "file, f",
+									Usage: "statement file to import",
+								},
+								cli.StringFlag{
+									Name:  "format",
+									Usage: "statement format (camt053, mt940, ofx), by default taken from the file extension",
+								},
+								cli.StringFlag{
+									Name:  "rate, r",
+									Usage: "rate the currency of the statement is changed at, units per euro. Required when the statement is not in euro",
+								},
+								cli.BoolFlag{
+									Name:  "dry-run",
+									Usage: "validate the file and print the problems found by line without saving anything",
+								},
 							},
 						},
 					},
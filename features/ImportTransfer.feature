@@ -40,3 +40,31 @@ Feature:
 
       | id                                   | invested | funds  |
       | b0c650ff-130e-4460-bcd9-423643ee314d | 720.00   | 720.00 |
+
+  Scenario: Import transfers again from a renamed file with new lines
+    When I add a new csv file "01_transfer.csv" to the "transfer" import folder with the following lines:
+
+      | Date       | From  | To     | Amount |
+      | 6/10/2017  | Bank1 | Wallet | 20,00  |
+      | 15/10/2017 | Bank1 | Wallet | 600,00 |
+
+    And I run a command "market-manager" with args "banking import transfer":
+    And I add a new csv file "02_transfer.csv" to the "transfer" import folder with the following lines:
+
+      | Date       | From  | To     | Amount |
+      | 6/10/2017  | Bank1 | Wallet | 20,00  |
+      | 15/10/2017 | Bank1 | Wallet | 600,00 |
+      | 6/11/2017  | Bank1 | Wallet | 100,00 |
+
+    And I run a command "market-manager" with args "banking import transfer":
+    Then following transfers should be stored:
+
+      | id | from_account                         | to_account                           | amount | date       |
+      | 1  | f8e0d0b7-8b5f-46e2-a65a-aae8a7ef5b63 | cf9ef982-a9c1-4c7b-bbd3-011ed81f1bbd | 20,00  | 6/10/2017  |
+      | 2  | f8e0d0b7-8b5f-46e2-a65a-aae8a7ef5b63 | cf9ef982-a9c1-4c7b-bbd3-011ed81f1bbd | 600,00 | 15/10/2017 |
+      | 3  | f8e0d0b7-8b5f-46e2-a65a-aae8a7ef5b63 | cf9ef982-a9c1-4c7b-bbd3-011ed81f1bbd | 100,00 | 6/11/2017  |
+
+    And the following wallets should have:
+
+      | id                                   | invested | funds  |
+      | b0c650ff-130e-4460-bcd9-423643ee314d | 720.00   | 720.00 |
@@ -23,6 +23,20 @@ func RegisterDBContext(s *godog.Suite, db *sqlx.DB) *DBContext {
 	dbc := DBContext{
 		db: db,
 		tables: []string{
+			"wallet_import_transaction",
+			"wallet_group_wallet",
+			"wallet_group",
+			"stock_fixed_income",
+			"etf_constituent",
+			"stock_screen",
+			"stock_dividend_change",
+			"alert_rule_trigger",
+			"alert_rule",
+			"watchlist_stock",
+			"watchlist",
+			"trade_journal_tag",
+			"trade_journal",
+			"wallet_ledger",
 			"wallet_item",
 			"trade_operation",
 			"trade",
@@ -33,6 +47,7 @@ func RegisterDBContext(s *godog.Suite, db *sqlx.DB) *DBContext {
 			"stock",
 			"stock_info",
 			"bank_account",
+			"import_row",
 			"import",
 		},
 	}
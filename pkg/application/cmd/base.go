@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/f2prateek/train"
@@ -23,6 +24,7 @@ import (
 	"github.com/dohernandez/market-manager/pkg/infrastructure/client"
 	cc "github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/valuation"
 )
 
 type (
@@ -69,12 +71,30 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	exchangeFinder := storage.NewExchangeFinder(cmd.DB)
 	stockInfoFinder := storage.NewStockInfoFinder(cmd.DB)
 	bankAccountFinder := storage.NewBankAccountFinder(cmd.DB)
+	brokerFinder := storage.NewBrokerFinder(cmd.DB)
+	ledgerFinder := storage.NewLedgerFinder(cmd.DB)
+	operationFinder := storage.NewOperationFinder(cmd.DB)
+	transferFinder := storage.NewTransferFinder(cmd.DB)
+	tradeFinder := storage.NewTradeFinder(cmd.DB)
+	watchlistFinder := storage.NewWatchlistFinder(cmd.DB)
+	alertFinder := storage.NewAlertFinder(cmd.DB)
+	screenFinder := storage.NewScreenFinder(cmd.DB)
+	etfFinder := storage.NewETFFinder(cmd.DB)
+	walletGroupFinder := storage.NewWalletGroupFinder(cmd.DB)
 
 	stockPersister := storage.NewStockPersister(cmd.DB)
 	walletPersister := storage.NewWalletPersister(cmd.DB)
 	stockInfoPersister := storage.NewStockInfoPersister(cmd.DB)
 	stockDividendPersister := storage.NewStockDividendPersister(cmd.DB)
 	transferPersister := storage.NewTransferPersister(cmd.DB)
+	brokerPersister := storage.NewBrokerPersister(cmd.DB)
+	tradePersister := storage.NewTradePersister(cmd.DB)
+	watchlistPersister := storage.NewWatchlistPersister(cmd.DB)
+	alertPersister := storage.NewAlertPersister(cmd.DB)
+	screenPersister := storage.NewScreenPersister(cmd.DB)
+	etfPersister := storage.NewETFPersister(cmd.DB)
+	walletGroupPersister := storage.NewWalletGroupPersister(cmd.DB)
+	bankAccountPersister := storage.NewBankAccountPersister(cmd.DB)
 
 	walletReload := storage.NewWalletReload(cmd.DB)
 	resourceStorage := storage.NewUtilImportStorage(cmd.DB)
@@ -93,45 +113,77 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	// SERVICE
 	//stockPrice := service.NewBasicStockPrice(cmd.ctx, iexClient)
 	stockPriceScrapeYahooService := service.NewYahooScrapeStockPrice(cmd.ctx, cmd.config.QuoteScraper.FinanceYahooQuoteURL)
+	cryptoPriceService := cmd.cryptoPriceService()
 	stockPriceVolatilityMarketChameleonService := service.NewMarketChameleonStockPriceVolatility(cmd.ctx, cmd.config.QuoteScraper.MarketChameleonURL)
 	stockDividendMarketChameleonService := service.NewStockDividendMarketChameleon(cmd.ctx, marketChameleonWWWUrlBuilder, marketChameleonWWWHtmlParser)
 	//stockDividendMarketChameleonService := service.NewStockDividendMarketChameleon(cmd.ctx, marketChameleonFileUrlBuilder, marketChameleonFileHtmlParser)
 	stockSummaryMarketChameleonService := service.NewStockSummaryMarketChameleon(cmd.ctx, cmd.config.QuoteScraper.MarketChameleonURL)
 	stockSummaryYahooService := service.NewStockSummaryYahoo(cmd.ctx, cmd.config.QuoteScraper.FinanceYahooQuoteURL)
+	alertSinks := cmd.alertSinks()
+	valuationModels := cmd.valuationModels()
 
 	// HANDLER
-	importStocksHandler := handler.NewImportStock(marketFinder, exchangeFinder, stockInfoFinder, stockPersister, stockInfoPersister)
+	importStocksHandler := handler.NewImportStock(marketFinder, exchangeFinder, stockFinder, stockInfoFinder, stockPersister, stockInfoPersister)
 	updateAllStockPriceHandler := handler.NewUpdateAllStockPrice(stockFinder)
 	updateOneStockPrice := handler.NewUpdateOneStockPrice(stockFinder)
+	updateStockBookValue := handler.NewUpdateStockBookValue(stockFinder, stockPersister)
 	updateWalletStocksPriceHandler := handler.NewUpdateWalletStocksPrice(walletFinder, stockFinder)
+	updateWatchlistStocksHandler := handler.NewUpdateWatchlistStocks(watchlistFinder, stockFinder)
 	updateAllStockDividendHandler := handler.NewUpdateAllStockDividend(stockFinder)
 	updateOneStockDividendHandler := handler.NewUpdateOneStockDividend(stockFinder)
 	updateWalletStocksDividendHandler := handler.NewUpdateWalletStocksDividend(walletFinder, stockFinder)
-	importTransferHandler := handler.NewImportTransfer(bankAccountFinder, transferPersister, walletFinder, walletPersister)
-	importWalletHandler := handler.NewImportWallet(bankAccountFinder, walletPersister)
-	importOperationHandler := handler.NewImportOperation(stockFinder)
-	listStockHandler := handler.NewListStock(stockFinder, stockDividendFinder)
-	walletDetailsHandler := handler.NewWalletDetails(walletFinder, stockFinder, stockDividendFinder, ccClient, cmd.config.Degiro.Retention)
+	importTransferHandler := handler.NewImportTransfer(bankAccountFinder, transferFinder, transferPersister, walletFinder, walletPersister)
+	importStatementHandler := handler.NewImportStatement(bankAccountFinder, transferFinder, transferPersister, walletFinder, walletPersister)
+	importWalletHandler := handler.NewImportWallet(bankAccountFinder, brokerFinder, walletPersister)
+	importOperationHandler := handler.NewImportOperation(stockFinder, walletFinder)
+	importBrokerOperationHandler := handler.NewImportBrokerOperation(walletFinder, stockFinder, stockPersister, bankAccountFinder)
+	listStockHandler := handler.NewListStock(stockFinder, stockDividendFinder, watchlistFinder, valuationModels)
+	walletDetailsHandler := handler.NewWalletDetails(walletFinder, walletGroupFinder, stockFinder, stockDividendFinder, ccClient)
 	reloadWalletHandler := handler.NewReloadWallet(walletFinder, walletReload)
 	importRetentionHandler := handler.NewImportRetention(stockFinder, walletFinder)
-	addOperationHandler := handler.NewAddOperation(stockFinder)
-	walletDateDetailsHandler := handler.NewWalletDateDetails(walletFinder, stockFinder, stockDividendFinder, ccClient, cmd.config.Degiro.Retention, bankAccountFinder)
+	addOperationHandler := handler.NewAddOperation(stockFinder, walletFinder)
+	walletDateDetailsHandler := handler.NewWalletDateDetails(walletFinder, walletGroupFinder, stockFinder, stockDividendFinder, ccClient, bankAccountFinder)
 	addStockHandler := handler.NewAddStock(marketFinder, exchangeFinder)
+	addCryptocurrencyHandler := handler.NewAddCryptocurrency(marketFinder, exchangeFinder)
+	addBondHandler := handler.NewAddBond(marketFinder, exchangeFinder)
+	updateBondPriceHandler := handler.NewUpdateBondPrice(stockFinder, stockPersister)
+	updateStockISINHandler := handler.NewUpdateStockISIN(stockFinder, stockPersister)
 	addDividendRetentionHandler := handler.NewAddDividendRetention(stockFinder, walletFinder)
+	addBrokerHandler := handler.NewAddBroker(brokerFinder, brokerPersister, exchangeFinder)
+	listBrokersHandler := handler.NewListBrokers(brokerFinder)
+	listWalletsHandler := handler.NewListWallets(walletFinder, brokerFinder)
+	walletLedgerHandler := handler.NewWalletLedger(walletFinder, ledgerFinder)
+	changeOperationHandler := handler.NewChangeOperation(walletFinder, walletPersister, operationFinder, stockFinder, stockDividendFinder, ccClient)
+	rebuildWalletHandler := handler.NewRebuildWallet(walletFinder, walletPersister, operationFinder, transferFinder, stockFinder, stockDividendFinder, ccClient)
+	tradeJournalHandler := handler.NewTradeJournal(walletFinder, tradeFinder, tradePersister)
+	tradeStatsHandler := handler.NewTradeStats(walletFinder, tradeFinder, stockFinder)
+	changeWatchlistHandler := handler.NewChangeWatchlist(watchlistFinder, watchlistPersister, stockFinder)
+	changeAlertRuleHandler := handler.NewChangeAlertRule(alertFinder, alertPersister, stockFinder, watchlistFinder)
+	listAlertRulesHandler := handler.NewListAlertRules(alertFinder)
+	listDividendChangesHandler := handler.NewListDividendChanges(stockFinder, stockDividendFinder)
+	screenStocksHandler := handler.NewScreenStocks(stockFinder, stockDividendFinder, screenFinder, screenPersister, valuationModels)
+	importETFHandler := handler.NewImportETF(stockFinder, etfPersister)
+	walletExposureHandler := handler.NewWalletExposure(walletFinder, walletGroupFinder, stockFinder, etfFinder, ccClient)
+	changeWalletGroupHandler := handler.NewChangeWalletGroup(walletGroupFinder, walletGroupPersister, walletFinder)
+	listWalletGroupsHandler := handler.NewListWalletGroups(walletGroupFinder)
+	changeBankAccountHandler := handler.NewChangeBankAccount(bankAccountFinder, bankAccountPersister, walletFinder, walletPersister)
+	listBankAccountsHandler := handler.NewListBankAccounts(bankAccountFinder, walletFinder)
 
 	// LISTENER
-	updateStockPrice := listener.NewUpdateStockPrice(stockFinder, stockPriceScrapeYahooService, stockPersister)
+	updateStockPrice := listener.NewUpdateStockPrice(stockFinder, stockPriceScrapeYahooService, cryptoPriceService, stockPersister)
 	updateStockDividendYield := listener.NewUpdateStockDividendYield(stockDividendFinder, stockPersister)
 	updateWalletCapital := listener.NewUpdateWalletCapital(walletFinder, walletPersister, ccClient)
 	updateStockPriceVolatility := listener.NewUpdateStockPriceVolatility(stockPriceVolatilityMarketChameleonService, stockPersister)
-	updateStockDividend := listener.NewUpdateStockDividend(stockDividendPersister, stockDividendMarketChameleonService)
-	addWalletOperation := listener.NewAddWalletOperation(stockFinder, walletFinder, walletPersister, ccClient)
-	registerWalletOperationImport := listener.NewRegisterWalletOperationImport(resourceStorage, cmd.config.Import.AccountsPath)
+	updateStockDividend := listener.NewUpdateStockDividend(stockDividendFinder, stockDividendPersister, stockDividendMarketChameleonService, alertSinks)
+	addWalletOperation := listener.NewAddWalletOperation(stockFinder, stockDividendFinder, walletFinder, walletPersister, operationFinder, ccClient)
+	registerWalletOperationImport := listener.NewRegisterWalletOperationImport(resourceStorage, cmd.config.Import.AccountsPath, cmd.config.Import.MappingsPath)
 	addStockSummaryInfo := listener.NewAddStockSummaryInfo(stockSummaryMarketChameleonService, stockSummaryYahooService)
 	saveStock := listener.NewSaveStock(stockInfoFinder, stockPersister, stockInfoPersister)
-	registerStockImport := listener.NewRegisterStockImport(resourceStorage, cmd.config.Import.StocksPath)
+	registerStockImport := listener.NewRegisterStockImport(resourceStorage, cmd.config.Import.StocksPath, cmd.config.Import.MappingsPath)
 	saveDividendRetention := listener.NewSaveDividendRetention(walletPersister)
-	registerDividendRetentionImport := listener.NewRegisterDividendRetentionImport(resourceStorage, cmd.config.Import.RetentionsPath)
+	saveBrokerImport := listener.NewSaveBrokerImport(walletFinder, walletPersister)
+	registerDividendRetentionImport := listener.NewRegisterDividendRetentionImport(resourceStorage, cmd.config.Import.RetentionsPath, cmd.config.Import.MappingsPath)
+	notifyStockAlert := listener.NewNotifyStockAlert(alertFinder, alertPersister, alertSinks)
 
 	// COMMAND BUS
 	bus := cbus.Bus{}
@@ -140,10 +192,10 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	// Import stocks
 	importStock := command.ImportStock{}
 	bus.Handle(&importStock, importStocksHandler)
-	bus.ListenCommand(cbus.AfterSuccess, &importStock, updateStockPrice)
-	bus.ListenCommand(cbus.AfterSuccess, &importStock, updateStockDividend)
-	bus.ListenCommand(cbus.AfterSuccess, &importStock, updateStockDividendYield)
-	bus.ListenCommand(cbus.AfterSuccess, &importStock, updateStockPriceVolatility)
+	bus.ListenCommand(cbus.AfterSuccess, &importStock, listener.NewSkipDryRun(updateStockPrice))
+	bus.ListenCommand(cbus.AfterSuccess, &importStock, listener.NewSkipDryRun(updateStockDividend))
+	bus.ListenCommand(cbus.AfterSuccess, &importStock, listener.NewSkipDryRun(updateStockDividendYield))
+	bus.ListenCommand(cbus.AfterSuccess, &importStock, listener.NewSkipDryRun(updateStockPriceVolatility))
 
 	// Update all stock price
 	updateAllStocksPrice := command.UpdateAllStocksPrice{}
@@ -151,6 +203,7 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	bus.ListenCommand(cbus.AfterSuccess, &updateAllStocksPrice, updateStockPrice)
 	bus.ListenCommand(cbus.AfterSuccess, &updateAllStocksPrice, updateStockDividendYield)
 	bus.ListenCommand(cbus.AfterSuccess, &updateAllStocksPrice, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &updateAllStocksPrice, notifyStockAlert)
 	//bus.ListenCommand(cbus.AfterSuccess, &updateAllStocksPrice, updateStockPriceVolatility)
 
 	// Update one stock price
@@ -159,16 +212,29 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	bus.ListenCommand(cbus.AfterSuccess, &updateOneStocksPrice, updateStockPrice)
 	bus.ListenCommand(cbus.AfterSuccess, &updateOneStocksPrice, updateStockDividendYield)
 	bus.ListenCommand(cbus.AfterSuccess, &updateOneStocksPrice, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &updateOneStocksPrice, notifyStockAlert)
 	bus.ListenCommand(cbus.AfterSuccess, &updateOneStocksPrice, updateStockPriceVolatility)
 
+	// Update stock book value
+	bus.Handle(&command.UpdateStockBookValue{}, updateStockBookValue)
+
 	// Update wallet stocks price
 	updateWalletStocksPrice := command.UpdateWalletStocksPrice{}
 	bus.Handle(&updateWalletStocksPrice, updateWalletStocksPriceHandler)
 	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksPrice, updateStockPrice)
 	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksPrice, updateStockDividendYield)
 	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksPrice, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksPrice, notifyStockAlert)
 	//bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksPrice, updateStockPriceVolatility)
 
+	// Update watchlist stocks price
+	updateWatchlistStocksPrice := command.UpdateWatchlistStocksPrice{}
+	bus.Handle(&updateWatchlistStocksPrice, updateWatchlistStocksHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksPrice, updateStockPrice)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksPrice, updateStockDividendYield)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksPrice, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksPrice, notifyStockAlert)
+
 	// Update all stock dividends
 	updateAllStocksDividend := command.UpdateAllStockDividend{}
 	bus.Handle(&updateAllStocksDividend, updateAllStockDividendHandler)
@@ -187,10 +253,20 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksDividend, updateStockDividend)
 	bus.ListenCommand(cbus.AfterSuccess, &updateWalletStocksDividend, updateStockDividendYield)
 
+	// Update watchlist stock dividends
+	updateWatchlistStocksDividend := command.UpdateWatchlistStocksDividend{}
+	bus.Handle(&updateWatchlistStocksDividend, updateWatchlistStocksHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksDividend, updateStockDividend)
+	bus.ListenCommand(cbus.AfterSuccess, &updateWatchlistStocksDividend, updateStockDividendYield)
+
 	// import transfer
 	importTransfer := command.ImportTransfer{}
 	bus.Handle(&importTransfer, importTransferHandler)
 
+	// import statement
+	importStatement := command.ImportStatement{}
+	bus.Handle(&importStatement, importStatementHandler)
+
 	// import wallet
 	importWallet := command.ImportWallet{}
 	bus.Handle(&importWallet, importWalletHandler)
@@ -198,13 +274,39 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	// import operation
 	importOperation := command.ImportOperation{}
 	bus.Handle(&importOperation, importOperationHandler)
-	bus.ListenCommand(cbus.AfterSuccess, &importOperation, addWalletOperation)
-	bus.ListenCommand(cbus.AfterSuccess, &importOperation, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &importOperation, listener.NewSkipDryRun(addWalletOperation))
+	bus.ListenCommand(cbus.AfterSuccess, &importOperation, listener.NewSkipDryRun(updateWalletCapital))
+
+	// import broker operation
+	importBrokerOperation := command.ImportBrokerOperation{}
+	bus.Handle(&importBrokerOperation, importBrokerOperationHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &importBrokerOperation, listener.NewSkipDryRun(addWalletOperation))
+	bus.ListenCommand(cbus.AfterSuccess, &importBrokerOperation, listener.NewSkipDryRun(saveBrokerImport))
+	bus.ListenCommand(cbus.AfterSuccess, &importBrokerOperation, listener.NewSkipDryRun(updateWalletCapital))
 
 	// List stocks
 	listStocks := command.ListStocks{}
 	bus.Handle(&listStocks, listStockHandler)
 
+	// Watchlists
+	bus.Handle(&command.AddWatchlist{}, changeWatchlistHandler)
+	bus.Handle(&command.DeleteWatchlist{}, changeWatchlistHandler)
+	bus.Handle(&command.AddWatchlistStocks{}, changeWatchlistHandler)
+	bus.Handle(&command.RemoveWatchlistStocks{}, changeWatchlistHandler)
+
+	// Alert rules
+	bus.Handle(&command.AddAlertRule{}, changeAlertRuleHandler)
+	bus.Handle(&command.DeleteAlertRule{}, changeAlertRuleHandler)
+	bus.Handle(&command.ListAlertRules{}, listAlertRulesHandler)
+
+	// Dividend changes
+	bus.Handle(&command.ListDividendChanges{}, listDividendChangesHandler)
+
+	// Stock screens
+	bus.Handle(&command.ScreenStocks{}, screenStocksHandler)
+	bus.Handle(&command.DeleteScreen{}, screenStocksHandler)
+	bus.Handle(&command.ListScreens{}, screenStocksHandler)
+
 	// Wallet details
 	walletDetails := command.WalletDetails{}
 	bus.Handle(&walletDetails, walletDetailsHandler)
@@ -212,10 +314,21 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	//Reload wallet
 	bus.Handle(&command.ReloadWallet{}, reloadWalletHandler)
 
+	// Rebuild wallet
+	bus.Handle(&command.RebuildWallet{}, rebuildWalletHandler)
+
+	// Trade journal and statistics
+	bus.Handle(&command.TradeJournal{}, tradeJournalHandler)
+	bus.Handle(&command.TradeStats{}, tradeStatsHandler)
+
+	// edit and delete operation
+	bus.Handle(&command.EditOperation{}, changeOperationHandler)
+	bus.Handle(&command.DeleteOperation{}, changeOperationHandler)
+
 	// import retention
 	importRetention := command.ImportRetention{}
 	bus.Handle(&importRetention, importRetentionHandler)
-	bus.ListenCommand(cbus.AfterSuccess, &importRetention, saveDividendRetention)
+	bus.ListenCommand(cbus.AfterSuccess, &importRetention, listener.NewSkipDryRun(saveDividendRetention))
 
 	// add dividend
 	addDividend := command.AddDividendOperation{}
@@ -245,6 +358,20 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	bus.ListenCommand(cbus.AfterSuccess, &addInterest, updateWalletCapital)
 	bus.ListenCommand(cbus.AfterSuccess, &addInterest, registerWalletOperationImport)
 
+	// add bond coupon
+	addCoupon := command.AddCouponOperation{}
+	bus.Handle(&addCoupon, addOperationHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &addCoupon, addWalletOperation)
+	bus.ListenCommand(cbus.AfterSuccess, &addCoupon, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &addCoupon, registerWalletOperationImport)
+
+	// add bond redemption
+	addRedemption := command.AddRedemptionOperation{}
+	bus.Handle(&addRedemption, addOperationHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &addRedemption, addWalletOperation)
+	bus.ListenCommand(cbus.AfterSuccess, &addRedemption, updateWalletCapital)
+	bus.ListenCommand(cbus.AfterSuccess, &addRedemption, registerWalletOperationImport)
+
 	// Wallet report
 	walletDateDetails := command.WalletDateDetails{}
 	bus.Handle(&walletDateDetails, walletDateDetailsHandler)
@@ -260,15 +387,134 @@ func (cmd *Base) initCommandBus() *cbus.Bus {
 	bus.ListenCommand(cbus.AfterSuccess, &addStock, updateStockPriceVolatility)
 	bus.ListenCommand(cbus.AfterSuccess, &addStock, registerStockImport)
 
+	// Add cryptocurrency
+	addCryptocurrency := command.AddCryptocurrency{}
+	bus.Handle(&addCryptocurrency, addCryptocurrencyHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &addCryptocurrency, saveStock)
+	bus.ListenCommand(cbus.AfterSuccess, &addCryptocurrency, updateStockPrice)
+
+	// add bond
+	addBond := command.AddBond{}
+	bus.Handle(&addBond, addBondHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &addBond, saveStock)
+	bus.ListenCommand(cbus.AfterSuccess, &addBond, updateStockPrice)
+	bus.ListenCommand(cbus.AfterSuccess, &addBond, updateStockDividend)
+	bus.ListenCommand(cbus.AfterSuccess, &addBond, updateStockDividendYield)
+
+	// update bond price
+	updateBondPrice := command.UpdateBondPrice{}
+	bus.Handle(&updateBondPrice, updateBondPriceHandler)
+	bus.ListenCommand(cbus.AfterSuccess, &updateBondPrice, updateStockPrice)
+	bus.ListenCommand(cbus.AfterSuccess, &updateBondPrice, updateStockDividendYield)
+	bus.ListenCommand(cbus.AfterSuccess, &updateBondPrice, updateWalletCapital)
+
+	// update stock isin
+	bus.Handle(&command.UpdateStockISIN{}, updateStockISINHandler)
+
 	// add dividend retention
 	addDividendRetention := command.AddDividendRetention{}
 	bus.Handle(&addDividendRetention, addDividendRetentionHandler)
 	bus.ListenCommand(cbus.AfterSuccess, &addDividendRetention, saveDividendRetention)
 	bus.ListenCommand(cbus.AfterSuccess, &addDividendRetention, registerDividendRetentionImport)
 
+	// add broker
+	bus.Handle(&command.AddBroker{}, addBrokerHandler)
+	bus.Handle(&command.AddBrokerCommission{}, addBrokerHandler)
+
+	// List brokers
+	bus.Handle(&command.ListBrokers{}, listBrokersHandler)
+
+	// List wallets
+	bus.Handle(&command.ListWallets{}, listWalletsHandler)
+
+	// Wallet ledger
+	bus.Handle(&command.WalletLedger{}, walletLedgerHandler)
+
+	// ETF look-through
+	bus.Handle(&command.ImportETF{}, importETFHandler)
+	bus.Handle(&command.WalletExposure{}, walletExposureHandler)
+
+	// Wallet groups
+	bus.Handle(&command.AddWalletGroup{}, changeWalletGroupHandler)
+	bus.Handle(&command.DeleteWalletGroup{}, changeWalletGroupHandler)
+	bus.Handle(&command.AddWalletGroupWallets{}, changeWalletGroupHandler)
+	bus.Handle(&command.RemoveWalletGroupWallets{}, changeWalletGroupHandler)
+	bus.Handle(&command.ListWalletGroups{}, listWalletGroupsHandler)
+
+	// Bank accounts
+	bus.Handle(&command.AddBankAccount{}, changeBankAccountHandler)
+	bus.Handle(&command.EditBankAccount{}, changeBankAccountHandler)
+	bus.Handle(&command.LinkBankAccount{}, changeBankAccountHandler)
+	bus.Handle(&command.ListBankAccounts{}, listBankAccountsHandler)
+
 	return &bus
 }
 
+// alertSinks builds the sinks where the alerts are delivered from the configuration
+func (cmd *Base) alertSinks() []service.AlertSink {
+	var sinks []service.AlertSink
+
+	for _, name := range strings.Split(cmd.config.Alert.Sinks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "stdout":
+			sinks = append(sinks, service.NewStdoutAlertSink())
+		case "file":
+			sinks = append(sinks, service.NewFileAlertSink(cmd.config.Alert.LogFile))
+		case "webhook":
+			timeout := time.Second * time.Duration(cmd.config.Alert.WebhookTimeout)
+
+			sinks = append(sinks, service.NewWebhookAlertSink(cmd.config.Alert.WebhookURL, cmd.newHTTPClient("ALERT-WEBHOOK", timeout)))
+		case "smtp":
+			var to []string
+			if cmd.config.Alert.SMTP.To != "" {
+				to = strings.Split(cmd.config.Alert.SMTP.To, ",")
+			}
+
+			sinks = append(sinks, service.NewSMTPAlertSink(
+				cmd.config.Alert.SMTP.Host,
+				cmd.config.Alert.SMTP.Port,
+				cmd.config.Alert.SMTP.Username,
+				cmd.config.Alert.SMTP.Password,
+				cmd.config.Alert.SMTP.From,
+				to,
+			))
+		default:
+			logger.FromContext(cmd.ctx).Warnf("Alert sink %q not supported", name)
+		}
+	}
+
+	return sinks
+}
+
+func (cmd *Base) cryptoPriceService() service.StockPrice {
+	if cmd.config.CryptoPrice.Provider != "file" {
+		logger.FromContext(cmd.ctx).Warnf("Crypto price provider %q not supported, using file", cmd.config.CryptoPrice.Provider)
+	}
+
+	return service.NewFileCryptoPrice(cmd.config.CryptoPrice.File)
+}
+
+func (cmd *Base) valuationModels() []valuation.Model {
+	var models []valuation.Model
+
+	for _, name := range strings.Split(cmd.config.Valuation.Models, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "ddm":
+			models = append(models, valuation.NewGordon(cmd.config.Valuation.DiscountRate, cmd.config.Valuation.Growth))
+		case "graham":
+			models = append(models, valuation.NewGraham())
+		case "yield_band":
+			models = append(models, valuation.NewYieldBand(cmd.config.Valuation.YieldBandYears))
+		default:
+			logger.FromContext(cmd.ctx).Warnf("Valuation model %q not supported", name)
+		}
+	}
+
+	return models
+}
+
 func (cmd *Base) newHTTPClient(name string, timeout time.Duration) *http.Client {
 	clt := http.Client{}
 
@@ -2,7 +2,8 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -15,7 +16,12 @@ import (
 	"github.com/dohernandez/market-manager/pkg/application/storage"
 	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/statement"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/screen"
 )
 
 type (
@@ -48,6 +54,11 @@ func (cmd *CLI) UpdatePrice(cliCtx *cli.Context) error {
 		if err != nil {
 			return err
 		}
+	} else if cliCtx.String("watchlist") != "" {
+		_, err := bus.ExecuteContext(ctx, &command.UpdateWatchlistStocksPrice{Watchlist: cliCtx.String("watchlist")})
+		if err != nil {
+			return err
+		}
 	} else {
 		_, err := bus.ExecuteContext(ctx, &command.UpdateAllStocksPrice{})
 		if err != nil {
@@ -78,6 +89,11 @@ func (cmd *CLI) UpdateDividend(cliCtx *cli.Context) error {
 		if err != nil {
 			return err
 		}
+	} else if cliCtx.String("watchlist") != "" {
+		_, err := bus.ExecuteContext(ctx, &command.UpdateWatchlistStocksDividend{Watchlist: cliCtx.String("watchlist")})
+		if err != nil {
+			return err
+		}
 	} else {
 		_, err := bus.ExecuteContext(ctx, &command.UpdateAllStockDividend{})
 		if err != nil {
@@ -90,22 +106,115 @@ func (cmd *CLI) UpdateDividend(cliCtx *cli.Context) error {
 	return nil
 }
 
+// UpdateBookValue updates the stock book value per share used by the Graham number valuation
+func (cmd *CLI) UpdateBookValue(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing stock symbol")
+	}
+
+	if cliCtx.String("value") == "" {
+		logger.FromContext(ctx).Fatal("Missing book value")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.UpdateStockBookValue{
+		Symbol:    cliCtx.String("stock"),
+		BookValue: cliCtx.Float64("value"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed updating book value")
+	}
+
+	logger.FromContext(ctx).Info("Update finished")
+
+	return nil
+}
+
+// UpdateBondPrice updates the clean price of a bond
+func (cmd *CLI) UpdateBondPrice(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing bond symbol")
+	}
+
+	if cliCtx.String("price") == "" {
+		logger.FromContext(ctx).Fatal("Missing clean price")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.UpdateBondPrice{
+		Symbol:     cliCtx.String("stock"),
+		CleanPrice: cliCtx.Float64("price"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed updating bond price")
+	}
+
+	logger.FromContext(ctx).Info("Update finished")
+
+	return nil
+}
+
+// UpdateStockISIN sets the ISIN the brokers statements refer the stock by
+func (cmd *CLI) UpdateStockISIN(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing stock symbol")
+	}
+
+	if cliCtx.String("isin") == "" {
+		logger.FromContext(ctx).Fatal("Missing isin")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.UpdateStockISIN{
+		Symbol: cliCtx.String("stock"),
+		ISIN:   cliCtx.String("isin"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed updating stock isin")
+	}
+
+	logger.FromContext(ctx).Info("Update finished")
+
+	return nil
+}
+
 func (cmd *CLI) ImportStock(cliCtx *cli.Context) error {
 	ctxt, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
+	mapping := cmd.importMapping(ctxt, cliCtx, "stock")
+
 	return cmd_cli.Import(
 		ctxt,
 		func(_ context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
 			bus := cmd.initCommandBus()
 
-			return bus.ExecuteContext(ctxt, &command.ImportStock{FilePath: ri.FilePath})
+			return bus.ExecuteContext(ctxt, &command.ImportStock{
+				FilePath: ri.FilePath,
+				DryRun:   cliCtx.Bool("dry-run"),
+				Mapping:  mapping,
+			})
 		},
 		cmd.resourceStorage,
 		"stocks",
 		cmd.config.Import.StocksPath,
 		cliCtx.String("file"),
 		"",
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
 	)
 }
 
@@ -113,18 +222,66 @@ func (cmd *CLI) ImportTransfer(cliCtx *cli.Context) error {
 	ctxt, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
+	mapping := cmd.importMapping(ctxt, cliCtx, "transfer")
+
 	return cmd_cli.Import(
 		ctxt,
 		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
 			bus := cmd.initCommandBus()
 
-			return bus.ExecuteContext(ctx, &command.ImportTransfer{FilePath: ri.FilePath})
+			return bus.ExecuteContext(ctx, &command.ImportTransfer{
+				FilePath: ri.FilePath,
+				DryRun:   cliCtx.Bool("dry-run"),
+				Mapping:  mapping,
+			})
 		},
 		cmd.resourceStorage,
 		"transfers",
 		cmd.config.Import.TransfersPath,
 		cliCtx.String("file"),
 		"",
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
+	)
+}
+
+// ImportStatement imports the transfers from the bank statements
+func (cmd *CLI) ImportStatement(cliCtx *cli.Context) error {
+	ctxt, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	format := cliCtx.String("format")
+	if format != "" {
+		switch statement.Format(format) {
+		case statement.CAMT053, statement.MT940, statement.OFX:
+		default:
+			logger.FromContext(ctxt).Fatalf("Statement format %q not supported", format)
+		}
+	}
+
+	return cmd_cli.ImportFiles(
+		ctxt,
+		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
+			bus := cmd.initCommandBus()
+
+			return bus.ExecuteContext(ctx, &command.ImportStatement{
+				FilePath: ri.FilePath,
+				Format:   format,
+				Rate:     cliCtx.Float64("rate"),
+				DryRun:   cliCtx.Bool("dry-run"),
+			})
+		},
+		cmd.resourceStorage,
+		"statements",
+		cmd.config.Import.StatementsPath,
+		cliCtx.String("file"),
+		"",
+		statement.Extensions(),
+		false,
+		nil,
+		0,
+		cliCtx.Bool("dry-run"),
 	)
 }
 
@@ -133,18 +290,29 @@ func (cmd *CLI) ImportWallet(cliCtx *cli.Context) error {
 	ctxt, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
+	mapping := cmd.importMapping(ctxt, cliCtx, "wallet")
+
 	return cmd_cli.Import(
 		ctxt,
 		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
 			bus := cmd.initCommandBus()
 
-			return bus.ExecuteContext(ctx, &command.ImportWallet{FilePath: ri.FilePath, Name: ri.ResourceName})
+			return bus.ExecuteContext(ctx, &command.ImportWallet{
+				FilePath: ri.FilePath,
+				Name:     ri.ResourceName,
+				Broker:   cliCtx.String("broker"),
+				DryRun:   cliCtx.Bool("dry-run"),
+				Mapping:  mapping,
+			})
 		},
 		cmd.resourceStorage,
 		"wallets",
 		cmd.config.Import.WalletsPath,
 		cliCtx.String("file"),
 		cliCtx.String("wallet"),
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
 	)
 }
 
@@ -152,18 +320,61 @@ func (cmd *CLI) ImportOperation(cliCtx *cli.Context) error {
 	ctxt, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
+	mapping := cmd.importMapping(ctxt, cliCtx, "operation")
+
 	return cmd_cli.Import(
 		ctxt,
 		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
 			bus := cmd.initCommandBus()
 
-			return bus.ExecuteContext(ctx, &command.ImportOperation{FilePath: ri.FilePath, Wallet: ri.ResourceName})
+			return bus.ExecuteContext(ctx, &command.ImportOperation{
+				FilePath:   ri.FilePath,
+				Wallet:     ri.ResourceName,
+				TradeMatch: cliCtx.String("match"),
+				DryRun:     cliCtx.Bool("dry-run"),
+				Mapping:    mapping,
+			})
 		},
 		cmd.resourceStorage,
 		"accounts",
 		cmd.config.Import.AccountsPath,
 		cliCtx.String("file"),
 		"",
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
+	)
+}
+
+// ImportBrokerOperation imports the operations from the files exported by the broker. The files are named
+// after the wallet and read in the import format of the wallet broker
+func (cmd *CLI) ImportBrokerOperation(cliCtx *cli.Context) error {
+	ctxt, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	return cmd_cli.ImportFiles(
+		ctxt,
+		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
+			bus := cmd.initCommandBus()
+
+			return bus.ExecuteContext(ctx, &command.ImportBrokerOperation{
+				FilePath:    ri.FilePath,
+				Wallet:      ri.ResourceName,
+				TradeMatch:  cliCtx.String("match"),
+				BankAccount: cliCtx.String("bank-account"),
+				DryRun:      cliCtx.Bool("dry-run"),
+			})
+		},
+		cmd.resourceStorage,
+		"brokers",
+		cmd.config.Import.BrokersPath,
+		cliCtx.String("file"),
+		cliCtx.String("wallet"),
+		[]string{".csv", ".xml"},
+		true,
+		nil,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
 	)
 }
 
@@ -171,6 +382,8 @@ func (cmd *CLI) ImportDividendRetention(cliCtx *cli.Context) error {
 	ctxt, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
+	mapping := cmd.importMapping(ctxt, cliCtx, "retention")
+
 	if cliCtx.String("wallet") == "" {
 		logger.FromContext(ctxt).Fatal("Missing wallet name")
 	}
@@ -180,13 +393,51 @@ func (cmd *CLI) ImportDividendRetention(cliCtx *cli.Context) error {
 		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
 			bus := cmd.initCommandBus()
 
-			return bus.ExecuteContext(ctx, &command.ImportRetention{FilePath: ri.FilePath, Wallet: ri.ResourceName})
+			return bus.ExecuteContext(ctx, &command.ImportRetention{
+				FilePath: ri.FilePath,
+				Wallet:   ri.ResourceName,
+				DryRun:   cliCtx.Bool("dry-run"),
+				Mapping:  mapping,
+			})
 		},
 		cmd.resourceStorage,
 		"retentions",
 		cmd.config.Import.RetentionsPath,
 		cliCtx.String("file"),
 		cliCtx.String("wallet"),
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
+	)
+}
+
+// ImportETF imports the constituents of an ETF. The file name is the symbol of the ETF
+func (cmd *CLI) ImportETF(cliCtx *cli.Context) error {
+	ctxt, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	mapping := cmd.importMapping(ctxt, cliCtx, "etf")
+
+	return cmd_cli.Import(
+		ctxt,
+		func(ctx context.Context, ri cmd_cli.ResourceImport) (interface{}, error) {
+			bus := cmd.initCommandBus()
+
+			return bus.ExecuteContext(ctx, &command.ImportETF{
+				FilePath: ri.FilePath,
+				ETF:      ri.ResourceName,
+				DryRun:   cliCtx.Bool("dry-run"),
+				Mapping:  mapping,
+			})
+		},
+		cmd.resourceStorage,
+		"etfs",
+		cmd.config.Import.ETFsPath,
+		cliCtx.String("file"),
+		cliCtx.String("etf"),
+		mapping,
+		cliCtx.Int("legacy-rows"),
+		cliCtx.Bool("dry-run"),
 	)
 }
 
@@ -198,7 +449,8 @@ func (cmd *CLI) ExportStocks(cliCtx *cli.Context) error {
 	bus := cmd.initCommandBus()
 
 	stks, err := bus.ExecuteContext(ctx, &command.ListStocks{
-		Exchange: cliCtx.String("exchange"),
+		Exchange:  cliCtx.String("exchange"),
+		Watchlist: cliCtx.String("watchlist"),
 	})
 	if err != nil {
 		return err
@@ -214,198 +466,816 @@ func (cmd *CLI) ExportStocks(cliCtx *cli.Context) error {
 	return nil
 }
 
-func (cmd *CLI) sortingFromCliCtx(cliCtx *cli.Context) util.Sorting {
-	sortBy := util.SortByStock
-	orderBy := util.OrderDescending
+// AddWatchlist creates an empty watchlist
+func (cmd *CLI) AddWatchlist(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
 
-	if cliCtx.String("sort") != "" {
-		sortBy = util.SortBy(cliCtx.String("sort"))
-	}
-	if cliCtx.String("order") != "" {
-		orderBy = util.OrderBy(cliCtx.String("order"))
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing watchlist name")
 	}
 
-	return util.Sorting{
-		By:    sortBy,
-		Order: orderBy,
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddWatchlist{Name: cliCtx.String("name")})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding watchlist")
 	}
+
+	logger.FromContext(ctx).Info("Add watchlist finished")
+
+	return nil
 }
 
-// ExportWalletDetails List in csv format or print into screen the wallet details
-func (cmd *CLI) ExportWallet(cliCtx *cli.Context) error {
+// DeleteWatchlist deletes the watchlist. The stocks are kept
+func (cmd *CLI) DeleteWatchlist(cliCtx *cli.Context) error {
 	ctx, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
-	if cliCtx.String("wallet") == "" {
-		logger.FromContext(ctx).Fatal("Missing wallet name")
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing watchlist name")
 	}
 
 	bus := cmd.initCommandBus()
 
-	sells := map[string]int{}
-	strSells := cliCtx.String("sells")
-	if strSells != "" {
-		sSells := strings.Split(strSells, ",")
-
-		for _, sSell := range sSells {
-			sa := strings.Split(sSell, ":")
-			a, _ := strconv.Atoi(sa[1])
-			sells[sa[0]] = a
-		}
+	_, err := bus.ExecuteContext(ctx, &command.DeleteWatchlist{Name: cliCtx.String("name")})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed deleting watchlist")
 	}
 
-	buys := map[string]int{}
-	strBuys := cliCtx.String("buys")
-	if strBuys != "" {
-		sBuys := strings.Split(strBuys, ",")
-
-		for _, sBuy := range sBuys {
-			ba := strings.Split(sBuy, ":")
-			a, _ := strconv.Atoi(ba[1])
-			buys[ba[0]] = a
-		}
-	}
+	logger.FromContext(ctx).Info("Delete watchlist finished")
 
-	commissions, err := cmd.getCommissionsToApplyStockOperation()
-	if (len(sells) > 0 || len(buys) > 0) && err != nil {
-		return errors.Wrapf(err, "Can execute buys or sells without commissions")
-	}
+	return nil
+}
 
-	var status operation.Status
-	switch cliCtx.String("status") {
-	case "inactive":
-		status = operation.Inactive
-	case "all":
-		status = operation.All
-	default:
-		status = operation.Active
-	}
+// AddWatchlistStocks adds the stocks to the watchlist
+func (cmd *CLI) AddWatchlistStocks(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
 
-	wOutput, err := bus.ExecuteContext(ctx, &command.WalletDetails{
-		Wallet:             cliCtx.String("wallet"),
-		Sells:              sells,
-		Buys:               buys,
-		Commissions:        commissions,
-		Status:             status,
-		IncreaseInvestment: cliCtx.String("transfer"),
-	})
-	if err != nil {
-		return err
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing watchlist name")
 	}
 
-	rOutput := render.OutputScreenWalletDetails{
-		WalletDetails: wOutput.(render.WalletDetailsOutput),
-		Sorting:       cmd.sortingFromCliCtx(cliCtx),
-		Precision:     2,
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing stock symbol")
 	}
 
-	if cliCtx.String("stock") != "" {
-		sls := render.NewScreenWalletStockDetails(cmd.ctx)
-		sls.Render(&render.OutputScreenWalletStockDetails{
-			OutputScreenWalletDetails: rOutput,
-			Stock: cliCtx.String("stock"),
-		})
+	bus := cmd.initCommandBus()
 
-		return nil
+	_, err := bus.ExecuteContext(ctx, &command.AddWatchlistStocks{
+		Watchlist: cliCtx.String("name"),
+		Stocks:    strings.Split(cliCtx.String("stock"), ","),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding stocks to watchlist")
 	}
 
-	sls := render.NewScreenWalletDetails()
-	sls.Render(&rOutput)
+	logger.FromContext(ctx).Info("Add watchlist stocks finished")
 
 	return nil
 }
 
-func (cmd *CLI) getCommissionsToApplyStockOperation() (map[string]command.Commission, error) {
-	exchanges := cmd.config.Degiro.Exchanges
-	commissions := map[string]command.Commission{}
+// RemoveWatchlistStocks removes the stocks from the watchlist
+func (cmd *CLI) RemoveWatchlistStocks(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
 
-	// NASDAQ
-	cCommission, err := json.Marshal(exchanges.NASDAQ)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Can not marshal config commission")
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing watchlist name")
 	}
 
-	var commission command.Commission
-	err = json.Unmarshal(cCommission, &commission)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Can not unmarshal config commission")
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing stock symbol")
 	}
 
-	commissions["NASDAQ"] = commission
-
-	// NYSE
-	cCommission, err = json.Marshal(exchanges.NYSE)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Can not marshal config commission")
-	}
+	bus := cmd.initCommandBus()
 
-	commission = command.Commission{}
-	err = json.Unmarshal(cCommission, &commission)
+	_, err := bus.ExecuteContext(ctx, &command.RemoveWatchlistStocks{
+		Watchlist: cliCtx.String("name"),
+		Stocks:    strings.Split(cliCtx.String("stock"), ","),
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "Can not unmarshal config commission")
+		logger.FromContext(ctx).WithError(err).Fatal("Failed removing stocks from watchlist")
 	}
 
-	commissions["NYSE"] = commission
+	logger.FromContext(ctx).Info("Remove watchlist stocks finished")
 
-	return commissions, err
+	return nil
 }
 
-// ReloadWallet reload the wallet operation
-func (cmd *CLI) ReloadWallet(cliCtx *cli.Context) error {
+// ListWatchlist print into screen the stocks of the watchlist
+func (cmd *CLI) ListWatchlist(cliCtx *cli.Context) error {
 	ctx, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
-	if cliCtx.String("wallet") == "" {
-		logger.FromContext(ctx).Fatal("Missing wallet name")
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing watchlist name")
 	}
 
 	bus := cmd.initCommandBus()
 
-	_, err := bus.ExecuteContext(ctx, &command.ReloadWallet{
-		Wallet: cliCtx.String("wallet"),
+	stks, err := bus.ExecuteContext(ctx, &command.ListStocks{
+		Watchlist: cliCtx.String("name"),
 	})
 	if err != nil {
-		logger.FromContext(ctx).WithError(err).Fatal("Failed reloading wallet")
+		return err
 	}
 
-	logger.FromContext(ctx).Info("Reloading finished")
+	sls := render.NewScreenListStocks(2)
+	sls.Render(&render.OutputScreenListStocks{
+		Stocks:  stks.([]*render.StockOutput),
+		GroupBy: util.GroupBy(cliCtx.String("group")),
+		Sorting: cmd.sortingFromCliCtx(cliCtx),
+	})
 
 	return nil
 }
 
-func (cmd *CLI) AddDividend(cliCtx *cli.Context) error {
+// AddAlertRule adds an alert rule to a stock or to the stocks of a watchlist
+func (cmd *CLI) AddAlertRule(cliCtx *cli.Context) error {
 	ctx, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
-	if cliCtx.String("wallet") == "" {
-		logger.FromContext(ctx).Fatal("Missing wallet name")
+	if cliCtx.String("stock") == "" && cliCtx.String("watchlist") == "" {
+		logger.FromContext(ctx).Fatal("Missing stock symbol or watchlist name")
 	}
 
-	if cliCtx.String("date") == "" {
-		logger.FromContext(ctx).Fatal("Missing operation's date")
+	if cliCtx.String("condition") == "" {
+		logger.FromContext(ctx).Fatal("Missing alert condition")
 	}
 
-	if cliCtx.String("stock") == "" {
-		logger.FromContext(ctx).Fatal("Missing operation's stock")
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddAlertRule{
+		Stock:     cliCtx.String("stock"),
+		Watchlist: cliCtx.String("watchlist"),
+		Condition: cliCtx.String("condition"),
+		Value:     cliCtx.Float64("value"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding alert rule")
 	}
 
-	if cliCtx.String("value") == "" {
-		logger.FromContext(ctx).Fatal("Missing dividend value")
+	logger.FromContext(ctx).Info("Add alert rule finished")
+
+	return nil
+}
+
+// DeleteAlertRule deletes an alert rule
+func (cmd *CLI) DeleteAlertRule(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("id") == "" {
+		logger.FromContext(ctx).Fatal("Missing alert rule id")
 	}
 
 	bus := cmd.initCommandBus()
 
-	_, err := bus.ExecuteContext(ctx, &command.AddDividendOperation{
-		Wallet: cliCtx.String("wallet"),
-		Date:   cliCtx.String("date"),
-		Stock:  cliCtx.String("stock"),
-		Value:  cliCtx.Float64("value"),
+	_, err := bus.ExecuteContext(ctx, &command.DeleteAlertRule{ID: cliCtx.String("id")})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed deleting alert rule")
+	}
+
+	logger.FromContext(ctx).Info("Delete alert rule finished")
+
+	return nil
+}
+
+// ListAlertRules print into screen the alert rules
+func (cmd *CLI) ListAlertRules(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	rs, err := bus.ExecuteContext(ctx, &command.ListAlertRules{})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListAlertRules()
+	sls.Render(&render.OutputScreenListAlertRules{
+		Rules:     rs.([]*alert.Rule),
+		Precision: 2,
+	})
+
+	return nil
+}
+
+// ListDividendChanges print into screen the dividend changes detected while updating the dividends
+func (cmd *CLI) ListDividendChanges(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	cs, err := bus.ExecuteContext(ctx, &command.ListDividendChanges{
+		From:  cliCtx.String("from"),
+		Stock: cliCtx.String("stock"),
+	})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListDividendChanges()
+	sls.Render(&render.OutputScreenListDividendChanges{
+		Changes:   cs.([]*render.DividendChangeOutput),
+		Precision: 4,
+	})
+
+	return nil
+}
+
+// Screen print into screen the stocks matching the screen expression, or manages the saved screens
+func (cmd *CLI) Screen(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	if cliCtx.Bool("list") {
+		ss, err := bus.ExecuteContext(ctx, &command.ListScreens{})
+		if err != nil {
+			return err
+		}
+
+		sls := render.NewScreenListScreens()
+		sls.Render(&render.OutputScreenListScreens{
+			Screens: ss.([]*screen.Screen),
+		})
+
+		return nil
+	}
+
+	if cliCtx.String("name") == "" && cliCtx.String("expr") == "" {
+		logger.FromContext(ctx).Fatal("Missing screen expression or name")
+	}
+
+	if cliCtx.Bool("delete") {
+		if cliCtx.String("name") == "" {
+			logger.FromContext(ctx).Fatal("Missing screen name")
+		}
+
+		_, err := bus.ExecuteContext(ctx, &command.DeleteScreen{Name: cliCtx.String("name")})
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Fatal("Failed deleting screen")
+		}
+
+		logger.FromContext(ctx).Info("Delete screen finished")
+
+		return nil
+	}
+
+	if cliCtx.Bool("save") && cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing screen name")
+	}
+
+	var limit *int
+	if cliCtx.IsSet("limit") {
+		l := cliCtx.Int("limit")
+		limit = &l
+	}
+
+	stks, err := bus.ExecuteContext(ctx, &command.ScreenStocks{
+		Name:       cliCtx.String("name"),
+		Expression: cliCtx.String("expr"),
+		Sort:       cliCtx.String("sort"),
+		Order:      cliCtx.String("order"),
+		Limit:      limit,
+		Save:       cliCtx.Bool("save"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed screening stocks")
+	}
+
+	// stocks come sorted and limited by the screen
+	sls := render.NewScreenListStocks(2)
+	sls.Render(&render.OutputScreenListStocks{
+		Stocks: stks.([]*render.StockOutput),
+	})
+
+	return nil
+}
+
+// importMapping loads the mapping profile the csv files of the import are read with, the one given by name or
+// else the one named after the import type. Without profile the files are read by the position of their columns
+func (cmd *CLI) importMapping(ctx context.Context, cliCtx *cli.Context, importType string) *util.Mapping {
+	name := cliCtx.String("mapping")
+	if name == "" {
+		return cmd.defaultImportMapping(ctx, importType)
+	}
+
+	m, err := util.LoadMapping(filepath.Join(cmd.config.Import.MappingsPath, name+".yml"))
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatalf("Failed loading mapping %s", name)
+	}
+
+	return m
+}
+
+// defaultImportMapping loads the mapping profile named after the import type, nil when there is none
+func (cmd *CLI) defaultImportMapping(ctx context.Context, importType string) *util.Mapping {
+	m, err := util.LoadMapping(filepath.Join(cmd.config.Import.MappingsPath, importType+".yml"))
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil
+		}
+
+		logger.FromContext(ctx).WithError(err).Fatalf("Failed loading mapping %s", importType)
+	}
+
+	return m
+}
+
+func (cmd *CLI) sortingFromCliCtx(cliCtx *cli.Context) util.Sorting {
+	sortBy := util.SortByStock
+	orderBy := util.OrderDescending
+
+	if cliCtx.String("sort") != "" {
+		sortBy = util.SortBy(cliCtx.String("sort"))
+	}
+	if cliCtx.String("order") != "" {
+		orderBy = util.OrderBy(cliCtx.String("order"))
+	}
+
+	return util.Sorting{
+		By:    sortBy,
+		Order: orderBy,
+	}
+}
+
+// ExportWallets print into screen the wallets summary, optionally filtered by broker
+func (cmd *CLI) ExportWallets(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	wOutputs, err := bus.ExecuteContext(ctx, &command.ListWallets{
+		Broker: cliCtx.String("broker"),
+	})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListWallets()
+	sls.Render(&render.OutputScreenListWallets{
+		Wallets:   wOutputs.([]*render.WalletOutput),
+		Precision: 2,
+	})
+
+	return nil
+}
+
+// ExportWalletLedger print into screen the wallet cash ledger for a date range
+func (cmd *CLI) ExportWalletLedger(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	lOutput, err := bus.ExecuteContext(ctx, &command.WalletLedger{
+		Wallet: cliCtx.String("wallet"),
+		From:   cliCtx.String("from"),
+		To:     cliCtx.String("to"),
+	})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenWalletLedger()
+	sls.Render(&render.OutputScreenWalletLedger{
+		Ledger:    lOutput.(render.WalletLedgerOutput),
+		Precision: 2,
+	})
+
+	return nil
+}
+
+// ExportWalletExposure print into screen the wallet capital by sector, country and stock looking through the ETFs
+func (cmd *CLI) ExportWalletExposure(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" && cliCtx.String("group") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet or wallet group name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	eOutput, err := bus.ExecuteContext(ctx, &command.WalletExposure{
+		Wallet: cliCtx.String("wallet"),
+		Group:  cliCtx.String("group"),
+	})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenWalletExposure()
+	sls.Render(&render.OutputScreenWalletExposure{
+		Exposure:  eOutput.(render.WalletExposureOutput),
+		Precision: 2,
+	})
+
+	return nil
+}
+
+// ExportWalletDetails List in csv format or print into screen the wallet details
+func (cmd *CLI) ExportWallet(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" && cliCtx.String("group") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet or wallet group name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	sells := map[string]float64{}
+	strSells := cliCtx.String("sells")
+	if strSells != "" {
+		sSells := strings.Split(strSells, ",")
+
+		for _, sSell := range sSells {
+			sa := strings.Split(sSell, ":")
+			a, _ := strconv.ParseFloat(sa[1], 64)
+			sells[sa[0]] = a
+		}
+	}
+
+	buys := map[string]float64{}
+	strBuys := cliCtx.String("buys")
+	if strBuys != "" {
+		sBuys := strings.Split(strBuys, ",")
+
+		for _, sBuy := range sBuys {
+			ba := strings.Split(sBuy, ":")
+			a, _ := strconv.ParseFloat(ba[1], 64)
+			buys[ba[0]] = a
+		}
+	}
+
+	var status operation.Status
+	switch cliCtx.String("status") {
+	case "inactive":
+		status = operation.Inactive
+	case "all":
+		status = operation.All
+	default:
+		status = operation.Active
+	}
+
+	wOutput, err := bus.ExecuteContext(ctx, &command.WalletDetails{
+		Wallet:             cliCtx.String("wallet"),
+		Group:              cliCtx.String("group"),
+		Sells:              sells,
+		Buys:               buys,
+		Status:             status,
+		IncreaseInvestment: cliCtx.String("transfer"),
+	})
+	if err != nil {
+		return err
+	}
+
+	rOutput := render.OutputScreenWalletDetails{
+		WalletDetails: wOutput.(render.WalletDetailsOutput),
+		Sorting:       cmd.sortingFromCliCtx(cliCtx),
+		Precision:     2,
+	}
+
+	if cliCtx.String("stock") != "" {
+		sls := render.NewScreenWalletStockDetails(cmd.ctx)
+		sls.Render(&render.OutputScreenWalletStockDetails{
+			OutputScreenWalletDetails: rOutput,
+			Stock: cliCtx.String("stock"),
+		})
+
+		return nil
+	}
+
+	sls := render.NewScreenWalletDetails()
+	sls.Render(&rOutput)
+
+	return nil
+}
+
+// ReloadWallet reload the wallet operation
+func (cmd *CLI) ReloadWallet(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.ReloadWallet{
+		Wallet: cliCtx.String("wallet"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed reloading wallet")
+	}
+
+	logger.FromContext(ctx).Info("Reloading finished")
+
+	return nil
+}
+
+// RebuildWallet replay the wallet transfers and operations, reporting or applying the differences
+func (cmd *CLI) RebuildWallet(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	rOutput, err := bus.ExecuteContext(ctx, &command.RebuildWallet{
+		Wallet: cliCtx.String("wallet"),
+		Apply:  cliCtx.Bool("apply"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed rebuilding wallet")
+	}
+
+	srw := render.NewScreenWalletRebuild()
+	srw.Render(&render.OutputScreenWalletRebuild{
+		Rebuild: rOutput.(render.WalletRebuildOutput),
+	})
+
+	return nil
+}
+
+// TradeJournal write the notes, strategy tags and exit targets of a trade
+func (cmd *CLI) TradeJournal(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("trade") == "" {
+		logger.FromContext(ctx).Fatal("Missing trade number")
+	}
+
+	tradeJournal := command.TradeJournal{
+		Wallet:      cliCtx.String("wallet"),
+		Trade:       cliCtx.Int("trade"),
+		Tags:        cliCtx.StringSlice("tag"),
+		TargetPrice: optionalFloat64(cliCtx, "target"),
+		StopLoss:    optionalFloat64(cliCtx, "stop-loss"),
+	}
+
+	if cliCtx.IsSet("note") {
+		note := cliCtx.String("note")
+		tradeJournal.Note = &note
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &tradeJournal)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed writing trade journal")
+	}
+
+	logger.FromContext(ctx).Info("Writing trade journal finished")
+
+	return nil
+}
+
+// TradeStats print into screen the statistics of the closed trades
+func (cmd *CLI) TradeStats(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	tsOutput, err := bus.ExecuteContext(ctx, &command.TradeStats{
+		Wallet: cliCtx.String("wallet"),
+		Tag:    cliCtx.String("tag"),
+		From:   cliCtx.String("from"),
+		To:     cliCtx.String("to"),
+	})
+	if err != nil {
+		return err
+	}
+
+	sts := render.NewScreenTradeStats()
+	sts.Render(&render.OutputScreenTradeStats{
+		TradeStats: tsOutput.(render.TradeStatsOutput),
+		Precision:  2,
+	})
+
+	return nil
+}
+
+// EditOperation edit the operation values, recomputing the wallet
+func (cmd *CLI) EditOperation(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("id") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's id")
+	}
+
+	editOperation := command.EditOperation{
+		Wallet:                cliCtx.String("wallet"),
+		ID:                    cliCtx.String("id"),
+		Date:                  cliCtx.String("date"),
+		Price:                 optionalFloat64(cliCtx, "price"),
+		PriceChange:           optionalFloat64(cliCtx, "price-change"),
+		PriceChangeCommission: optionalFloat64(cliCtx, "price-change-commission"),
+		Value:                 optionalFloat64(cliCtx, "value"),
+		Commission:            optionalFloat64(cliCtx, "commission"),
+	}
+
+	if cliCtx.String("amount") != "" {
+		amount := cliCtx.Float64("amount")
+		editOperation.Amount = &amount
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &editOperation)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed editing operation")
+	}
+
+	logger.FromContext(ctx).Info("Editing operation finished")
+
+	return nil
+}
+
+// optionalFloat64 returns nil when the flag is not given
+func optionalFloat64(cliCtx *cli.Context, name string) *float64 {
+	if cliCtx.String(name) == "" {
+		return nil
+	}
+
+	v := cliCtx.Float64(name)
+
+	return &v
+}
+
+// DeleteOperation delete the operation, recomputing the wallet
+func (cmd *CLI) DeleteOperation(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("id") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's id")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.DeleteOperation{
+		Wallet: cliCtx.String("wallet"),
+		ID:     cliCtx.String("id"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed deleting operation")
+	}
+
+	logger.FromContext(ctx).Info("Deleting operation finished")
+
+	return nil
+}
+
+func (cmd *CLI) AddDividend(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("date") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's date")
+	}
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's stock")
+	}
+
+	if cliCtx.String("value") == "" {
+		logger.FromContext(ctx).Fatal("Missing dividend value")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddDividendOperation{
+		Wallet: cliCtx.String("wallet"),
+		Date:   cliCtx.String("date"),
+		Stock:  cliCtx.String("stock"),
+		Value:  cliCtx.Float64("value"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding dividend operation to the wallet")
+	}
+
+	logger.FromContext(ctx).Info("Adding dividend operation to the wallet finished")
+
+	return nil
+}
+
+// AddCoupon adds a bond coupon operation to the wallet
+func (cmd *CLI) AddCoupon(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("date") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's date")
+	}
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's bond")
+	}
+
+	if cliCtx.String("value") == "" {
+		logger.FromContext(ctx).Fatal("Missing coupon value")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddCouponOperation{
+		Wallet: cliCtx.String("wallet"),
+		Date:   cliCtx.String("date"),
+		Stock:  cliCtx.String("stock"),
+		Value:  cliCtx.Float64("value"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding coupon operation to the wallet")
+	}
+
+	logger.FromContext(ctx).Info("Adding coupon operation to the wallet finished")
+
+	return nil
+}
+
+// AddRedemption adds a bond redemption operation to the wallet
+func (cmd *CLI) AddRedemption(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	if cliCtx.String("date") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's date")
+	}
+
+	if cliCtx.String("stock") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's bond")
+	}
+
+	if cliCtx.String("amount") == "" {
+		logger.FromContext(ctx).Fatal("Missing operation's amount")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddRedemptionOperation{
+		Wallet:      cliCtx.String("wallet"),
+		Trade:       cliCtx.String("trade"),
+		Date:        cliCtx.String("date"),
+		Stock:       cliCtx.String("stock"),
+		Amount:      cliCtx.Float64("amount"),
+		PriceChange: cliCtx.Float64("price-change"),
+		Value:       cliCtx.Float64("value"),
+		Commission:  cliCtx.Float64("commission"),
 	})
 	if err != nil {
-		logger.FromContext(ctx).WithError(err).Fatal("Failed adding dividend operation to the wallet")
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding redemption operation to the wallet")
 	}
 
-	logger.FromContext(ctx).Info("Adding dividend operation to the wallet finished")
+	logger.FromContext(ctx).Info("Adding redemption operation to the wallet finished")
 
 	return nil
 }
@@ -418,10 +1288,6 @@ func (cmd *CLI) AddBuyStock(cliCtx *cli.Context) error {
 		logger.FromContext(ctx).Fatal("Missing wallet name")
 	}
 
-	if cliCtx.String("trade") == "" {
-		logger.FromContext(ctx).Fatal("Missing operation's trade")
-	}
-
 	if cliCtx.String("date") == "" {
 		logger.FromContext(ctx).Fatal("Missing operation's date")
 	}
@@ -466,7 +1332,7 @@ func (cmd *CLI) AddBuyStock(cliCtx *cli.Context) error {
 		PriceChange:           cliCtx.Float64("price-change"),
 		PriceChangeCommission: cliCtx.Float64("price-change-commission"),
 		Commission:            cliCtx.Float64("commission"),
-		Amount:                cliCtx.Int("amount"),
+		Amount:                cliCtx.Float64("amount"),
 	})
 	if err != nil {
 		logger.FromContext(ctx).WithError(err).Fatal("Failed adding buy operation to the wallet")
@@ -485,10 +1351,6 @@ func (cmd *CLI) AddSellStock(cliCtx *cli.Context) error {
 		logger.FromContext(ctx).Fatal("Missing wallet name")
 	}
 
-	if cliCtx.String("trade") == "" {
-		logger.FromContext(ctx).Fatal("Missing operation's trade")
-	}
-
 	if cliCtx.String("date") == "" {
 		logger.FromContext(ctx).Fatal("Missing operation's date")
 	}
@@ -525,6 +1387,7 @@ func (cmd *CLI) AddSellStock(cliCtx *cli.Context) error {
 
 	_, err := bus.ExecuteContext(ctx, &command.AddSellOperation{
 		Trade:                 cliCtx.String("trade"),
+		TradeMatch:            cliCtx.String("match"),
 		Wallet:                cliCtx.String("wallet"),
 		Date:                  cliCtx.String("date"),
 		Stock:                 cliCtx.String("stock"),
@@ -533,7 +1396,7 @@ func (cmd *CLI) AddSellStock(cliCtx *cli.Context) error {
 		PriceChange:           cliCtx.Float64("price-change"),
 		PriceChangeCommission: cliCtx.Float64("price-change-commission"),
 		Commission:            cliCtx.Float64("commission"),
-		Amount:                cliCtx.Int("amount"),
+		Amount:                cliCtx.Float64("amount"),
 	})
 	if err != nil {
 		logger.FromContext(ctx).WithError(err).Fatal("Failed sell dividend operation to the wallet")
@@ -580,8 +1443,8 @@ func (cmd *CLI) ExportSnapshotWallet(cliCtx *cli.Context) error {
 	ctx, cancelCtx := context.WithCancel(context.TODO())
 	defer cancelCtx()
 
-	if cliCtx.String("wallet") == "" {
-		logger.FromContext(ctx).Fatal("Missing wallet name")
+	if cliCtx.String("wallet") == "" && cliCtx.String("group") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet or wallet group name")
 	}
 
 	if cliCtx.String("date") == "" {
@@ -596,11 +1459,14 @@ func (cmd *CLI) ExportSnapshotWallet(cliCtx *cli.Context) error {
 	bus := cmd.initCommandBus()
 
 	wOutput, err := bus.ExecuteContext(ctx, &command.WalletDateDetails{
-		Wallet:        cliCtx.String("wallet"),
-		Date:          cliCtx.String("date"),
-		TransferPath:  cmd.config.Import.TransfersPath,
-		OperationPath: cmd.config.Import.AccountsPath,
-		Excludes:      excludes,
+		Wallet:           cliCtx.String("wallet"),
+		Group:            cliCtx.String("group"),
+		Date:             cliCtx.String("date"),
+		TransferPath:     cmd.config.Import.TransfersPath,
+		OperationPath:    cmd.config.Import.AccountsPath,
+		Excludes:         excludes,
+		TransferMapping:  cmd.defaultImportMapping(ctx, "transfer"),
+		OperationMapping: cmd.defaultImportMapping(ctx, "operation"),
 	})
 	if err != nil {
 		return err
@@ -646,6 +1512,7 @@ func (cmd *CLI) AddStock(cliCtx *cli.Context) error {
 	_, err := bus.ExecuteContext(ctx, &command.AddStock{
 		Symbol:   cliCtx.String("stock"),
 		Exchange: cliCtx.String("exchange"),
+		ISIN:     cliCtx.String("isin"),
 	})
 	if err != nil {
 		logger.FromContext(ctx).WithError(err).Fatal("Failed adding stock")
@@ -656,6 +1523,83 @@ func (cmd *CLI) AddStock(cliCtx *cli.Context) error {
 	return nil
 }
 
+// AddCryptocurrency adds a cryptocurrency pair
+func (cmd *CLI) AddCryptocurrency(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("symbol") == "" {
+		logger.FromContext(ctx).Fatal("Missing cryptocurrency symbol")
+	}
+
+	if cliCtx.String("quote") == "" {
+		logger.FromContext(ctx).Fatal("Missing quote currency")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddCryptocurrency{
+		Symbol: cliCtx.String("symbol"),
+		Quote:  cliCtx.String("quote"),
+		Name:   cliCtx.String("name"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding cryptocurrency")
+	}
+
+	logger.FromContext(ctx).Info("Add cryptocurrency finished")
+
+	return nil
+}
+
+// AddBond adds a fixed income instrument
+func (cmd *CLI) AddBond(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("symbol") == "" {
+		logger.FromContext(ctx).Fatal("Missing bond symbol")
+	}
+
+	if cliCtx.String("exchange") == "" {
+		logger.FromContext(ctx).Fatal("Missing exchange")
+	}
+
+	if cliCtx.String("face-value") == "" {
+		logger.FromContext(ctx).Fatal("Missing face value")
+	}
+
+	if cliCtx.String("maturity") == "" {
+		logger.FromContext(ctx).Fatal("Missing maturity")
+	}
+
+	price := float64(100)
+	if cliCtx.String("price") != "" {
+		price = cliCtx.Float64("price")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddBond{
+		Symbol:          cliCtx.String("symbol"),
+		Name:            cliCtx.String("name"),
+		Exchange:        cliCtx.String("exchange"),
+		FaceValue:       cliCtx.Float64("face-value"),
+		CouponRate:      cliCtx.Float64("coupon"),
+		CouponFrequency: cliCtx.Int("frequency"),
+		IssueDate:       cliCtx.String("issue"),
+		Maturity:        cliCtx.String("maturity"),
+		CleanPrice:      price,
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding bond")
+	}
+
+	logger.FromContext(ctx).Info("Add bond finished")
+
+	return nil
+}
+
 // AddDividendRetention adds dividend retention.
 func (cmd *CLI) AddDividendRetention(cliCtx *cli.Context) error {
 	ctx, cancelCtx := context.WithCancel(context.TODO())
@@ -688,3 +1632,326 @@ func (cmd *CLI) AddDividendRetention(cliCtx *cli.Context) error {
 
 	return nil
 }
+
+// AddBroker adds a broker where wallets can be hold.
+func (cmd *CLI) AddBroker(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing broker name")
+	}
+
+	if cliCtx.String("format") == "" {
+		logger.FromContext(ctx).Fatal("Missing broker import format")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddBroker{
+		Name:         cliCtx.String("name"),
+		Retention:    cliCtx.Float64("retention"),
+		Margin:       cliCtx.Float64("margin"),
+		Connectivity: cliCtx.Float64("connectivity"),
+		ImportFormat: cliCtx.String("format"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding broker")
+	}
+
+	logger.FromContext(ctx).Info("Add broker finished")
+
+	return nil
+}
+
+// AddBrokerCommission adds or updates the broker commission for an exchange.
+func (cmd *CLI) AddBrokerCommission(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("broker") == "" {
+		logger.FromContext(ctx).Fatal("Missing broker name")
+	}
+
+	if cliCtx.String("exchange") == "" {
+		logger.FromContext(ctx).Fatal("Missing exchange symbol")
+	}
+
+	if cliCtx.String("apply") == "" {
+		logger.FromContext(ctx).Fatal("Missing commission apply")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddBrokerCommission{
+		Broker:        cliCtx.String("broker"),
+		Exchange:      cliCtx.String("exchange"),
+		Base:          cliCtx.Float64("base"),
+		Extra:         cliCtx.Float64("extra"),
+		ExtraCurrency: cliCtx.String("extra-currency"),
+		Apply:         cliCtx.String("apply"),
+		Maximum:       cliCtx.Float64("maximum"),
+		Change:        cliCtx.Float64("change"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding broker commission")
+	}
+
+	logger.FromContext(ctx).Info("Add broker commission finished")
+
+	return nil
+}
+
+// AddWalletGroup creates a wallet group, with the wallets given if any
+func (cmd *CLI) AddWalletGroup(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet group name")
+	}
+
+	var wallets []string
+	if cliCtx.String("wallet") != "" {
+		wallets = strings.Split(cliCtx.String("wallet"), ",")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddWalletGroup{
+		Name:    cliCtx.String("name"),
+		Wallets: wallets,
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding wallet group")
+	}
+
+	logger.FromContext(ctx).Info("Add wallet group finished")
+
+	return nil
+}
+
+// DeleteWalletGroup deletes the wallet group. The wallets are kept
+func (cmd *CLI) DeleteWalletGroup(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet group name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.DeleteWalletGroup{Name: cliCtx.String("name")})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed deleting wallet group")
+	}
+
+	logger.FromContext(ctx).Info("Delete wallet group finished")
+
+	return nil
+}
+
+// AddWalletGroupWallets adds the wallets to the wallet group
+func (cmd *CLI) AddWalletGroupWallets(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet group name")
+	}
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddWalletGroupWallets{
+		Group:   cliCtx.String("name"),
+		Wallets: strings.Split(cliCtx.String("wallet"), ","),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding wallets to wallet group")
+	}
+
+	logger.FromContext(ctx).Info("Add wallet group wallets finished")
+
+	return nil
+}
+
+// RemoveWalletGroupWallets removes the wallets from the wallet group
+func (cmd *CLI) RemoveWalletGroupWallets(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("name") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet group name")
+	}
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.RemoveWalletGroupWallets{
+		Group:   cliCtx.String("name"),
+		Wallets: strings.Split(cliCtx.String("wallet"), ","),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed removing wallets from wallet group")
+	}
+
+	logger.FromContext(ctx).Info("Remove wallet group wallets finished")
+
+	return nil
+}
+
+// ListWalletGroups print into screen the wallet groups with their wallets
+func (cmd *CLI) ListWalletGroups(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	gs, err := bus.ExecuteContext(ctx, &command.ListWalletGroups{})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListWalletGroups()
+	sls.Render(&render.OutputScreenListWalletGroups{
+		Groups: gs.([]*group.Group),
+	})
+
+	return nil
+}
+
+// AddBankAccount adds a bank account
+func (cmd *CLI) AddBankAccount(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("alias") == "" {
+		logger.FromContext(ctx).Fatal("Missing bank account alias")
+	}
+
+	if cliCtx.String("account-no") == "" {
+		logger.FromContext(ctx).Fatal("Missing bank account number")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.AddBankAccount{
+		Name:          cliCtx.String("name"),
+		Alias:         cliCtx.String("alias"),
+		AccountNo:     cliCtx.String("account-no"),
+		AccountNoType: cliCtx.String("account-no-type"),
+		Type:          cliCtx.String("type"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed adding bank account")
+	}
+
+	logger.FromContext(ctx).Info("Add bank account finished")
+
+	return nil
+}
+
+// EditBankAccount changes the bank account
+func (cmd *CLI) EditBankAccount(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("alias") == "" {
+		logger.FromContext(ctx).Fatal("Missing bank account alias")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.EditBankAccount{
+		Alias:         cliCtx.String("alias"),
+		Name:          cliCtx.String("name"),
+		NewAlias:      cliCtx.String("new-alias"),
+		AccountNo:     cliCtx.String("account-no"),
+		AccountNoType: cliCtx.String("account-no-type"),
+		Type:          cliCtx.String("type"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed editing bank account")
+	}
+
+	logger.FromContext(ctx).Info("Edit bank account finished")
+
+	return nil
+}
+
+// LinkBankAccount links the bank account to the wallet
+func (cmd *CLI) LinkBankAccount(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	if cliCtx.String("alias") == "" {
+		logger.FromContext(ctx).Fatal("Missing bank account alias")
+	}
+
+	if cliCtx.String("wallet") == "" {
+		logger.FromContext(ctx).Fatal("Missing wallet name")
+	}
+
+	bus := cmd.initCommandBus()
+
+	_, err := bus.ExecuteContext(ctx, &command.LinkBankAccount{
+		Alias:  cliCtx.String("alias"),
+		Wallet: cliCtx.String("wallet"),
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Fatal("Failed linking bank account")
+	}
+
+	logger.FromContext(ctx).Info("Link bank account finished")
+
+	return nil
+}
+
+// ListBankAccounts print into screen the bank accounts and the wallet they are linked to
+func (cmd *CLI) ListBankAccounts(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	bs, err := bus.ExecuteContext(ctx, &command.ListBankAccounts{})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListBankAccounts()
+	sls.Render(&render.OutputScreenListBankAccounts{
+		BankAccounts: bs.([]*render.BankAccountOutput),
+	})
+
+	return nil
+}
+
+// ListBrokers print into screen the brokers with their fee schedule
+func (cmd *CLI) ListBrokers(cliCtx *cli.Context) error {
+	ctx, cancelCtx := context.WithCancel(context.TODO())
+	defer cancelCtx()
+
+	bus := cmd.initCommandBus()
+
+	bs, err := bus.ExecuteContext(ctx, &command.ListBrokers{})
+	if err != nil {
+		return err
+	}
+
+	sls := render.NewScreenListBrokers()
+	sls.Render(&render.OutputScreenListBrokers{
+		Brokers:   bs.([]*broker.Broker),
+		Precision: 2,
+	})
+
+	return nil
+}
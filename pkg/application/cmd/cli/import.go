@@ -6,7 +6,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/dohernandez/market-manager/pkg/application/render"
 	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
 	"github.com/dohernandez/market-manager/pkg/market-manager"
@@ -27,14 +30,73 @@ func Import(
 	resourcePath,
 	filePath,
 	fileName string,
+	mapping *util.Mapping,
+	legacyRows int,
+	dryRun bool,
+) error {
+	return ImportFiles(
+		ctxt,
+		busExecuteContext,
+		resourceStorage,
+		resource,
+		resourcePath,
+		filePath,
+		fileName,
+		[]string{".csv"},
+		false,
+		mapping,
+		legacyRows,
+		dryRun,
+	)
+}
+
+// ImportFiles imports the files of the resource path with any of the extensions given. The file given without
+// extension is looked for with the first one. The files already imported are skipped by their content, even
+// renamed, and so are the rows of the csv files imported from another file or an earlier version of the file.
+// The first row of the csv files is always kept when they have header, as well as when the mapping they are read
+// with tells so. The legacy rows are the rows the file given had when it was imported, if it was imported before
+// its rows were told apart and edited since. On a dry run the files are validated and the problems found are
+// printed, nothing is saved
+func ImportFiles(
+	ctxt context.Context,
+	busExecuteContext busExecuteContextFunc,
+	resourceStorage util.ResourceStorage,
+	resource,
+	resourcePath,
+	filePath,
+	fileName string,
+	exts []string,
+	header bool,
+	mapping *util.Mapping,
+	legacyRows int,
+	dryRun bool,
 ) error {
 	log := logger.FromContext(ctxt)
 
-	ris, err := getResourceImports(ctxt, filePath, resourcePath, fileName)
+	if legacyRows > 0 && filePath == "" {
+		log.Fatal("The legacy rows are told for a single file, give the file")
+	}
+
+	format := csvFormat{header: header || mapping.HasHeader(), comma: mapping.Comma(), mapping: mapping.Name()}
+
+	ris, err := getResourceImports(ctxt, filePath, resourcePath, fileName, exts)
 	if err != nil {
 		log.WithError(err).Fatal("Failed importing")
 	}
 
+	if dryRun {
+		reports, err := validateImport(ctxt, busExecuteContext, resourceStorage, resource, ris, format, legacyRows)
+		if err != nil {
+			log.WithError(err).Fatal("Failed validating import")
+		}
+
+		render.NewScreenImportReport().Render(&render.OutputScreenImportReport{
+			Reports: reports,
+		})
+
+		return nil
+	}
+
 	fn := func(ctx context.Context, busExecuteContext busExecuteContextFunc, ri ResourceImport) error {
 		_, err := busExecuteContext(ctx, ri)
 		if err != nil {
@@ -44,7 +106,7 @@ func Import(
 		return nil
 	}
 
-	err = runImport(ctxt, busExecuteContext, resourceStorage, resource, ris, fn)
+	err = runImport(ctxt, busExecuteContext, resourceStorage, resource, ris, format, legacyRows, fn)
 	if err != nil {
 		log.WithError(err).Fatal("Failed importing")
 	}
@@ -54,11 +116,14 @@ func Import(
 	return nil
 }
 
-func getResourceImports(_ context.Context, filePath, importPath, fileName string) ([]ResourceImport, error) {
+func getResourceImports(_ context.Context, filePath, importPath, fileName string, exts []string) ([]ResourceImport, error) {
 	var ris []ResourceImport
 
 	if filePath != "" {
-		filePath := fmt.Sprintf("%s/%s.csv", importPath, filePath)
+		filePath := fmt.Sprintf("%s/%s", importPath, filePath)
+		if !hasExtension(filePath, exts) {
+			filePath = filePath + exts[0]
+		}
 
 		ris = append(ris, ResourceImport{
 			FilePath:     filePath,
@@ -70,7 +135,7 @@ func getResourceImports(_ context.Context, filePath, importPath, fileName string
 				return nil
 			}
 
-			if filepath.Ext(path) == ".csv" {
+			if hasExtension(path, exts) {
 				filePath := path
 				rName := util.GeResourceNameFromFilePath(filePath)
 
@@ -98,12 +163,26 @@ func getResourceImports(_ context.Context, filePath, importPath, fileName string
 	return ris, nil
 }
 
+func hasExtension(file string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+
+	return false
+}
+
 func runImport(
 	ctxt context.Context,
 	busExecuteContext busExecuteContextFunc,
 	resourceStorage util.ResourceStorage,
 	resourceType string,
 	ris []ResourceImport,
+	format csvFormat,
+	legacyRows int,
 	fn func(ctx context.Context, busExecuteContext busExecuteContextFunc, ri ResourceImport) error,
 ) error {
 	irs, err := resourceStorage.FindAllByResource(resourceType)
@@ -115,36 +194,181 @@ func runImport(
 		irs = []util.Resource{}
 	}
 
+	rs, err := resourceStorage.FindAllRowsByResource(resourceType)
+	if err != nil {
+		return err
+	}
+
+	imported := newImportedRows(rs)
+
 	log := logger.FromContext(ctxt)
 
+	var nNew, nSkipped, nConflicting int
+
 	for _, ri := range ris {
 		fileName := path.Base(ri.FilePath)
 
-		var found bool
-		for _, ir := range irs {
-			if ir.FileName == fileName {
-				found = true
+		hash, err := util.FileHash(ri.FilePath)
+		if err != nil {
+			return err
+		}
+
+		var (
+			legacy     bool
+			importedAt time.Time
+		)
+
+		ir, found := findImportedFile(irs, fileName, hash)
+		if found {
+			if ir.Hash == hash {
+				log.Infof("File %s already imported as %s, skipped", fileName, ir.FileName)
 
-				break
+				continue
 			}
+
+			// imported before the files were told by their content
+			legacy = ir.Hash == ""
+			importedAt = ir.CreatedAt
 		}
 
-		if !found {
+		r := util.NewResource(resourceType, fileName)
+		r.Hash = hash
+		r.Mapping = format.mapping
+
+		if !hasExtension(fileName, []string{".csv"}) {
 			log.Infof("Importing file %s", fileName)
 
 			if err := fn(ctxt, busExecuteContext, ri); err != nil {
 				return err
 			}
 
-			ir := util.NewResource(resourceType, fileName)
-			err := resourceStorage.Persist(ir)
-			if err != nil {
+			if err := resourceStorage.Persist(r); err != nil {
 				return err
 			}
 
+			irs = append(irs, r)
+
 			log.Infof("Imported file %s", fileName)
+
+			continue
+		}
+
+		fr, err := readFileRows(ri.FilePath, format)
+		if err != nil {
+			return err
+		}
+
+		fr.classify(imported, resourceType, fileName)
+
+		var taken []int
+
+		if legacy {
+			var ok bool
+
+			taken, ok, err = fr.takeLegacy(ri.FilePath, importedAt, legacyRows)
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				log.Warnf(
+					"File %s was edited after it was imported, before its rows were told apart, not imported. "+
+						"Import it with --file and --legacy-rows telling the rows it had",
+					fileName,
+				)
+
+				continue
+			}
+
+			log.Infof(
+				"File %s imported before its rows were told apart, %d of its rows are taken as imported",
+				fileName,
+				len(taken),
+			)
+		}
+
+		for _, i := range fr.conflicting {
+			log.Warnf(
+				"File %s line %d was edited after it was imported, not imported again, fix it by hand",
+				fileName,
+				fr.numbers[i],
+			)
+		}
+
+		if len(fr.new) > 0 {
+			log.Infof("Importing file %s", fileName)
+
+			fri := ri
+			if len(fr.new) < len(fr.lines) {
+				fri.FilePath, err = fr.writeNew(ri.FilePath)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = fn(ctxt, busExecuteContext, fri)
+
+			if fri.FilePath != ri.FilePath {
+				os.RemoveAll(filepath.Dir(fri.FilePath))
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := resourceStorage.Persist(r); err != nil {
+			return err
+		}
+
+		irs = append(irs, r)
+
+		newRows := fr.rows(r, append(taken, fr.new...))
+
+		if err := resourceStorage.PersistRows(newRows); err != nil {
+			return err
 		}
+
+		imported.add(newRows)
+
+		log.Infof(
+			"Imported file %s: %d new, %d skipped, %d conflicting rows",
+			fileName,
+			len(fr.new),
+			len(fr.skipped),
+			len(fr.conflicting),
+		)
+
+		nNew += len(fr.new)
+		nSkipped += len(fr.skipped)
+		nConflicting += len(fr.conflicting)
+	}
+
+	if nNew+nSkipped+nConflicting > 0 {
+		log.Infof("Rows imported: %d new, %d skipped, %d conflicting", nNew, nSkipped, nConflicting)
 	}
 
 	return nil
 }
+
+// findImportedFile finds the file imported with the same content, or else with the same name
+func findImportedFile(irs []util.Resource, fileName, hash string) (util.Resource, bool) {
+	for _, ir := range irs {
+		if ir.Hash == hash {
+			return ir, true
+		}
+	}
+
+	var (
+		last  util.Resource
+		found bool
+	)
+
+	for _, ir := range irs {
+		if ir.FileName == fileName && (!found || ir.CreatedAt.After(last.CreatedAt)) {
+			last, found = ir, true
+		}
+	}
+
+	return last, found
+}
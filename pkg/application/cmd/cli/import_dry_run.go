@@ -0,0 +1,141 @@
+package cmd_cli
+
+import (
+	"context"
+	"path"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+// validateImport validates the files as a dry run, nothing is saved. The whole file is handed to the handler
+// so the lines reported are the ones of the file, the rows already imported or conflicting are reported too
+func validateImport(
+	ctxt context.Context,
+	busExecuteContext busExecuteContextFunc,
+	resourceStorage util.ResourceStorage,
+	resourceType string,
+	ris []ResourceImport,
+	format csvFormat,
+	legacyRows int,
+) ([]*util.ImportReport, error) {
+	irs, err := resourceStorage.FindAllByResource(resourceType)
+	if err != nil {
+		if err != mm.ErrNotFound {
+			return nil, err
+		}
+
+		irs = []util.Resource{}
+	}
+
+	rs, err := resourceStorage.FindAllRowsByResource(resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	imported := newImportedRows(rs)
+
+	var reports []*util.ImportReport
+
+	for _, ri := range ris {
+		fileName := path.Base(ri.FilePath)
+
+		hash, err := util.FileHash(ri.FilePath)
+		if err != nil {
+			return nil, err
+		}
+
+		ir, found := findImportedFile(irs, fileName, hash)
+		if found && ir.Hash == hash {
+			report := &util.ImportReport{File: fileName}
+			report.AddIssuef(0, "file already imported as %s, skipped", ir.FileName)
+
+			reports = append(reports, report)
+
+			continue
+		}
+
+		res, err := busExecuteContext(ctxt, ri)
+		if err != nil {
+			report := &util.ImportReport{File: fileName}
+			report.AddIssue(0, err)
+
+			reports = append(reports, report)
+
+			continue
+		}
+
+		report, ok := res.(*util.ImportReport)
+		if !ok {
+			return nil, errors.Errorf("import of %s does not support dry run", resourceType)
+		}
+
+		report.File = fileName
+
+		// the handler does not count the header among the lines of the file
+		if format.header {
+			for i := range report.Issues {
+				if report.Issues[i].Line > 0 {
+					report.Issues[i].Line++
+				}
+			}
+		}
+
+		if !hasExtension(fileName, []string{".csv"}) {
+			report.New = report.Rows
+			reports = append(reports, report)
+
+			continue
+		}
+
+		fr, err := readFileRows(ri.FilePath, format)
+		if err != nil {
+			return nil, err
+		}
+
+		fr.classify(imported, resourceType, fileName)
+
+		if found && ir.Hash == "" {
+			taken, ok, err := fr.takeLegacy(ri.FilePath, ir.CreatedAt, legacyRows)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				report.AddIssuef(
+					0,
+					"file edited after it was imported, before its rows were told apart, tell the rows it had with --legacy-rows",
+				)
+
+				fr.skipped = append(fr.skipped, fr.new...)
+				fr.new = nil
+			} else {
+				report.AddIssuef(
+					0,
+					"file imported before its rows were told apart, %d of its rows are taken as imported",
+					len(taken),
+				)
+			}
+		}
+
+		for _, i := range fr.skipped {
+			if r, ok := imported.fingerprints[fr.fingerprints[i]]; ok {
+				report.AddIssuef(fr.numbers[i], "already imported from %s line %d, skipped", r.FileName, r.Line)
+			}
+		}
+
+		for _, i := range fr.conflicting {
+			report.AddIssuef(fr.numbers[i], "edited after it was imported, not imported again, fix it by hand")
+		}
+
+		report.New = len(fr.new)
+		report.Skipped = len(fr.skipped)
+		report.Conflicting = len(fr.conflicting)
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,216 @@
+package cmd_cli
+
+import (
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
+
+type (
+	// importedRows holds the rows imported of a resource by their fingerprint and by the line of the file
+	// they were imported from
+	importedRows struct {
+		fingerprints map[string]util.ResourceRow
+		files        map[string]map[int]string
+	}
+
+	// csvFormat tells whether the first row of the csv files is the header and the delimiter of their fields
+	csvFormat struct {
+		header bool
+		comma  rune
+		// mapping is the name of the mapping profile the files are read with
+		mapping string
+	}
+
+	// fileRows are the rows of a csv file told apart by whether they were imported already
+	fileRows struct {
+		comma        rune
+		header       []string
+		lines        [][]string
+		numbers      []int
+		fingerprints []string
+
+		new         []int
+		skipped     []int
+		conflicting []int
+	}
+)
+
+func newImportedRows(rs []util.ResourceRow) *importedRows {
+	ir := &importedRows{
+		fingerprints: map[string]util.ResourceRow{},
+		files:        map[string]map[int]string{},
+	}
+
+	ir.add(rs)
+
+	return ir
+}
+
+func (ir *importedRows) add(rs []util.ResourceRow) {
+	for _, r := range rs {
+		ir.fingerprints[r.Fingerprint] = r
+
+		if ir.files[r.FileName] == nil {
+			ir.files[r.FileName] = map[int]string{}
+		}
+
+		ir.files[r.FileName][r.Line] = r.Fingerprint
+	}
+}
+
+// readFileRows reads the rows of the csv file, the first one is kept apart when the file has header
+func readFileRows(file string, format csvFormat) (*fileRows, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comma = format.comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	lines, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	fr := &fileRows{comma: format.comma}
+
+	first := 1
+	if format.header && len(lines) > 0 {
+		fr.header = lines[0]
+		lines = lines[1:]
+		first = 2
+	}
+
+	fr.lines = lines
+	fr.numbers = make([]int, len(lines))
+	for i := range lines {
+		fr.numbers[i] = first + i
+	}
+
+	return fr, nil
+}
+
+// classify tells apart the rows of the file already imported as the resource, the new ones and the ones
+// conflicting. A row conflicts when the line of the file was imported with another content that is no longer
+// in the file, the row was edited after it was imported and has to be fixed by hand
+func (fr *fileRows) classify(ir *importedRows, resource, fileName string) {
+	fr.fingerprints = util.Fingerprints(resource, fr.lines)
+
+	inFile := make(map[string]bool, len(fr.fingerprints))
+	for _, f := range fr.fingerprints {
+		inFile[f] = true
+	}
+
+	for i, f := range fr.fingerprints {
+		if _, ok := ir.fingerprints[f]; ok {
+			fr.skipped = append(fr.skipped, i)
+
+			continue
+		}
+
+		if old, ok := ir.files[fileName][fr.numbers[i]]; ok && !inFile[old] {
+			fr.conflicting = append(fr.conflicting, i)
+
+			continue
+		}
+
+		fr.new = append(fr.new, i)
+	}
+}
+
+// takeLegacy takes as imported the new rows the file had when it was imported, before its rows were told apart
+// by their fingerprint. They are all the rows when the file was not modified since, otherwise the first rows
+// told. It returns the rows taken and false when the rows the file had are not known
+func (fr *fileRows) takeLegacy(file string, importedAt time.Time, legacyRows int) ([]int, bool, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, false, err
+	}
+
+	n := len(fr.lines)
+	if info.ModTime().After(importedAt) {
+		if legacyRows <= 0 {
+			return nil, false, nil
+		}
+
+		n = legacyRows
+	}
+
+	var taken, rest []int
+
+	for _, i := range fr.new {
+		if i < n {
+			taken = append(taken, i)
+		} else {
+			rest = append(rest, i)
+		}
+	}
+
+	fr.skipped = append(fr.skipped, taken...)
+	fr.new = rest
+
+	return taken, true, nil
+}
+
+// rows returns the rows to save as imported of the lines given
+func (fr *fileRows) rows(r util.Resource, is []int) []util.ResourceRow {
+	fs := make([]string, len(is))
+	ns := make([]int, len(is))
+
+	for j, i := range is {
+		fs[j] = fr.fingerprints[i]
+		ns[j] = fr.numbers[i]
+	}
+
+	return util.NewResourceRows(r, fs, ns)
+}
+
+// writeNew writes the header and the new rows to a file named as the one given in a temporary directory,
+// the directory has to be removed once imported
+func (fr *fileRows) writeNew(file string) (string, error) {
+	dir, err := ioutil.TempDir("", "import")
+	if err != nil {
+		return "", err
+	}
+
+	newFile := filepath.Join(dir, path.Base(file))
+
+	f, err := os.Create(newFile)
+	if err != nil {
+		os.RemoveAll(dir)
+
+		return "", err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Comma = fr.comma
+
+	if fr.header != nil {
+		w.Write(fr.header)
+	}
+
+	for _, i := range fr.new {
+		w.Write(fr.lines[i])
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		os.RemoveAll(dir)
+
+		return "", err
+	}
+
+	return newFile, nil
+}
@@ -0,0 +1,8 @@
+package command
+
+type AddAlertRule struct {
+	Stock     string
+	Watchlist string
+	Condition string
+	Value     float64
+}
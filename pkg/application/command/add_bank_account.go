@@ -0,0 +1,9 @@
+package command
+
+type AddBankAccount struct {
+	Name          string
+	Alias         string
+	AccountNo     string
+	AccountNoType string
+	Type          string
+}
@@ -0,0 +1,18 @@
+package command
+
+type AddBond struct {
+	// Symbol of the bond, usually its ISIN
+	Symbol   string
+	Name     string
+	Exchange string
+	// FaceValue paid back per bond at maturity
+	FaceValue float64
+	// CouponRate annual percentage of the face value
+	CouponRate float64
+	// CouponFrequency coupons paid per year, 0 for zero coupon
+	CouponFrequency int
+	IssueDate       string
+	Maturity        string
+	// CleanPrice percentage of the face value
+	CleanPrice float64
+}
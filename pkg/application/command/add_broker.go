@@ -0,0 +1,9 @@
+package command
+
+type AddBroker struct {
+	Name         string
+	Retention    float64
+	Margin       float64
+	Connectivity float64
+	ImportFormat string
+}
@@ -0,0 +1,12 @@
+package command
+
+type AddBrokerCommission struct {
+	Broker        string
+	Exchange      string
+	Base          float64
+	Extra         float64
+	ExtraCurrency string
+	Apply         string
+	Maximum       float64
+	Change        float64
+}
@@ -1,6 +1,7 @@
 package command
 
 type AddBuyOperation struct {
+	// Trade overrides the trade assigned automatically
 	Trade                 string
 	Date                  string
 	Wallet                string
@@ -9,6 +10,6 @@ type AddBuyOperation struct {
 	PriceChange           float64
 	PriceChangeCommission float64
 	Commission            float64
-	Amount                int
+	Amount                float64
 	Value                 float64
 }
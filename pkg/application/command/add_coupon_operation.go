@@ -0,0 +1,8 @@
+package command
+
+type AddCouponOperation struct {
+	Date   string
+	Wallet string
+	Stock  string
+	Value  float64
+}
@@ -0,0 +1,9 @@
+package command
+
+type AddCryptocurrency struct {
+	// Symbol of the base currency, e.g. BTC
+	Symbol string
+	// Quote currency code the pair is priced in, e.g. EUR
+	Quote string
+	Name  string
+}
@@ -0,0 +1,14 @@
+package command
+
+type AddRedemptionOperation struct {
+	// Trade overrides the trade chosen automatically
+	Trade       string
+	Date        string
+	Wallet      string
+	Stock       string
+	PriceChange float64
+	Commission  float64
+	Amount      float64
+	// Value defaults to the face value of the bonds redeemed
+	Value float64
+}
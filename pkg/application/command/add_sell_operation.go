@@ -1,7 +1,9 @@
 package command
 
 type AddSellOperation struct {
+	// Trade overrides the trade chosen by TradeMatch
 	Trade                 string
+	TradeMatch            string
 	Date                  string
 	Wallet                string
 	Stock                 string
@@ -9,6 +11,6 @@ type AddSellOperation struct {
 	PriceChange           float64
 	PriceChangeCommission float64
 	Commission            float64
-	Amount                int
+	Amount                float64
 	Value                 float64
 }
@@ -3,4 +3,6 @@ package command
 type AddStock struct {
 	Symbol   string
 	Exchange string
+	// ISIN optional, the brokers statements refer the stocks by it
+	ISIN string
 }
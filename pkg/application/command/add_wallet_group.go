@@ -0,0 +1,6 @@
+package command
+
+type AddWalletGroup struct {
+	Name    string
+	Wallets []string
+}
@@ -0,0 +1,6 @@
+package command
+
+type AddWalletGroupWallets struct {
+	Group   string
+	Wallets []string
+}
@@ -0,0 +1,5 @@
+package command
+
+type AddWatchlist struct {
+	Name string
+}
@@ -0,0 +1,6 @@
+package command
+
+type AddWatchlistStocks struct {
+	Watchlist string
+	Stocks    []string
+}
@@ -0,0 +1,5 @@
+package command
+
+type DeleteAlertRule struct {
+	ID string
+}
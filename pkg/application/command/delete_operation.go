@@ -0,0 +1,6 @@
+package command
+
+type DeleteOperation struct {
+	Wallet string
+	ID     string
+}
@@ -0,0 +1,5 @@
+package command
+
+type DeleteScreen struct {
+	Name string
+}
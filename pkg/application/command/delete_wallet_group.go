@@ -0,0 +1,5 @@
+package command
+
+type DeleteWalletGroup struct {
+	Name string
+}
@@ -0,0 +1,5 @@
+package command
+
+type DeleteWatchlist struct {
+	Name string
+}
@@ -0,0 +1,11 @@
+package command
+
+// EditBankAccount changes the bank account with the alias given. The empty fields are left unchanged
+type EditBankAccount struct {
+	Alias         string
+	Name          string
+	NewAlias      string
+	AccountNo     string
+	AccountNoType string
+	Type          string
+}
@@ -0,0 +1,14 @@
+package command
+
+// EditOperation changes the values of an operation. Nil values and an empty date are left as they are.
+type EditOperation struct {
+	Wallet                string
+	ID                    string
+	Date                  string
+	Amount                *float64
+	Price                 *float64
+	PriceChange           *float64
+	PriceChangeCommission *float64
+	Value                 *float64
+	Commission            *float64
+}
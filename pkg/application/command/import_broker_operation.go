@@ -0,0 +1,26 @@
+package command
+
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+)
+
+// ImportBrokerOperation imports the operations from the files exported by the broker of the wallet.
+// BankAccount is the alias of the account the deposits and withdrawals of the broker are transferred from
+// and to. Transfers, TransactionIDs and Retentions are filled by the handler with the deposits and withdrawals
+// read, the ids of the broker transactions of each operation and transfer, by their id, and the dividend
+// retention per share of the stocks, by stock id
+type ImportBrokerOperation struct {
+	FilePath    string
+	Wallet      string
+	TradeMatch  string
+	BankAccount string
+	// DryRun validates the file without saving anything
+	DryRun bool
+
+	Transfers      []*transfer.Transfer
+	TransactionIDs map[uuid.UUID][]string
+	Retentions     map[uuid.UUID]mm.Value
+}
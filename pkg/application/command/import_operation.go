@@ -1,9 +1,19 @@
 package command
 
-import "github.com/satori/go.uuid"
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
 
 type ImportOperation struct {
 	FilePath string
 	Wallet   string
-	Trades   map[uuid.UUID]string
+	// Trades overrides the trade assigned automatically to the operations
+	Trades     map[uuid.UUID]string
+	TradeMatch string
+	// DryRun validates the file without saving anything
+	DryRun bool
+	// Mapping is the profile the file is read with, nil to read the columns by their position
+	Mapping *util.Mapping
 }
@@ -0,0 +1,9 @@
+package command
+
+type ImportStatement struct {
+	FilePath string
+	Format   string
+	Rate     float64
+	// DryRun validates the file without saving anything
+	DryRun bool
+}
@@ -1,6 +1,13 @@
 package command
 
+import "github.com/dohernandez/market-manager/pkg/application/util"
+
 type ImportWallet struct {
 	FilePath string
 	Name     string
+	Broker   string
+	// DryRun validates the file without saving anything
+	DryRun bool
+	// Mapping is the profile the file is read with, nil to read the columns by their position
+	Mapping *util.Mapping
 }
@@ -0,0 +1,6 @@
+package command
+
+type LinkBankAccount struct {
+	Alias  string
+	Wallet string
+}
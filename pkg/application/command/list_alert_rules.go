@@ -0,0 +1,3 @@
+package command
+
+type ListAlertRules struct{}
@@ -0,0 +1,6 @@
+package command
+
+type ListDividendChanges struct {
+	From  string
+	Stock string
+}
@@ -1,5 +1,6 @@
 package command
 
 type ListStocks struct {
-	Exchange string
+	Exchange  string
+	Watchlist string
 }
@@ -0,0 +1,5 @@
+package command
+
+type ListWallets struct {
+	Broker string
+}
@@ -0,0 +1,7 @@
+package command
+
+type RebuildWallet struct {
+	Wallet string
+	// Apply stores the rebuilt wallet, otherwise only the differences are reported
+	Apply bool
+}
@@ -0,0 +1,6 @@
+package command
+
+type RemoveWalletGroupWallets struct {
+	Group   string
+	Wallets []string
+}
@@ -0,0 +1,6 @@
+package command
+
+type RemoveWatchlistStocks struct {
+	Watchlist string
+	Stocks    []string
+}
@@ -0,0 +1,11 @@
+package command
+
+type ScreenStocks struct {
+	// Name of the saved screen to run, or to save the expression as when Save is true
+	Name       string
+	Expression string
+	Sort       string
+	Order      string
+	Limit      *int
+	Save       bool
+}
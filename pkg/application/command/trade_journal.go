@@ -0,0 +1,11 @@
+package command
+
+// TradeJournal writes the journal of a trade. Nil values are left as they are, given tags replace the existing ones.
+type TradeJournal struct {
+	Wallet      string
+	Trade       int
+	Note        *string
+	Tags        []string
+	TargetPrice *float64
+	StopLoss    *float64
+}
@@ -0,0 +1,8 @@
+package command
+
+type TradeStats struct {
+	Wallet string
+	Tag    string
+	From   string
+	To     string
+}
@@ -0,0 +1,7 @@
+package command
+
+type UpdateBondPrice struct {
+	Symbol string
+	// CleanPrice percentage of the face value
+	CleanPrice float64
+}
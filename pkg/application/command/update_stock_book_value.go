@@ -0,0 +1,6 @@
+package command
+
+type UpdateStockBookValue struct {
+	Symbol    string
+	BookValue float64
+}
@@ -0,0 +1,6 @@
+package command
+
+type UpdateStockISIN struct {
+	Symbol string
+	ISIN   string
+}
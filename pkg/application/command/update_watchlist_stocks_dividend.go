@@ -0,0 +1,5 @@
+package command
+
+type UpdateWatchlistStocksDividend struct {
+	Watchlist string
+}
@@ -0,0 +1,5 @@
+package command
+
+type UpdateWatchlistStocksPrice struct {
+	Watchlist string
+}
@@ -1,11 +1,17 @@
 package command
 
+import "github.com/dohernandez/market-manager/pkg/application/util"
+
 type (
 	WalletDateDetails struct {
 		Wallet        string
+		Group         string
 		Date          string
 		TransferPath  string
 		OperationPath string
 		Excludes      []string
+		// TransferMapping and OperationMapping are the profiles the files of the paths are read with
+		TransferMapping  *util.Mapping
+		OperationMapping *util.Mapping
 	}
 )
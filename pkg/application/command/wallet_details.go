@@ -3,36 +3,12 @@ package command
 import "github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 
 type (
-	Commission struct {
-		Commission struct {
-			Base struct {
-				Amount   float64
-				Currency string
-			}
-			Extra struct {
-				Amount   float64
-				Currency string
-				Apply    string
-			}
-			Maximum struct {
-				Amount   float64
-				Currency string
-			}
-		}
-
-		ChangeCommission struct {
-			Amount   float64
-			Currency string
-		}
-	}
-
 	WalletDetails struct {
 		Wallet string
+		Group  string
 
-		Sells map[string]int
-		Buys  map[string]int
-
-		Commissions map[string]Commission
+		Sells map[string]float64
+		Buys  map[string]float64
 
 		Status operation.Status
 
@@ -0,0 +1,6 @@
+package command
+
+type WalletExposure struct {
+	Wallet string
+	Group  string
+}
@@ -0,0 +1,7 @@
+package command
+
+type WalletLedger struct {
+	Wallet string
+	From   string
+	To     string
+}
@@ -22,10 +22,15 @@ type Specification struct {
 	}
 	Import struct {
 		AccountsPath   string `envconfig:"ACCOUNTS_PATH" default:"resources/import/accounts"`
+		BrokersPath    string `envconfig:"BROKERS_PATH" default:"resources/import/brokers"`
 		StocksPath     string `envconfig:"STOCKS_PATH" default:"resources/import/stocks"`
 		TransfersPath  string `envconfig:"TRANSFERS_PATH" default:"resources/import/transfers"`
+		StatementsPath string `envconfig:"STATEMENTS_PATH" default:"resources/import/statements"`
 		WalletsPath    string `envconfig:"WALLETS_PATH" default:"resources/import/wallets"`
 		RetentionsPath string `envconfig:"RETENTIONS_PATH" default:"resources/import/retentions"`
+		ETFsPath       string `envconfig:"ETFS_PATH" default:"resources/import/etfs"`
+		// MappingsPath holds the yaml mapping profiles the csv files are read with, named after the import type
+		MappingsPath string `envconfig:"MAPPINGS_PATH" default:"resources/import/mappings"`
 	}
 
 	IEXTrading struct {
@@ -43,92 +48,38 @@ type Specification struct {
 		MarketChameleonPath  string `envconfig:"FINANCE_YAHOO_QUOTE_URL" default:"resources/import/market-chameleon"`
 	}
 
-	Degiro struct {
-		Retention float64 `envconfig:"RETENTION" default:"15"`
-		Exchanges struct {
-			NASDAQ struct {
-				Commission struct {
-					Base struct {
-						Amount   float64 `envconfig:"NASDAQ_COMMISSION_BASE" default:"0.50"`
-						Currency string  `envconfig:"NASDAQ_COMMISSION_BASE_CURRENCY" default:"€"`
-					}
-					Extra struct {
-						Amount   float64 `envconfig:"NASDAQ_COMMISSION_EXTRA" default:"0.004"`
-						Currency string  `envconfig:"NASDAQ_COMMISSION_EXTRA_CURRENCY" default:"$"`
-						Apply    string  `envconfig:"NASDAQ_COMMISSION_EXTRA_APPLY" default:"PER_STOCK"`
-					}
-				}
-				ChangeCommission struct {
-					Amount   float64 `envconfig:"NASDAQ_CHANGE_COMMISSION" default:"0.16"`
-					Currency string  `envconfig:"NASDAQ_CHANGE_COMMISSION_CURRENCY" default:"€"`
-				}
-			}
-			NYSE struct {
-				Commission struct {
-					Base struct {
-						Amount   float64 `envconfig:"NYSE_COMMISSION_BASE" default:"0.50"`
-						Currency string  `envconfig:"NYSE_COMMISSION_BASE_CURRENCY" default:"€"`
-					}
-					Extra struct {
-						Amount   float64 `envconfig:"NYSE_COMMISSION_EXTRA" default:"0.004"`
-						Currency string  `envconfig:"NYSE_COMMISSION_EXTRA_CURRENCY" default:"$"`
-						Apply    string  `envconfig:"NYSE_COMMISSION_EXTRA_APPLY" default:"PER_STOCK"`
-					}
-				}
-				ChangeCommission struct {
-					Amount   float64 `envconfig:"NASDAQ_CHANGE_COMMISSION" default:"0.16"`
-					Currency string  `envconfig:"NASDAQ_CHANGE_COMMISSION_CURRENCY" default:"€"`
-				}
-			}
-			BME struct {
-				Commission struct {
-					Base struct {
-						Amount   float64 `envconfig:"BME_COMMISSION_BASE" default:"2"`
-						Currency string  `envconfig:"BME_COMMISSION_BASE_CURRENCY" default:"€"`
-					}
-					Extra struct {
-						Amount   float64 `envconfig:"BME_COMMISSION_EXTRA" default:"0.04"`
-						Currency string  `envconfig:"BME_COMMISSION_EXTRA_CURRENCY" default:"€"`
-						Apply    string  `envconfig:"BME_COMMISSION_EXTRA_APPLY" default:"INVESTED_PERCENTAGE"`
-					}
-					Maximum struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_MAXIMUM" default:"60"`
-						Currency string  `envconfig:"FRA_COMMISSION_MAXIMUM_CURRENCY" default:"€"`
-					}
-				}
-			}
-			FRA struct {
-				Commission struct {
-					Base struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_BASE" default:"7.5"`
-						Currency string  `envconfig:"FRA_COMMISSION_BASE_CURRENCY" default:"€"`
-					}
-					Extra struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_EXTRA" default:"0.08"`
-						Currency string  `envconfig:"FRA_COMMISSION_EXTRA_CURRENCY" default:"€"`
-						Apply    string  `envconfig:"FRA_COMMISSION_EXTRA_APPLY" default:"INVESTED_PERCENTAGE"`
-					}
-				}
-			}
-			BIT struct {
-				Commission struct {
-					Base struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_BASE" default:"4"`
-						Currency string  `envconfig:"FRA_COMMISSION_BASE_CURRENCY" default:"€"`
-					}
-					Extra struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_EXTRA" default:"0.04"`
-						Currency string  `envconfig:"FRA_COMMISSION_EXTRA_CURRENCY" default:"€"`
-						Apply    string  `envconfig:"FRA_COMMISSION_EXTRA_APPLY" default:"INVESTED_PERCENTAGE"`
-					}
-					Maximum struct {
-						Amount   float64 `envconfig:"FRA_COMMISSION_MAXIMUM" default:"60"`
-						Currency string  `envconfig:"FRA_COMMISSION_MAXIMUM_CURRENCY" default:"€"`
-					}
-				}
-			}
+	CryptoPrice struct {
+		// Provider of the cryptocurrency prices (file)
+		Provider string `envconfig:"CRYPTO_PRICE_PROVIDER" default:"file"`
+		File     string `envconfig:"CRYPTO_PRICE_FILE" default:"resources/import/crypto/prices.csv"`
+	}
+
+	Alert struct {
+		// Sinks comma separated list of sinks where the alerts are delivered (stdout, file, webhook, smtp)
+		Sinks          string `envconfig:"ALERT_SINKS" default:"stdout"`
+		LogFile        string `envconfig:"ALERT_LOG_FILE" default:"resources/alerts.log"`
+		WebhookURL     string `envconfig:"ALERT_WEBHOOK_URL"`
+		WebhookTimeout int    `envconfig:"ALERT_WEBHOOK_TIMEOUT" default:"15"`
+		SMTP           struct {
+			Host     string `envconfig:"ALERT_SMTP_HOST" default:"localhost"`
+			Port     int    `envconfig:"ALERT_SMTP_PORT" default:"25"`
+			Username string `envconfig:"ALERT_SMTP_USERNAME"`
+			Password string `envconfig:"ALERT_SMTP_PASSWORD"`
+			From     string `envconfig:"ALERT_SMTP_FROM" default:"market-manager@localhost"`
+			// To comma separated list of recipients
+			To string `envconfig:"ALERT_SMTP_TO"`
 		}
 	}
+
+	Valuation struct {
+		// Models comma separated list of models the stocks are valued with (ddm, graham, yield_band)
+		Models string `envconfig:"VALUATION_MODELS" default:"ddm,graham,yield_band"`
+		// DDM discount rate and dividend growth percentages
+		DiscountRate float64 `envconfig:"VALUATION_DDM_DISCOUNT_RATE" default:"8"`
+		Growth       float64 `envconfig:"VALUATION_DDM_GROWTH" default:"3"`
+		// YieldBandYears years of dividend yields the yield band is averaged over
+		YieldBandYears int `envconfig:"VALUATION_YIELD_BAND_YEARS" default:"5"`
+	}
 }
 
 // LoadEnv load config variables into Specification.
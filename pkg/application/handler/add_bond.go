@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/exchange"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/market"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type addBond struct {
+	marketFinder   market.Finder
+	exchangeFinder exchange.Finder
+}
+
+func NewAddBond(marketFinder market.Finder, exchangeFinder exchange.Finder) *addBond {
+	return &addBond{
+		marketFinder:   marketFinder,
+		exchangeFinder: exchangeFinder,
+	}
+}
+
+func (h *addBond) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.AddBond)
+
+	if cmd.FaceValue <= 0 {
+		return nil, errors.New("face value has to be positive")
+	}
+
+	if cmd.Maturity == "" {
+		return nil, errors.New("missing maturity")
+	}
+
+	m, err := h.marketFinder.FindByName(market.Bond)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading market %s - error [%s]",
+			market.Bond,
+			err,
+		)
+
+		return nil, err
+	}
+
+	e, err := h.exchangeFinder.FindBySymbol(cmd.Exchange)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading exchange %s - error [%s]",
+			cmd.Exchange,
+			err,
+		)
+
+		return nil, err
+	}
+
+	maturity, err := parseOperationDateString(cmd.Maturity)
+	if err != nil {
+		return nil, errors.Wrap(err, "maturity")
+	}
+
+	fi := &stock.FixedIncome{
+		FaceValue:       mm.Value{Amount: cmd.FaceValue},
+		CouponRate:      cmd.CouponRate,
+		CouponFrequency: cmd.CouponFrequency,
+		Maturity:        maturity,
+		CleanPrice:      cmd.CleanPrice,
+	}
+
+	if cmd.IssueDate != "" {
+		fi.IssueDate, err = parseOperationDateString(cmd.IssueDate)
+		if err != nil {
+			return nil, errors.Wrap(err, "issue date")
+		}
+	} else if !fi.IsZeroCoupon() {
+		// the coupons are scheduled back from the maturity up to the issue
+		return nil, errors.New("issue date is required for the bonds paying coupons")
+	}
+
+	if !fi.IssueDate.Before(fi.Maturity) {
+		return nil, errors.New("issue date has to be before the maturity")
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = cmd.Symbol
+	}
+
+	bond := stock.NewBond(m, e, strings.ToUpper(name), cmd.Symbol, fi)
+
+	// the bonds are usually listed by their ISIN
+	if stock.IsISIN(bond.Symbol) {
+		bond.ISIN = bond.Symbol
+	}
+
+	return []*stock.Stock{bond}, nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/exchange"
+)
+
+type (
+	addBroker struct {
+		brokerFinder    broker.Finder
+		brokerPersister broker.Persister
+		exchangeFinder  exchange.Finder
+	}
+)
+
+func NewAddBroker(
+	brokerFinder broker.Finder,
+	brokerPersister broker.Persister,
+	exchangeFinder exchange.Finder,
+) *addBroker {
+	return &addBroker{
+		brokerFinder:    brokerFinder,
+		brokerPersister: brokerPersister,
+		exchangeFinder:  exchangeFinder,
+	}
+}
+
+func (h *addBroker) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var b *broker.Broker
+
+	switch cmd := command.(type) {
+	case *appCommand.AddBroker:
+		b, err = h.newBroker(cmd)
+	case *appCommand.AddBrokerCommission:
+		b, err = h.addCommission(cmd)
+	default:
+		logger.FromContext(ctx).Error(
+			"addBroker: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while adding broker -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.brokerPersister.Persist(b)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting broker [%s] -> error [%s]",
+			b.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (h *addBroker) newBroker(cmd *appCommand.AddBroker) (*broker.Broker, error) {
+	if cmd.Name == "" {
+		return nil, errors.New("missing broker name")
+	}
+
+	_, err := h.brokerFinder.FindByName(cmd.Name)
+	if err == nil {
+		return nil, errors.Errorf("broker %q already exist", cmd.Name)
+	}
+
+	if err != mm.ErrNotFound {
+		return nil, err
+	}
+
+	importFormat := broker.ImportFormat(cmd.ImportFormat)
+	switch importFormat {
+	case broker.Degiro, broker.InteractiveBrokers:
+	default:
+		return nil, errors.Errorf("import format %q not supported", cmd.ImportFormat)
+	}
+
+	return broker.NewBroker(
+		cmd.Name,
+		cmd.Retention,
+		cmd.Margin,
+		mm.Value{Amount: cmd.Connectivity, Currency: mm.Euro},
+		importFormat,
+	), nil
+}
+
+func (h *addBroker) addCommission(cmd *appCommand.AddBrokerCommission) (*broker.Broker, error) {
+	b, err := h.brokerFinder.FindByName(cmd.Broker)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading broker %q", cmd.Broker)
+	}
+
+	e, err := h.exchangeFinder.FindBySymbol(cmd.Exchange)
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading exchange %q", cmd.Exchange)
+	}
+
+	apply := broker.CommissionApply(cmd.Apply)
+	switch apply {
+	case broker.PerStock, broker.InvestedPercentage:
+	default:
+		return nil, errors.Errorf("commission apply %q not supported", cmd.Apply)
+	}
+
+	extraCurrency := mm.Currency(cmd.ExtraCurrency)
+	if extraCurrency == "" {
+		extraCurrency = mm.ExchangeCurrency(e.Symbol)
+	}
+
+	b.Commissions[e.Symbol] = broker.Commission{
+		Base:    mm.Value{Amount: cmd.Base, Currency: mm.Euro},
+		Extra:   mm.Value{Amount: cmd.Extra, Currency: extraCurrency},
+		Apply:   apply,
+		Maximum: mm.Value{Amount: cmd.Maximum, Currency: mm.Euro},
+		Change:  mm.Value{Amount: cmd.Change, Currency: mm.Euro},
+	}
+
+	return b, nil
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/exchange"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/market"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// cryptocurrencyExchange is the exchange the cryptocurrency pairs are listed in
+const cryptocurrencyExchange = "CRYPTO"
+
+type addCryptocurrency struct {
+	marketFinder   market.Finder
+	exchangeFinder exchange.Finder
+}
+
+func NewAddCryptocurrency(marketFinder market.Finder, exchangeFinder exchange.Finder) *addCryptocurrency {
+	return &addCryptocurrency{
+		marketFinder:   marketFinder,
+		exchangeFinder: exchangeFinder,
+	}
+}
+
+func (h *addCryptocurrency) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.AddCryptocurrency)
+
+	if _, ok := mm.CurrencyFromCode(cmd.Quote); !ok {
+		logger.FromContext(ctx).Errorf(
+			"An error happen quote currency %s not supported",
+			cmd.Quote,
+		)
+
+		return nil, errors.Errorf("quote currency %s not supported", cmd.Quote)
+	}
+
+	m, err := h.marketFinder.FindByName(market.Cryptocurrency)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading market %s - error [%s]",
+			market.Cryptocurrency,
+			err,
+		)
+
+		return nil, err
+	}
+
+	e, err := h.exchangeFinder.FindBySymbol(cryptocurrencyExchange)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading exchange %s - error [%s]",
+			cryptocurrencyExchange,
+			err,
+		)
+
+		return nil, err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = cmd.Symbol
+	}
+
+	return []*stock.Stock{
+		stock.NewCryptocurrency(m, e, strings.ToUpper(name), cmd.Symbol, cmd.Quote),
+	}, nil
+}
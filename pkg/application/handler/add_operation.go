@@ -6,6 +6,7 @@ import (
 
 	"github.com/gogolfing/cbus"
 	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
 
 	"time"
 
@@ -13,20 +14,24 @@ import (
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
 	"github.com/dohernandez/market-manager/pkg/market-manager"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 )
 
 type (
 	addOperation struct {
-		stockFinder stock.Finder
+		stockFinder  stock.Finder
+		walletFinder wallet.Finder
 	}
 )
 
 func NewAddOperation(
 	stockFinder stock.Finder,
+	walletFinder wallet.Finder,
 ) *addOperation {
 	return &addOperation{
-		stockFinder: stockFinder,
+		stockFinder:  stockFinder,
+		walletFinder: walletFinder,
 	}
 }
 
@@ -40,18 +45,22 @@ func (h *addOperation) Handle(ctx context.Context, command cbus.Command) (result
 		priceChangeCommission mm.Value
 		value                 mm.Value
 		commission            mm.Value
-		amount                int
+		amount                float64
+		// wallet, trade and match rule of the sells and redemptions
+		wName       string
+		tradeNumber string
+		match       wallet.TradeMatch
 	)
 	switch cmd := command.(type) {
 	case *appCommand.AddDividendOperation:
 		action = operation.Dividend
 		symbol = cmd.Stock
-		date = parseOperationDateString(cmd.Date)
+		date, err = parseOperationDateString(cmd.Date)
 		value = mm.Value{Amount: cmd.Value, Currency: mm.Euro}
 	case *appCommand.AddBuyOperation:
 		action = operation.Buy
 		symbol = cmd.Stock
-		date = parseOperationDateString(cmd.Date)
+		date, err = parseOperationDateString(cmd.Date)
 		price = mm.Value{Amount: cmd.Price}
 		priceChange = mm.Value{Amount: cmd.PriceChange}
 		priceChangeCommission = mm.Value{Amount: cmd.PriceChangeCommission, Currency: mm.Euro}
@@ -60,9 +69,18 @@ func (h *addOperation) Handle(ctx context.Context, command cbus.Command) (result
 
 		amount = cmd.Amount
 	case *appCommand.AddSellOperation:
+		match, err = wallet.ParseTradeMatch(cmd.TradeMatch)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+
+		wName = cmd.Wallet
+		tradeNumber = cmd.Trade
 		action = operation.Sell
 		symbol = cmd.Stock
-		date = parseOperationDateString(cmd.Date)
+		date, err = parseOperationDateString(cmd.Date)
 		price = mm.Value{Amount: cmd.Price}
 		priceChange = mm.Value{Amount: cmd.PriceChange}
 		priceChangeCommission = mm.Value{Amount: cmd.PriceChangeCommission, Currency: mm.Euro}
@@ -72,8 +90,25 @@ func (h *addOperation) Handle(ctx context.Context, command cbus.Command) (result
 		amount = cmd.Amount
 	case *appCommand.AddInterestOperation:
 		action = operation.Interest
-		date = parseOperationDateString(cmd.Date)
+		date, err = parseOperationDateString(cmd.Date)
+		value = mm.Value{Amount: cmd.Value, Currency: mm.Euro}
+	case *appCommand.AddCouponOperation:
+		action = operation.Coupon
+		symbol = cmd.Stock
+		date, err = parseOperationDateString(cmd.Date)
+		value = mm.Value{Amount: cmd.Value, Currency: mm.Euro}
+	case *appCommand.AddRedemptionOperation:
+		wName = cmd.Wallet
+		tradeNumber = cmd.Trade
+		match = wallet.FIFO
+		action = operation.Redemption
+		symbol = cmd.Stock
+		date, err = parseOperationDateString(cmd.Date)
+		priceChange = mm.Value{Amount: cmd.PriceChange}
 		value = mm.Value{Amount: cmd.Value, Currency: mm.Euro}
+		commission = mm.Value{Amount: cmd.Commission, Currency: mm.Euro}
+
+		amount = cmd.Amount
 	default:
 		logger.FromContext(ctx).Error(
 			"addOperation: Operation action not supported",
@@ -82,6 +117,12 @@ func (h *addOperation) Handle(ctx context.Context, command cbus.Command) (result
 		return nil, errors.New("operation action not supported")
 	}
 
+	if err != nil {
+		logger.FromContext(ctx).Error(err)
+
+		return nil, err
+	}
+
 	s := new(stock.Stock)
 
 	if action != operation.Interest {
@@ -105,8 +146,48 @@ func (h *addOperation) Handle(ctx context.Context, command cbus.Command) (result
 		}
 	}
 
+	if action == operation.Coupon || action == operation.Redemption {
+		if !s.IsBond() {
+			logger.FromContext(ctx).Errorf(
+				"An error happen stock [%s] is not a bond, %s not supported",
+				symbol,
+				action,
+			)
+
+			return nil, errors.Errorf("stock %s is not a bond", symbol)
+		}
+	}
+
+	if action == operation.Redemption {
+		// the bonds are redeemed at the face value
+		price = s.FixedIncome.FaceValue
+
+		if value.Amount == 0 {
+			value.Amount = amount * price.Amount
+
+			if price.Currency != mm.Euro && priceChange.Amount > 0 {
+				value.Amount = value.Amount / priceChange.Amount
+			}
+		}
+	}
+
 	o := operation.NewOperation(date, s, action, amount, price, priceChange, priceChangeCommission, value, commission)
 
+	if o.Action.IsDisposal() {
+		trades := map[uuid.UUID]string{o.ID: tradeNumber}
+
+		if err := checkDisposals(h.walletFinder, wName, []*operation.Operation{o}, trades, match); err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while choosing the trade of the operation [%s] stock [%s] -> error [%s]",
+				action,
+				symbol,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
 	return []*operation.Operation{
 		o,
 	}, nil
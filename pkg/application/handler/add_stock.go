@@ -49,7 +49,19 @@ func (h *addStock) Handle(ctx context.Context, command cbus.Command) (result int
 		return nil, err
 	}
 
-	return []*stock.Stock{
-		stock.NewStockFromSymbol(m, e, command.(*appCommand.AddStock).Symbol),
-	}, nil
+	stk := stock.NewStockFromSymbol(m, e, command.(*appCommand.AddStock).Symbol)
+
+	if isin := command.(*appCommand.AddStock).ISIN; isin != "" {
+		if err := stk.SetISIN(isin); err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while setting stock isin %s - error [%s]",
+				isin,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	return []*stock.Stock{stk}, nil
 }
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	changeAlertRule struct {
+		alertFinder     alert.Finder
+		alertPersister  alert.Persister
+		stockFinder     stock.Finder
+		watchlistFinder watchlist.Finder
+	}
+)
+
+func NewChangeAlertRule(
+	alertFinder alert.Finder,
+	alertPersister alert.Persister,
+	stockFinder stock.Finder,
+	watchlistFinder watchlist.Finder,
+) *changeAlertRule {
+	return &changeAlertRule{
+		alertFinder:     alertFinder,
+		alertPersister:  alertPersister,
+		stockFinder:     stockFinder,
+		watchlistFinder: watchlistFinder,
+	}
+}
+
+// Handle adds or deletes an alert rule
+func (h *changeAlertRule) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var r *alert.Rule
+
+	switch cmd := command.(type) {
+	case *appCommand.AddAlertRule:
+		r, err = h.newRule(cmd)
+		if err == nil {
+			err = h.alertPersister.Persist(r)
+		}
+	case *appCommand.DeleteAlertRule:
+		var ID uuid.UUID
+
+		ID, err = uuid.FromString(cmd.ID)
+		if err != nil {
+			err = errors.Wrapf(err, "invalid alert rule id %q", cmd.ID)
+
+			break
+		}
+
+		r, err = h.alertFinder.FindByID(ID)
+		if err == nil {
+			err = h.alertPersister.Delete(r)
+		}
+	default:
+		logger.FromContext(ctx).Error(
+			"changeAlertRule: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while changing alert rule -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (h *changeAlertRule) newRule(cmd *appCommand.AddAlertRule) (*alert.Rule, error) {
+	if (cmd.Stock == "") == (cmd.Watchlist == "") {
+		return nil, errors.New("alert rule must be set either on a stock or on a watchlist")
+	}
+
+	c, err := alert.ParseCondition(cmd.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	if (c == alert.PriceBelow || c == alert.DailyChangeAbove) && cmd.Value <= 0 {
+		return nil, errors.Errorf("alert condition %q requires a value", c)
+	}
+
+	var (
+		stk *stock.Stock
+		w   *watchlist.Watchlist
+	)
+
+	if cmd.Stock != "" {
+		stk, err = h.stockFinder.FindBySymbol(cmd.Stock)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find stock %q", cmd.Stock)
+		}
+	} else {
+		w, err = h.watchlistFinder.FindByName(cmd.Watchlist)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find watchlist %q", cmd.Watchlist)
+		}
+	}
+
+	return alert.NewRule(stk, w, c, cmd.Value), nil
+}
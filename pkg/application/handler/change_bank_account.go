@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+)
+
+type (
+	changeBankAccount struct {
+		bankAccountFinder    bank.Finder
+		bankAccountPersister bank.Persister
+		walletFinder         wallet.Finder
+		walletPersister      wallet.Persister
+	}
+)
+
+func NewChangeBankAccount(
+	bankAccountFinder bank.Finder,
+	bankAccountPersister bank.Persister,
+	walletFinder wallet.Finder,
+	walletPersister wallet.Persister,
+) *changeBankAccount {
+	return &changeBankAccount{
+		bankAccountFinder:    bankAccountFinder,
+		bankAccountPersister: bankAccountPersister,
+		walletFinder:         walletFinder,
+		walletPersister:      walletPersister,
+	}
+}
+
+// Handle adds or edits a bank account, or links it to a wallet
+func (h *changeBankAccount) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var a *bank.Account
+
+	switch cmd := command.(type) {
+	case *appCommand.AddBankAccount:
+		a, err = h.newBankAccount(cmd)
+	case *appCommand.EditBankAccount:
+		a, err = h.editBankAccount(cmd)
+	case *appCommand.LinkBankAccount:
+		return h.linkBankAccount(ctx, cmd)
+	default:
+		logger.FromContext(ctx).Error(
+			"changeBankAccount: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while changing bank account -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.bankAccountPersister.Persist(a)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting bank account [%s] -> error [%s]",
+			a.Alias,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (h *changeBankAccount) newBankAccount(cmd *appCommand.AddBankAccount) (*bank.Account, error) {
+	if cmd.Alias == "" {
+		return nil, errors.New("missing bank account alias")
+	}
+
+	if err := h.checkAliasFree(cmd.Alias); err != nil {
+		return nil, err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = cmd.Alias
+	}
+
+	accountNoType := bank.IBAN
+	if cmd.AccountNoType != "" {
+		accountNoType = bank.AccountNoType(cmd.AccountNoType)
+	}
+
+	accountType := bank.Checking
+	if cmd.Type != "" {
+		accountType = bank.AccountType(cmd.Type)
+	}
+
+	return bank.NewAccount(name, cmd.Alias, cmd.AccountNo, accountNoType, accountType)
+}
+
+func (h *changeBankAccount) editBankAccount(cmd *appCommand.EditBankAccount) (*bank.Account, error) {
+	a, err := h.bankAccountFinder.FindByAlias(cmd.Alias)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find bank account %q", cmd.Alias)
+	}
+
+	if cmd.NewAlias != "" && cmd.NewAlias != a.Alias {
+		if err := h.checkAliasFree(cmd.NewAlias); err != nil {
+			return nil, err
+		}
+
+		a.Alias = cmd.NewAlias
+	}
+
+	if cmd.Name != "" {
+		a.Name = cmd.Name
+	}
+
+	if cmd.AccountNo != "" || cmd.AccountNoType != "" {
+		accountNo := a.AccountNo
+		if cmd.AccountNo != "" {
+			accountNo = cmd.AccountNo
+		}
+
+		accountNoType := a.AccountNoType
+		if cmd.AccountNoType != "" {
+			accountNoType = bank.AccountNoType(cmd.AccountNoType)
+		}
+
+		if err := a.SetAccountNo(accountNo, accountNoType); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Type != "" {
+		if err := a.SetType(bank.AccountType(cmd.Type)); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// checkAliasFree returns error when there is already a bank account with the alias. The transfers are imported by alias
+func (h *changeBankAccount) checkAliasFree(alias string) error {
+	_, err := h.bankAccountFinder.FindByAlias(alias)
+	if err == nil {
+		return errors.Errorf("bank account %q already exists", alias)
+	}
+
+	if err != mm.ErrNotFound {
+		return err
+	}
+
+	return nil
+}
+
+func (h *changeBankAccount) linkBankAccount(ctx context.Context, cmd *appCommand.LinkBankAccount) (*bank.Account, error) {
+	a, err := h.bankAccountFinder.FindByAlias(cmd.Alias)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading bank account [%s] -> error [%s]",
+			cmd.Alias,
+			err,
+		)
+
+		return nil, err
+	}
+
+	w, err := h.walletFinder.FindByName(cmd.Wallet)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.walletFinder.LoadBankAccounts(w)
+	if err == nil {
+		err = w.AddBankAccount(a)
+	}
+
+	if err == nil {
+		err = h.walletPersister.PersistBankAccounts(w)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while linking bank account [%s] to wallet [%s] -> error [%s]",
+			a.Alias,
+			w.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return a, nil
+}
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+type (
+	changeOperation struct {
+		walletFinder        wallet.Finder
+		walletPersister     wallet.Persister
+		operationFinder     operation.Finder
+		stockFinder         stock.Finder
+		stockDividendFinder dividend.Finder
+
+		ccClient *cc.Client
+	}
+)
+
+func NewChangeOperation(
+	walletFinder wallet.Finder,
+	walletPersister wallet.Persister,
+	operationFinder operation.Finder,
+	stockFinder stock.Finder,
+	stockDividendFinder dividend.Finder,
+	ccClient *cc.Client,
+) *changeOperation {
+	return &changeOperation{
+		walletFinder:        walletFinder,
+		walletPersister:     walletPersister,
+		operationFinder:     operationFinder,
+		stockFinder:         stockFinder,
+		stockDividendFinder: stockDividendFinder,
+		ccClient:            ccClient,
+	}
+}
+
+// Handle edits or deletes an operation, rebuilding the wallet item and trades of the operation stock
+// from its remaining operations and correcting the wallet accounting by the difference
+func (h *changeOperation) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var wName, oID string
+
+	switch cmd := command.(type) {
+	case *appCommand.EditOperation:
+		wName = cmd.Wallet
+		oID = cmd.ID
+	case *appCommand.DeleteOperation:
+		wName = cmd.Wallet
+		oID = cmd.ID
+	default:
+		logger.FromContext(ctx).Error(
+			"changeOperation: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	w, old, err := h.loadOperation(wName, oID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading operation [%s] from wallet [%s] -> error [%s]",
+			oID,
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	ops, trades, err := h.loadStockOperations(w, old)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading operations of stock [%s] from wallet [%s] -> error [%s]",
+			old.Stock.Symbol,
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	var o *operation.Operation
+
+	switch cmd := command.(type) {
+	case *appCommand.EditOperation:
+		o, err = editOperation(old, cmd)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while editing operation [%s] -> error [%s]",
+				oID,
+				err,
+			)
+
+			return nil, err
+		}
+
+		for k, so := range ops {
+			if so.ID == o.ID {
+				ops[k] = o
+			}
+		}
+
+		sortOperations(ops)
+
+		w.ReplaceOperation(old, o)
+	case *appCommand.DeleteOperation:
+		o = old
+
+		for k, so := range ops {
+			if so.ID == o.ID {
+				ops = append(ops[:k], ops[k+1:]...)
+
+				break
+			}
+		}
+
+		w.RemoveOperation(o)
+	}
+
+	if o.Stock.ID != uuid.Nil {
+		err = w.RebuildItem(o.Stock, ops, trades)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while rebuilding wallet item of stock [%s] -> error [%s]",
+				o.Stock.Symbol,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	switch command.(type) {
+	case *appCommand.EditOperation:
+		err = h.walletPersister.UpdateOperation(w, o)
+	case *appCommand.DeleteOperation:
+		err = h.walletPersister.DeleteOperation(w, o)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting operation [%s] -> error [%s]",
+			oID,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (h *changeOperation) loadOperation(wName, oID string) (*wallet.Wallet, *operation.Operation, error) {
+	w, err := h.walletFinder.FindByName(wName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ID, err := uuid.FromString(oID)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid operation id %q", oID)
+	}
+
+	o, err := h.operationFinder.FindByID(w.ID, ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if o.Stock.ID != uuid.Nil {
+		stk, err := h.stockFinder.FindByID(o.Stock.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stk.Dividends, err = h.stockDividendFinder.FindAllFormStock(stk.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		o.Stock = stk
+	}
+
+	return w, o, nil
+}
+
+// loadStockOperations loads all the operations of the operation stock, sharing the stock instance, along with
+// the stored wallet item and the trade numbers of the operations
+func (h *changeOperation) loadStockOperations(w *wallet.Wallet, o *operation.Operation) ([]*operation.Operation, map[uuid.UUID]int, error) {
+	if o.Stock.ID == uuid.Nil {
+		return nil, nil, nil
+	}
+
+	ops, err := h.operationFinder.FindAllByWalletAndStock(w.ID, o.Stock.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, so := range ops {
+		so.Stock = o.Stock
+	}
+
+	trades, err := h.operationFinder.FindTradeNumbersByWallet(w.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = h.walletFinder.LoadItemByStock(w, o.Stock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currencyConverter, err := h.ccClient.Converter.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w.SetCapitalRate(wallet.CapitalRate{
+		EURUSD: currencyConverter.EURUSD,
+		EURCAD: currencyConverter.EURCAD,
+	})
+
+	return ops, trades, nil
+}
+
+// editOperation returns a copy of the operation with the values of the command applied
+func editOperation(old *operation.Operation, cmd *appCommand.EditOperation) (*operation.Operation, error) {
+	o := *old
+
+	if cmd.Date != "" {
+		date, err := parseOperationDateString(cmd.Date)
+		if err != nil {
+			return nil, err
+		}
+
+		o.Date = date
+	}
+
+	if cmd.Amount != nil {
+		if o.Action != operation.Buy && !o.Action.IsDisposal() {
+			return nil, errors.Errorf("amount can not be set on a %s operation", o.Action)
+		}
+
+		o.Amount = *cmd.Amount
+	}
+
+	if cmd.Price != nil {
+		o.Price = mm.Value{Amount: *cmd.Price, Currency: o.Price.Currency}
+	}
+
+	if cmd.PriceChange != nil {
+		o.PriceChange = mm.Value{Amount: *cmd.PriceChange, Currency: o.PriceChange.Currency}
+	}
+
+	if cmd.PriceChangeCommission != nil {
+		o.PriceChangeCommission = mm.Value{Amount: *cmd.PriceChangeCommission, Currency: mm.Euro}
+	}
+
+	if cmd.Value != nil {
+		o.Value = mm.Value{Amount: *cmd.Value, Currency: mm.Euro}
+	}
+
+	if cmd.Commission != nil {
+		o.Commission = mm.Value{Amount: *cmd.Commission, Currency: mm.Euro}
+	}
+
+	return &o, nil
+}
+
+// sortOperations sorts the operations in the order they are replayed,
+// buys before sells before the rest when they happen at the same date
+func sortOperations(ops []*operation.Operation) {
+	rank := func(a operation.Action) int {
+		switch a {
+		case operation.Buy:
+			return 0
+		case operation.Sell, operation.Redemption:
+			return 1
+		}
+
+		return 2
+	}
+
+	sort.SliceStable(ops, func(i, j int) bool {
+		if !ops[i].Date.Equal(ops[j].Date) {
+			return ops[i].Date.Before(ops[j].Date)
+		}
+
+		return rank(ops[i].Action) < rank(ops[j].Action)
+	})
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type (
+	changeWalletGroup struct {
+		groupFinder    group.Finder
+		groupPersister group.Persister
+		walletFinder   wallet.Finder
+	}
+)
+
+func NewChangeWalletGroup(
+	groupFinder group.Finder,
+	groupPersister group.Persister,
+	walletFinder wallet.Finder,
+) *changeWalletGroup {
+	return &changeWalletGroup{
+		groupFinder:    groupFinder,
+		groupPersister: groupPersister,
+		walletFinder:   walletFinder,
+	}
+}
+
+// Handle creates or deletes a wallet group, or adds or removes wallets from it
+func (h *changeWalletGroup) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var g *group.Group
+
+	switch cmd := command.(type) {
+	case *appCommand.AddWalletGroup:
+		g, err = h.newGroup(cmd.Name)
+		if err == nil {
+			err = h.changeWallets(g, cmd.Wallets, (*group.Group).AddWallet)
+		}
+	case *appCommand.DeleteWalletGroup:
+		return h.deleteGroup(ctx, cmd.Name)
+	case *appCommand.AddWalletGroupWallets:
+		g, err = h.findGroup(cmd.Group)
+		if err == nil {
+			err = h.changeWallets(g, cmd.Wallets, (*group.Group).AddWallet)
+		}
+	case *appCommand.RemoveWalletGroupWallets:
+		g, err = h.findGroup(cmd.Group)
+		if err == nil {
+			err = h.changeWallets(g, cmd.Wallets, (*group.Group).RemoveWallet)
+		}
+	default:
+		logger.FromContext(ctx).Error(
+			"changeWalletGroup: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while changing wallet group -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.groupPersister.Persist(g)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting wallet group [%s] -> error [%s]",
+			g.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (h *changeWalletGroup) newGroup(name string) (*group.Group, error) {
+	if name == "" {
+		return nil, errors.New("missing wallet group name")
+	}
+
+	_, err := h.groupFinder.FindByName(name)
+	if err == nil {
+		return nil, errors.Errorf("wallet group %q already exists", name)
+	}
+
+	if err != mm.ErrNotFound {
+		return nil, err
+	}
+
+	return group.NewGroup(name), nil
+}
+
+func (h *changeWalletGroup) findGroup(name string) (*group.Group, error) {
+	g, err := h.groupFinder.FindByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find wallet group %q", name)
+	}
+
+	return g, nil
+}
+
+func (h *changeWalletGroup) deleteGroup(ctx context.Context, name string) (*group.Group, error) {
+	g, err := h.groupFinder.FindByName(name)
+	if err == nil {
+		err = h.groupPersister.Delete(g)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while deleting wallet group [%s] -> error [%s]",
+			name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// changeWallets applies the change to the group for each of the wallet names
+func (h *changeWalletGroup) changeWallets(g *group.Group, names []string, change func(*group.Group, *wallet.Wallet) bool) error {
+	for _, name := range names {
+		w, err := h.walletFinder.FindByName(name)
+		if err != nil {
+			return errors.Wrapf(err, "find wallet %q", name)
+		}
+
+		change(g, w)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	changeWatchlist struct {
+		watchlistFinder    watchlist.Finder
+		watchlistPersister watchlist.Persister
+		stockFinder        stock.Finder
+	}
+)
+
+func NewChangeWatchlist(
+	watchlistFinder watchlist.Finder,
+	watchlistPersister watchlist.Persister,
+	stockFinder stock.Finder,
+) *changeWatchlist {
+	return &changeWatchlist{
+		watchlistFinder:    watchlistFinder,
+		watchlistPersister: watchlistPersister,
+		stockFinder:        stockFinder,
+	}
+}
+
+// Handle creates or deletes a watchlist, or adds or removes stocks from it
+func (h *changeWatchlist) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var w *watchlist.Watchlist
+
+	switch cmd := command.(type) {
+	case *appCommand.AddWatchlist:
+		w, err = h.newWatchlist(cmd.Name)
+	case *appCommand.DeleteWatchlist:
+		return h.deleteWatchlist(ctx, cmd.Name)
+	case *appCommand.AddWatchlistStocks:
+		w, err = h.changeStocks(cmd.Watchlist, cmd.Stocks, (*watchlist.Watchlist).AddStock)
+	case *appCommand.RemoveWatchlistStocks:
+		w, err = h.changeStocks(cmd.Watchlist, cmd.Stocks, (*watchlist.Watchlist).RemoveStock)
+	default:
+		logger.FromContext(ctx).Error(
+			"changeWatchlist: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while changing watchlist -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.watchlistPersister.Persist(w)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting watchlist [%s] -> error [%s]",
+			w.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (h *changeWatchlist) newWatchlist(name string) (*watchlist.Watchlist, error) {
+	if name == "" {
+		return nil, errors.New("missing watchlist name")
+	}
+
+	_, err := h.watchlistFinder.FindByName(name)
+	if err == nil {
+		return nil, errors.Errorf("watchlist %q already exists", name)
+	}
+
+	if err != mm.ErrNotFound {
+		return nil, err
+	}
+
+	return watchlist.NewWatchlist(name), nil
+}
+
+func (h *changeWatchlist) deleteWatchlist(ctx context.Context, name string) (*watchlist.Watchlist, error) {
+	w, err := h.watchlistFinder.FindByName(name)
+	if err == nil {
+		err = h.watchlistPersister.Delete(w)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while deleting watchlist [%s] -> error [%s]",
+			name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// changeStocks applies the change to the watchlist for each of the stock symbols
+func (h *changeWatchlist) changeStocks(name string, symbols []string, change func(*watchlist.Watchlist, *stock.Stock) bool) (*watchlist.Watchlist, error) {
+	w, err := h.watchlistFinder.FindByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find watchlist %q", name)
+	}
+
+	for _, symbol := range symbols {
+		stk, err := h.stockFinder.FindBySymbol(symbol)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find stock %q", symbol)
+		}
+
+		change(w, stk)
+	}
+
+	return w, nil
+}
@@ -7,8 +7,11 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
 
+	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
@@ -16,9 +19,23 @@ import (
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 )
 
-func createTransferFromLine(line []string, bankAccountFinder bank.Finder) (*transfer.Transfer, error) {
+// createTransferFromLine creates the transfer from the line: date, from, to, amount and optionally
+// the currency code, the rate the currency was changed at (units per euro) and the fee. The dates and numbers
+// are read in the locale of the mapping, if any
+func createTransferFromLine(line []string, bankAccountFinder bank.Finder, mapping *util.Mapping) (*transfer.Transfer, error) {
+	if len(line) < 4 {
+		return nil, errors.Errorf("expected at least 4 columns, found %d", len(line))
+	}
 
-	date := parseTransferDateString(line[0])
+	dt, err := mapping.Date(line[0])
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseTransferDateString(dt)
+	if err != nil {
+		return nil, err
+	}
 
 	from, err := bankAccountFinder.FindByAlias(line[1])
 	if err != nil {
@@ -30,36 +47,81 @@ func createTransferFromLine(line []string, bankAccountFinder bank.Finder) (*tran
 		return nil, errors.New(fmt.Sprintf("%s %q", err.Error(), line[2]))
 	}
 
-	amount, err := parseTransferPriceString(line[3])
+	amount, err := parseTransferPriceString(line[3], mapping)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "parsing amount %q", line[3])
 	}
 
-	t := transfer.NewTransfer(from, to, amount, date)
+	if len(line) < 5 || line[4] == "" {
+		return transfer.NewTransfer(from, to, amount, date), nil
+	}
 
-	return t, nil
+	currency, ok := mm.CurrencyFromCode(line[4])
+	if !ok {
+		return nil, errors.Errorf("currency %q not supported", line[4])
+	}
+
+	var rate, fee float64
+
+	if len(line) > 5 && line[5] != "" {
+		rate, err = parseTransferPriceString(line[5], mapping)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing rate %q", line[5])
+		}
+	}
+
+	if len(line) > 6 && line[6] != "" {
+		fee, err = parseTransferPriceString(line[6], mapping)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing fee %q", line[6])
+		}
+	}
+
+	return transfer.NewCurrencyTransfer(
+		from,
+		to,
+		mm.Value{Amount: amount, Currency: currency},
+		mm.Value{Amount: fee, Currency: currency},
+		rate,
+		date,
+	)
 }
 
-// parseTransferDateString - parse a potentially partial date string to Time
-func parseTransferDateString(dt string) time.Time {
+// parseTransferDateString - parse a potentially partial date string to Time, the date can not be empty
+func parseTransferDateString(dt string) (time.Time, error) {
 	if dt == "" {
-		return time.Now()
+		return time.Time{}, errors.New("date can not be empty")
 	}
 
-	t, _ := time.Parse("2/1/2006", dt)
+	t, err := time.Parse("2/1/2006", dt)
+	if err != nil {
+		return t, errors.Errorf("date %q not valid, expected day/month/year", dt)
+	}
 
-	return t
+	return t, nil
 }
 
-// parseTransferPriceString - parse a potentially float string to float64
-func parseTransferPriceString(price string) (float64, error) {
-	price = strings.Replace(price, ".", "", 1)
+// parseTransferPriceString - parse a potentially float string to float64. The numbers are read in the locale of
+// the mapping and in Spanish when it does not tell: "." thousands separator and "," decimal one
+func parseTransferPriceString(price string, mapping *util.Mapping) (float64, error) {
+	if mapping.HasNumberLocale() {
+		return strconv.ParseFloat(mapping.Number(price), 64)
+	}
+
+	price = strings.Replace(strings.TrimSpace(price), ".", "", -1)
 	price = strings.Replace(price, ",", ".", 1)
 
 	return strconv.ParseFloat(price, 64)
 }
 
-func createOperationFromLine(line []string, stockFinder stock.Finder) (*operation.Operation, error) {
+// createOperationFromLine creates the operation from the line: trade, date, stock, operation, amount, price,
+// price change, price change commission, value and commission. The dates and numbers are read in the locale of
+// the mapping, if any
+func createOperationFromLine(line []string, stockFinder stock.Finder, mapping *util.Mapping) (*operation.Operation, error) {
+	if len(line) < 10 {
+		return nil, errors.Errorf("expected 10 columns, found %d", len(line))
+	}
+
 	action, err := parseOperationString(line[3])
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing operation string")
@@ -73,16 +135,31 @@ func createOperationFromLine(line []string, stockFinder stock.Finder) (*operatio
 		}
 	}
 
-	date := parseOperationDateString(line[1])
-	amount, _ := strconv.Atoi(line[4])
+	dt, err := mapping.Date(line[1])
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := parseOperationDateString(dt)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := make([]float64, 6)
+	for i, name := range []string{"amount", "price", "price change", "price change commission", "value", "commission"} {
+		ns[i], err = parseOperationPriceString(mapping.Number(line[4+i]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s %q", name, line[4+i])
+		}
+	}
 
-	price := mm.Value{Amount: parseOperationPriceString(line[5])}
-	priceChange := mm.Value{Amount: parseOperationPriceString(line[6])}
-	priceChangeCommission := mm.Value{Amount: parseOperationPriceString(line[7]), Currency: mm.Euro}
-	value := mm.Value{Amount: parseOperationPriceString(line[8]), Currency: mm.Euro}
-	commission := mm.Value{Amount: parseOperationPriceString(line[9]), Currency: mm.Euro}
+	price := mm.Value{Amount: ns[1]}
+	priceChange := mm.Value{Amount: ns[2]}
+	priceChangeCommission := mm.Value{Amount: ns[3], Currency: mm.Euro}
+	value := mm.Value{Amount: ns[4], Currency: mm.Euro}
+	commission := mm.Value{Amount: ns[5], Currency: mm.Euro}
 
-	o := operation.NewOperation(date, s, action, amount, price, priceChange, priceChangeCommission, value, commission)
+	o := operation.NewOperation(date, s, action, ns[0], price, priceChange, priceChangeCommission, value, commission)
 
 	return o, nil
 }
@@ -104,29 +181,37 @@ func parseOperationString(o string) (operation.Action, error) {
 		return operation.Dividend, nil
 	case "Interés":
 		return operation.Interest, nil
+	case "Cupón":
+		return operation.Coupon, nil
+	case "Amortización":
+		return operation.Redemption, nil
 	}
 
-	return operation.Action(""), errors.New("operation not valid")
+	return operation.Action(""), errors.Errorf("operation %q not valid", o)
 }
 
-// parseOperationDateString - parse a potentially partial date string to Time
-func parseOperationDateString(dt string) time.Time {
+// parseOperationDateString - parse a potentially partial date string to Time, the date can not be empty
+func parseOperationDateString(dt string) (time.Time, error) {
 	if dt == "" {
-		return time.Now()
+		return time.Time{}, errors.New("date can not be empty")
 	}
 
-	t, _ := time.Parse("2/1/2006", dt)
+	t, err := time.Parse("2/1/2006", dt)
+	if err != nil {
+		return t, errors.Errorf("date %q not valid, expected day/month/year", dt)
+	}
 
-	return t
+	return t, nil
 }
 
-// parseOperationPriceString - parse a potentially partial date string to Time
-func parseOperationPriceString(price string) float64 {
-	price = strings.Replace(price, ",", ".", 1)
-
-	p, _ := strconv.ParseFloat(price, 64)
+// parseOperationPriceString - parse a potentially float string to float64, an empty string is 0
+func parseOperationPriceString(price string) (float64, error) {
+	price = strings.Replace(strings.TrimSpace(price), ",", ".", 1)
+	if price == "" {
+		return 0, nil
+	}
 
-	return p
+	return strconv.ParseFloat(price, 64)
 }
 
 func loadWalletWithActiveWalletItems(walletFinder wallet.Finder, stockFinder stock.Finder, name string) (*wallet.Wallet, error) {
@@ -154,3 +239,81 @@ func loadWalletWithActiveWalletItems(walletFinder wallet.Finder, stockFinder sto
 
 	return w, err
 }
+
+// loadGroupWallet loads each wallet of the group by its name with the load given, the way the report loads one
+// wallet, and consolidates them into one wallet
+func loadGroupWallet(groupFinder group.Finder, name string, load func(name string) (*wallet.Wallet, error)) (*wallet.Wallet, error) {
+	g, err := groupFinder.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, gw := range g.Wallets {
+		w, err := load(gw.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Wallets[id] = w
+	}
+
+	return g.Consolidate(), nil
+}
+
+// checkDisposals replays the operations on the wallet the way they are booked, the operations with trade number
+// go to their trade and the rest are assigned by the match rule. An error is returned for the first sell or
+// redemption no single open trade holds, so the command fails before the operations are booked
+func checkDisposals(
+	walletFinder wallet.Finder,
+	name string,
+	ops []*operation.Operation,
+	trades map[uuid.UUID]string,
+	match wallet.TradeMatch,
+) error {
+	w, err := walletFinder.FindByName(name)
+	if err != nil {
+		return err
+	}
+
+	if err = walletFinder.LoadActiveItems(w); err != nil {
+		return err
+	}
+
+	if err = walletFinder.LoadActiveTrades(w); err != nil {
+		return err
+	}
+
+	for _, o := range ops {
+		err = w.AddOperation(o)
+		if err == mm.ErrCanNotAddOperation {
+			if err = walletFinder.LoadItemByStock(w, o.Stock); err != nil {
+				return err
+			}
+
+			err = w.AddOperation(o)
+		}
+
+		if err != nil {
+			// the operation is not booked either
+			continue
+		}
+
+		if nTrade := trades[o.ID]; nTrade != "" {
+			n, _ := strconv.Atoi(nTrade)
+			w.AddTrade(n, o)
+
+			continue
+		}
+
+		switch {
+		case o.Action == operation.Buy:
+			w.AssignTrade(o, match)
+		case o.Action.IsDisposal():
+			if err := w.AssignTrade(o, match); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,543 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"os"
+	"time"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker/degiro"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker/ib"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type importBrokerOperation struct {
+	walletFinder      wallet.Finder
+	stockFinder       stock.Finder
+	stockPersister    stock.Persister
+	bankAccountFinder bank.Finder
+}
+
+func NewImportBrokerOperation(
+	walletFinder wallet.Finder,
+	stockFinder stock.Finder,
+	stockPersister stock.Persister,
+	bankAccountFinder bank.Finder,
+) *importBrokerOperation {
+	return &importBrokerOperation{
+		walletFinder:      walletFinder,
+		stockFinder:       stockFinder,
+		stockPersister:    stockPersister,
+		bankAccountFinder: bankAccountFinder,
+	}
+}
+
+// Handle reads the operations of the file in the import format of the wallet broker. The stocks are resolved
+// by their ISIN, the ones without it are matched by symbol or name and their ISIN is saved for the next imports
+func (h *importBrokerOperation) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.ImportBrokerOperation)
+
+	match, err := wallet.ParseTradeMatch(cmd.TradeMatch)
+	if err != nil {
+		logger.FromContext(ctx).Error(err)
+
+		return nil, err
+	}
+
+	w, err := h.walletFinder.FindByName(cmd.Wallet)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding wallet [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if w.Broker == nil {
+		return nil, errors.Errorf("wallet %s has no broker to tell the import format", w.Name)
+	}
+
+	f, err := os.Open(cmd.FilePath)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while opening file [%s] -> error [%s]",
+			cmd.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+	defer f.Close()
+
+	if cmd.DryRun {
+		return h.validate(cmd, w, f)
+	}
+
+	var ops []*operation.Operation
+
+	switch w.Broker.ImportFormat {
+	case broker.Degiro:
+		ops, err = h.importDegiro(ctx, cmd, w, f)
+	case broker.InteractiveBrokers:
+		ops, err = h.importInteractiveBrokers(ctx, cmd, w, f)
+	default:
+		err = errors.Errorf("import format %q of broker %s not supported", w.Broker.ImportFormat, w.Broker.Name)
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while reading operations from [%s] -> error [%s]",
+			cmd.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+
+	stks := map[string]*stock.Stock{}
+
+	for _, o := range ops {
+		o.Stock, err = h.resolveStock(ctx, stks, o.Stock)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkDisposals(h.walletFinder, w.Name, ops, nil, match); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while choosing the trades of the operations from [%s] -> error [%s]",
+			cmd.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return ops, nil
+}
+
+// importDegiro reads the file exported. The dividend tax withheld is handed to the listeners through the command
+// as the retention per share of the stocks held in the wallet, the last one withheld of each stock
+func (h *importBrokerOperation) importDegiro(
+	ctx context.Context,
+	cmd *appCommand.ImportBrokerOperation,
+	w *wallet.Wallet,
+	f *os.File,
+) ([]*operation.Operation, error) {
+	r, err := degiro.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.Retentions) == 0 {
+		return r.Operations, nil
+	}
+
+	if err = h.walletFinder.LoadActiveItems(w); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] items -> error [%s]",
+			w.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	stks := map[string]*stock.Stock{}
+	rs := map[uuid.UUID]mm.Value{}
+	withheldAt := map[uuid.UUID]time.Time{}
+
+	for _, rt := range r.Retentions {
+		stk, err := h.resolveStock(ctx, stks, rt.Stock)
+		if err != nil {
+			return nil, err
+		}
+
+		i, ok := w.Items[stk.ID]
+		if !ok || i.Amount <= 0 {
+			logger.FromContext(ctx).Warnf("Retention of stock [%s] not saved, it is not held in the wallet", stk.Symbol)
+
+			continue
+		}
+
+		if rt.Date.Before(withheldAt[stk.ID]) {
+			continue
+		}
+
+		rs[stk.ID] = mm.Value{Amount: rt.Tax.Amount / i.Amount, Currency: rt.Tax.Currency}
+		withheldAt[stk.ID] = rt.Date
+	}
+
+	cmd.Retentions = rs
+
+	return r.Operations, nil
+}
+
+// importInteractiveBrokers reads the flex query report leaving out the transactions already imported to the
+// wallet. The transfers of the deposits and withdrawals from and to the bank account of the command, the ids of
+// the transactions of each operation and transfer and the retentions are handed to the listeners through the
+// command, so the operations, transfers and ids are booked together
+func (h *importBrokerOperation) importInteractiveBrokers(
+	ctx context.Context,
+	cmd *appCommand.ImportBrokerOperation,
+	w *wallet.Wallet,
+	f *os.File,
+) ([]*operation.Operation, error) {
+	r, err := ib.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := h.walletFinder.FindImportedTransactionIDs(w)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding wallet [%s] imported transactions -> error [%s]",
+			w.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	r.Exclude(imported)
+
+	for _, u := range r.Unsupported {
+		logger.FromContext(ctx).Warnf("Not imported, add it by hand: %s", u)
+	}
+
+	stks := map[string]*stock.Stock{}
+	rs := map[uuid.UUID]mm.Value{}
+
+	for _, rt := range r.Retentions {
+		stk, err := h.resolveStock(ctx, stks, rt.Stock)
+		if err != nil {
+			return nil, err
+		}
+
+		rs[stk.ID] = rt.PerShare
+	}
+
+	ids := map[uuid.UUID][]string{}
+
+	for _, e := range r.Entries {
+		ids[e.Operation.ID] = e.IDs()
+	}
+
+	var ts []*transfer.Transfer
+
+	if len(r.Transfers) > 0 {
+		if cmd.BankAccount == "" {
+			logger.FromContext(ctx).Warnf(
+				"%d deposits and withdrawals not imported, tell the bank account they are transferred from and to",
+				len(r.Transfers),
+			)
+		} else {
+			ts, err = h.transferInteractiveBrokers(ctx, cmd.BankAccount, w, r.Transfers)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, t := range ts {
+				if id := r.Transfers[i].TransactionID; id != "" {
+					ids[t.ID] = []string{id}
+				}
+			}
+		}
+	}
+
+	cmd.Transfers = ts
+	cmd.TransactionIDs = ids
+	cmd.Retentions = rs
+
+	return r.Operations(), nil
+}
+
+// transferInteractiveBrokers creates the transfers of the deposits from the bank account to the wallet broker
+// account and of the withdrawals back, in the order of the ones read
+func (h *importBrokerOperation) transferInteractiveBrokers(
+	ctx context.Context,
+	alias string,
+	w *wallet.Wallet,
+	its []*ib.Transfer,
+) ([]*transfer.Transfer, error) {
+	external, err := h.bankAccountFinder.FindByAlias(alias)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding bank account [%s] -> error [%s]",
+			alias,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if err = h.walletFinder.LoadBankAccounts(w); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] bank accounts -> error [%s]",
+			w.Name,
+			err,
+		)
+
+		return nil, err
+	}
+
+	var account *bank.Account
+	for _, ba := range w.BankAccounts {
+		if account == nil || ba.Type == bank.Brokerage {
+			account = ba
+		}
+	}
+
+	if account == nil {
+		return nil, errors.Errorf("wallet %s has no bank account to transfer the deposits and withdrawals to", w.Name)
+	}
+
+	var ts []*transfer.Transfer
+
+	for _, it := range its {
+		from, to := external, account
+		if it.Amount.Amount < 0 {
+			from, to = account, external
+		}
+
+		amount := mm.Value{Amount: math.Abs(it.Amount.Amount), Currency: it.Amount.Currency}
+
+		t, err := transfer.NewCurrencyTransfer(from, to, amount, mm.Value{}, it.Rate, it.Date)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while creating transfer from [%s] to [%s] -> error [%s]",
+				from.Alias,
+				to.Alias,
+				err,
+			)
+
+			return nil, err
+		}
+
+		ts = append(ts, t)
+	}
+
+	return ts, nil
+}
+
+// resolveStock returns the stock stored of the one read from the file, the stocks already resolved are kept by
+// their ISIN or symbol. The ones without both, as the interests and fees, are returned as they are
+func (h *importBrokerOperation) resolveStock(
+	ctx context.Context,
+	stks map[string]*stock.Stock,
+	s *stock.Stock,
+) (*stock.Stock, error) {
+	key := s.ISIN
+	if key == "" {
+		key = s.Symbol
+	}
+
+	if key == "" {
+		return s, nil
+	}
+
+	if stk, ok := stks[key]; ok {
+		return stk, nil
+	}
+
+	stk, err := h.findStock(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	stks[key] = stk
+
+	return stk, nil
+}
+
+// validate reads the file telling the stocks not found, the transactions that can not be imported and the
+// deposits and withdrawals without a bank account. Broker files are not read by lines, the issues are of line 0
+func (h *importBrokerOperation) validate(
+	cmd *appCommand.ImportBrokerOperation,
+	w *wallet.Wallet,
+	f *os.File,
+) (*util.ImportReport, error) {
+	report := &util.ImportReport{}
+
+	var (
+		ops []*operation.Operation
+		err error
+	)
+
+	switch w.Broker.ImportFormat {
+	case broker.Degiro:
+		var r *degiro.Report
+
+		r, err = degiro.Parse(f)
+		if err == nil {
+			ops = r.Operations
+		}
+	case broker.InteractiveBrokers:
+		ops, err = h.validateInteractiveBrokers(cmd, w, f, report)
+	default:
+		err = errors.Errorf("import format %q of broker %s not supported", w.Broker.ImportFormat, w.Broker.Name)
+	}
+
+	if err != nil {
+		report.AddIssue(0, err)
+
+		return report, nil
+	}
+
+	checked := map[string]bool{}
+
+	for _, o := range ops {
+		report.Rows++
+
+		key := o.Stock.ISIN
+		if key == "" {
+			key = o.Stock.Symbol
+		}
+
+		if key == "" || checked[key] {
+			continue
+		}
+
+		checked[key] = true
+
+		if _, err := h.lookupStock(o.Stock); err != nil {
+			report.AddIssuef(0, "%s %s on %s: %s", o.Action, o.Stock.Name, o.Date.Format("2006-01-02"), err)
+		}
+	}
+
+	return report, nil
+}
+
+// validateInteractiveBrokers reads the flex query report leaving out the transactions already imported to the
+// wallet, the ones that can not be imported and the deposits and withdrawals are added to the report
+func (h *importBrokerOperation) validateInteractiveBrokers(
+	cmd *appCommand.ImportBrokerOperation,
+	w *wallet.Wallet,
+	f *os.File,
+	report *util.ImportReport,
+) ([]*operation.Operation, error) {
+	r, err := ib.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := h.walletFinder.FindImportedTransactionIDs(w)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Exclude(imported)
+
+	for _, u := range r.Unsupported {
+		report.AddIssuef(0, "not imported, add it by hand: %s", u)
+	}
+
+	for _, rt := range r.Retentions {
+		if _, err := h.lookupStock(rt.Stock); err != nil {
+			report.AddIssuef(0, "retention of %s: %s", rt.Stock.Name, err)
+		}
+	}
+
+	if len(r.Transfers) > 0 {
+		if cmd.BankAccount == "" {
+			report.AddIssuef(
+				0,
+				"%d deposits and withdrawals not imported, tell the bank account they are transferred from and to",
+				len(r.Transfers),
+			)
+		} else if _, err := h.bankAccountFinder.FindByAlias(cmd.BankAccount); err != nil {
+			report.AddIssuef(0, "find bank account %q: %s", cmd.BankAccount, err)
+		}
+	}
+
+	return r.Operations(), nil
+}
+
+// findStock finds the stock by its ISIN, or by its symbol or name when no stock has the ISIN yet
+func (h *importBrokerOperation) findStock(ctx context.Context, s *stock.Stock) (*stock.Stock, error) {
+	stk, err := h.lookupStock(s)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stock [%s] symbol [%s] isin [%s], add it or set its isin -> error [%s]",
+			s.Name,
+			s.Symbol,
+			s.ISIN,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if s.ISIN == "" || stk.ISIN == s.ISIN {
+		return stk, nil
+	}
+
+	if err = stk.SetISIN(s.ISIN); err != nil {
+		return nil, err
+	}
+
+	if err = h.stockPersister.UpdateISIN(stk); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while updating stock isin: symbol [%s] -> error [%s]",
+			stk.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Infof("Stock %s matched by symbol or name, isin %s saved", stk.Symbol, stk.ISIN)
+
+	return stk, nil
+}
+
+// lookupStock finds the stock by its ISIN, or by its symbol or name when no stock has the ISIN yet, without
+// saving the ISIN of the stock matched
+func (h *importBrokerOperation) lookupStock(s *stock.Stock) (*stock.Stock, error) {
+	if s.ISIN != "" {
+		stk, err := h.stockFinder.FindByISIN(s.ISIN)
+		if err == nil {
+			return stk, nil
+		}
+
+		if err != mm.ErrNotFound {
+			return nil, errors.Wrapf(err, "find stock by isin %s", s.ISIN)
+		}
+	}
+
+	var (
+		stk *stock.Stock
+		err error
+	)
+
+	if s.Symbol != "" {
+		stk, err = h.stockFinder.FindBySymbol(s.Symbol)
+	} else {
+		stk, err = h.stockFinder.FindByName(s.Name)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "find stock %s isin %s", s.Name, s.ISIN)
+	}
+
+	if s.ISIN != "" && stk.ISIN != "" && stk.ISIN != s.ISIN {
+		return nil, errors.Errorf("stock %s matched by symbol or name has isin %s instead of %s", stk.Symbol, stk.ISIN, s.ISIN)
+	}
+
+	return stk, nil
+}
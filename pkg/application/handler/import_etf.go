@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/etf"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type importETF struct {
+	stockFinder  stock.Finder
+	etfPersister etf.Persister
+}
+
+func NewImportETF(stockFinder stock.Finder, etfPersister etf.Persister) *importETF {
+	return &importETF{
+		stockFinder:  stockFinder,
+		etfPersister: etfPersister,
+	}
+}
+
+// Handle imports the constituents of the ETF from a csv file with the columns
+// name, symbol, sector, country and weight. The previous constituents are replaced
+func (h *importETF) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	importETF := command.(*appCommand.ImportETF)
+
+	symbol := importETF.ETF
+	if symbol == "" {
+		logger.FromContext(ctx).Error("An error happen while loading etf -> error [etf can not be empty]")
+
+		return nil, errors.New("missing etf symbol")
+	}
+
+	stk, err := h.stockFinder.FindBySymbol(symbol)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading etf [%s] -> error [%s]",
+			symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if !stk.IsETF() {
+		return nil, fmt.Errorf("stock %s is not an %s", stk.Symbol, stock.ETFType)
+	}
+
+	r := util.NewCsvMappingReader(importETF.FilePath, importETF.Mapping)
+
+	if err := r.Open(); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while opening etf file [%s] -> error [%s]",
+			importETF.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+	defer r.Close()
+
+	if importETF.DryRun {
+		return h.validate(r, importETF.Mapping)
+	}
+
+	holdings := etf.NewHoldings(stk)
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			logger.FromContext(ctx).Fatal(err)
+		}
+
+		if len(line) < 5 {
+			return nil, fmt.Errorf("line %d: expected 5 columns, found %d", n, len(line))
+		}
+
+		weight, err := strconv.ParseFloat(strings.Replace(importETF.Mapping.Number(line[4]), ",", ".", 1), 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: weight %q", n, line[4])
+		}
+
+		c := etf.NewConstituent(line[0], line[1], line[2], line[3], weight)
+
+		if c.Symbol != "" {
+			cStk, err := h.stockFinder.FindBySymbol(c.Symbol)
+			if err != nil && err != mm.ErrNotFound {
+				return nil, err
+			}
+
+			c.Stock = cStk
+		}
+
+		holdings.AddConstituent(c)
+	}
+
+	err = h.etfPersister.Persist(holdings)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting etf [%s] constituents -> error [%s]",
+			symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Debugf("Imported %d constituents into etf [%s]", len(holdings.Constituents), symbol)
+
+	return holdings, nil
+}
+
+// validate reads all the constituents of the file telling the lines with wrong weights and unknown stocks
+func (h *importETF) validate(r *util.CsvReader, mapping *util.Mapping) (*util.ImportReport, error) {
+	report := &util.ImportReport{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		if len(line) < 5 {
+			report.AddIssuef(n, "expected 5 columns, found %d", len(line))
+
+			continue
+		}
+
+		if _, err := strconv.ParseFloat(strings.Replace(mapping.Number(line[4]), ",", ".", 1), 64); err != nil {
+			report.AddIssuef(n, "weight %q is not a number", line[4])
+		}
+
+		if line[1] == "" {
+			continue
+		}
+
+		_, err = h.stockFinder.FindBySymbol(line[1])
+		if err == mm.ErrNotFound {
+			report.AddIssuef(n, "stock %q not found, the constituent is saved without stock", line[1])
+		} else if err != nil {
+			report.AddIssue(n, err)
+		}
+	}
+
+	return report, nil
+}
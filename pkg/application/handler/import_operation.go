@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"io"
+	"strconv"
 
 	"github.com/gogolfing/cbus"
 
@@ -12,24 +13,41 @@ import (
 	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 )
 
 type importOperation struct {
-	stockFinder stock.Finder
+	stockFinder  stock.Finder
+	walletFinder wallet.Finder
 }
 
 func NewImportOperation(
 	stockFinder stock.Finder,
+	walletFinder wallet.Finder,
 ) *importOperation {
 	return &importOperation{
-		stockFinder: stockFinder,
+		stockFinder:  stockFinder,
+		walletFinder: walletFinder,
 	}
 }
 
 func (h *importOperation) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
 	filePath := command.(*appCommand.ImportOperation).FilePath
-	r := util.NewCsvReader(filePath)
+	mapping := command.(*appCommand.ImportOperation).Mapping
+
+	match, err := wallet.ParseTradeMatch(command.(*appCommand.ImportOperation).TradeMatch)
+	if err != nil {
+		logger.FromContext(ctx).Error(err)
+
+		return nil, err
+	}
+
+	if command.(*appCommand.ImportOperation).DryRun {
+		return h.validate(filePath, mapping)
+	}
+
+	r := util.NewCsvMappingReader(filePath, mapping)
 
 	r.Open()
 	defer r.Close()
@@ -45,7 +63,7 @@ func (h *importOperation) Handle(ctx context.Context, command cbus.Command) (res
 			logger.FromContext(ctx).Fatal(err)
 		}
 
-		o, err := createOperationFromLine(line, h.stockFinder)
+		o, err := createOperationFromLine(line, h.stockFinder, mapping)
 		if err != nil {
 			logger.FromContext(ctx).Errorf(
 				"An error happen while createOperationFromLine %s -> error [%s]",
@@ -63,7 +81,55 @@ func (h *importOperation) Handle(ctx context.Context, command cbus.Command) (res
 		}
 	}
 
+	if err := checkDisposals(h.walletFinder, command.(*appCommand.ImportOperation).Wallet, os, trades, match); err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while choosing the trades of the operations from [%s] -> error [%s]",
+			filePath,
+			err,
+		)
+
+		return nil, err
+	}
+
 	command.(*appCommand.ImportOperation).Trades = trades
 
 	return os, nil
 }
+
+// validate reads all the operations of the file telling the lines with unknown stocks, values not valid and
+// trades that are not a number
+func (h *importOperation) validate(filePath string, mapping *util.Mapping) (*util.ImportReport, error) {
+	r := util.NewCsvMappingReader(filePath, mapping)
+
+	if err := r.Open(); err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	report := &util.ImportReport{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		if _, err := createOperationFromLine(line, h.stockFinder, mapping); err != nil {
+			report.AddIssue(n, err)
+
+			continue
+		}
+
+		if _, err := strconv.Atoi(line[0]); line[0] != "" && err != nil {
+			report.AddIssuef(n, "trade %q is not a number", line[0])
+		}
+	}
+
+	return report, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strconv"
 
 	"github.com/gogolfing/cbus"
 
@@ -32,7 +33,8 @@ func NewImportRetention(
 
 func (h *importRetention) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
 	filePath := command.(*appCommand.ImportRetention).FilePath
-	r := util.NewCsvReader(filePath)
+	mapping := command.(*appCommand.ImportRetention).Mapping
+	r := util.NewCsvMappingReader(filePath, mapping)
 
 	r.Open()
 	defer r.Close()
@@ -58,6 +60,10 @@ func (h *importRetention) Handle(ctx context.Context, command cbus.Command) (res
 		return nil, err
 	}
 
+	if command.(*appCommand.ImportRetention).DryRun {
+		return h.validate(r, w, mapping)
+	}
+
 	for {
 		line, err := r.ReadLine()
 		if err == io.EOF {
@@ -71,9 +77,53 @@ func (h *importRetention) Handle(ctx context.Context, command cbus.Command) (res
 				continue
 			}
 
-			i.DividendRetention = mm.ValueDollarFromString(line[1])
+			i.DividendRetention = mm.ValueDollarFromString(mapping.Number(line[1]))
 		}
 	}
 
 	return w, nil
 }
+
+// validate reads all the retentions of the file telling the lines with stocks not held in the wallet and
+// retentions that are not a number
+func (h *importRetention) validate(r *util.CsvReader, w *wallet.Wallet, mapping *util.Mapping) (*util.ImportReport, error) {
+	report := &util.ImportReport{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		if len(line) < 2 {
+			report.AddIssuef(n, "expected at least 2 columns, found %d", len(line))
+
+			continue
+		}
+
+		held := false
+		for _, i := range w.Items {
+			if i.Stock.Name == line[0] {
+				held = true
+
+				break
+			}
+		}
+
+		if !held {
+			report.AddIssuef(n, "stock %q not held in wallet %s", line[0], w.Name)
+		}
+
+		if _, err := strconv.ParseFloat(mapping.Number(line[1]), 64); err != nil {
+			report.AddIssuef(n, "retention %q is not a number", line[1])
+		}
+	}
+
+	return report, nil
+}
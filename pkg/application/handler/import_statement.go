@@ -0,0 +1,434 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/gogolfing/cbus"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/statement"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+)
+
+type (
+	importStatement struct {
+		bankAccountFinder bank.Finder
+		transferFinder    transfer.Finder
+		transferPersister transfer.Persister
+		walletFinder      wallet.Finder
+		walletPersister   wallet.Persister
+	}
+
+	// statementImport holds what is read along one import of statements
+	statementImport struct {
+		// wallets by bank account id, nil when the account is not linked to any wallet
+		wallets map[uuid.UUID]*wallet.Wallet
+		// movements read in the import, to pair them with the same ones of the statement of the other account
+		movements []*movement
+		// paired are the ids of the transfers stored already paired with a movement read
+		paired map[uuid.UUID]bool
+	}
+
+	// movement is a transfer read from the statement of one of its accounts, the side. The same movement comes
+	// in the statements of both accounts
+	movement struct {
+		transfer *transfer.Transfer
+		side     uuid.UUID
+		paired   bool
+	}
+)
+
+func NewImportStatement(
+	bankAccountFinder bank.Finder,
+	transferFinder transfer.Finder,
+	transferPersister transfer.Persister,
+	walletFinder wallet.Finder,
+	walletPersister wallet.Persister,
+) *importStatement {
+	return &importStatement{
+		bankAccountFinder: bankAccountFinder,
+		transferFinder:    transferFinder,
+		transferPersister: transferPersister,
+		walletFinder:      walletFinder,
+		walletPersister:   walletPersister,
+	}
+}
+
+// Handle creates the transfers of the statement movements between our bank and broker accounts. The counterpart
+// of the movement is looked up by its account number, the movements to unknown accounts or between accounts not
+// linked to any wallet are ignored, as well as the ones already imported
+func (h *importStatement) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.ImportStatement)
+
+	format := statement.Format(cmd.Format)
+	if format == "" {
+		format, err = statement.FormatFromFile(cmd.FilePath)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while reading statement format -> error [%s]",
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(cmd.FilePath)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while opening statement file [%s] -> error [%s]",
+			cmd.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+	defer f.Close()
+
+	ss, err := statement.Parse(f, format)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while parsing statement file [%s] -> error [%s]",
+			cmd.FilePath,
+			err,
+		)
+
+		return nil, err
+	}
+
+	si := &statementImport{
+		wallets: map[uuid.UUID]*wallet.Wallet{},
+		paired:  map[uuid.UUID]bool{},
+	}
+
+	if cmd.DryRun {
+		return h.validate(ctx, si, ss, cmd.Rate)
+	}
+
+	var (
+		ts []*transfer.Transfer
+		ws []*wallet.Wallet
+	)
+
+	wsi := map[uuid.UUID]bool{}
+
+	for _, s := range ss {
+		account, err := h.bankAccountFinder.FindByAccountNo(s.AccountNo)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding bank account [%s] -> error [%s]",
+				s.AccountNo,
+				err,
+			)
+
+			return nil, err
+		}
+
+		for _, e := range s.Entries {
+			t, err := h.createTransferFromEntry(ctx, si, account, e, cmd.Rate)
+			if err != nil {
+				return nil, err
+			}
+
+			if t == nil || si.pair(t, account.ID) {
+				continue
+			}
+
+			imported, err := h.wasImported(si, t, account.ID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while finding transfers from [%s] to [%s] -> error [%s]",
+					t.From.Alias,
+					t.To.Alias,
+					err,
+				)
+
+				return nil, err
+			}
+
+			if imported {
+				logger.FromContext(ctx).Debugf(
+					"Transfer from [%s] to [%s] of %.2f on %s already imported",
+					t.From.Alias,
+					t.To.Alias,
+					t.Amount.Amount,
+					t.Date.Format("2006-01-02"),
+				)
+
+				continue
+			}
+
+			ts = append(ts, t)
+
+			if w := si.wallets[t.From.ID]; w != nil {
+				if err := w.TransferOut(t); err != nil {
+					logger.FromContext(ctx).Errorf(
+						"An error happen while booking transfer from [%s] to [%s] -> error [%s]",
+						t.From.Alias,
+						t.To.Alias,
+						err,
+					)
+
+					return nil, err
+				}
+
+				if !wsi[w.ID] {
+					wsi[w.ID] = true
+					ws = append(ws, w)
+				}
+			}
+
+			if w := si.wallets[t.To.ID]; w != nil {
+				if err := w.TransferIn(t); err != nil {
+					logger.FromContext(ctx).Errorf(
+						"An error happen while booking transfer from [%s] to [%s] -> error [%s]",
+						t.From.Alias,
+						t.To.Alias,
+						err,
+					)
+
+					return nil, err
+				}
+
+				if !wsi[w.ID] {
+					wsi[w.ID] = true
+					ws = append(ws, w)
+				}
+			}
+		}
+	}
+
+	if len(ts) == 0 {
+		return ts, nil
+	}
+
+	err = h.transferPersister.PersistAll(ts)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting transfer -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	err = h.walletPersister.UpdateAllAccounting(ws)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting wallets -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// createTransferFromEntry creates the transfer of the entry, nil when the entry is not a movement between
+// our bank and broker accounts
+func (h *importStatement) createTransferFromEntry(
+	ctx context.Context,
+	si *statementImport,
+	account *bank.Account,
+	e *statement.Entry,
+	rate float64,
+) (*transfer.Transfer, error) {
+	if e.CounterpartAccountNo == "" {
+		return nil, nil
+	}
+
+	counterpart, err := h.bankAccountFinder.FindByAccountNo(e.CounterpartAccountNo)
+	if err != nil {
+		if err == mm.ErrNotFound {
+			return nil, nil
+		}
+
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding bank account [%s] -> error [%s]",
+			e.CounterpartAccountNo,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if counterpart.ID == account.ID {
+		return nil, nil
+	}
+
+	for _, a := range []*bank.Account{account, counterpart} {
+		if err := h.loadWallet(si, a); err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding wallet by bank account [%s] -> error [%s]",
+				a.AccountNo,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	if si.wallets[account.ID] == nil && si.wallets[counterpart.ID] == nil {
+		return nil, nil
+	}
+
+	from, to := counterpart, account
+	if e.IsDebit() {
+		from, to = account, counterpart
+	}
+
+	amount := mm.Value{Amount: math.Abs(e.Amount.Amount), Currency: e.Amount.Currency}
+
+	t, err := transfer.NewCurrencyTransfer(from, to, amount, mm.Value{}, rate, e.Date)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while creating transfer from [%s] to [%s] -> error [%s]",
+			from.Alias,
+			to.Alias,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// validate goes through all the movements of the statements telling the unknown accounts, the movements to
+// unknown accounts and the ones already imported. Statements have no lines, the issues are of line 0
+func (h *importStatement) validate(
+	ctx context.Context,
+	si *statementImport,
+	ss []*statement.Statement,
+	rate float64,
+) (*util.ImportReport, error) {
+	report := &util.ImportReport{}
+
+	for _, s := range ss {
+		account, err := h.bankAccountFinder.FindByAccountNo(s.AccountNo)
+		if err != nil {
+			report.AddIssuef(0, "find bank account %q: %s", s.AccountNo, err)
+
+			continue
+		}
+
+		for _, e := range s.Entries {
+			report.Rows++
+
+			entry := fmt.Sprintf("%s %.2f %s", e.Date.Format("2006-01-02"), e.Amount.Amount, e.Amount.Currency)
+
+			if e.CounterpartAccountNo != "" {
+				_, err := h.bankAccountFinder.FindByAccountNo(e.CounterpartAccountNo)
+				if err == mm.ErrNotFound {
+					report.AddIssuef(0, "movement %s of %s: counterpart account %q unknown, ignored", entry, account.Alias, e.CounterpartAccountNo)
+
+					continue
+				} else if err != nil {
+					report.AddIssuef(0, "movement %s of %s: %s", entry, account.Alias, err)
+
+					continue
+				}
+			}
+
+			t, err := h.createTransferFromEntry(ctx, si, account, e, rate)
+			if err != nil {
+				report.AddIssuef(0, "movement %s of %s: %s", entry, account.Alias, err)
+
+				continue
+			}
+
+			if t == nil || si.pair(t, account.ID) {
+				continue
+			}
+
+			imported, err := h.wasImported(si, t, account.ID)
+			if err != nil {
+				report.AddIssuef(0, "movement %s of %s: %s", entry, account.Alias, err)
+
+				continue
+			}
+
+			if imported {
+				report.AddIssuef(0, "movement %s of %s: transfer from %s to %s already imported", entry, account.Alias, t.From.Alias, t.To.Alias)
+
+				continue
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// loadWallet loads the wallet the account is linked to into the import, once per account
+func (h *importStatement) loadWallet(si *statementImport, a *bank.Account) error {
+	if _, ok := si.wallets[a.ID]; ok {
+		return nil
+	}
+
+	w, err := h.walletFinder.FindByBankAccount(a)
+	if err != nil {
+		if err != mm.ErrNotFound {
+			return err
+		}
+
+		w = nil
+	}
+
+	si.wallets[a.ID] = w
+
+	return nil
+}
+
+// pair tells whether the transfer read from the statement of the account is the same movement read from the
+// statement of the other account of the transfer, each movement is paired once. The movements repeated in the
+// same statement are different transfers
+func (si *statementImport) pair(t *transfer.Transfer, side uuid.UUID) bool {
+	for _, m := range si.movements {
+		if !m.paired && m.side != side && sameTransfer(t, m.transfer) {
+			m.paired = true
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// wasImported tells whether the transfer read from the statement of the account was already imported, each
+// transfer stored is paired once. The transfer is kept to pair it with the statement of the other account
+func (h *importStatement) wasImported(si *statementImport, t *transfer.Transfer, side uuid.UUID) (bool, error) {
+	ts, err := h.transferFinder.FindAllByAccountsAndDate(t.From.ID, t.To.ID, t.Date)
+	if err != nil {
+		return false, err
+	}
+
+	si.movements = append(si.movements, &movement{transfer: t, side: side})
+
+	for _, it := range ts {
+		if !si.paired[it.ID] && sameTransfer(t, it) {
+			si.paired[it.ID] = true
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func sameTransfer(t, ot *transfer.Transfer) bool {
+	return t.From.ID == ot.From.ID &&
+		t.To.ID == ot.To.ID &&
+		t.Date.Format("2006-01-02") == ot.Date.Format("2006-01-02") &&
+		t.Amount.Currency == ot.Amount.Currency &&
+		math.Abs(t.Amount.Amount-ot.Amount.Amount) < 0.005
+}
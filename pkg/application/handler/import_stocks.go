@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"io"
+	"strings"
 
 	"github.com/gogolfing/cbus"
 
@@ -18,6 +19,7 @@ import (
 type importStock struct {
 	marketFinder       market.Finder
 	exchangeFinder     exchange.Finder
+	stockFinder        stock.Finder
 	stockInfoFinder    stock.InfoFinder
 	stockPersister     stock.Persister
 	stockInfoPersister stock.InfoPersister
@@ -28,6 +30,7 @@ type importStock struct {
 func NewImportStock(
 	marketFinder market.Finder,
 	exchangeFinder exchange.Finder,
+	stockFinder stock.Finder,
 	stockInfoFinder stock.InfoFinder,
 	stockPersister stock.Persister,
 	stockInfoPersister stock.InfoPersister,
@@ -35,6 +38,7 @@ func NewImportStock(
 	return &importStock{
 		marketFinder:       marketFinder,
 		exchangeFinder:     exchangeFinder,
+		stockFinder:        stockFinder,
 		stockInfoFinder:    stockInfoFinder,
 		stockPersister:     stockPersister,
 		stockInfoPersister: stockInfoPersister,
@@ -44,7 +48,13 @@ func NewImportStock(
 
 func (h *importStock) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
 	filePath := command.(*appCommand.ImportStock).FilePath
-	r := util.NewCsvReader(filePath)
+	mapping := command.(*appCommand.ImportStock).Mapping
+
+	if command.(*appCommand.ImportStock).DryRun {
+		return h.validate(filePath, mapping)
+	}
+
+	r := util.NewCsvMappingReader(filePath, mapping)
 
 	r.Open()
 	defer r.Close()
@@ -85,6 +95,14 @@ func (h *importStock) Handle(ctx context.Context, command cbus.Command) (result
 		}
 
 		stk := stock.NewStock(m, e, line[0], line[2], t, sector, industry)
+
+		// the ISIN column is optional
+		if len(line) > 6 && line[6] != "" {
+			if err := stk.SetISIN(line[6]); err != nil {
+				return nil, err
+			}
+		}
+
 		ss = append(ss, stk)
 
 		logger.FromContext(ctx).Debugf("Added new stock [%+v]", stk)
@@ -103,6 +121,64 @@ func (h *importStock) Handle(ctx context.Context, command cbus.Command) (result
 	return ss, nil
 }
 
+// validate reads all the stocks of the file telling the lines with unknown exchanges, ISIN not valid and
+// the stocks already stored or repeated in the file
+func (h *importStock) validate(filePath string, mapping *util.Mapping) (*util.ImportReport, error) {
+	r := util.NewCsvMappingReader(filePath, mapping)
+
+	if err := r.Open(); err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	report := &util.ImportReport{}
+	symbols := map[string]int{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		if len(line) < 6 {
+			report.AddIssuef(n, "expected at least 6 columns, found %d", len(line))
+
+			continue
+		}
+
+		if _, err := h.exchangeFinder.FindBySymbol(line[1]); err != nil {
+			report.AddIssuef(n, "find exchange %q: %s", line[1], err)
+		}
+
+		symbol := strings.ToUpper(line[2])
+		if prev, ok := symbols[symbol]; ok {
+			report.AddIssuef(n, "stock %s repeated, first on line %d", symbol, prev)
+		} else {
+			symbols[symbol] = n
+		}
+
+		if _, err := h.stockFinder.FindBySymbol(symbol); err == nil {
+			report.AddIssuef(n, "stock %s already stored", symbol)
+		} else if err != mm.ErrNotFound {
+			return nil, err
+		}
+
+		if len(line) > 6 && line[6] != "" {
+			if err := new(stock.Stock).SetISIN(line[6]); err != nil {
+				report.AddIssue(n, err)
+			}
+		}
+	}
+
+	return report, nil
+}
+
 func (h *importStock) getStockInfo(ctx context.Context, value string, t stock.InfoType) (*stock.Info, error) {
 	if stkInfo, ok := h.stockInfos[value]; ok {
 		return stkInfo, nil
@@ -18,6 +18,7 @@ import (
 
 type importTransfer struct {
 	bankAccountFinder bank.Finder
+	transferFinder    transfer.Finder
 	transferPersister transfer.Persister
 	walletFinder      wallet.Finder
 	walletPersister   wallet.Persister
@@ -25,12 +26,14 @@ type importTransfer struct {
 
 func NewImportTransfer(
 	bankAccountFinder bank.Finder,
+	transferFinder transfer.Finder,
 	transferPersister transfer.Persister,
 	walletFinder wallet.Finder,
 	walletPersister wallet.Persister,
 ) *importTransfer {
 	return &importTransfer{
 		bankAccountFinder: bankAccountFinder,
+		transferFinder:    transferFinder,
 		transferPersister: transferPersister,
 		walletFinder:      walletFinder,
 		walletPersister:   walletPersister,
@@ -38,8 +41,13 @@ func NewImportTransfer(
 }
 
 func (h *importTransfer) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
-	filePath := command.(*appCommand.ImportTransfer).FilePath
-	r := util.NewCsvReader(filePath)
+	cmd := command.(*appCommand.ImportTransfer)
+
+	if cmd.DryRun {
+		return h.validate(ctx, cmd.FilePath, cmd.Mapping)
+	}
+
+	r := util.NewCsvMappingReader(cmd.FilePath, cmd.Mapping)
 
 	r.Open()
 	defer r.Close()
@@ -60,7 +68,7 @@ func (h *importTransfer) Handle(ctx context.Context, command cbus.Command) (resu
 			logger.FromContext(ctx).Fatal(err)
 		}
 
-		t, err := createTransferFromLine(line, h.bankAccountFinder)
+		t, err := createTransferFromLine(line, h.bankAccountFinder, cmd.Mapping)
 		if err != nil {
 			logger.FromContext(ctx).Errorf(
 				"An error happen while creating transfer -> error [%s]",
@@ -102,7 +110,16 @@ func (h *importTransfer) Handle(ctx context.Context, command cbus.Command) (resu
 					ws = append(ws, w)
 				}
 
-				w.IncreaseInvestment(t.Amount)
+				if err := w.TransferIn(t); err != nil {
+					logger.FromContext(ctx).Errorf(
+						"An error happen while booking transfer from [%s] to [%s] -> error [%s]",
+						t.From.Alias,
+						t.To.Alias,
+						err,
+					)
+
+					return nil, err
+				}
 
 				continue
 			}
@@ -111,7 +128,16 @@ func (h *importTransfer) Handle(ctx context.Context, command cbus.Command) (resu
 			ws = append(ws, w)
 		}
 
-		w.DecreaseInvestment(t.Amount)
+		if err := w.TransferOut(t); err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while booking transfer from [%s] to [%s] -> error [%s]",
+				t.From.Alias,
+				t.To.Alias,
+				err,
+			)
+
+			return nil, err
+		}
 	}
 
 	err = h.transferPersister.PersistAll(ts)
@@ -136,3 +162,80 @@ func (h *importTransfer) Handle(ctx context.Context, command cbus.Command) (resu
 
 	return ts, nil
 }
+
+// validate reads all the transfers of the file telling the lines with unknown bank accounts, accounts not
+// held by any wallet, values not valid and the transfers already stored
+func (h *importTransfer) validate(ctx context.Context, filePath string, mapping *util.Mapping) (*util.ImportReport, error) {
+	r := util.NewCsvMappingReader(filePath, mapping)
+
+	if err := r.Open(); err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	report := &util.ImportReport{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		t, err := createTransferFromLine(line, h.bankAccountFinder, mapping)
+		if err != nil {
+			report.AddIssue(n, err)
+
+			continue
+		}
+
+		held, err := h.isHeldByWallet(t.From)
+		if err == nil && !held {
+			held, err = h.isHeldByWallet(t.To)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !held {
+			report.AddIssuef(n, "no wallet holds the bank account %q nor %q", t.From.Alias, t.To.Alias)
+		}
+
+		ts, err := h.transferFinder.FindAllByAccountsAndDate(t.From.ID, t.To.ID, t.Date)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding transfers from [%s] to [%s] -> error [%s]",
+				t.From.Alias,
+				t.To.Alias,
+				err,
+			)
+
+			return nil, err
+		}
+
+		for _, st := range ts {
+			if sameTransfer(t, st) {
+				report.AddIssuef(n, "transfer from %q to %q of %.2f already stored", t.From.Alias, t.To.Alias, t.Amount.Amount)
+
+				break
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (h *importTransfer) isHeldByWallet(ba *bank.Account) (bool, error) {
+	_, err := h.walletFinder.FindByBankAccount(ba)
+	if err == mm.ErrNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
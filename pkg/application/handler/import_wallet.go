@@ -13,28 +13,32 @@ import (
 	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
 	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
 )
 
 type importWallet struct {
 	bankAccountFinder bank.Finder
+	brokerFinder      broker.Finder
 	walletPersister   wallet.Persister
 }
 
 func NewImportWallet(
 	bankAccountFinder bank.Finder,
+	brokerFinder broker.Finder,
 	walletPersister wallet.Persister,
 ) *importWallet {
 	return &importWallet{
 		bankAccountFinder: bankAccountFinder,
+		brokerFinder:      brokerFinder,
 		walletPersister:   walletPersister,
 	}
 }
 
 func (h *importWallet) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
 	filePath := command.(*appCommand.ImportWallet).FilePath
-	r := util.NewCsvReader(filePath)
+	r := util.NewCsvMappingReader(filePath, command.(*appCommand.ImportWallet).Mapping)
 
 	r.Open()
 	defer r.Close()
@@ -45,6 +49,26 @@ func (h *importWallet) Handle(ctx context.Context, command cbus.Command) (result
 		return nil, errors.New("missing wallet name")
 	}
 
+	var b *broker.Broker
+
+	bName := command.(*appCommand.ImportWallet).Broker
+	if bName != "" {
+		b, err = h.brokerFinder.FindByName(bName)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while loading broker [%s] -> error [%s]",
+				bName,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	if command.(*appCommand.ImportWallet).DryRun {
+		return h.validate(r)
+	}
+
 	var ws []*wallet.Wallet
 	for {
 		line, err := r.ReadLine()
@@ -61,6 +85,8 @@ func (h *importWallet) Handle(ctx context.Context, command cbus.Command) (result
 		}
 
 		w := wallet.NewWallet(name, url)
+		w.Broker = b
+
 		err = w.AddBankAccount(bankAccount)
 		if err != nil {
 			return nil, err
@@ -82,6 +108,36 @@ func (h *importWallet) Handle(ctx context.Context, command cbus.Command) (result
 	return ws, nil
 }
 
+// validate reads all the wallets of the file telling the lines with unknown bank accounts
+func (h *importWallet) validate(r *util.CsvReader) (*util.ImportReport, error) {
+	report := &util.ImportReport{}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			report.AddIssue(n, err)
+
+			break
+		}
+
+		report.Rows++
+
+		if len(line) < 2 {
+			report.AddIssuef(n, "expected 2 columns, found %d", len(line))
+
+			continue
+		}
+
+		if _, err := h.bankAccountFinder.FindByAlias(line[1]); err != nil {
+			report.AddIssuef(n, "find bank account %q: %s", line[1], err)
+		}
+	}
+
+	return report, nil
+}
+
 // parseDateString - parse a potentially partial date string to Time
 func (h *importWallet) parseDateString(dt string) time.Time {
 	if dt == "" {
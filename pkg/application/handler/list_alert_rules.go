@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+)
+
+type listAlertRules struct {
+	alertFinder alert.Finder
+}
+
+func NewListAlertRules(alertFinder alert.Finder) *listAlertRules {
+	return &listAlertRules{
+		alertFinder: alertFinder,
+	}
+}
+
+func (h *listAlertRules) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	rs, err := h.alertFinder.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding alert rules -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return rs, nil
+}
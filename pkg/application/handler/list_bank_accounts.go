@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+)
+
+type listBankAccounts struct {
+	bankAccountFinder bank.Finder
+	walletFinder      wallet.Finder
+}
+
+func NewListBankAccounts(bankAccountFinder bank.Finder, walletFinder wallet.Finder) *listBankAccounts {
+	return &listBankAccounts{
+		bankAccountFinder: bankAccountFinder,
+		walletFinder:      walletFinder,
+	}
+}
+
+// Handle lists the bank accounts along with the wallet each one is linked to
+func (h *listBankAccounts) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	as, err := h.bankAccountFinder.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding bank accounts -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	var bOutputs []*render.BankAccountOutput
+	for _, a := range as {
+		bOutput := &render.BankAccountOutput{
+			Account: a,
+		}
+
+		w, err := h.walletFinder.FindByBankAccount(a)
+		if err != nil {
+			if err != mm.ErrNotFound {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while finding wallet of bank account [%s] -> error [%s]",
+					a.Alias,
+					err,
+				)
+
+				return nil, err
+			}
+		} else {
+			bOutput.Wallet = w.Name
+		}
+
+		bOutputs = append(bOutputs, bOutput)
+	}
+
+	return bOutputs, nil
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+)
+
+type listBrokers struct {
+	brokerFinder broker.Finder
+}
+
+func NewListBrokers(brokerFinder broker.Finder) *listBrokers {
+	return &listBrokers{
+		brokerFinder: brokerFinder,
+	}
+}
+
+func (h *listBrokers) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	bs, err := h.brokerFinder.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding brokers -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return bs, nil
+}
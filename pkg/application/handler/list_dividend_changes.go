@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogolfing/cbus"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+// defaultDividendChangesDays is how far back the dividend changes are listed when no date is given
+const defaultDividendChangesDays = 30
+
+type listDividendChanges struct {
+	stockFinder         stock.Finder
+	stockDividendFinder dividend.Finder
+}
+
+func NewListDividendChanges(stockFinder stock.Finder, stockDividendFinder dividend.Finder) *listDividendChanges {
+	return &listDividendChanges{
+		stockFinder:         stockFinder,
+		stockDividendFinder: stockDividendFinder,
+	}
+}
+
+func (h *listDividendChanges) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.ListDividendChanges)
+
+	from := time.Now().AddDate(0, 0, -defaultDividendChangesDays)
+	if cmd.From != "" {
+		from, err = parseOperationDateString(cmd.From)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+	}
+
+	cs, err := h.stockDividendFinder.FindAllChangesFrom(from)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding dividend changes -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	stocks := map[uuid.UUID]*stock.Stock{}
+
+	var dcs []*render.DividendChangeOutput
+	for _, c := range cs {
+		stk, ok := stocks[c.StockID]
+		if !ok {
+			stk, err = h.stockFinder.FindByID(c.StockID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while finding stock [%s] -> error [%s]",
+					c.StockID,
+					err,
+				)
+
+				return nil, err
+			}
+
+			stocks[c.StockID] = stk
+		}
+
+		if cmd.Stock != "" && stk.Symbol != cmd.Stock {
+			continue
+		}
+
+		dcs = append(dcs, &render.DividendChangeOutput{
+			Stock:      stk.Name,
+			Symbol:     stk.Symbol,
+			Type:       c.Type,
+			ExDate:     c.ExDate,
+			Previous:   c.Previous,
+			Amount:     c.Amount,
+			DetectedAt: c.DetectedAt,
+		})
+	}
+
+	return dcs, nil
+}
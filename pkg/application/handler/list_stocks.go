@@ -13,32 +13,49 @@ import (
 	"github.com/dohernandez/market-manager/pkg/market-manager"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/valuation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
 )
 
 type listStocks struct {
 	stockFinder         stock.Finder
 	stockDividendFinder dividend.Finder
+	watchlistFinder     watchlist.Finder
+	valuationModels     []valuation.Model
 }
 
 func NewListStock(
 	stockFinder stock.Finder,
 	stockDividendFinder dividend.Finder,
+	watchlistFinder watchlist.Finder,
+	valuationModels []valuation.Model,
 ) *listStocks {
 	return &listStocks{
 		stockFinder:         stockFinder,
 		stockDividendFinder: stockDividendFinder,
+		watchlistFinder:     watchlistFinder,
+		valuationModels:     valuationModels,
 	}
 }
 
 func (h *listStocks) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
 	exchange := command.(*appCommand.ListStocks).Exchange
+	wName := command.(*appCommand.ListStocks).Watchlist
 
-	var (
-		stks  []*stock.Stock
-		rstks []*render.StockOutput
-	)
+	var stks []*stock.Stock
 
-	if exchange == "" {
+	if wName != "" {
+		stks, err = findWatchlistStocks(h.watchlistFinder, h.stockFinder, wName)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding stocks from watchlist [%s] -> error [%s]",
+				wName,
+				err,
+			)
+
+			return nil, err
+		}
+	} else if exchange == "" {
 		stks, err = h.stockFinder.FindAll()
 		if err != nil {
 			logger.FromContext(ctx).Errorf(
@@ -64,10 +81,39 @@ func (h *listStocks) Handle(ctx context.Context, command cbus.Command) (result i
 		}
 	}
 
+	return stocksOutput(ctx, h.stockDividendFinder, h.valuationModels, stks)
+}
+
+// stocksOutput builds the stocks output along with their upcoming dividend and their valuation by the models
+func stocksOutput(
+	ctx context.Context,
+	stockDividendFinder dividend.Finder,
+	valuationModels []valuation.Model,
+	stks []*stock.Stock,
+) ([]*render.StockOutput, error) {
+	var rstks []*render.StockOutput
+
+	now := time.Now()
+
 	for _, stk := range stks {
 		var exDate time.Time
 
-		d, err := h.stockDividendFinder.FindUpcoming(stk.ID)
+		if len(valuationModels) > 0 && len(stk.Dividends) == 0 {
+			ds, err := stockDividendFinder.FindAllFormStock(stk.ID)
+			if err != nil && err != mm.ErrNotFound {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while finding dividends from [%s] -> error [%s]",
+					stk.Symbol,
+					err,
+				)
+
+				return nil, err
+			}
+
+			stk.Dividends = ds
+		}
+
+		d, err := stockDividendFinder.FindUpcoming(stk.ID)
 		if err != nil {
 			if err != mm.ErrNotFound {
 				logger.FromContext(ctx).Errorf(
@@ -102,10 +148,11 @@ func (h *listStocks) Handle(ctx context.Context, command cbus.Command) (result i
 			HV52Week:       stk.HV52Week,
 			HV20Day:        stk.HV20Day,
 			PER:            stk.PER,
+			Valuations:     valuation.Value(stk, valuationModels, now),
 
 			PriceWithHighLow: stk.ComparePriceWithHighLow(),
 		})
 	}
 
-	return rstks, err
+	return rstks, nil
 }
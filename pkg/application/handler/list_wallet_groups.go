@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+)
+
+type listWalletGroups struct {
+	groupFinder group.Finder
+}
+
+func NewListWalletGroups(groupFinder group.Finder) *listWalletGroups {
+	return &listWalletGroups{
+		groupFinder: groupFinder,
+	}
+}
+
+func (h *listWalletGroups) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	gs, err := h.groupFinder.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding wallet groups -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return gs, nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type listWallets struct {
+	walletFinder wallet.Finder
+	brokerFinder broker.Finder
+}
+
+func NewListWallets(walletFinder wallet.Finder, brokerFinder broker.Finder) *listWallets {
+	return &listWallets{
+		walletFinder: walletFinder,
+		brokerFinder: brokerFinder,
+	}
+}
+
+func (h *listWallets) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	bName := command.(*appCommand.ListWallets).Broker
+
+	var ws []*wallet.Wallet
+
+	if bName == "" {
+		ws, err = h.walletFinder.FindAll()
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding wallets -> error [%s]",
+				err,
+			)
+
+			return nil, err
+		}
+	} else {
+		b, err := h.brokerFinder.FindByName(bName)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding broker [%s] -> error [%s]",
+				bName,
+				err,
+			)
+
+			return nil, err
+		}
+
+		ws, err = h.walletFinder.FindAllByBroker(b)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding wallets from broker [%s] -> error [%s]",
+				bName,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	var wOutputs []*render.WalletOutput
+	for _, w := range ws {
+		var brokerName string
+		if w.Broker != nil {
+			brokerName = w.Broker.Name
+		}
+
+		wOutputs = append(wOutputs, &render.WalletOutput{
+			Wallet:             w.Name,
+			Broker:             brokerName,
+			Capital:            w.Capital,
+			Invested:           w.Invested,
+			Funds:              w.Funds,
+			FreeMargin:         w.FreeMargin(),
+			NetCapital:         w.NetCapital(),
+			NetBenefits:        w.NetBenefits(),
+			PercentageBenefits: w.PercentageBenefits(),
+			DividendPayed:      w.Dividend,
+			Connection:         w.Connection,
+			Interest:           w.Interest,
+			Commission:         w.Commission,
+		})
+	}
+
+	return wOutputs, nil
+}
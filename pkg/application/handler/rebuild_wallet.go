@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+type rebuildWallet struct {
+	walletFinder        wallet.Finder
+	walletPersister     wallet.Persister
+	operationFinder     operation.Finder
+	transferFinder      transfer.Finder
+	stockFinder         stock.Finder
+	stockDividendFinder dividend.Finder
+
+	ccClient *cc.Client
+}
+
+func NewRebuildWallet(
+	walletFinder wallet.Finder,
+	walletPersister wallet.Persister,
+	operationFinder operation.Finder,
+	transferFinder transfer.Finder,
+	stockFinder stock.Finder,
+	stockDividendFinder dividend.Finder,
+	ccClient *cc.Client,
+) *rebuildWallet {
+	return &rebuildWallet{
+		walletFinder:        walletFinder,
+		walletPersister:     walletPersister,
+		operationFinder:     operationFinder,
+		transferFinder:      transferFinder,
+		stockFinder:         stockFinder,
+		stockDividendFinder: stockDividendFinder,
+		ccClient:            ccClient,
+	}
+}
+
+// Handle replays the wallet transfers and operations from scratch and compares the result with the stored wallet,
+// storing the rebuilt wallet when the command says so
+func (h *rebuildWallet) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	rebuildWallet := command.(*appCommand.RebuildWallet)
+
+	wName := rebuildWallet.Wallet
+	if wName == "" {
+		logger.FromContext(ctx).Error("An error happen while loading wallet -> error [wallet can not be empty]")
+
+		return nil, errors.New("missing wallet name")
+	}
+
+	stocks := map[uuid.UUID]*stock.Stock{}
+
+	w, err := h.loadStoredWallet(wName, stocks)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	ts, ops, trades, err := h.loadLog(w, stocks)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading transfers and operations from wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	rw, err := wallet.Rebuild(w, ts, ops, trades)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while rebuilding wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	rOutput := render.WalletRebuildOutput{
+		Wallet:      w.Name,
+		Differences: w.Diff(rw),
+	}
+
+	if rebuildWallet.Apply && len(rOutput.Differences) > 0 {
+		err = h.walletPersister.PersistRebuild(rw)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while persisting rebuilt wallet [%s] -> error [%s]",
+				wName,
+				err,
+			)
+
+			return nil, err
+		}
+
+		rOutput.Applied = true
+	}
+
+	return rOutput, nil
+}
+
+func (h *rebuildWallet) loadStoredWallet(name string, stocks map[uuid.UUID]*stock.Stock) (*wallet.Wallet, error) {
+	w, err := h.walletFinder.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.walletFinder.LoadBankAccounts(w); err != nil {
+		return nil, err
+	}
+
+	if err = h.walletFinder.LoadAllItems(w); err != nil {
+		return nil, err
+	}
+
+	for _, i := range w.Items {
+		stk, err := h.findStock(i.Stock.ID, stocks)
+		if err != nil {
+			return nil, err
+		}
+
+		i.Stock = stk
+	}
+
+	if err = h.walletFinder.LoadActiveTrades(w); err != nil {
+		return nil, err
+	}
+
+	currencyConverter, err := h.ccClient.Converter.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	w.SetCapitalRate(wallet.CapitalRate{
+		EURUSD: currencyConverter.EURUSD,
+		EURCAD: currencyConverter.EURCAD,
+	})
+
+	return w, nil
+}
+
+// loadLog loads the wallet transfers and operations, sharing the stock instances with the stored wallet,
+// along with the trade numbers of the operations
+func (h *rebuildWallet) loadLog(w *wallet.Wallet, stocks map[uuid.UUID]*stock.Stock) ([]*transfer.Transfer, []*operation.Operation, map[uuid.UUID]int, error) {
+	ts, err := h.transferFinder.FindAllByWallet(w.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	ops, err := h.operationFinder.FindAllByWallet(w.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, o := range ops {
+		if o.Stock.ID == uuid.Nil {
+			continue
+		}
+
+		stk, err := h.findStock(o.Stock.ID, stocks)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		o.Stock = stk
+	}
+
+	trades, err := h.operationFinder.FindTradeNumbersByWallet(w.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return ts, ops, trades, nil
+}
+
+func (h *rebuildWallet) findStock(ID uuid.UUID, stocks map[uuid.UUID]*stock.Stock) (*stock.Stock, error) {
+	if stk, ok := stocks[ID]; ok {
+		return stk, nil
+	}
+
+	stk, err := h.stockFinder.FindByID(ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find stock %q", ID)
+	}
+
+	stk.Dividends, err = h.stockDividendFinder.FindAllFormStock(ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find dividends of stock %q", ID)
+	}
+
+	stocks[ID] = stk
+
+	return stk, nil
+}
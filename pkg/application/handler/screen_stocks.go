@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/screen"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/valuation"
+)
+
+type screenStocks struct {
+	stockFinder         stock.Finder
+	stockDividendFinder dividend.Finder
+	screenFinder        screen.Finder
+	screenPersister     screen.Persister
+	valuationModels     []valuation.Model
+}
+
+func NewScreenStocks(
+	stockFinder stock.Finder,
+	stockDividendFinder dividend.Finder,
+	screenFinder screen.Finder,
+	screenPersister screen.Persister,
+	valuationModels []valuation.Model,
+) *screenStocks {
+	return &screenStocks{
+		stockFinder:         stockFinder,
+		stockDividendFinder: stockDividendFinder,
+		screenFinder:        screenFinder,
+		screenPersister:     screenPersister,
+		valuationModels:     valuationModels,
+	}
+}
+
+// Handle runs a screen over all the stocks, saves or deletes a screen, or lists the saved ones
+func (h *screenStocks) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	switch cmd := command.(type) {
+	case *appCommand.ScreenStocks:
+		return h.screenStocks(ctx, cmd)
+	case *appCommand.DeleteScreen:
+		s, err := h.screenFinder.FindByName(cmd.Name)
+		if err == nil {
+			err = h.screenPersister.Delete(s)
+		}
+
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while deleting screen [%s] -> error [%s]",
+				cmd.Name,
+				err,
+			)
+
+			return nil, err
+		}
+
+		return s, nil
+	case *appCommand.ListScreens:
+		ss, err := h.screenFinder.FindAll()
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding screens -> error [%s]",
+				err,
+			)
+
+			return nil, err
+		}
+
+		return ss, nil
+	}
+
+	logger.FromContext(ctx).Error(
+		"screenStocks: Command not supported",
+	)
+
+	return nil, errors.New("command not supported")
+}
+
+func (h *screenStocks) screenStocks(ctx context.Context, cmd *appCommand.ScreenStocks) (interface{}, error) {
+	s, err := h.screen(cmd)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading screen -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	if cmd.Save {
+		err = h.screenPersister.Persist(s)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while saving screen [%s] -> error [%s]",
+				s.Name,
+				err,
+			)
+
+			return nil, err
+		}
+	}
+
+	stks, err := h.stockFinder.FindAll()
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stocks -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	stks, err = s.Run(stks)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while screening stocks -> error [%s]",
+			err,
+		)
+
+		return nil, err
+	}
+
+	return stocksOutput(ctx, h.stockDividendFinder, h.valuationModels, stks)
+}
+
+// screen returns the saved screen with the command values overriding it, or a new screen from the command
+func (h *screenStocks) screen(cmd *appCommand.ScreenStocks) (*screen.Screen, error) {
+	if cmd.Expression == "" {
+		if cmd.Name == "" {
+			return nil, errors.New("missing screen expression or name")
+		}
+
+		s, err := h.screenFinder.FindByName(cmd.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find screen %q", cmd.Name)
+		}
+
+		if cmd.Sort != "" {
+			s.Sort = cmd.Sort
+		}
+
+		if cmd.Order != "" {
+			s.Desc = util.OrderBy(cmd.Order) == util.OrderDescending
+		}
+
+		if cmd.Limit != nil {
+			s.Limit = *cmd.Limit
+		}
+
+		return s, nil
+	}
+
+	if cmd.Save && cmd.Name == "" {
+		return nil, errors.New("missing screen name to save")
+	}
+
+	var limit int
+	if cmd.Limit != nil {
+		limit = *cmd.Limit
+	}
+
+	s, err := screen.NewScreen(cmd.Name, cmd.Expression, cmd.Sort, util.OrderBy(cmd.Order) == util.OrderDescending, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Save {
+		// keep the id when the screen is replaced
+		if old, err := h.screenFinder.FindByName(cmd.Name); err == nil {
+			s.ID = old.ID
+		}
+	}
+
+	return s, nil
+}
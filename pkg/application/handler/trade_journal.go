@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type tradeJournal struct {
+	walletFinder   wallet.Finder
+	tradeFinder    trade.Finder
+	tradePersister trade.Persister
+}
+
+func NewTradeJournal(walletFinder wallet.Finder, tradeFinder trade.Finder, tradePersister trade.Persister) *tradeJournal {
+	return &tradeJournal{
+		walletFinder:   walletFinder,
+		tradeFinder:    tradeFinder,
+		tradePersister: tradePersister,
+	}
+}
+
+func (h *tradeJournal) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.TradeJournal)
+
+	w, err := h.walletFinder.FindByName(cmd.Wallet)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return nil, err
+	}
+
+	t, err := h.tradeFinder.FindByNumber(w.ID, cmd.Trade)
+	if err != nil {
+		if err == mm.ErrNotFound {
+			err = errors.Errorf("trade %d not found in wallet %s", cmd.Trade, w.Name)
+		}
+
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading trade [%d] -> error [%s]",
+			cmd.Trade,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if cmd.Note != nil {
+		t.Journal.Note = *cmd.Note
+	}
+
+	if cmd.Tags != nil {
+		t.Journal.Tags = cmd.Tags
+	}
+
+	if cmd.TargetPrice != nil {
+		t.Journal.TargetPrice = mm.Value{Amount: *cmd.TargetPrice}
+	}
+
+	if cmd.StopLoss != nil {
+		t.Journal.StopLoss = mm.Value{Amount: *cmd.StopLoss}
+	}
+
+	err = h.tradePersister.PersistJournal(w.ID, t)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting journal of trade [%d] -> error [%s]",
+			cmd.Trade,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogolfing/cbus"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type tradeStats struct {
+	walletFinder wallet.Finder
+	tradeFinder  trade.Finder
+	stockFinder  stock.Finder
+}
+
+func NewTradeStats(walletFinder wallet.Finder, tradeFinder trade.Finder, stockFinder stock.Finder) *tradeStats {
+	return &tradeStats{
+		walletFinder: walletFinder,
+		tradeFinder:  tradeFinder,
+		stockFinder:  stockFinder,
+	}
+}
+
+func (h *tradeStats) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.TradeStats)
+
+	w, err := h.walletFinder.FindByName(cmd.Wallet)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return nil, err
+	}
+
+	var from time.Time
+	if cmd.From != "" {
+		from, err = parseOperationDateString(cmd.From)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+	}
+
+	to := time.Now()
+	if cmd.To != "" {
+		to, err = parseOperationDateString(cmd.To)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+	}
+
+	// to is inclusive, the whole day is taken
+	to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	ts, err := h.tradeFinder.FindAllClosedByWallet(w.ID, from, to)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading closed trades from wallet [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return nil, err
+	}
+
+	var tagged []*trade.Trade
+	for _, t := range ts {
+		if cmd.Tag != "" && !t.Journal.HasTag(cmd.Tag) {
+			continue
+		}
+
+		tagged = append(tagged, t)
+	}
+
+	stats := trade.NewStats(tagged)
+
+	for _, t := range []*trade.Trade{stats.Best, stats.Worst} {
+		if t == nil {
+			continue
+		}
+
+		stk, err := h.stockFinder.FindByID(t.Stock.ID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while loading stock of trade [%d] -> error [%s]",
+				t.Number,
+				err,
+			)
+
+			return nil, err
+		}
+
+		t.Stock = stk
+		t.Journal.TargetPrice.Currency = mm.ExchangeCurrency(stk.Exchange.Symbol)
+		t.Journal.StopLoss.Currency = mm.ExchangeCurrency(stk.Exchange.Symbol)
+	}
+
+	return render.TradeStatsOutput{
+		Wallet: w.Name,
+		Tag:    cmd.Tag,
+		From:   from,
+		To:     to,
+		Stats:  stats,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type updateBondPrice struct {
+	stockFinder    stock.Finder
+	stockPersister stock.Persister
+}
+
+func NewUpdateBondPrice(stockFinder stock.Finder, stockPersister stock.Persister) *updateBondPrice {
+	return &updateBondPrice{
+		stockFinder:    stockFinder,
+		stockPersister: stockPersister,
+	}
+}
+
+func (h *updateBondPrice) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.UpdateBondPrice)
+
+	stk, err := h.stockFinder.FindBySymbol(cmd.Symbol)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stock: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if !stk.IsBond() {
+		return nil, errors.Errorf("stock %s is not a bond", stk.Symbol)
+	}
+
+	stk.FixedIncome.CleanPrice = cmd.CleanPrice
+
+	err = h.stockPersister.UpdateCleanPrice(stk)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while updating bond clean price: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return []*stock.Stock{stk}, nil
+}
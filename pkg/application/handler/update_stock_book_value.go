@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type updateStockBookValue struct {
+	stockFinder    stock.Finder
+	stockPersister stock.Persister
+}
+
+func NewUpdateStockBookValue(stockFinder stock.Finder, stockPersister stock.Persister) *updateStockBookValue {
+	return &updateStockBookValue{
+		stockFinder:    stockFinder,
+		stockPersister: stockPersister,
+	}
+}
+
+func (h *updateStockBookValue) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.UpdateStockBookValue)
+
+	stk, err := h.stockFinder.FindBySymbol(cmd.Symbol)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stock: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	stk.BookValue = cmd.BookValue
+
+	err = h.stockPersister.UpdateBookValue(stk)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while updating stock book value: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return stk, nil
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type updateStockISIN struct {
+	stockFinder    stock.Finder
+	stockPersister stock.Persister
+}
+
+func NewUpdateStockISIN(stockFinder stock.Finder, stockPersister stock.Persister) *updateStockISIN {
+	return &updateStockISIN{
+		stockFinder:    stockFinder,
+		stockPersister: stockPersister,
+	}
+}
+
+func (h *updateStockISIN) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	cmd := command.(*appCommand.UpdateStockISIN)
+
+	stk, err := h.stockFinder.FindBySymbol(cmd.Symbol)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stock: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	if err = stk.SetISIN(cmd.ISIN); err != nil {
+		return nil, err
+	}
+
+	err = h.stockPersister.UpdateISIN(stk)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while updating stock isin: symbol [%s] -> error [%s]",
+			cmd.Symbol,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return []*stock.Stock{stk}, nil
+}
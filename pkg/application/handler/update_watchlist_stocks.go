@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type updateWatchlistStocks struct {
+	watchlistFinder watchlist.Finder
+	stockFinder     stock.Finder
+}
+
+func NewUpdateWatchlistStocks(watchlistFinder watchlist.Finder, stockFinder stock.Finder) *updateWatchlistStocks {
+	return &updateWatchlistStocks{
+		watchlistFinder: watchlistFinder,
+		stockFinder:     stockFinder,
+	}
+}
+
+// Handle returns the stocks of the watchlist for the listeners to update their price or dividends
+func (h *updateWatchlistStocks) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	var wName string
+
+	switch cmd := command.(type) {
+	case *appCommand.UpdateWatchlistStocksPrice:
+		wName = cmd.Watchlist
+	case *appCommand.UpdateWatchlistStocksDividend:
+		wName = cmd.Watchlist
+	default:
+		logger.FromContext(ctx).Error(
+			"updateWatchlistStocks: Command not supported",
+		)
+
+		return nil, errors.New("command not supported")
+	}
+
+	if wName == "" {
+		logger.FromContext(ctx).Error("An error happen while loading watchlist -> error [watchlist can not be empty]")
+
+		return nil, errors.New("missing watchlist name")
+	}
+
+	stks, err := findWatchlistStocks(h.watchlistFinder, h.stockFinder, wName)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading stocks watchlist [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	return stks, nil
+}
+
+// findWatchlistStocks returns the stocks of the watchlist fully loaded
+func findWatchlistStocks(watchlistFinder watchlist.Finder, stockFinder stock.Finder, name string) ([]*stock.Stock, error) {
+	w, err := watchlistFinder.FindByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "find watchlist %q", name)
+	}
+
+	var stks []*stock.Stock
+	for ID := range w.Stocks {
+		stk, err := stockFinder.FindByID(ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find stock %q", ID)
+		}
+
+		stks = append(stks, stk)
+	}
+
+	return stks, nil
+}
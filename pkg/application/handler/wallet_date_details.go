@@ -21,6 +21,7 @@ import (
 	"github.com/dohernandez/market-manager/pkg/application/util"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
@@ -40,19 +41,19 @@ const getPriceConcurrency = 10
 
 func NewWalletDateDetails(
 	walletFinder wallet.Finder,
+	groupFinder group.Finder,
 	stockFinder stock.Finder,
 	dividendFinder dividend.Finder,
 	ccClient *cc.Client,
-	retention float64,
 	bankAccountFinder bank.Finder,
 ) *walletDateDetails {
 	return &walletDateDetails{
 		walletDetails: &walletDetails{
 			walletFinder:   walletFinder,
+			groupFinder:    groupFinder,
 			stockFinder:    stockFinder,
 			dividendFinder: dividendFinder,
 			ccClient:       ccClient,
-			retention:      retention,
 		},
 		bankAccountFinder: bankAccountFinder,
 	}
@@ -62,21 +63,38 @@ func (h *walletDateDetails) Handle(ctx context.Context, command cbus.Command) (r
 	walletDateDetails := command.(*appCommand.WalletDateDetails)
 
 	wName := walletDateDetails.Wallet
-	if wName == "" {
+	if wName == "" && walletDateDetails.Group == "" {
 		logger.FromContext(ctx).Error("An error happen while loading wallet -> error [wallet can not be empty]")
 
 		return nil, errors.New("missing wallet name")
 	}
 
-	date := parseOperationDateString(walletDateDetails.Date)
+	date, err := parseOperationDateString(walletDateDetails.Date)
+	if err != nil {
+		logger.FromContext(ctx).Error(err)
 
-	w, err := h.loadWalletWithWalletItemsAndWalletTradesAtDate(
-		wName,
-		date,
-		walletDateDetails.TransferPath,
-		walletDateDetails.OperationPath,
-		walletDateDetails.Excludes,
-	)
+		return nil, err
+	}
+
+	load := func(name string) (*wallet.Wallet, error) {
+		return h.loadWalletWithWalletItemsAndWalletTradesAtDate(
+			name,
+			date,
+			walletDateDetails.TransferPath,
+			walletDateDetails.OperationPath,
+			walletDateDetails.TransferMapping,
+			walletDateDetails.OperationMapping,
+			walletDateDetails.Excludes,
+		)
+	}
+
+	var w *wallet.Wallet
+	if walletDateDetails.Group != "" {
+		wName = walletDateDetails.Group
+		w, err = loadGroupWallet(h.groupFinder, wName, load)
+	} else {
+		w, err = load(wName)
+	}
 	if err != nil {
 		logger.FromContext(ctx).Errorf(
 			"An error happen while loading wallet [%s] -> error [%s]",
@@ -122,6 +140,8 @@ func (h *walletDateDetails) loadWalletWithWalletItemsAndWalletTradesAtDate(
 	date time.Time,
 	transferPath,
 	operationPath string,
+	transferMapping,
+	operationMapping *util.Mapping,
 	excludes []string,
 ) (*wallet.Wallet, error) {
 	w, err := h.walletFinder.FindByName(name)
@@ -135,12 +155,13 @@ func (h *walletDateDetails) loadWalletWithWalletItemsAndWalletTradesAtDate(
 	}
 
 	wd := wallet.NewWallet(w.Name, w.URL)
+	wd.Broker = w.Broker
 
 	for _, b := range w.BankAccounts {
 		wd.AddBankAccount(b)
 	}
 
-	transfers, err := h.loadTransfersUntilDate(transferPath, date)
+	transfers, err := h.loadTransfersUntilDate(transferPath, transferMapping, date)
 	if err != nil {
 		return nil, errors.Wrap(err, "loading transfer")
 	}
@@ -148,26 +169,47 @@ func (h *walletDateDetails) loadWalletWithWalletItemsAndWalletTradesAtDate(
 	for _, t := range transfers {
 		for _, b := range wd.BankAccounts {
 			if t.From.ID == b.ID {
-				wd.DecreaseInvestment(t.Amount)
+				if err := wd.TransferOut(t); err != nil {
+					return nil, errors.Wrap(err, "booking transfer")
+				}
 
 				break
 			}
 
 			if t.To.ID == b.ID {
-				wd.IncreaseInvestment(t.Amount)
+				if err := wd.TransferIn(t); err != nil {
+					return nil, errors.Wrap(err, "booking transfer")
+				}
 
 				break
 			}
 		}
 	}
 
-	trades, ops, err := h.loadOperationUntilDate(operationPath, date)
+	trades, ops, err := h.loadOperationUntilDate(operationPath, operationMapping, date)
 	if err != nil {
 		return nil, errors.Wrap(err, "loading operation")
 	}
 
+	// Dividends are allocated to the trades by the stocks held at the ex-date
+	stockDividends := map[uuid.UUID][]dividend.StockDividend{}
+
 	for _, o := range ops {
-		if o.Action == operation.Buy || o.Action == operation.Sell || o.Action == operation.Dividend {
+		if o.Action.IsIncome() {
+			ds, ok := stockDividends[o.Stock.ID]
+			if !ok {
+				ds, err = h.dividendFinder.FindAllFormStock(o.Stock.ID)
+				if err != nil {
+					return nil, errors.Wrapf(err, "loading dividends of stock %s", o.Stock.Symbol)
+				}
+
+				stockDividends[o.Stock.ID] = ds
+			}
+
+			o.Stock.Dividends = ds
+		}
+
+		if o.Action == operation.Buy || o.Action.IsDisposal() || o.Action.IsIncome() {
 			exclude := false
 			for _, symbol := range excludes {
 				if symbol == o.Stock.Symbol {
@@ -188,7 +230,9 @@ func (h *walletDateDetails) loadWalletWithWalletItemsAndWalletTradesAtDate(
 		if ok {
 			n, _ := strconv.Atoi(nTrade)
 			wd.AddTrade(n, o)
-		} else if o.Action == operation.Dividend {
+		} else if o.Action == operation.Buy || o.Action.IsDisposal() {
+			wd.AssignTrade(o, wallet.FIFO)
+		} else if o.Action.IsIncome() {
 			wd.AddTrade(0, o)
 		}
 	}
@@ -196,7 +240,7 @@ func (h *walletDateDetails) loadWalletWithWalletItemsAndWalletTradesAtDate(
 	return wd, nil
 }
 
-func (h *walletDateDetails) loadTransfersUntilDate(importPath string, date time.Time) ([]*transfer.Transfer, error) {
+func (h *walletDateDetails) loadTransfersUntilDate(importPath string, mapping *util.Mapping, date time.Time) ([]*transfer.Transfer, error) {
 	var filePaths []string
 
 	filepath.Walk(importPath, func(path string, info os.FileInfo, err error) error {
@@ -214,7 +258,7 @@ func (h *walletDateDetails) loadTransfersUntilDate(importPath string, date time.
 	var transfers []*transfer.Transfer
 
 	for _, filePath := range filePaths {
-		r := util.NewCsvReader(filePath)
+		r := util.NewCsvMappingReader(filePath, mapping)
 
 		r.Open()
 
@@ -228,7 +272,7 @@ func (h *walletDateDetails) loadTransfersUntilDate(importPath string, date time.
 				panic(err)
 			}
 
-			t, err := createTransferFromLine(line, h.bankAccountFinder)
+			t, err := createTransferFromLine(line, h.bankAccountFinder, mapping)
 			if err != nil {
 				r.Close()
 
@@ -248,7 +292,7 @@ func (h *walletDateDetails) loadTransfersUntilDate(importPath string, date time.
 	return transfers, nil
 }
 
-func (h *walletDateDetails) loadOperationUntilDate(importPath string, date time.Time) (map[uuid.UUID]string, []*operation.Operation, error) {
+func (h *walletDateDetails) loadOperationUntilDate(importPath string, mapping *util.Mapping, date time.Time) (map[uuid.UUID]string, []*operation.Operation, error) {
 	var filePaths []string
 
 	filepath.Walk(importPath, func(path string, info os.FileInfo, err error) error {
@@ -267,7 +311,7 @@ func (h *walletDateDetails) loadOperationUntilDate(importPath string, date time.
 	trades := map[uuid.UUID]string{}
 
 	for _, filePath := range filePaths {
-		r := util.NewCsvReader(filePath)
+		r := util.NewCsvMappingReader(filePath, mapping)
 
 		r.Open()
 
@@ -281,7 +325,7 @@ func (h *walletDateDetails) loadOperationUntilDate(importPath string, date time.
 				panic(err)
 			}
 
-			o, err := createOperationFromLine(line, h.stockFinder)
+			o, err := createOperationFromLine(line, h.stockFinder, mapping)
 
 			if err != nil {
 				r.Close()
@@ -12,6 +12,8 @@ import (
 	cc "github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
 	mm "github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
@@ -21,26 +23,26 @@ import (
 type (
 	walletDetails struct {
 		walletFinder   wallet.Finder
+		groupFinder    group.Finder
 		stockFinder    stock.Finder
 		dividendFinder dividend.Finder
 		ccClient       *cc.Client
-		retention      float64
 	}
 )
 
 func NewWalletDetails(
 	walletFinder wallet.Finder,
+	groupFinder group.Finder,
 	stockFinder stock.Finder,
 	dividendFinder dividend.Finder,
 	ccClient *cc.Client,
-	retention float64,
 ) *walletDetails {
 	return &walletDetails{
 		walletFinder:   walletFinder,
+		groupFinder:    groupFinder,
 		stockFinder:    stockFinder,
 		dividendFinder: dividendFinder,
 		ccClient:       ccClient,
-		retention:      retention,
 	}
 }
 
@@ -48,7 +50,8 @@ func (h *walletDetails) Handle(ctx context.Context, command cbus.Command) (resul
 	walletDetails := command.(*appCommand.WalletDetails)
 
 	wName := walletDetails.Wallet
-	if wName == "" {
+	gName := walletDetails.Group
+	if wName == "" && gName == "" {
 		logger.FromContext(ctx).Error("An error happen while loading wallet -> error [wallet can not be empty]")
 
 		return nil, errors.New("missing wallet name")
@@ -56,11 +59,18 @@ func (h *walletDetails) Handle(ctx context.Context, command cbus.Command) (resul
 
 	sells := walletDetails.Sells
 	buys := walletDetails.Buys
-	commissions := walletDetails.Commissions
 	status := walletDetails.Status
 	increaseInvestment := walletDetails.IncreaseInvestment
 
-	w, err := h.loadWalletWithWalletItemsAndWalletTrades(wName, status)
+	var w *wallet.Wallet
+	if gName != "" {
+		wName = gName
+		w, err = loadGroupWallet(h.groupFinder, gName, func(name string) (*wallet.Wallet, error) {
+			return h.loadWalletWithWalletItemsAndWalletTrades(name, status)
+		})
+	} else {
+		w, err = h.loadWalletWithWalletItemsAndWalletTrades(wName, status)
+	}
 	if err != nil {
 		logger.FromContext(ctx).Errorf(
 			"An error happen while loading wallet [%s] -> error [%s]",
@@ -71,10 +81,12 @@ func (h *walletDetails) Handle(ctx context.Context, command cbus.Command) (resul
 		return nil, err
 	}
 
-	w.IncreaseInvestment(mm.ValueEuroFromString(increaseInvestment))
+	if err := w.IncreaseInvestment(mm.ValueEuroFromString(increaseInvestment)); err != nil {
+		return nil, err
+	}
 
 	if len(sells) > 0 {
-		if err := h.addSellsOperationToWallet(w, sells, commissions); err != nil {
+		if err := h.addSellsOperationToWallet(w, sells); err != nil {
 			logger.FromContext(ctx).Errorf(
 				"An error happen while loading sell stock symbol [%s] -> error [%s]",
 				wName,
@@ -86,7 +98,7 @@ func (h *walletDetails) Handle(ctx context.Context, command cbus.Command) (resul
 	}
 
 	if len(buys) > 0 {
-		if err := h.addBuysOperationToWallet(w, buys, commissions); err != nil {
+		if err := h.addBuysOperationToWallet(w, buys); err != nil {
 			logger.FromContext(ctx).Errorf(
 				"An error happen while loading buys stock symbol [%s] -> error [%s]",
 				wName,
@@ -188,7 +200,7 @@ func (h *walletDetails) loadWalletWithWalletItemsAndWalletTrades(name string, st
 	return w, err
 }
 
-func (h *walletDetails) addSellsOperationToWallet(w *wallet.Wallet, sells map[string]int, commissions map[string]appCommand.Commission) error {
+func (h *walletDetails) addSellsOperationToWallet(w *wallet.Wallet, sells map[string]float64) error {
 	for symbol, amount := range sells {
 		stk, err := h.stockFinder.FindBySymbol(symbol)
 		if err != nil {
@@ -205,7 +217,7 @@ func (h *walletDetails) addSellsOperationToWallet(w *wallet.Wallet, sells map[st
 			capitalRate = 1
 		}
 
-		o := h.createOperation(stk, amount, operation.Sell, capitalRate, commissions)
+		o := h.createOperation(stk, amount, operation.Sell, capitalRate, h.walletCommissions(w))
 
 		w.AddOperation(o)
 	}
@@ -215,10 +227,10 @@ func (h *walletDetails) addSellsOperationToWallet(w *wallet.Wallet, sells map[st
 
 func (h *walletDetails) createOperation(
 	stk *stock.Stock,
-	amount int,
+	amount float64,
 	action operation.Action,
 	capitalRate float64,
-	commissions map[string]appCommand.Commission,
+	commissions map[string]broker.Commission,
 ) *operation.Operation {
 	pChange := mm.Value{
 		Amount:   capitalRate,
@@ -228,24 +240,15 @@ func (h *walletDetails) createOperation(
 	now := time.Now()
 
 	oValue := mm.Value{
-		Amount:   stk.Value.Amount * float64(amount) / pChange.Amount,
+		Amount:   stk.Value.Amount * amount / pChange.Amount,
 		Currency: mm.Euro,
 	}
 
 	var commission, pChangeCommission mm.Value
 	marketCommission, ok := commissions[stk.Exchange.Symbol]
 	if ok {
-		pChangeCommission.Amount = marketCommission.ChangeCommission.Amount
-		pChangeCommission.Currency = mm.Currency(marketCommission.ChangeCommission.Currency)
-
-		if stk.Exchange.Symbol == "NASDAQ" || stk.Exchange.Symbol == "NYSE" {
-			commission.Amount = marketCommission.Commission.Base.Amount
-			extra := marketCommission.Commission.Extra.Amount * float64(amount) / pChange.Amount
-
-			commission = commission.Increase(mm.Value{Amount: extra, Currency: mm.Euro})
-		} else {
-			panic("Commission to apply not defined")
-		}
+		pChangeCommission = marketCommission.Change
+		commission = marketCommission.Calculate(amount, oValue, pChange.Amount)
 	}
 
 	o := operation.NewOperation(now, stk, action, amount, stk.Value, pChange, pChangeCommission, oValue, commission)
@@ -253,7 +256,37 @@ func (h *walletDetails) createOperation(
 	return o
 }
 
-func (h *walletDetails) addBuysOperationToWallet(w *wallet.Wallet, buys map[string]int, commissions map[string]appCommand.Commission) error {
+// walletCommissions returns the commissions to apply by exchange, the ones of the broker where the wallet is hold.
+// None apply to the wallets without broker
+func (h *walletDetails) walletCommissions(w *wallet.Wallet) map[string]broker.Commission {
+	if w.Broker != nil {
+		return w.Broker.Commissions
+	}
+
+	return map[string]broker.Commission{}
+}
+
+// walletConnectivity returns the yearly connectivity fee of the broker where the wallet is hold, none for
+// the wallets without broker
+func (h *walletDetails) walletConnectivity(w *wallet.Wallet) mm.Value {
+	if w.Broker != nil {
+		return w.Broker.Connectivity
+	}
+
+	return mm.Value{Currency: mm.Euro}
+}
+
+// walletRetention returns the default dividend retention of the broker where the wallet is hold, none for
+// the wallets without broker
+func (h *walletDetails) walletRetention(w *wallet.Wallet) float64 {
+	if w.Broker != nil {
+		return w.Broker.Retention
+	}
+
+	return 0
+}
+
+func (h *walletDetails) addBuysOperationToWallet(w *wallet.Wallet, buys map[string]float64) error {
 	for symbol, amount := range buys {
 		stk, err := h.stockFinder.FindBySymbol(symbol)
 		if err != nil {
@@ -270,7 +303,7 @@ func (h *walletDetails) addBuysOperationToWallet(w *wallet.Wallet, buys map[stri
 			capitalRate = 1
 		}
 
-		o := h.createOperation(stk, amount, operation.Buy, capitalRate, commissions)
+		o := h.createOperation(stk, amount, operation.Buy, capitalRate, h.walletCommissions(w))
 
 		w.AddOperation(o)
 	}
@@ -284,7 +317,8 @@ func (h *walletDetails) dividendsProjectedDate(w *wallet.Wallet, date time.Time)
 	year := date.Year()
 
 	for _, item := range w.Items {
-		ds, err := h.dividendFinder.FindAllDividendsFromThisYearOn(item.Stock.ID, year)
+		// the whole history is loaded, the dividend growth since the first buy needs it
+		ds, err := h.dividendFinder.FindAllFormStock(item.Stock.ID)
 		if err != nil {
 			if err != mm.ErrNotFound {
 				return nil, errors.Wrapf(
@@ -308,7 +342,7 @@ func (h *walletDetails) dividendsProjectedDate(w *wallet.Wallet, date time.Time)
 				if d.ExDate.Month() == month && d.ExDate.Year() == year {
 					grossDividend := d.Amount.Amount * float64(item.Amount)
 
-					ret := h.retention * grossDividend / 100
+					ret := h.walletRetention(w) * grossDividend / 100
 
 					if item.DividendRetention.Amount > 0 {
 						ret = item.DividendRetention.Amount * float64(item.Amount)
@@ -360,11 +394,18 @@ func (h *walletDetails) dividendsProjectedDate(w *wallet.Wallet, date time.Time)
 }
 
 func (h *walletDetails) walletDetailOutput(w *wallet.Wallet, dividendsProjected []render.WalletDividendProjected) render.WalletDetailsOutput {
-	wDProjectedYear := w.DividendNetProjectedNextYear(h.retention)
+	wDProjectedYear := w.DividendNetProjectedNextYear(h.walletRetention(w))
 	wDProjectedYear = wDProjectedYear.Increase(w.Dividend)
 	dividendYearYield := wDProjectedYear.Amount * 100 / w.Invested.Amount
+	var brokerName string
+	if w.Broker != nil {
+		brokerName = w.Broker.Name
+	}
+
 	wDetailsOutput := render.WalletDetailsOutput{
 		WalletOutput: render.WalletOutput{
+			Wallet:                w.Name,
+			Broker:                brokerName,
 			Capital:               w.Capital,
 			Invested:              w.Invested,
 			Funds:                 w.Funds,
@@ -377,6 +418,7 @@ func (h *walletDetails) walletDetailOutput(w *wallet.Wallet, dividendsProjected
 			DividendProjected:     dividendsProjected,
 			DividendYearProjected: wDProjectedYear,
 			DividendYearYield:     dividendYearYield,
+			ConnectivityYear:      h.walletConnectivity(w),
 			Connection:            w.Connection,
 			Interest:              w.Interest,
 			Commission:            w.Commission,
@@ -442,7 +484,7 @@ func (h *walletDetails) walletStocksOutput(w *wallet.Wallet, status operation.St
 					Currency: mm.Dollar,
 				}
 
-				retention := h.retention * dividendToPayGross.Amount / 100
+				retention := h.walletRetention(w) * dividendToPayGross.Amount / 100
 				if item.DividendRetention.Amount > 0 {
 					retention = float64(item.Amount) * item.DividendRetention.Amount
 				}
@@ -529,6 +571,9 @@ func (h *walletDetails) walletStocksOutput(w *wallet.Wallet, status operation.St
 			Change:             item.Change(),
 			WAPrice:            wAPrice,
 			WADYield:           wADYield,
+			YieldOnCost:        item.YieldOnCost(now),
+			DividendGrowth:     item.DividendGrowth(now),
+			DividendOnCost:     item.DividendOnCost(),
 			Trades:             sTrades,
 		})
 	}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	cc "github.com/dohernandez/market-manager/pkg/infrastructure/client/currency-converter"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/etf"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type walletExposure struct {
+	walletFinder wallet.Finder
+	groupFinder  group.Finder
+	stockFinder  stock.Finder
+	etfFinder    etf.Finder
+	ccClient     *cc.Client
+}
+
+func NewWalletExposure(
+	walletFinder wallet.Finder,
+	groupFinder group.Finder,
+	stockFinder stock.Finder,
+	etfFinder etf.Finder,
+	ccClient *cc.Client,
+) *walletExposure {
+	return &walletExposure{
+		walletFinder: walletFinder,
+		groupFinder:  groupFinder,
+		stockFinder:  stockFinder,
+		etfFinder:    etfFinder,
+		ccClient:     ccClient,
+	}
+}
+
+func (h *walletExposure) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	walletExposure := command.(*appCommand.WalletExposure)
+
+	wName := walletExposure.Wallet
+	if wName == "" && walletExposure.Group == "" {
+		logger.FromContext(ctx).Error("An error happen while loading wallet -> error [wallet can not be empty]")
+
+		return nil, errors.New("missing wallet name")
+	}
+
+	var w *wallet.Wallet
+	if walletExposure.Group != "" {
+		wName = walletExposure.Group
+		w, err = loadGroupWallet(h.groupFinder, wName, func(name string) (*wallet.Wallet, error) {
+			return loadWalletWithActiveWalletItems(h.walletFinder, h.stockFinder, name)
+		})
+	} else {
+		w, err = loadWalletWithActiveWalletItems(h.walletFinder, h.stockFinder, wName)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	currencyConverter, err := h.ccClient.Converter.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	w.SetCapitalRate(wallet.CapitalRate{
+		EURUSD: currencyConverter.EURUSD,
+		EURCAD: currencyConverter.EURCAD,
+	})
+
+	eOutput := render.WalletExposureOutput{
+		Wallet: w.Name,
+	}
+
+	holdings := map[uuid.UUID]*etf.Holdings{}
+	stks := map[uuid.UUID]*stock.Stock{}
+
+	for _, i := range w.Items {
+		if !i.Stock.IsETF() {
+			continue
+		}
+
+		hs, err := h.loadHoldings(i.Stock, stks)
+		if err != nil {
+			if err != mm.ErrNotFound {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while loading etf [%s] constituents -> error [%s]",
+					i.Stock.Symbol,
+					err,
+				)
+
+				return nil, err
+			}
+
+			// Without constituents the ETF is reported as a single position
+			eOutput.NotLookedThrough = append(eOutput.NotLookedThrough, i.Stock.Name)
+
+			continue
+		}
+
+		holdings[i.Stock.ID] = hs
+	}
+
+	e := w.Exposure(holdings)
+
+	eOutput.Capital = e.Capital
+	eOutput.Sectors = exposureOutput(e.Sectors, e.Capital)
+	eOutput.Countries = exposureOutput(e.Countries, e.Capital)
+	eOutput.Stocks = exposureOutput(e.Stocks, e.Capital)
+
+	sort.Strings(eOutput.NotLookedThrough)
+
+	return eOutput, nil
+}
+
+// loadHoldings loads the constituents of the ETF along with the stocks they are linked to
+func (h *walletExposure) loadHoldings(stk *stock.Stock, stks map[uuid.UUID]*stock.Stock) (*etf.Holdings, error) {
+	holdings, err := h.etfFinder.FindByETF(stk)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range holdings.Constituents {
+		if c.Stock == nil {
+			continue
+		}
+
+		cStk, ok := stks[c.Stock.ID]
+		if !ok {
+			cStk, err = h.stockFinder.FindByID(c.Stock.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			stks[cStk.ID] = cStk
+		}
+
+		c.Stock = cStk
+	}
+
+	return holdings, nil
+}
+
+func exposureOutput(group map[string]mm.Value, capital mm.Value) []*render.ExposureOutput {
+	var esOutput []*render.ExposureOutput
+
+	for name, v := range group {
+		var percentage float64
+		if capital.Amount > 0 {
+			percentage = v.Amount * 100 / capital.Amount
+		}
+
+		esOutput = append(esOutput, &render.ExposureOutput{
+			Name:       name,
+			Capital:    v,
+			Percentage: percentage,
+		})
+	}
+
+	sort.Slice(esOutput, func(i, j int) bool {
+		if esOutput[i].Capital.Amount == esOutput[j].Capital.Amount {
+			return esOutput[i].Name < esOutput[j].Name
+		}
+
+		return esOutput[i].Capital.Amount > esOutput[j].Capital.Amount
+	})
+
+	return esOutput
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogolfing/cbus"
+	"github.com/pkg/errors"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/application/render"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/ledger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type walletLedger struct {
+	walletFinder wallet.Finder
+	ledgerFinder ledger.Finder
+}
+
+func NewWalletLedger(walletFinder wallet.Finder, ledgerFinder ledger.Finder) *walletLedger {
+	return &walletLedger{
+		walletFinder: walletFinder,
+		ledgerFinder: ledgerFinder,
+	}
+}
+
+func (h *walletLedger) Handle(ctx context.Context, command cbus.Command) (result interface{}, err error) {
+	walletLedger := command.(*appCommand.WalletLedger)
+
+	wName := walletLedger.Wallet
+	if wName == "" {
+		logger.FromContext(ctx).Error("An error happen while loading wallet -> error [wallet can not be empty]")
+
+		return nil, errors.New("missing wallet name")
+	}
+
+	w, err := h.walletFinder.FindByName(wName)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	var from time.Time
+	if walletLedger.From != "" {
+		from, err = parseOperationDateString(walletLedger.From)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+	}
+
+	to := time.Now()
+	if walletLedger.To != "" {
+		to, err = parseOperationDateString(walletLedger.To)
+		if err != nil {
+			logger.FromContext(ctx).Error(err)
+
+			return nil, err
+		}
+	}
+
+	// to is inclusive, the whole day is taken
+	to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	es, err := h.ledgerFinder.FindAllByWalletAndDateRange(w.ID, from, to)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading ledger from wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
+
+		return nil, err
+	}
+
+	lOutput := render.WalletLedgerOutput{
+		Wallet:  w.Name,
+		From:    from,
+		To:      to,
+		Funds:   w.Funds,
+		Opening: mm.Value{Currency: mm.Euro},
+		Closing: mm.Value{Currency: mm.Euro},
+		// The funds are only comparable with the balance of the ledger up to today
+		CompareFunds: walletLedger.To == "",
+	}
+
+	if len(es) > 0 {
+		first := es[0]
+		lOutput.Opening = first.Balance.Decrease(first.Amount)
+		lOutput.Closing = es[len(es)-1].Balance
+	}
+
+	for _, e := range es {
+		lOutput.Entries = append(lOutput.Entries, &render.LedgerEntryOutput{
+			Date:        e.Date,
+			Kind:        string(e.Kind),
+			Description: e.Description,
+			Amount:      e.Amount,
+			Balance:     e.Balance,
+			OperationID: e.OperationID,
+		})
+	}
+
+	return lOutput, nil
+}
@@ -14,22 +14,34 @@ import (
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
 )
 
 type addWalletOperation struct {
-	stockFinder     stock.Finder
-	walletFinder    wallet.Finder
-	walletPersister wallet.Persister
+	stockFinder         stock.Finder
+	stockDividendFinder dividend.Finder
+	walletFinder        wallet.Finder
+	walletPersister     wallet.Persister
+	operationFinder     operation.Finder
 
 	ccClient *cc.Client
 }
 
-func NewAddWalletOperation(stockFinder stock.Finder, walletFinder wallet.Finder, walletPersister wallet.Persister, ccClient *cc.Client) *addWalletOperation {
+func NewAddWalletOperation(
+	stockFinder stock.Finder,
+	stockDividendFinder dividend.Finder,
+	walletFinder wallet.Finder,
+	walletPersister wallet.Persister,
+	operationFinder operation.Finder,
+	ccClient *cc.Client,
+) *addWalletOperation {
 	return &addWalletOperation{
-		stockFinder:     stockFinder,
-		walletFinder:    walletFinder,
-		walletPersister: walletPersister,
-		ccClient:        ccClient,
+		stockFinder:         stockFinder,
+		stockDividendFinder: stockDividendFinder,
+		walletFinder:        walletFinder,
+		walletPersister:     walletPersister,
+		operationFinder:     operationFinder,
+		ccClient:            ccClient,
 	}
 }
 
@@ -41,7 +53,11 @@ func (l *addWalletOperation) OnEvent(ctx context.Context, event cbus.Event) {
 		return
 	}
 
-	var wName string
+	var (
+		wName  string
+		match  string
+		broker *appCommand.ImportBrokerOperation
+	)
 	trades := map[uuid.UUID]string{}
 
 	switch cmd := event.Command.(type) {
@@ -53,11 +69,22 @@ func (l *addWalletOperation) OnEvent(ctx context.Context, event cbus.Event) {
 	case *appCommand.AddSellOperation:
 		wName = cmd.Wallet
 		trades[ops[0].ID] = cmd.Trade
+		match = cmd.TradeMatch
 	case *appCommand.ImportOperation:
 		wName = cmd.Wallet
 		trades = cmd.Trades
+		match = cmd.TradeMatch
+	case *appCommand.ImportBrokerOperation:
+		wName = cmd.Wallet
+		match = cmd.TradeMatch
+		broker = cmd
 	case *appCommand.AddInterestOperation:
 		wName = cmd.Wallet
+	case *appCommand.AddCouponOperation:
+		wName = cmd.Wallet
+	case *appCommand.AddRedemptionOperation:
+		wName = cmd.Wallet
+		trades[ops[0].ID] = cmd.Trade
 	default:
 		logger.FromContext(ctx).Error(
 			"addWalletOperation: Operation action not supported",
@@ -86,28 +113,30 @@ func (l *addWalletOperation) OnEvent(ctx context.Context, event cbus.Event) {
 		return
 	}
 
+	var booked []*operation.Operation
+
+	tradeMatch, _ := wallet.ParseTradeMatch(match)
+
 	for _, o := range ops {
 		err = w.AddOperation(o)
-		if err != nil {
-			if err == mm.ErrCanNotAddOperation {
-				err = l.walletFinder.LoadItemByStock(w, o.Stock)
-				if err != nil {
-					logger.FromContext(ctx).Errorf(
-						"An error happen while loading wallet item from stock operation [%s:%s] -> error [%s]",
-						o.Action,
-						o.Stock.Symbol,
-						err,
-					)
-
-					return
-				}
-
-				err = w.AddOperation(o)
-				if err == nil {
-					continue
-				}
+		if err == mm.ErrCanNotAddOperation {
+			// the item of the stock is not active, it is loaded and the operation added again
+			err = l.walletFinder.LoadItemByStock(w, o.Stock)
+			if err != nil {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while loading wallet item from stock operation [%s:%s] -> error [%s]",
+					o.Action,
+					o.Stock.Symbol,
+					err,
+				)
+
+				return
 			}
 
+			err = w.AddOperation(o)
+		}
+
+		if err != nil {
 			logger.FromContext(ctx).Warnf(
 				"An error happen while adding operation [%s] stock [%s] to wallet name [%s] -> error [%s]",
 				o.Action,
@@ -119,15 +148,42 @@ func (l *addWalletOperation) OnEvent(ctx context.Context, event cbus.Event) {
 			continue
 		}
 
-		nTrade, ok := trades[o.ID]
-		if ok {
+		booked = append(booked, o)
+
+		nTrade := trades[o.ID]
+		if nTrade != "" {
 			n, _ := strconv.Atoi(nTrade)
 			w.AddTrade(n, o)
-		} else if o.Action == operation.Dividend {
+		} else if o.Action == operation.Buy || o.Action.IsDisposal() {
+			err = w.AssignTrade(o, tradeMatch)
+			if err != nil {
+				logger.FromContext(ctx).Warnf(
+					"An error happen while assigning operation [%s] stock [%s] to a trade -> error [%s]",
+					o.Action,
+					o.Stock.Symbol,
+					err,
+				)
+			}
+		} else if o.Action.IsIncome() {
+			err = l.loadDividendAllocation(w, o)
+			if err != nil {
+				logger.FromContext(ctx).Warnf(
+					"An error happen while loading trades holding stock [%s] at dividend ex-date -> error [%s]",
+					o.Stock.Symbol,
+					err,
+				)
+			}
+
 			w.AddTrade(0, o)
 		}
 	}
 
+	if broker != nil {
+		l.persistBrokerImport(ctx, w, broker, booked)
+
+		return
+	}
+
 	err = l.walletPersister.PersistOperations(w)
 	if err != nil {
 		logger.FromContext(ctx).Errorf(
@@ -137,6 +193,105 @@ func (l *addWalletOperation) OnEvent(ctx context.Context, event cbus.Event) {
 	}
 }
 
+// persistBrokerImport books the operations added to the wallet along with the transfers of the deposits and
+// withdrawals of the broker and the ids of their broker transactions. Only the ids of the operations added are
+// saved, so the ones left out are imported again next time
+func (l *addWalletOperation) persistBrokerImport(
+	ctx context.Context,
+	w *wallet.Wallet,
+	cmd *appCommand.ImportBrokerOperation,
+	booked []*operation.Operation,
+) {
+	var ids []string
+
+	for _, o := range booked {
+		ids = append(ids, cmd.TransactionIDs[o.ID]...)
+	}
+
+	if len(cmd.Transfers) > 0 {
+		err := l.walletFinder.LoadBankAccounts(w)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while loading wallet [%s] bank accounts -> error [%s]",
+				w.Name,
+				err,
+			)
+
+			return
+		}
+	}
+
+	for _, t := range cmd.Transfers {
+		var err error
+		if _, ok := w.BankAccounts[t.To.ID]; ok {
+			err = w.TransferIn(t)
+		} else {
+			err = w.TransferOut(t)
+		}
+
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while booking transfer from [%s] to [%s] -> error [%s]",
+				t.From.Alias,
+				t.To.Alias,
+				err,
+			)
+
+			return
+		}
+
+		ids = append(ids, cmd.TransactionIDs[t.ID]...)
+	}
+
+	err := l.walletPersister.PersistBrokerImport(w, cmd.Transfers, ids)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting broker import -> error [%s]",
+			err,
+		)
+	}
+}
+
+// loadDividendAllocation loads what is needed to allocate the dividend to the trades by the stocks held
+// at the ex-date: the stock dividends and all the trades of the stock along with their stored operations
+func (l *addWalletOperation) loadDividendAllocation(w *wallet.Wallet, o *operation.Operation) error {
+	ds, err := l.stockDividendFinder.FindAllFormStock(o.Stock.ID)
+	if err != nil {
+		return err
+	}
+
+	o.Stock.Dividends = ds
+
+	item := w.Items[o.Stock.ID]
+
+	err = l.walletFinder.LoadItemTrades(w, item)
+	if err != nil {
+		return err
+	}
+
+	ops, err := l.operationFinder.FindAllByWalletAndStock(w.ID, o.Stock.ID)
+	if err != nil {
+		return err
+	}
+
+	numbers, err := l.operationFinder.FindTradeNumbersByWallet(w.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, so := range ops {
+		t, ok := item.Trades[numbers[so.ID]]
+		if !ok || t.HasOperation(so.ID) {
+			continue
+		}
+
+		so.Stock = item.Stock
+		t.Operations = append(t.Operations, so)
+	}
+
+	return nil
+}
+
 func (l *addWalletOperation) loadWalletWithActiveWalletItemsAndActiveWalletTrades(name string) (*wallet.Wallet, error) {
 	w, err := l.walletFinder.FindByName(name)
 	if err != nil {
@@ -2,6 +2,7 @@ package listener
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strconv"
 
@@ -21,45 +22,147 @@ func getResourceNumberFromFilePath(fileName string) int {
 	return n
 }
 
-func getCsvWriterFromResourceImport(resourceStorage util.ResourceStorage, resource, importPath, filesGroup string) (*util.CsvWriter, error) {
-	r, err := resourceStorage.FindLastByResourceAndFilesGroup(resource, filesGroup)
+// getCsvWriterFromResourceImport returns the writer of the last file of the files group, or of a new file when
+// the last one is full. The new file is imported with the mapping of the last one, its header is written first
+func getCsvWriterFromResourceImport(
+	resourceStorage util.ResourceStorage,
+	r util.Resource,
+	importPath,
+	filesGroup string,
+	mapping *util.Mapping,
+) (*util.CsvWriter, error) {
+	filePath := fmt.Sprintf("%s/%s", importPath, r.FileName)
+
+	header, lines, err := util.ReadRecords(filePath, mapping)
 	if err != nil {
-		return nil, errors.Wrapf(err, "loading latest %q resource import", resource)
+		return nil, errors.Wrapf(err, "loading previous lines from resource %q", filePath)
 	}
 
-	var lines [][]string
+	if len(lines) < linePerFile {
+		return util.NewCsvMappingWriter(filePath, mapping), nil
+	}
 
-	filePath := fmt.Sprintf("%s/%s", importPath, r.FileName)
-	rf := util.NewCsvReader(filePath)
+	fileNumber := getResourceNumberFromFilePath(r.FileName)
 
-	rf.Open()
-	defer rf.Close()
+	fName := fmt.Sprintf("%d_%s.csv", fileNumber+1, filesGroup)
+	if fileNumber < 10 {
+		fName = fmt.Sprintf("0%d_%s.csv", fileNumber+1, filesGroup)
+	}
 
-	lines, err = rf.ReadAllLines()
+	filePath = fmt.Sprintf("%s/%s", importPath, fName)
+	wf := util.NewCsvMappingWriter(filePath, mapping)
+
+	if header != nil {
+		if err = wf.Open(); err != nil {
+			return nil, err
+		}
+
+		err = wf.WriteAllLines([][]string{header})
+		wf.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ir := util.NewResource(r.Resource, fName)
+	ir.Mapping = r.Mapping
+
+	if err = resourceStorage.Persist(ir); err != nil {
+		return nil, err
+	}
+
+	return wf, nil
+}
+
+// importMapping loads the mapping profile the file of the resource was imported with, nil when it was read by
+// the position of the columns
+func importMapping(mappingsPath string, r util.Resource) (*util.Mapping, error) {
+	if r.Mapping == "" {
+		return nil, nil
+	}
+
+	m, err := util.LoadMapping(filepath.Join(mappingsPath, r.Mapping+".yml"))
 	if err != nil {
-		return nil, errors.Wrapf(err, "loading previous lines from resource %q", filePath)
+		return nil, errors.Wrapf(err, "loading mapping %q of resource %q", r.Mapping, r.FileName)
+	}
+
+	return m, nil
+}
+
+// registerImportLines appends the lines to the last file of the files group, or to a new one when the last file
+// is full, and saves them as imported, so they are skipped when the file is imported again. The lines are written
+// in the layout of the mapping the file was imported with, the layout tells their columns holding dates and numbers
+func registerImportLines(
+	resourceStorage util.ResourceStorage,
+	resource,
+	importPath,
+	mappingsPath,
+	filesGroup string,
+	lines [][]string,
+	layout util.LineLayout,
+) error {
+	r, err := resourceStorage.FindLastByResourceAndFilesGroup(resource, filesGroup)
+	if err != nil {
+		return errors.Wrapf(err, "loading latest %q resource import", resource)
 	}
 
-	nLines := len(lines)
-	if err != nil || nLines >= linePerFile {
-		fileNumber := getResourceNumberFromFilePath(r.FileName)
+	mapping, err := importMapping(mappingsPath, r)
+	if err != nil {
+		return err
+	}
 
-		fName := fmt.Sprintf("%d_%s.csv", fileNumber+1, filesGroup)
-		if fileNumber < 10 {
-			fName = fmt.Sprintf("0%d_%s.csv", fileNumber+1, filesGroup)
+	header, _, err := util.ReadRecords(fmt.Sprintf("%s/%s", importPath, r.FileName), mapping)
+	if err != nil {
+		return errors.Wrapf(err, "loading header from resource %q", r.FileName)
+	}
+
+	records := make([][]string, len(lines))
+	for i, line := range lines {
+		records[i], err = mapping.Record(line, header, layout)
+		if err != nil {
+			return errors.Wrapf(err, "writing line %d in the layout of mapping %q", i+1, r.Mapping)
 		}
+	}
+
+	wf, err := getCsvWriterFromResourceImport(resourceStorage, r, importPath, filesGroup, mapping)
+	if err != nil {
+		return err
+	}
 
-		filePath = fmt.Sprintf("%s/%s", importPath, fName)
+	if err = wf.Open(); err != nil {
+		return err
+	}
 
-		defer func() {
-			ir := util.NewResource(resource, fName)
+	err = wf.WriteAllLines(records)
+	wf.Close()
+
+	if err != nil {
+		return err
+	}
+
+	r, err = resourceStorage.FindLastByResourceAndFilesGroup(resource, filesGroup)
+	if err != nil {
+		return errors.Wrapf(err, "loading latest %q resource import", resource)
+	}
+
+	header, all, err := util.ReadRecords(fmt.Sprintf("%s/%s", importPath, r.FileName), mapping)
+	if err != nil {
+		return errors.Wrapf(err, "loading lines from resource %q", r.FileName)
+	}
+
+	fs := util.Fingerprints(resource, all)
+
+	// line numbers start at 1, after the header when the file has
+	first := len(all) - len(records)
+	if header != nil {
+		first++
+	}
 
-			err := resourceStorage.Persist(ir)
-			if err != nil {
-				panic(err)
-			}
-		}()
+	ns := make([]int, len(records))
+	for i := range ns {
+		ns[i] = first + i + 1
 	}
 
-	return util.NewCsvWriter(filePath), nil
+	return resourceStorage.PersistRows(util.NewResourceRows(r, fs[len(all)-len(records):], ns))
 }
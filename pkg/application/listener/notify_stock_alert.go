@@ -0,0 +1,86 @@
+package listener
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogolfing/cbus"
+
+	"github.com/dohernandez/market-manager/pkg/application/service"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type notifyStockAlert struct {
+	alertFinder    alert.Finder
+	alertPersister alert.Persister
+	sinks          []service.AlertSink
+}
+
+func NewNotifyStockAlert(alertFinder alert.Finder, alertPersister alert.Persister, sinks []service.AlertSink) *notifyStockAlert {
+	return &notifyStockAlert{
+		alertFinder:    alertFinder,
+		alertPersister: alertPersister,
+		sinks:          sinks,
+	}
+}
+
+// OnEvent evaluates the alert rules of the updated stocks, delivering the triggered alerts to the sinks
+func (l *notifyStockAlert) OnEvent(ctx context.Context, event cbus.Event) {
+	stks, ok := event.Result.([]*stock.Stock)
+	if !ok {
+		logger.FromContext(ctx).Warn("notifyStockAlert: Result instance not supported")
+
+		return
+	}
+
+	now := time.Now()
+
+	for _, stk := range stks {
+		rs, err := l.alertFinder.FindAllByStock(stk.ID)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while finding alert rules: stock [%s] -> error [%s]",
+				stk.Symbol,
+				err,
+			)
+
+			continue
+		}
+
+		for _, r := range rs {
+			a, ok := r.Evaluate(stk, now)
+			if !ok {
+				continue
+			}
+
+			notifyAlert(ctx, l.sinks, a)
+
+			err := l.alertPersister.UpdateLastTriggered(r, stk.ID)
+			if err != nil {
+				logger.FromContext(ctx).Errorf(
+					"An error happen while updating alert rule [%s] -> error [%s]",
+					r.ID,
+					err,
+				)
+			}
+		}
+	}
+
+	logger.FromContext(ctx).Debug("Evaluated stocks alert rules")
+}
+
+// notifyAlert delivers the alert to every sink, a failing sink does not prevent the delivery to the rest
+func notifyAlert(ctx context.Context, sinks []service.AlertSink, a alert.Alert) {
+	for _, s := range sinks {
+		err := s.Notify(a)
+		if err != nil {
+			logger.FromContext(ctx).Errorf(
+				"An error happen while notifying alert [%s] -> error [%s]",
+				a.Subject,
+				err,
+			)
+		}
+	}
+}
@@ -16,12 +16,14 @@ import (
 type registerDividendRetentionImport struct {
 	resourceStorage util.ResourceStorage
 	importPath      string
+	mappingsPath    string
 }
 
-func NewRegisterDividendRetentionImport(resourceStorage util.ResourceStorage, importPath string) *registerDividendRetentionImport {
+func NewRegisterDividendRetentionImport(resourceStorage util.ResourceStorage, importPath, mappingsPath string) *registerDividendRetentionImport {
 	return &registerDividendRetentionImport{
 		resourceStorage: resourceStorage,
 		importPath:      importPath,
+		mappingsPath:    mappingsPath,
 	}
 }
 
@@ -40,18 +42,6 @@ func (l *registerDividendRetentionImport) OnEvent(ctx context.Context, event cbu
 		return
 	}
 
-	wf, err := getCsvWriterFromResourceImport(l.resourceStorage, "retentions", l.importPath, oWallet.Name)
-	if err != nil {
-		logger.FromContext(ctx).Errorf(
-			"An error happen while getting csv writer from import for retentions -> error [%s]",
-			err,
-		)
-	}
-
-	wf.Open()
-	defer wf.Close()
-	defer wf.Flush()
-
 	var lines [][]string
 	now := time.Now()
 
@@ -67,9 +57,15 @@ func (l *registerDividendRetentionImport) OnEvent(ctx context.Context, event cbu
 		})
 	}
 
-	err = wf.WriteAllLines(lines)
+	// the retention
+	layout := util.LineLayout{Numbers: []int{1}}
+
+	err := registerImportLines(l.resourceStorage, "retentions", l.importPath, l.mappingsPath, oWallet.Name, lines, layout)
 	if err != nil {
-		logger.FromContext(ctx).Warn(err)
+		logger.FromContext(ctx).Errorf(
+			"An error happen while registering retentions into import -> error [%s]",
+			err,
+		)
 
 		return
 	}
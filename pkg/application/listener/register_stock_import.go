@@ -13,12 +13,14 @@ import (
 type registerStockImport struct {
 	resourceStorage util.ResourceStorage
 	importPath      string
+	mappingsPath    string
 }
 
-func NewRegisterStockImport(resourceStorage util.ResourceStorage, importPath string) *registerStockImport {
+func NewRegisterStockImport(resourceStorage util.ResourceStorage, importPath, mappingsPath string) *registerStockImport {
 	return &registerStockImport{
 		resourceStorage: resourceStorage,
 		importPath:      importPath,
+		mappingsPath:    mappingsPath,
 	}
 }
 
@@ -30,18 +32,6 @@ func (l *registerStockImport) OnEvent(ctx context.Context, event cbus.Event) {
 		return
 	}
 
-	wf, err := getCsvWriterFromResourceImport(l.resourceStorage, "stocks", l.importPath, "stocks")
-	if err != nil {
-		logger.FromContext(ctx).Errorf(
-			"An error happen while getting csv writer from import for stocks -> error [%s]",
-			err,
-		)
-	}
-
-	wf.Open()
-	defer wf.Close()
-	defer wf.Flush()
-
 	var lines [][]string
 	for _, s := range ss {
 		line := []string{
@@ -56,9 +46,12 @@ func (l *registerStockImport) OnEvent(ctx context.Context, event cbus.Event) {
 		lines = append(lines, line)
 	}
 
-	err = wf.WriteAllLines(lines)
+	err := registerImportLines(l.resourceStorage, "stocks", l.importPath, l.mappingsPath, "stocks", lines, util.LineLayout{})
 	if err != nil {
-		logger.FromContext(ctx).Warn(err)
+		logger.FromContext(ctx).Errorf(
+			"An error happen while registering stocks into import -> error [%s]",
+			err,
+		)
 
 		return
 	}
@@ -3,6 +3,7 @@ package listener
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/gogolfing/cbus"
 
@@ -15,12 +16,14 @@ import (
 type registerWalletOperationImport struct {
 	resourceStorage util.ResourceStorage
 	importPath      string
+	mappingsPath    string
 }
 
-func NewRegisterWalletOperationImport(resourceStorage util.ResourceStorage, importPath string) *registerWalletOperationImport {
+func NewRegisterWalletOperationImport(resourceStorage util.ResourceStorage, importPath, mappingsPath string) *registerWalletOperationImport {
 	return &registerWalletOperationImport{
 		resourceStorage: resourceStorage,
 		importPath:      importPath,
+		mappingsPath:    mappingsPath,
 	}
 }
 
@@ -45,6 +48,11 @@ func (l *registerWalletOperationImport) OnEvent(ctx context.Context, event cbus.
 		trade = cmd.Trade
 	case *appCommand.AddInterestOperation:
 		wName = cmd.Wallet
+	case *appCommand.AddCouponOperation:
+		wName = cmd.Wallet
+	case *appCommand.AddRedemptionOperation:
+		wName = cmd.Wallet
+		trade = cmd.Trade
 	default:
 		logger.FromContext(ctx).Error(
 			"registerWalletOperationImport: Operation action not supported",
@@ -62,19 +70,6 @@ func (l *registerWalletOperationImport) OnEvent(ctx context.Context, event cbus.
 		return
 	}
 
-	wf, err := getCsvWriterFromResourceImport(l.resourceStorage, "accounts", l.importPath, wName)
-	if err != nil {
-		logger.FromContext(ctx).Errorf(
-			"An error happen while getting csv writer from import for wallet [%s] -> error [%s]",
-			wName,
-			err,
-		)
-	}
-
-	wf.Open()
-	defer wf.Close()
-	defer wf.Flush()
-
 	var lines [][]string
 	for _, o := range ops {
 		var (
@@ -89,18 +84,24 @@ func (l *registerWalletOperationImport) OnEvent(ctx context.Context, event cbus.
 		v := fmt.Sprintf("%.2f", o.Value.Amount)
 
 		switch o.Action {
-		case operation.Dividend:
+		case operation.Dividend, operation.Coupon:
 			action = "Dividendo"
+			if o.Action == operation.Coupon {
+				action = "Cupón"
+			}
+
 			stockName = o.Stock.Name
 			price = v
-		case operation.Buy, operation.Sell:
+		case operation.Buy, operation.Sell, operation.Redemption:
 			action = "Compra"
 			if o.Action == operation.Sell {
 				action = "Venta"
+			} else if o.Action == operation.Redemption {
+				action = "Amortización"
 			}
 
 			stockName = o.Stock.Name
-			amount = fmt.Sprintf("%d", o.Amount)
+			amount = strconv.FormatFloat(o.Amount, 'f', -1, 64)
 			price = fmt.Sprintf("%.2f", o.Price.Amount)
 			priceChange = fmt.Sprintf("%.2f", o.PriceChange.Amount)
 			priceChangeCommission = fmt.Sprintf("%.2f", o.PriceChangeCommission.Amount)
@@ -130,9 +131,16 @@ func (l *registerWalletOperationImport) OnEvent(ctx context.Context, event cbus.
 		lines = append(lines, line)
 	}
 
-	err = wf.WriteAllLines(lines)
+	// the date and the numbers, from the amount to the commission
+	layout := util.LineLayout{Dates: []int{1}, Numbers: []int{4, 5, 6, 7, 8, 9}}
+
+	err := registerImportLines(l.resourceStorage, "accounts", l.importPath, l.mappingsPath, wName, lines, layout)
 	if err != nil {
-		logger.FromContext(ctx).Warn(err)
+		logger.FromContext(ctx).Errorf(
+			"An error happen while registering operations into import for wallet [%s] -> error [%s]",
+			wName,
+			err,
+		)
 
 		return
 	}
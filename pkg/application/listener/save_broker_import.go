@@ -0,0 +1,86 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+	"github.com/satori/go.uuid"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type saveBrokerImport struct {
+	walletFinder    wallet.Finder
+	walletPersister wallet.Persister
+}
+
+func NewSaveBrokerImport(walletFinder wallet.Finder, walletPersister wallet.Persister) *saveBrokerImport {
+	return &saveBrokerImport{
+		walletFinder:    walletFinder,
+		walletPersister: walletPersister,
+	}
+}
+
+// OnEvent saves the dividend retention per share of the stocks held in the wallet. The ids of the broker
+// transactions imported are saved along with the operations by addWalletOperation
+func (l *saveBrokerImport) OnEvent(ctx context.Context, event cbus.Event) {
+	cmd, ok := event.Command.(*appCommand.ImportBrokerOperation)
+	if !ok {
+		logger.FromContext(ctx).Warn("saveBrokerImport: command instance not supported")
+
+		return
+	}
+
+	if len(cmd.Retentions) == 0 {
+		return
+	}
+
+	w, err := l.walletFinder.FindByName(cmd.Wallet)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet name [%s] -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return
+	}
+
+	err = l.walletFinder.LoadActiveItems(w)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while loading wallet name [%s] items -> error [%s]",
+			cmd.Wallet,
+			err,
+		)
+
+		return
+	}
+
+	// only the retentions read are saved, the items holding the rest are left out
+	items := map[uuid.UUID]*wallet.Item{}
+
+	for id, r := range cmd.Retentions {
+		i, ok := w.Items[id]
+		if !ok {
+			logger.FromContext(ctx).Warnf("Retention of stock [%s] not saved, it is not held in the wallet", id)
+
+			continue
+		}
+
+		i.DividendRetention = r
+		items[id] = i
+	}
+
+	w.Items = items
+
+	err = l.walletPersister.UpdateRetentions(w)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting dividend retention -> error [%s]",
+			err,
+		)
+	}
+}
@@ -0,0 +1,44 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/gogolfing/cbus"
+
+	appCommand "github.com/dohernandez/market-manager/pkg/application/command"
+)
+
+type skipDryRun struct {
+	listener cbus.Listener
+}
+
+// NewSkipDryRun wraps the listener of an import command so it is not called when the import is a dry run,
+// nothing is saved and the result is the validation report instead of the items imported
+func NewSkipDryRun(listener cbus.Listener) *skipDryRun {
+	return &skipDryRun{
+		listener: listener,
+	}
+}
+
+func (l *skipDryRun) OnEvent(ctx context.Context, event cbus.Event) {
+	if isDryRun(event.Command) {
+		return
+	}
+
+	l.listener.OnEvent(ctx, event)
+}
+
+func isDryRun(command cbus.Command) bool {
+	switch cmd := command.(type) {
+	case *appCommand.ImportStock:
+		return cmd.DryRun
+	case *appCommand.ImportOperation:
+		return cmd.DryRun
+	case *appCommand.ImportBrokerOperation:
+		return cmd.DryRun
+	case *appCommand.ImportRetention:
+		return cmd.DryRun
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package listener
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/dohernandez/market-manager/pkg/application/service"
 	"github.com/dohernandez/market-manager/pkg/infrastructure/logger"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
 )
@@ -20,8 +22,10 @@ const (
 )
 
 type updateStockDividend struct {
+	stockDividendFinder    dividend.Finder
 	stockDividendPersister dividend.Persister
 	stockDividendService   service.StockDividend
+	sinks                  []service.AlertSink
 	startHistoricalDate    time.Time
 
 	concurrency int
@@ -29,14 +33,18 @@ type updateStockDividend struct {
 }
 
 func NewUpdateStockDividend(
+	stockDividendFinder dividend.Finder,
 	stockDividendPersister dividend.Persister,
 	stockDividendService service.StockDividend,
+	sinks []service.AlertSink,
 ) *updateStockDividend {
 	startHistoricalDate, _ := time.Parse("2-Jan-2006", "1-Jan-2017")
 
 	return &updateStockDividend{
+		stockDividendFinder:    stockDividendFinder,
 		stockDividendPersister: stockDividendPersister,
 		stockDividendService:   stockDividendService,
+		sinks:                  sinks,
 		startHistoricalDate:    startHistoricalDate,
 		concurrency:            updateDividendConcurrency,
 		sleep:                  updateDividendSleep,
@@ -94,7 +102,23 @@ func (l *updateStockDividend) updateDividendConcurrency(
 }
 
 func (l *updateStockDividend) updateDividend(ctx context.Context, stk *stock.Stock) {
-	err := l.stockDividendPersister.DeleteAll(stk.ID)
+	// cryptocurrencies do not pay dividends
+	if stk.IsCryptocurrency() {
+		return
+	}
+
+	stored, err := l.stockDividendFinder.FindAllFormStock(stk.ID)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while finding stock dividend symbol [%s] -> error [%s]",
+			stk.Symbol,
+			err,
+		)
+
+		return
+	}
+
+	err = l.stockDividendPersister.DeleteAll(stk.ID)
 	if err != nil {
 		logger.FromContext(ctx).Errorf(
 			"An error happen while deleting all stock dividend symbol [%s] -> error [%s]",
@@ -107,7 +131,16 @@ func (l *updateStockDividend) updateDividend(ctx context.Context, stk *stock.Sto
 
 	var ds []dividend.StockDividend
 
+	// the coupons of the bonds are known from the schedule
+	if stk.IsBond() {
+		ds = stk.FixedIncome.Coupons()
+		l.persistDividends(ctx, stk, stored, ds, true)
+
+		return
+	}
+
 	dsf, err := l.stockDividendService.Future(stk)
+	withFuture := err == nil
 	if err != nil {
 		logger.FromContext(ctx).Errorf(
 			"An error happen while updating all stock dividend future symbol [%s] -> error [%s]",
@@ -129,16 +162,82 @@ func (l *updateStockDividend) updateDividend(ctx context.Context, stk *stock.Sto
 		ds = append(ds, dsh...)
 	}
 
-	if len(ds) > 0 {
-		stk.Dividends = ds
-		err = l.stockDividendPersister.PersistAll(stk.ID, ds)
-		if err != nil {
-			logger.FromContext(ctx).Errorf(
-				"An error happen while updating all stock dividend symbol [%s] -> error [%s]",
-				stk.Symbol,
-				err,
-			)
-		}
+	l.persistDividends(ctx, stk, stored, ds, withFuture)
+}
+
+func (l *updateStockDividend) persistDividends(
+	ctx context.Context,
+	stk *stock.Stock,
+	stored []dividend.StockDividend,
+	ds []dividend.StockDividend,
+	withFuture bool,
+) {
+	if len(ds) == 0 {
+		return
+	}
+
+	stk.Dividends = ds
+	err := l.stockDividendPersister.PersistAll(stk.ID, ds)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while updating all stock dividend symbol [%s] -> error [%s]",
+			stk.Symbol,
+			err,
+		)
+
+		return
+	}
+
+	l.notifyChanges(ctx, stk, dividend.Compare(stk.ID, stored, ds, withFuture, time.Now()))
+}
+
+// notifyChanges stores the dividend changes and delivers them to the sinks
+func (l *updateStockDividend) notifyChanges(ctx context.Context, stk *stock.Stock, cs []dividend.Change) {
+	if len(cs) == 0 {
+		return
+	}
+
+	err := l.stockDividendPersister.PersistChanges(cs)
+	if err != nil {
+		logger.FromContext(ctx).Errorf(
+			"An error happen while persisting stock dividend changes symbol [%s] -> error [%s]",
+			stk.Symbol,
+			err,
+		)
+	}
+
+	for _, c := range cs {
+		notifyAlert(ctx, l.sinks, dividendChangeAlert(stk, c))
+	}
+}
+
+func dividendChangeAlert(stk *stock.Stock, c dividend.Change) alert.Alert {
+	exDate := c.ExDate.Format("02/01/2006")
+
+	var msg string
+
+	switch c.Type {
+	case dividend.NewAnnouncement:
+		msg = fmt.Sprintf("%s announced a dividend of %.4f %s with ex-date %s", stk.Symbol, c.Amount.Amount, c.Amount.Currency, exDate)
+	case dividend.Suspended:
+		msg = fmt.Sprintf("%s dividend of %.4f %s with ex-date %s is no longer expected", stk.Symbol, c.Previous.Amount, c.Previous.Currency, exDate)
+	default:
+		msg = fmt.Sprintf(
+			"%s dividend with ex-date %s %s from %.4f to %.4f %s",
+			stk.Symbol,
+			exDate,
+			c.Type,
+			c.Previous.Amount,
+			c.Amount.Amount,
+			c.Amount.Currency,
+		)
+	}
+
+	return alert.Alert{
+		Date:    c.DetectedAt,
+		Stock:   stk,
+		Subject: fmt.Sprintf("%s dividend %s", stk.Symbol, c.Type),
+		Message: msg,
 	}
 }
 
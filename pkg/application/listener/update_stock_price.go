@@ -17,16 +17,23 @@ import (
 const updatePriceConcurrency = 10
 
 type updateStockPrice struct {
-	stockFinder       stock.Finder
-	stockPriceService service.StockPrice
-	stockPersister    stock.Persister
+	stockFinder        stock.Finder
+	stockPriceService  service.StockPrice
+	cryptoPriceService service.StockPrice
+	stockPersister     stock.Persister
 }
 
-func NewUpdateStockPrice(stockFinder stock.Finder, stockPriceService service.StockPrice, stockPersister stock.Persister) *updateStockPrice {
+func NewUpdateStockPrice(
+	stockFinder stock.Finder,
+	stockPriceService service.StockPrice,
+	cryptoPriceService service.StockPrice,
+	stockPersister stock.Persister,
+) *updateStockPrice {
 	return &updateStockPrice{
-		stockFinder:       stockFinder,
-		stockPriceService: stockPriceService,
-		stockPersister:    stockPersister,
+		stockFinder:        stockFinder,
+		stockPriceService:  stockPriceService,
+		cryptoPriceService: cryptoPriceService,
+		stockPersister:     stockPersister,
 	}
 }
 
@@ -79,19 +86,32 @@ func (l *updateStockPrice) OnEvent(ctx context.Context, event cbus.Event) {
 }
 
 func (l *updateStockPrice) updateStock(stk *stock.Stock) error {
-	p, err := l.stockPriceService.Price(stk)
+	// the bonds are valued at the clean price given by hand
+	if stk.IsBond() {
+		return l.updateBond(stk)
+	}
+
+	priceService := l.stockPriceService
+	currency := mm.Currency(mm.Dollar)
+
+	if stk.IsCryptocurrency() {
+		priceService = l.cryptoPriceService
+		currency = stk.QuoteCurrency()
+	}
+
+	p, err := priceService.Price(stk)
 	if err != nil {
 		return errors.Wrapf(err, "symbol : %s", stk.Symbol)
 	}
 
 	stk.Value = mm.Value{
 		Amount:   p.Close,
-		Currency: mm.Dollar,
+		Currency: currency,
 	}
 
 	stk.Change = mm.Value{
 		Amount:   p.Change,
-		Currency: mm.Dollar,
+		Currency: currency,
 	}
 
 	if p.High52Week > 0 {
@@ -127,3 +147,16 @@ func (l *updateStockPrice) updateStock(stk *stock.Stock) error {
 
 	return nil
 }
+
+func (l *updateStockPrice) updateBond(stk *stock.Stock) error {
+	v := stk.FixedIncome.CleanValue()
+
+	stk.Change = v.Decrease(stk.Value)
+	stk.Value = v
+
+	if err := l.stockPersister.UpdatePrice(stk); err != nil {
+		return errors.Wrapf(err, "symbol : %s", stk.Symbol)
+	}
+
+	return nil
+}
@@ -34,6 +34,11 @@ func (l *updateStockPriceVolatility) OnEvent(ctx context.Context, event cbus.Eve
 
 	concurrency := updatePriceVolatilityConcurrency
 	for _, stk := range stks {
+		// the volatility is scraped for the stocks only
+		if stk.IsCryptocurrency() || stk.IsBond() {
+			continue
+		}
+
 		wg.Add(1)
 		concurrency--
 
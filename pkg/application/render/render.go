@@ -6,7 +6,11 @@ import (
 	"github.com/satori/go.uuid"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/valuation"
 )
 
 type (
@@ -35,6 +39,7 @@ type (
 		HV52Week            float64
 		HV20Day             float64
 		PER                 float64
+		Valuations          []valuation.Valuation
 
 		PriceWithHighLow int
 	}
@@ -72,7 +77,7 @@ type (
 
 	WalletStockOutput struct {
 		StockOutput
-		Amount             int
+		Amount             float64
 		Capital            mm.Value
 		Invested           mm.Value
 		DividendPayed      mm.Value
@@ -85,6 +90,9 @@ type (
 		Change             mm.Value
 		WAPrice            mm.Value
 		WADYield           float64
+		YieldOnCost        float64
+		DividendGrowth     float64
+		DividendOnCost     float64
 		Trades             []*TradeOutput
 	}
 
@@ -95,6 +103,8 @@ type (
 	}
 
 	WalletOutput struct {
+		Wallet                string
+		Broker                string
 		Capital               mm.Value
 		Invested              mm.Value
 		Funds                 mm.Value
@@ -106,6 +116,7 @@ type (
 		DividendPayedYield    float64
 		DividendYearProjected mm.Value
 		DividendYearYield     float64
+		ConnectivityYear      mm.Value
 		Connection            mm.Value
 		Interest              mm.Value
 		Commission            mm.Value
@@ -113,6 +124,70 @@ type (
 		DividendProjected []WalletDividendProjected
 	}
 
+	LedgerEntryOutput struct {
+		Date        time.Time
+		Kind        string
+		Description string
+		Amount      mm.Value
+		Balance     mm.Value
+		OperationID uuid.UUID
+	}
+
+	WalletLedgerOutput struct {
+		Wallet       string
+		From         time.Time
+		To           time.Time
+		Opening      mm.Value
+		Closing      mm.Value
+		Funds        mm.Value
+		CompareFunds bool
+		Entries      []*LedgerEntryOutput
+	}
+
+	TradeStatsOutput struct {
+		Wallet string
+		Tag    string
+		From   time.Time
+		To     time.Time
+		Stats  trade.Stats
+	}
+
+	DividendChangeOutput struct {
+		Stock      string
+		Symbol     string
+		Type       dividend.ChangeType
+		ExDate     time.Time
+		Previous   mm.Value
+		Amount     mm.Value
+		DetectedAt time.Time
+	}
+
+	WalletRebuildOutput struct {
+		Wallet      string
+		Applied     bool
+		Differences []wallet.Difference
+	}
+
+	ExposureOutput struct {
+		Name       string
+		Capital    mm.Value
+		Percentage float64
+	}
+
+	WalletExposureOutput struct {
+		Wallet           string
+		Capital          mm.Value
+		Sectors          []*ExposureOutput
+		Countries        []*ExposureOutput
+		Stocks           []*ExposureOutput
+		NotLookedThrough []string
+	}
+
+	BankAccountOutput struct {
+		Account *bank.Account
+		Wallet  string
+	}
+
 	WalletDetailsOutput struct {
 		WalletOutput       WalletOutput
 		WalletStockOutputs []*WalletStockOutput
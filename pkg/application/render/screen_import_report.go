@@ -0,0 +1,81 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
+
+type (
+	OutputScreenImportReport struct {
+		Reports []*util.ImportReport
+	}
+
+	screenImportReport struct{}
+)
+
+func NewScreenImportReport() *screenImportReport {
+	return &screenImportReport{}
+}
+
+func (s *screenImportReport) Render(output interface{}) {
+	sOutput := output.(*OutputScreenImportReport)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+	inRed := color.New(color.FgRed).FprintlnFunc()
+	inGreen := color.New(color.FgGreen).FprintlnFunc()
+
+	var nIssues int
+
+	for _, r := range sOutput.Reports {
+		noColor(tw, "")
+		noColor(tw, fmt.Sprintf("# %s", r.File))
+		noColor(tw, "")
+
+		header(tw, "Rows\t New\t Skipped\t Conflicting\t Problems\t")
+		inNormal(tw, fmt.Sprintf("%d\t %d\t %d\t %d\t %d\t", r.Rows, r.New, r.Skipped, r.Conflicting, len(r.Issues)))
+
+		if len(r.Issues) == 0 {
+			continue
+		}
+
+		nIssues += len(r.Issues)
+
+		sort.SliceStable(r.Issues, func(i, j int) bool {
+			return r.Issues[i].Line < r.Issues[j].Line
+		})
+
+		noColor(tw, "")
+		header(tw, "Line\t Problem\t")
+
+		for _, i := range r.Issues {
+			line := "-"
+			if i.Line > 0 {
+				line = fmt.Sprintf("%d", i.Line)
+			}
+
+			inRed(tw, fmt.Sprintf("%s\t %s\t", line, i.Message))
+		}
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+
+	if nIssues == 0 {
+		inGreen(os.Stdout, "Dry run, no problems found, nothing was saved")
+
+		return
+	}
+
+	inRed(os.Stdout, fmt.Sprintf("Dry run, %d problems found, nothing was saved", nIssues))
+}
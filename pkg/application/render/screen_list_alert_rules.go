@@ -0,0 +1,71 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+)
+
+type (
+	OutputScreenListAlertRules struct {
+		Rules []*alert.Rule
+
+		Precision int
+	}
+
+	screenListAlertRules struct{}
+)
+
+func NewScreenListAlertRules() *screenListAlertRules {
+	return &screenListAlertRules{}
+}
+
+func (s *screenListAlertRules) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListAlertRules)
+
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	noColor(tw, "")
+	header(tw, "ID\t Stock\t Watchlist\t Condition\t Value\t Last Triggered\t")
+
+	for _, r := range sOutput.Rules {
+		var symbol, wName, lastTriggered string
+
+		if r.Stock != nil {
+			symbol = r.Stock.Symbol
+		}
+
+		if r.Watchlist != nil {
+			wName = r.Watchlist.Name
+		}
+
+		if last := r.LastTriggeredAt(); !last.IsZero() {
+			lastTriggered = last.Format("02 Jan 06 15:04")
+		}
+
+		inNormal(tw, fmt.Sprintf(
+			"%s\t %s\t %s\t %s\t %.*f\t %s\t",
+			r.ID,
+			symbol,
+			wName,
+			r.Condition,
+			precision,
+			r.Value,
+			lastTriggered,
+		))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+)
+
+type (
+	OutputScreenListBankAccounts struct {
+		BankAccounts []*BankAccountOutput
+	}
+
+	screenListBankAccounts struct{}
+)
+
+func NewScreenListBankAccounts() *screenListBankAccounts {
+	return &screenListBankAccounts{}
+}
+
+func (s *screenListBankAccounts) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListBankAccounts)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	noColor(tw, "")
+	header(tw, "Alias\t Name\t Type\t Account No\t Wallet\t")
+
+	for _, b := range sOutput.BankAccounts {
+		inNormal(tw, fmt.Sprintf(
+			"%s\t %s\t %s\t %s\t %s\t",
+			b.Account.Alias,
+			b.Account.Name,
+			b.Account.Type,
+			b.Account.AccountNo,
+			b.Wallet,
+		))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
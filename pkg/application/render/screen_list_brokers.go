@@ -0,0 +1,86 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+)
+
+type (
+	OutputScreenListBrokers struct {
+		Brokers []*broker.Broker
+
+		Precision int
+	}
+
+	screenListBrokers struct{}
+)
+
+func NewScreenListBrokers() *screenListBrokers {
+	return &screenListBrokers{}
+}
+
+func (s *screenListBrokers) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListBrokers)
+
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	for _, b := range sOutput.Brokers {
+		noColor(tw, "")
+		noColor(tw, fmt.Sprintf("# %s", b.Name))
+		noColor(tw, "")
+
+		header(tw, "Retention\t Margin\t Connectivity\t Import Format\t")
+		inNormal(tw, fmt.Sprintf(
+			"%s\t %s\t %s\t %s\t",
+			util.SPrintPercentage(b.Retention, precision),
+			util.SPrintPercentage(b.Margin, precision),
+			util.SPrintValue(b.Connectivity, precision),
+			b.ImportFormat,
+		))
+
+		if len(b.Commissions) == 0 {
+			continue
+		}
+
+		var exchanges []string
+		for symbol := range b.Commissions {
+			exchanges = append(exchanges, symbol)
+		}
+
+		sort.Strings(exchanges)
+
+		noColor(tw, "")
+		header(tw, "Exchange\t Base\t Extra\t Apply\t Maximum\t Change\t")
+
+		for _, symbol := range exchanges {
+			c := b.Commissions[symbol]
+
+			inNormal(tw, fmt.Sprintf(
+				"%s\t %s\t %s\t %s\t %s\t %s\t",
+				symbol,
+				util.SPrintValue(c.Base, precision),
+				util.SPrintValue(c.Extra, precision+2),
+				c.Apply,
+				util.SPrintValue(c.Maximum, precision),
+				util.SPrintValue(c.Change, precision),
+			))
+		}
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+type (
+	OutputScreenListDividendChanges struct {
+		Changes []*DividendChangeOutput
+
+		Precision int
+	}
+
+	screenListDividendChanges struct{}
+)
+
+func NewScreenListDividendChanges() *screenListDividendChanges {
+	return &screenListDividendChanges{}
+}
+
+func (s *screenListDividendChanges) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListDividendChanges)
+
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+	inGreen := color.New(color.FgGreen).FprintlnFunc()
+	inRed := color.New(color.FgRed).FprintlnFunc()
+
+	noColor(tw, "")
+	header(tw, "Detected\t Stock\t Symbol\t Change\t Ex Date\t Previous\t Amount\t")
+
+	for _, c := range sOutput.Changes {
+		str := fmt.Sprintf(
+			"%s\t %s\t %s\t %s\t %s\t %s\t %s\t",
+			util.SPrintDate(c.DetectedAt),
+			util.SPrintTruncate(c.Stock, 20),
+			c.Symbol,
+			c.Type,
+			util.SPrintDate(c.ExDate),
+			util.SPrintValue(c.Previous, precision),
+			util.SPrintValue(c.Amount, precision),
+		)
+
+		switch c.Type {
+		case dividend.Raised:
+			inGreen(tw, str)
+		case dividend.Cut, dividend.Suspended:
+			inRed(tw, str)
+		default:
+			inNormal(tw, str)
+		}
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
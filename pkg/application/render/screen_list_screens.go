@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/screen"
+)
+
+type (
+	OutputScreenListScreens struct {
+		Screens []*screen.Screen
+	}
+
+	screenListScreens struct{}
+)
+
+func NewScreenListScreens() *screenListScreens {
+	return &screenListScreens{}
+}
+
+func (s *screenListScreens) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListScreens)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	noColor(tw, "")
+	header(tw, "Name\t Expression\t Sort\t Order\t Limit\t")
+
+	for _, sc := range sOutput.Screens {
+		order := "asc"
+		if sc.Desc {
+			order = "desc"
+		}
+
+		var limit string
+		if sc.Limit > 0 {
+			limit = fmt.Sprintf("%d", sc.Limit)
+		}
+
+		inNormal(tw, fmt.Sprintf(
+			"%s\t %s\t %s\t %s\t %s\t",
+			sc.Name,
+			sc.Expression,
+			sc.Sort,
+			order,
+			limit,
+		))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -168,7 +168,15 @@ func (s *screenListStocks) Render(output interface{}) {
 func (s *screenListStocks) renderStocks(tw *tabwriter.Writer, stks []*StockOutput) {
 	header := color.New(color.FgWhite).FprintlnFunc()
 
-	header(tw, "#\t Stock\t Market\t Symbol\t Price\t High 52wk\t Low 52wk\t HV 52wk\t HV 20day\t Buy Under\t Dividend\t  D. Yield\t EPS\t Ex Date\t Change\t")
+	// the valuation models are the same for every stock
+	var vheader string
+	if len(stks) > 0 {
+		for _, v := range stks[0].Valuations {
+			vheader += fmt.Sprintf(" %s FV\t %s MoS\t", v.Model, v.Model)
+		}
+	}
+
+	header(tw, "#\t Stock\t Market\t Symbol\t Price\t High 52wk\t Low 52wk\t HV 52wk\t HV 20day\t Buy Under\t Dividend\t  D. Yield\t EPS\t Ex Date\t Change\t"+vheader)
 
 	normal := color.New(color.FgWhite).FprintlnFunc()
 	overSell := color.New(color.FgGreen).FprintlnFunc()
@@ -198,6 +206,16 @@ func (s *screenListStocks) renderStocks(tw *tabwriter.Writer, stks []*StockOutpu
 			util.SPrintValue(stk.Change, s.precision),
 		)
 
+		for _, v := range stk.Valuations {
+			if !v.Valid {
+				str += " -\t -\t"
+
+				continue
+			}
+
+			str += fmt.Sprintf(" %s\t %s\t", util.SPrintValue(v.FairValue, s.precision), util.SPrintPercentage(v.MarginOfSafety, s.precision))
+		}
+
 		switch stk.PriceWithHighLow {
 		case 1:
 			overBuy(tw, str)
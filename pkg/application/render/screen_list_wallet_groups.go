@@ -0,0 +1,57 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+)
+
+type (
+	OutputScreenListWalletGroups struct {
+		Groups []*group.Group
+	}
+
+	screenListWalletGroups struct{}
+)
+
+func NewScreenListWalletGroups() *screenListWalletGroups {
+	return &screenListWalletGroups{}
+}
+
+func (s *screenListWalletGroups) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListWalletGroups)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	for _, g := range sOutput.Groups {
+		noColor(tw, "")
+		noColor(tw, fmt.Sprintf("# %s", g.Name))
+		noColor(tw, "")
+
+		var names []string
+		for _, w := range g.Wallets {
+			names = append(names, w.Name)
+		}
+
+		sort.Strings(names)
+
+		header(tw, "Wallet\t")
+
+		for _, name := range names {
+			inNormal(tw, fmt.Sprintf("%s\t", name))
+		}
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
+
+type (
+	OutputScreenListWallets struct {
+		Wallets []*WalletOutput
+
+		Precision int
+	}
+
+	screenListWallets struct{}
+)
+
+func NewScreenListWallets() *screenListWallets {
+	return &screenListWallets{}
+}
+
+func (s *screenListWallets) Render(output interface{}) {
+	sOutput := output.(*OutputScreenListWallets)
+
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+
+	noColor(tw, "")
+
+	header := color.New(color.FgWhite).FprintlnFunc()
+	header(tw, "#\t Wallet\t Broker\t Invested\t Capital\t Funds\t Free Margin\t Net Capital\t Net Benefits\t % Benefits\t Dividends\t Connection\t Interest\t Commissions\t")
+
+	inProfits := color.New(color.FgGreen).FprintlnFunc()
+	inLooses := color.New(color.FgRed).FprintlnFunc()
+
+	for i, w := range sOutput.Wallets {
+		str := fmt.Sprintf(
+			"%d\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %.*f%%\t %s\t %s\t %s\t %s\t",
+			i+1,
+			w.Wallet,
+			w.Broker,
+			util.SPrintValue(w.Invested, precision),
+			util.SPrintValue(w.Capital, precision),
+			util.SPrintValue(w.Funds, precision),
+			util.SPrintValue(w.FreeMargin, precision),
+			util.SPrintValue(w.NetCapital, precision),
+			util.SPrintValue(w.NetBenefits, precision),
+			precision,
+			w.PercentageBenefits,
+			util.SPrintValue(w.DividendPayed, precision),
+			util.SPrintValue(w.Connection, precision),
+			util.SPrintValue(w.Interest, precision),
+			util.SPrintValue(w.Commission, precision),
+		)
+
+		if w.PercentageBenefits < 0 {
+			inLooses(tw, str)
+		} else {
+			inProfits(tw, str)
+		}
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+)
+
+type (
+	OutputScreenTradeStats struct {
+		TradeStats TradeStatsOutput
+
+		Precision int
+	}
+
+	screenTradeStats struct{}
+)
+
+func NewScreenTradeStats() *screenTradeStats {
+	return &screenTradeStats{}
+}
+
+func (s *screenTradeStats) Render(output interface{}) {
+	sOutput := output.(*OutputScreenTradeStats)
+
+	tsOutput := sOutput.TradeStats
+	stats := tsOutput.Stats
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+	inGreen := color.New(color.FgGreen).FprintlnFunc()
+	inRed := color.New(color.FgRed).FprintlnFunc()
+
+	title := fmt.Sprintf("# Trades %s (%s - %s)", tsOutput.Wallet, util.SPrintDate(tsOutput.From), util.SPrintDate(tsOutput.To))
+	if tsOutput.Tag != "" {
+		title = fmt.Sprintf("%s tagged %s", title, tsOutput.Tag)
+	}
+
+	noColor(tw, "")
+	noColor(tw, title)
+	noColor(tw, "")
+
+	if stats.Trades == 0 {
+		inNormal(tw, "No closed trades")
+		noColor(tw, "")
+
+		tw.Flush()
+
+		return
+	}
+
+	header(tw, "Trades\t Wins\t Losses\t Win rate\t Avg. gain\t Avg. loss\t Avg. holding days\t Expectancy\t")
+
+	pColor := inGreen
+	if stats.Expectancy.Amount < 0 {
+		pColor = inRed
+	}
+
+	pColor(tw, fmt.Sprintf(
+		"%d\t %d\t %d\t %s\t %s\t %s\t %.1f\t %s\t",
+		stats.Trades,
+		stats.Wins,
+		stats.Losses,
+		util.SPrintPercentage(stats.WinRate, precision),
+		util.SPrintValue(stats.AverageGain, precision),
+		util.SPrintValue(stats.AverageLoss, precision),
+		stats.AverageHoldingDays,
+		util.SPrintValue(stats.Expectancy, precision),
+	))
+
+	noColor(tw, "")
+	header(tw, "\t #\t Stock\t Opened\t Closed\t Net\t Benefit\t Target\t Stop loss\t Tags\t Note\t")
+
+	for _, t := range []struct {
+		name  string
+		trade *trade.Trade
+	}{
+		{"Best", stats.Best},
+		{"Worst", stats.Worst},
+	} {
+		pColor := inGreen
+		if t.trade.CloseNet.Amount < 0 {
+			pColor = inRed
+		}
+
+		j := t.trade.Journal
+
+		pColor(tw, fmt.Sprintf(
+			"%s\t %d\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t",
+			t.name,
+			t.trade.Number,
+			t.trade.Stock.Symbol,
+			util.SPrintDate(t.trade.OpenedAt),
+			util.SPrintDate(t.trade.ClosedAt),
+			util.SPrintValue(t.trade.CloseNet, precision),
+			util.SPrintPercentage(t.trade.BenefitPercentage(), precision),
+			util.SPrintValue(j.TargetPrice, precision),
+			util.SPrintValue(j.StopLoss, precision),
+			strings.Join(j.Tags, ", "),
+			util.SPrintTruncate(j.Note, 40),
+		))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -150,12 +150,12 @@ func (s *screenWalletDetails) renderItemStocks(tw *tabwriter.Writer, wStocks []*
 	for i, stk := range wStocks {
 
 		str := fmt.Sprintf(
-			"%d\t %s\t %s\t %s\t %d\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %.*f%%\t %s\t",
+			"%d\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %.*f%%\t %s\t",
 			i+1,
 			util.SPrintTruncate(stk.Stock, 27),
 			stk.Market,
 			stk.Symbol,
-			stk.Amount,
+			util.SPrintAmount(stk.Amount),
 			util.SPrintValue(stk.Capital, precision),
 			util.SPrintValue(stk.Invested, precision),
 			util.SPrintPercentage(stk.PercentageWallet, precision),
@@ -182,13 +182,13 @@ func (s *screenWalletDetails) renderWalletDividendProjected(tw *tabwriter.Writer
 	noColor(tw, "")
 
 	header := color.New(color.FgWhite).FprintlnFunc()
-	header(tw, "Month\t Dividend\t D. Yield\t          Month\t Dividend\t D. Yield\t          Month\t Dividend\t D. Yield\t          Year\t Dividend\t D. Yield\t")
+	header(tw, "Month\t Dividend\t D. Yield\t          Month\t Dividend\t D. Yield\t          Month\t Dividend\t D. Yield\t          Year\t Dividend\t D. Yield\t Connectivity\t")
 
 	now := time.Now()
 
 	inNormal := color.New(color.FgWhite).FprintlnFunc()
 	inNormal(tw, fmt.Sprintf(
-		"%s\t %s\t %s\t          %s\t %s\t %s\t          %s\t %s\t %s\t          %d\t %s\t %s\t",
+		"%s\t %s\t %s\t          %s\t %s\t %s\t          %s\t %s\t %s\t          %d\t %s\t %s\t %s\t",
 		wOutput.DividendProjected[0].Month,
 		util.SPrintValue(wOutput.DividendProjected[0].Projected, precision),
 		util.SPrintPercentage(wOutput.DividendProjected[0].Yield, precision),
@@ -201,6 +201,7 @@ func (s *screenWalletDetails) renderWalletDividendProjected(tw *tabwriter.Writer
 		now.Year(),
 		util.SPrintValue(wOutput.DividendYearProjected, precision),
 		util.SPrintPercentage(wOutput.DividendYearYield, precision),
+		util.SPrintValue(wOutput.ConnectivityYear, precision),
 	))
 }
 
@@ -210,7 +211,7 @@ func (s *screenWalletDetails) renderStocksDividends(tw *tabwriter.Writer, wStock
 	noColor(tw, "")
 
 	header := color.New(color.FgWhite).FprintlnFunc()
-	header(tw, "#\t Stock\t Market\t Symbol\t AMT\t Invested\t Ex Date\t Dividend\t Retention (%)\t D. Yield\t WA D. Yield\t D. Pay\t")
+	header(tw, "#\t Stock\t Market\t Symbol\t AMT\t Invested\t Ex Date\t Dividend\t Retention (%)\t D. Yield\t WA D. Yield\t YoC\t D. Growth\t D. on Cost\t D. Pay\t")
 
 	inNormal := color.New(color.FgWhite).FprintlnFunc()
 	inHeightLight := color.New(color.FgYellow).FprintlnFunc()
@@ -225,12 +226,12 @@ func (s *screenWalletDetails) renderStocksDividends(tw *tabwriter.Writer, wStock
 		}
 
 		str := fmt.Sprintf(
-			"%d\t %s\t %s\t %s\t %d\t %s\t %s %s\t %s\t %s %s\t %s\t %s\t %s\t",
+			"%d\t %s\t %s\t %s\t %s\t %s\t %s %s\t %s\t %s %s\t %s\t %s\t %s\t %s\t %s\t %s\t",
 			i+1,
 			util.SPrintTruncate(stk.Stock, 27),
 			stk.Market,
 			stk.Symbol,
-			stk.Amount,
+			util.SPrintAmount(stk.Amount),
 			util.SPrintValue(stk.Invested, precision),
 			util.SPrintDate(stk.ExDate),
 			util.SPrintInitialDividendStatus(stk.DividendStatus),
@@ -239,6 +240,9 @@ func (s *screenWalletDetails) renderStocksDividends(tw *tabwriter.Writer, wStock
 			strPercentageRetention,
 			util.SPrintPercentage(stk.DYield, precision),
 			util.SPrintPercentage(stk.WADYield, precision),
+			util.SPrintPercentage(stk.YieldOnCost, precision),
+			util.SPrintPercentage(stk.DividendGrowth, precision),
+			util.SPrintPercentage(stk.DividendOnCost, precision),
 			util.SPrintValue(stk.DividendToPay, precision),
 		)
 
@@ -264,12 +268,12 @@ func (s *screenWalletDetails) renderStocks(tw *tabwriter.Writer, wStocks []*Wall
 
 	for i, stk := range wStocks {
 		str := fmt.Sprintf(
-			"%d\t %s\t %s\t %s\t %d\t %s\t %s\t %s\t %s\t %.*f\t %.*f\t %s\t %.*f\t %.*f\t %s\t",
+			"%d\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %s\t %.*f\t %.*f\t %s\t %.*f\t %.*f\t %s\t",
 			i+1,
 			util.SPrintTruncate(stk.Stock, 27),
 			stk.Market,
 			stk.Symbol,
-			stk.Amount,
+			util.SPrintAmount(stk.Amount),
 			util.SPrintValue(stk.Value, precision),
 			util.SPrintValue(stk.WAPrice, precision),
 			util.SPrintValue(stk.High52Week, precision),
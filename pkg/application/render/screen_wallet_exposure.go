@@ -0,0 +1,73 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
+
+type (
+	OutputScreenWalletExposure struct {
+		Exposure WalletExposureOutput
+
+		Precision int
+	}
+
+	screenWalletExposure struct{}
+)
+
+func NewScreenWalletExposure() *screenWalletExposure {
+	return &screenWalletExposure{}
+}
+
+func (s *screenWalletExposure) Render(output interface{}) {
+	sOutput := output.(*OutputScreenWalletExposure)
+
+	eOutput := sOutput.Exposure
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	inYellow := color.New(color.FgYellow).FprintlnFunc()
+
+	noColor(tw, "")
+	noColor(tw, fmt.Sprintf("# Exposure %s (capital %s)", eOutput.Wallet, util.SPrintValue(eOutput.Capital, precision)))
+
+	s.renderGroup(tw, "Sector", eOutput.Sectors, precision)
+	s.renderGroup(tw, "Country", eOutput.Countries, precision)
+	s.renderGroup(tw, "Stock", eOutput.Stocks, precision)
+
+	noColor(tw, "")
+
+	if len(eOutput.NotLookedThrough) > 0 {
+		inYellow(tw, fmt.Sprintf("ETFs without constituents: %s", strings.Join(eOutput.NotLookedThrough, ", ")))
+		noColor(tw, "")
+	}
+
+	tw.Flush()
+}
+
+func (s *screenWalletExposure) renderGroup(tw *tabwriter.Writer, name string, esOutput []*ExposureOutput, precision int) {
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+
+	noColor(tw, "")
+	header(tw, fmt.Sprintf("#\t %s\t Capital\t %%\t", name))
+
+	for i, e := range esOutput {
+		inNormal(tw, fmt.Sprintf(
+			"%d\t %s\t %s\t %s\t",
+			i+1,
+			e.Name,
+			util.SPrintValue(e.Capital, precision),
+			util.SPrintPercentage(e.Percentage, precision),
+		))
+	}
+}
@@ -0,0 +1,98 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/application/util"
+)
+
+type (
+	OutputScreenWalletLedger struct {
+		Ledger WalletLedgerOutput
+
+		Precision int
+	}
+
+	screenWalletLedger struct{}
+)
+
+func NewScreenWalletLedger() *screenWalletLedger {
+	return &screenWalletLedger{}
+}
+
+func (s *screenWalletLedger) Render(output interface{}) {
+	sOutput := output.(*OutputScreenWalletLedger)
+
+	lOutput := sOutput.Ledger
+	precision := sOutput.Precision
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+	inIncome := color.New(color.FgGreen).FprintlnFunc()
+	inExpense := color.New(color.FgRed).FprintlnFunc()
+
+	noColor(tw, "")
+	noColor(tw, fmt.Sprintf("# Ledger %s (%s - %s)", lOutput.Wallet, util.SPrintDate(lOutput.From), util.SPrintDate(lOutput.To)))
+	noColor(tw, "")
+
+	header(tw, "#\t Date\t Kind\t Description\t Amount\t Balance\t Operation\t")
+	inNormal(tw, fmt.Sprintf("\t \t \t Opening balance\t \t %s\t \t", util.SPrintValue(lOutput.Opening, precision)))
+
+	for i, e := range lOutput.Entries {
+		var oID string
+		if e.OperationID != uuid.Nil {
+			oID = e.OperationID.String()
+		}
+
+		str := fmt.Sprintf(
+			"%d\t %s\t %s\t %s\t %s\t %s\t %s\t",
+			i+1,
+			util.SPrintDate(e.Date),
+			e.Kind,
+			util.SPrintTruncate(e.Description, 40),
+			util.SPrintValue(e.Amount, precision),
+			util.SPrintValue(e.Balance, precision),
+			oID,
+		)
+
+		if e.Amount.Amount < 0 {
+			inExpense(tw, str)
+		} else {
+			inIncome(tw, str)
+		}
+	}
+
+	inNormal(tw, fmt.Sprintf("\t \t \t Closing balance\t \t %s\t \t", util.SPrintValue(lOutput.Closing, precision)))
+
+	if lOutput.CompareFunds {
+		noColor(tw, "")
+		header(tw, "Funds\t Ledger balance\t Difference\t")
+
+		diff := lOutput.Funds.Decrease(lOutput.Closing)
+
+		pColor := inIncome
+		if math.Abs(diff.Amount) >= 0.01 {
+			pColor = inExpense
+		}
+
+		pColor(tw, fmt.Sprintf(
+			"%s\t %s\t %s\t",
+			util.SPrintValue(lOutput.Funds, precision),
+			util.SPrintValue(lOutput.Closing, precision),
+			util.SPrintValue(diff, precision),
+		))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
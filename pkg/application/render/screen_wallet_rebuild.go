@@ -0,0 +1,66 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+)
+
+type (
+	OutputScreenWalletRebuild struct {
+		Rebuild WalletRebuildOutput
+	}
+
+	screenWalletRebuild struct{}
+)
+
+func NewScreenWalletRebuild() *screenWalletRebuild {
+	return &screenWalletRebuild{}
+}
+
+func (s *screenWalletRebuild) Render(output interface{}) {
+	sOutput := output.(*OutputScreenWalletRebuild)
+
+	rOutput := sOutput.Rebuild
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.Debug)
+
+	noColor := color.New(color.Reset).FprintlnFunc()
+	header := color.New(color.FgWhite).FprintlnFunc()
+	inNormal := color.New(color.FgWhite).FprintlnFunc()
+	inGreen := color.New(color.FgGreen).FprintlnFunc()
+	inRed := color.New(color.FgRed).FprintlnFunc()
+
+	noColor(tw, "")
+	noColor(tw, fmt.Sprintf("# Rebuild %s", rOutput.Wallet))
+	noColor(tw, "")
+
+	if len(rOutput.Differences) == 0 {
+		inGreen(tw, "Stored wallet matches the transfers and operations")
+		noColor(tw, "")
+
+		tw.Flush()
+
+		return
+	}
+
+	header(tw, "Scope\t Name\t Field\t Stored\t Rebuilt\t")
+
+	for _, d := range rOutput.Differences {
+		inNormal(tw, fmt.Sprintf("%s\t %s\t %s\t %s\t %s\t", d.Scope, d.Name, d.Field, d.Stored, d.Rebuilt))
+	}
+
+	noColor(tw, "")
+
+	if rOutput.Applied {
+		inGreen(tw, fmt.Sprintf("%d corrections applied", len(rOutput.Differences)))
+	} else {
+		inRed(tw, fmt.Sprintf("%d differences found, use --apply to correct them", len(rOutput.Differences)))
+	}
+
+	noColor(tw, "")
+
+	tw.Flush()
+}
@@ -97,7 +97,9 @@ func (s *Account) UpdateWalletsAccountingByTransfers(ts []*transfer.Transfer) er
 					ws = append(ws, w)
 				}
 
-				w.IncreaseInvestment(t.Amount)
+				if err := w.TransferIn(t); err != nil {
+					return err
+				}
 				continue
 			}
 
@@ -105,7 +107,9 @@ func (s *Account) UpdateWalletsAccountingByTransfers(ts []*transfer.Transfer) er
 			ws = append(ws, w)
 		}
 
-		w.DecreaseInvestment(t.Amount)
+		if err := w.TransferOut(t); err != nil {
+			return err
+		}
 	}
 
 	return s.walletPersister.UpdateAllAccounting(ws)
@@ -318,7 +322,7 @@ func (s *Account) createOperation(
 		}
 	}
 
-	o := operation.NewOperation(now, stk, action, amount, stk.Value, pChange, pChangeCommission, oValue, commission)
+	o := operation.NewOperation(now, stk, action, float64(amount), stk.Value, pChange, pChangeCommission, oValue, commission)
 
 	return o
 }
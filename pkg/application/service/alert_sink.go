@@ -0,0 +1,161 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+)
+
+const alertDateFormat = "2006-01-02 15:04:05"
+
+type (
+	writerAlertSink struct {
+		mu sync.Mutex
+		w  io.Writer
+	}
+
+	fileAlertSink struct {
+		path string
+	}
+
+	webhookAlertSink struct {
+		url    string
+		client *http.Client
+	}
+
+	smtpAlertSink struct {
+		addr string
+		auth smtp.Auth
+		from string
+		to   []string
+	}
+
+	webhookAlertPayload struct {
+		Date    string `json:"date"`
+		Symbol  string `json:"symbol"`
+		Subject string `json:"subject"`
+		Message string `json:"message"`
+	}
+)
+
+// NewStdoutAlertSink delivers the alerts to the standard output
+func NewStdoutAlertSink() *writerAlertSink {
+	return NewWriterAlertSink(os.Stdout)
+}
+
+// NewWriterAlertSink delivers the alerts to the writer, one line per alert
+func NewWriterAlertSink(w io.Writer) *writerAlertSink {
+	return &writerAlertSink{
+		w: w,
+	}
+}
+
+func (s *writerAlertSink) Notify(a alert.Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintln(s.w, formatAlertLine(a))
+
+	return err
+}
+
+// NewFileAlertSink appends the alerts to the file, one line per alert
+func NewFileAlertSink(path string) *fileAlertSink {
+	return &fileAlertSink{
+		path: path,
+	}
+}
+
+func (s *fileAlertSink) Notify(a alert.Alert) error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "open alert log file %q", s.path)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintln(f, formatAlertLine(a))
+
+	return err
+}
+
+// NewWebhookAlertSink posts the alerts as json to the url
+func NewWebhookAlertSink(url string, client *http.Client) *webhookAlertSink {
+	return &webhookAlertSink{
+		url:    url,
+		client: client,
+	}
+}
+
+func (s *webhookAlertSink) Notify(a alert.Alert) error {
+	body, err := json.Marshal(webhookAlertPayload{
+		Date:    a.Date.Format(alertDateFormat),
+		Symbol:  a.Stock.Symbol,
+		Subject: a.Subject,
+		Message: a.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "post alert to webhook %q", s.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("post alert to webhook %q responded with status %d", s.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// NewSMTPAlertSink mails the alerts. The authentication is skipped when the username is empty
+func NewSMTPAlertSink(host string, port int, username, password, from string, to []string) *smtpAlertSink {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpAlertSink{
+		addr: fmt.Sprintf("%s:%d", host, port),
+		auth: auth,
+		from: from,
+		to:   to,
+	}
+}
+
+func (s *smtpAlertSink) Notify(a alert.Alert) error {
+	if len(s.to) == 0 {
+		return errors.New("missing alert mail recipients")
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		s.from,
+		strings.Join(s.to, ", "),
+		a.Subject,
+		a.Message,
+	)
+
+	err := smtp.SendMail(s.addr, s.auth, s.from, s.to, []byte(msg))
+	if err != nil {
+		return errors.Wrapf(err, "mail alert through %q", s.addr)
+	}
+
+	return nil
+}
+
+func formatAlertLine(a alert.Alert) string {
+	return fmt.Sprintf("[%s] %s: %s", a.Date.Format(alertDateFormat), a.Subject, a.Message)
+}
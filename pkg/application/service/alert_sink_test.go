@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// smtpStub is a local SMTP server accepting one mail, the commands and the data received are kept
+type smtpStub struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpStub{
+		listener: l,
+		done:     make(chan struct{}),
+	}
+
+	go s.serve()
+
+	return s
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	r := bufio.NewReader(conn)
+	reply := func(l string) {
+		conn.Write([]byte(l + "\r\n"))
+	}
+
+	reply("220 localhost stub")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.TrimRight(line, "\r\n")
+		s.commands = append(s.commands, cmd)
+
+		switch verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			reply("250 OK")
+		case "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+
+			var data []string
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if l == ".\r\n" {
+					break
+				}
+
+				data = append(data, l)
+			}
+
+			s.data = strings.Join(data, "")
+
+			reply("250 OK")
+		case "QUIT":
+			reply("221 bye")
+
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) close() {
+	s.listener.Close()
+	<-s.done
+}
+
+func TestSMTPAlertSinkNotify(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	sink := NewSMTPAlertSink("127.0.0.1", stub.port(), "", "", "mm@localhost", []string{"me@localhost", "you@localhost"})
+
+	err := sink.Notify(alert.Alert{
+		Date:    time.Date(2018, 5, 4, 10, 30, 0, 0, time.UTC),
+		Stock:   &stock.Stock{Symbol: "AAPL"},
+		Subject: "AAPL price_below",
+		Message: "AAPL price 150.00 USD is below 160.00",
+	})
+	stub.close()
+
+	assert.NoError(t, err)
+	assert.Contains(t, stub.commands, "MAIL FROM:<mm@localhost>")
+	assert.Contains(t, stub.commands, "RCPT TO:<me@localhost>")
+	assert.Contains(t, stub.commands, "RCPT TO:<you@localhost>")
+	assert.Contains(t, stub.data, "From: mm@localhost\r\n")
+	assert.Contains(t, stub.data, "To: me@localhost, you@localhost\r\n")
+	assert.Contains(t, stub.data, "Subject: AAPL price_below\r\n")
+	assert.Contains(t, stub.data, "\r\n\r\nAAPL price 150.00 USD is below 160.00\r\n")
+}
+
+func TestSMTPAlertSinkNotifyWithoutRecipients(t *testing.T) {
+	sink := NewSMTPAlertSink("127.0.0.1", 25, "", "", "mm@localhost", nil)
+
+	err := sink.Notify(alert.Alert{Stock: &stock.Stock{Symbol: "AAPL"}})
+
+	assert.EqualError(t, err, "missing alert mail recipients")
+}
+
+func TestSMTPAlertSinkNotifyRejected(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("554 no service\r\n"))
+	}()
+
+	defer l.Close()
+
+	sink := NewSMTPAlertSink("127.0.0.1", l.Addr().(*net.TCPAddr).Port, "", "", "mm@localhost", []string{"me@localhost"})
+
+	err = sink.Notify(alert.Alert{Stock: &stock.Stock{Symbol: "AAPL"}, Subject: "AAPL price_below"})
+
+	assert.Error(t, err)
+}
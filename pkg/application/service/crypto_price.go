@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// fileCryptoPrice reads the price of the cryptocurrency pairs from a csv file with the columns
+// symbol, price, change, high 52 week and low 52 week. The file is read on every request, the prices
+// are as fresh as whatever keeps the file updated, at any time of the week.
+type fileCryptoPrice struct {
+	path string
+}
+
+var _ StockPrice = &fileCryptoPrice{}
+
+func NewFileCryptoPrice(path string) *fileCryptoPrice {
+	return &fileCryptoPrice{
+		path: path,
+	}
+}
+
+func (s *fileCryptoPrice) Price(stk *stock.Stock) (stock.Price, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return stock.Price{}, errors.Wrap(err, "open crypto prices file")
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+
+	for {
+		line, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return stock.Price{}, errors.Wrap(err, "read crypto prices file")
+		}
+
+		if len(line) < 2 || !strings.EqualFold(strings.TrimSpace(line[0]), stk.Symbol) {
+			continue
+		}
+
+		p := stock.Price{
+			Date: time.Now(),
+		}
+
+		values := []*float64{&p.Close, &p.Change, &p.High52Week, &p.Low52Week}
+		for i, v := range values {
+			if i+1 >= len(line) || strings.TrimSpace(line[i+1]) == "" {
+				break
+			}
+
+			*v, err = strconv.ParseFloat(strings.TrimSpace(line[i+1]), 64)
+			if err != nil {
+				return stock.Price{}, errors.Wrapf(err, "parse price of %s", stk.Symbol)
+			}
+		}
+
+		p.High = p.Close
+		p.Low = p.Close
+
+		return p, nil
+	}
+
+	return stock.Price{}, errors.Errorf("price of %s not found in %s", stk.Symbol, s.path)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"time"
 
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
 )
@@ -27,3 +28,7 @@ type StockDividend interface {
 	Future(stk *stock.Stock) ([]dividend.StockDividend, error)
 	Historical(stk *stock.Stock, fromDate time.Time) ([]dividend.StockDividend, error)
 }
+
+type AlertSink interface {
+	Notify(a alert.Alert) error
+}
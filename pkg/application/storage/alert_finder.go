@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	alertFinder struct {
+		db sqlx.Queryer
+	}
+
+	alertRuleTuple struct {
+		ID            uuid.UUID     `db:"id"`
+		StockID       uuid.NullUUID `db:"stock_id"`
+		StockSymbol   string        `db:"stock_symbol"`
+		WatchlistID   uuid.NullUUID `db:"watchlist_id"`
+		WatchlistName string        `db:"watchlist_name"`
+		Condition     string        `db:"condition"`
+		Value         float64       `db:"value"`
+	}
+
+	alertRuleTriggerTuple struct {
+		AlertRuleID     uuid.UUID `db:"alert_rule_id"`
+		StockID         uuid.UUID `db:"stock_id"`
+		LastTriggeredAt time.Time `db:"last_triggered_at"`
+	}
+)
+
+var _ alert.Finder = &alertFinder{}
+
+const alertRuleSelect = `
+	SELECT ar.id, ar.stock_id, COALESCE(s.symbol, '') AS stock_symbol, ar.watchlist_id,
+		COALESCE(w.name, '') AS watchlist_name, ar.condition, ar.value
+	FROM alert_rule ar
+	LEFT JOIN stock s ON ar.stock_id = s.id
+	LEFT JOIN watchlist w ON ar.watchlist_id = w.id
+`
+
+func NewAlertFinder(db sqlx.Queryer) *alertFinder {
+	return &alertFinder{
+		db: db,
+	}
+}
+
+func (f *alertFinder) FindByID(ID uuid.UUID) (*alert.Rule, error) {
+	var tuple alertRuleTuple
+
+	query := alertRuleSelect + `WHERE ar.id = $1`
+
+	err := sqlx.Get(f.db, &tuple, query, ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select alert rule with id %q", ID)
+	}
+
+	r := f.hydrate(tuple)
+
+	if err = f.loadTriggers([]*alert.Rule{r}); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (f *alertFinder) FindAll() ([]*alert.Rule, error) {
+	var tuples []alertRuleTuple
+
+	query := alertRuleSelect + `ORDER BY ar.created_at`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select alert rules")
+	}
+
+	rs := f.hydrateAll(tuples)
+
+	if err = f.loadTriggers(rs); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+func (f *alertFinder) FindAllByStock(stockID uuid.UUID) ([]*alert.Rule, error) {
+	var tuples []alertRuleTuple
+
+	query := alertRuleSelect + `
+		WHERE ar.stock_id = $1
+		OR ar.watchlist_id IN (SELECT watchlist_id FROM watchlist_stock WHERE stock_id = $1)
+		ORDER BY ar.created_at
+	`
+
+	err := sqlx.Select(f.db, &tuples, query, stockID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select alert rules with stock id %q", stockID)
+	}
+
+	rs := f.hydrateAll(tuples)
+
+	if err = f.loadTriggers(rs); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// loadTriggers loads when the rules were last triggered by each stock
+func (f *alertFinder) loadTriggers(rs []*alert.Rule) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	rules := make(map[uuid.UUID]*alert.Rule, len(rs))
+	ids := make([]uuid.UUID, 0, len(rs))
+
+	for _, r := range rs {
+		rules[r.ID] = r
+		ids = append(ids, r.ID)
+	}
+
+	query, args, err := sqlx.In(
+		`SELECT alert_rule_id, stock_id, last_triggered_at FROM alert_rule_trigger WHERE alert_rule_id IN (?)`,
+		ids,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Select alert rule triggers")
+	}
+
+	var tuples []alertRuleTriggerTuple
+
+	err = sqlx.Select(f.db, &tuples, sqlx.Rebind(sqlx.DOLLAR, query), args...)
+	if err != nil && err != sql.ErrNoRows {
+		return errors.Wrap(err, "Select alert rule triggers")
+	}
+
+	for _, tuple := range tuples {
+		rules[tuple.AlertRuleID].LastTriggered[tuple.StockID] = tuple.LastTriggeredAt
+	}
+
+	return nil
+}
+
+func (f *alertFinder) hydrateAll(tuples []alertRuleTuple) []*alert.Rule {
+	var rs []*alert.Rule
+	for _, tuple := range tuples {
+		rs = append(rs, f.hydrate(tuple))
+	}
+
+	return rs
+}
+
+func (f *alertFinder) hydrate(tuple alertRuleTuple) *alert.Rule {
+	r := &alert.Rule{
+		ID:            tuple.ID,
+		Condition:     alert.Condition(tuple.Condition),
+		Value:         tuple.Value,
+		LastTriggered: map[uuid.UUID]time.Time{},
+	}
+
+	if tuple.StockID.Valid {
+		r.Stock = &stock.Stock{
+			ID:     tuple.StockID.UUID,
+			Symbol: tuple.StockSymbol,
+		}
+	}
+
+	if tuple.WatchlistID.Valid {
+		r.Watchlist = &watchlist.Watchlist{
+			ID:   tuple.WatchlistID.UUID,
+			Name: tuple.WatchlistName,
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/alert"
+)
+
+type (
+	// alertPersister struct to hold necessary dependencies
+	alertPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ alert.Persister = &alertPersister{}
+
+func NewAlertPersister(db *sqlx.DB) *alertPersister {
+	return &alertPersister{
+		db: db,
+	}
+}
+
+func (p *alertPersister) Persist(r *alert.Rule) error {
+	query := `
+		INSERT INTO alert_rule(id, stock_id, watchlist_id, condition, value)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE
+		SET condition = excluded.condition,
+			value = excluded.value
+	`
+
+	var stockID, watchlistID uuid.NullUUID
+	if r.Stock != nil {
+		stockID = uuid.NullUUID{UUID: r.Stock.ID, Valid: true}
+	}
+	if r.Watchlist != nil {
+		watchlistID = uuid.NullUUID{UUID: r.Watchlist.ID, Valid: true}
+	}
+
+	_, err := p.db.Exec(query, r.ID, stockID, watchlistID, r.Condition, r.Value)
+	if err != nil {
+		return errors.Wrapf(err, "Persist alert rule %q", r.ID)
+	}
+
+	return nil
+}
+
+func (p *alertPersister) Delete(r *alert.Rule) error {
+	query := `DELETE FROM alert_rule WHERE id = $1`
+
+	_, err := p.db.Exec(query, r.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Delete alert rule %q", r.ID)
+	}
+
+	return nil
+}
+
+func (p *alertPersister) UpdateLastTriggered(r *alert.Rule, stockID uuid.UUID) error {
+	query := `
+		INSERT INTO alert_rule_trigger(alert_rule_id, stock_id, last_triggered_at) VALUES ($1, $2, $3)
+		ON CONFLICT (alert_rule_id, stock_id) DO UPDATE
+		SET last_triggered_at = excluded.last_triggered_at
+	`
+
+	_, err := p.db.Exec(query, r.ID, stockID, r.LastTriggered[stockID])
+	if err != nil {
+		return errors.Wrapf(err, "Update alert rule %q last triggered by stock %q", r.ID, stockID)
+	}
+
+	return nil
+}
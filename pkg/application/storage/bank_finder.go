@@ -2,11 +2,11 @@ package storage
 
 import (
 	"database/sql"
-	"errors"
-	"fmt"
+	"strings"
 
 	"github.com/almerlucke/go-iban/iban"
 	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
@@ -24,6 +24,7 @@ type (
 		AccountNo     string    `db:"account_no"`
 		Alias         string    `db:"alias"`
 		AccountNoType string    `db:"account_no_type"`
+		AccountType   string    `db:"account_type"`
 	}
 )
 
@@ -35,6 +36,29 @@ func NewBankAccountFinder(db sqlx.Queryer) *bankAccountFinder {
 	}
 }
 
+func (f *bankAccountFinder) FindAll() ([]*bank.Account, error) {
+	var tuples []bankAccountTuple
+
+	query := `SELECT * FROM bank_account ORDER BY alias`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select bank accounts")
+	}
+
+	var as []*bank.Account
+	for _, tuple := range tuples {
+		a, err := hydrateBankAccount(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		as = append(as, a)
+	}
+
+	return as, nil
+}
+
 func (f *bankAccountFinder) FindByAlias(alias string) (*bank.Account, error) {
 	var tuple bankAccountTuple
 
@@ -46,23 +70,50 @@ func (f *bankAccountFinder) FindByAlias(alias string) (*bank.Account, error) {
 			return nil, mm.ErrNotFound
 		}
 
-		return nil, errors.New(fmt.Sprintf("Select bank_account form alias %q", alias))
+		return nil, errors.Errorf("Select bank_account form alias %q", alias)
+	}
+
+	return hydrateBankAccount(&tuple)
+}
+
+// FindByAccountNo finds the bank account by its number, regardless of the spaces of the print format
+func (f *bankAccountFinder) FindByAccountNo(accountNo string) (*bank.Account, error) {
+	var tuple bankAccountTuple
+
+	query := `SELECT * FROM bank_account WHERE upper(replace(account_no, ' ', '')) = $1`
+
+	accountNo = strings.ToUpper(strings.Replace(accountNo, " ", "", -1))
+
+	err := sqlx.Get(f.db, &tuple, query, accountNo)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select bank_account from account no %q", accountNo)
 	}
 
-	var AccountNo string
+	return hydrateBankAccount(&tuple)
+}
+
+// hydrateBankAccount builds the bank account, the IBAN account numbers are given in their print format
+func hydrateBankAccount(tuple *bankAccountTuple) (*bank.Account, error) {
+	accountNo := tuple.AccountNo
 	if bank.AccountNoType(tuple.AccountNoType) == bank.IBAN {
 		IBAN, err := iban.NewIBAN(tuple.AccountNo)
 		if err != nil {
 			return nil, err
 		}
 
-		AccountNo = IBAN.PrintCode
+		accountNo = IBAN.PrintCode
 	}
 
 	return &bank.Account{
-		ID:        tuple.ID,
-		Name:      tuple.Name,
-		AccountNo: AccountNo,
-		Alias:     alias,
+		ID:            tuple.ID,
+		Name:          tuple.Name,
+		AccountNo:     accountNo,
+		Alias:         tuple.Alias,
+		AccountNoType: bank.AccountNoType(tuple.AccountNoType),
+		Type:          bank.AccountType(tuple.AccountType),
 	}, nil
 }
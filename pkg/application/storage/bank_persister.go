@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+)
+
+type (
+	// bankAccountPersister struct to hold necessary dependencies
+	bankAccountPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ bank.Persister = &bankAccountPersister{}
+
+func NewBankAccountPersister(db *sqlx.DB) *bankAccountPersister {
+	return &bankAccountPersister{
+		db: db,
+	}
+}
+
+// Persist stores the bank account, updating it when it already exists
+func (p *bankAccountPersister) Persist(a *bank.Account) error {
+	query := `
+		INSERT INTO bank_account(id, name, account_no, alias, account_no_type, account_type)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = excluded.name,
+			account_no = excluded.account_no,
+			alias = excluded.alias,
+			account_no_type = excluded.account_no_type,
+			account_type = excluded.account_type
+	`
+
+	_, err := p.db.Exec(query, a.ID, a.Name, a.AccountNo, a.Alias, a.AccountNoType, a.Type)
+	if err != nil {
+		return errors.Wrapf(err, "Persist bank account %q", a.Alias)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+)
+
+type (
+	brokerFinder struct {
+		db sqlx.Queryer
+	}
+
+	brokerTuple struct {
+		ID           uuid.UUID `db:"id"`
+		Name         string    `db:"name"`
+		Retention    float64   `db:"retention"`
+		Margin       float64   `db:"margin"`
+		Connectivity string    `db:"connectivity"`
+		ImportFormat string    `db:"import_format"`
+	}
+
+	brokerCommissionTuple struct {
+		Exchange        string  `db:"symbol"`
+		Base            float64 `db:"base"`
+		BaseCurrency    string  `db:"base_currency"`
+		Extra           float64 `db:"extra"`
+		ExtraCurrency   string  `db:"extra_currency"`
+		ExtraApply      string  `db:"extra_apply"`
+		Maximum         float64 `db:"maximum"`
+		MaximumCurrency string  `db:"maximum_currency"`
+		Change          float64 `db:"change"`
+		ChangeCurrency  string  `db:"change_currency"`
+	}
+)
+
+var _ broker.Finder = &brokerFinder{}
+
+func NewBrokerFinder(db sqlx.Queryer) *brokerFinder {
+	return &brokerFinder{
+		db: db,
+	}
+}
+
+func (f *brokerFinder) FindByName(name string) (*broker.Broker, error) {
+	var tuple brokerTuple
+
+	query := `SELECT * FROM broker WHERE name ilike $1`
+
+	err := sqlx.Get(f.db, &tuple, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select broker with name %q", name)
+	}
+
+	return f.hydrate(&tuple)
+}
+
+func (f *brokerFinder) FindByID(ID uuid.UUID) (*broker.Broker, error) {
+	var tuple brokerTuple
+
+	query := `SELECT * FROM broker WHERE id = $1`
+
+	err := sqlx.Get(f.db, &tuple, query, ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select broker with id %q", ID)
+	}
+
+	return f.hydrate(&tuple)
+}
+
+func (f *brokerFinder) FindAll() ([]*broker.Broker, error) {
+	var tuples []brokerTuple
+
+	query := `SELECT * FROM broker ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrap(err, "Select brokers")
+	}
+
+	var bs []*broker.Broker
+	for _, tuple := range tuples {
+		b, err := f.hydrate(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		bs = append(bs, b)
+	}
+
+	return bs, nil
+}
+
+func (f *brokerFinder) hydrate(tuple *brokerTuple) (*broker.Broker, error) {
+	b := &broker.Broker{
+		ID:           tuple.ID,
+		Name:         tuple.Name,
+		Retention:    tuple.Retention,
+		Margin:       tuple.Margin,
+		Connectivity: mm.ValueEuroFromString(tuple.Connectivity),
+		ImportFormat: broker.ImportFormat(tuple.ImportFormat),
+		Commissions:  map[string]broker.Commission{},
+	}
+
+	var tuples []brokerCommissionTuple
+
+	query := `SELECT e.symbol, bc.base, bc.base_currency, bc.extra, bc.extra_currency, bc.extra_apply,
+				bc.maximum, bc.maximum_currency, bc.change, bc.change_currency
+			FROM broker_commission bc
+			INNER JOIN exchange e ON bc.exchange_id = e.id
+			WHERE bc.broker_id = $1`
+
+	err := sqlx.Select(f.db, &tuples, query, b.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "Select broker commissions with broker id %q", b.ID)
+	}
+
+	for _, tuple := range tuples {
+		b.Commissions[tuple.Exchange] = broker.Commission{
+			Base:    mm.Value{Amount: tuple.Base, Currency: mm.Currency(tuple.BaseCurrency)},
+			Extra:   mm.Value{Amount: tuple.Extra, Currency: mm.Currency(tuple.ExtraCurrency)},
+			Apply:   broker.CommissionApply(tuple.ExtraApply),
+			Maximum: mm.Value{Amount: tuple.Maximum, Currency: mm.Currency(tuple.MaximumCurrency)},
+			Change:  mm.Value{Amount: tuple.Change, Currency: mm.Currency(tuple.ChangeCurrency)},
+		}
+	}
+
+	return b, nil
+}
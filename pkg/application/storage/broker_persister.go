@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+)
+
+type (
+	// brokerPersister struct to hold necessary dependencies
+	brokerPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ broker.Persister = &brokerPersister{}
+
+func NewBrokerPersister(db *sqlx.DB) *brokerPersister {
+	return &brokerPersister{
+		db: db,
+	}
+}
+
+func (p *brokerPersister) Persist(b *broker.Broker) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		if err := p.execUpsert(tx, b); err != nil {
+			return err
+		}
+
+		return p.execCommissionUpsert(tx, b)
+	})
+}
+
+func (p *brokerPersister) execUpsert(tx *sqlx.Tx, b *broker.Broker) error {
+	query := `
+		INSERT INTO broker(id, name, retention, margin, connectivity, import_format) 
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE
+		SET name = excluded.name,
+			retention = excluded.retention, 
+			margin = excluded.margin, 
+			connectivity = excluded.connectivity, 
+			import_format = excluded.import_format
+	`
+
+	_, err := tx.Exec(query, b.ID, b.Name, b.Retention, b.Margin, b.Connectivity.Amount, b.ImportFormat)
+	if err != nil {
+		return errors.Wrapf(err, "execUpsert")
+	}
+
+	return nil
+}
+
+func (p *brokerPersister) execCommissionUpsert(tx *sqlx.Tx, b *broker.Broker) error {
+	query := `
+		INSERT INTO broker_commission(
+			broker_id, 
+			exchange_id, 
+			base, 
+			base_currency, 
+			extra, 
+			extra_currency, 
+			extra_apply, 
+			maximum, 
+			maximum_currency, 
+			change, 
+			change_currency
+		) 
+		SELECT $1, e.id, $3, $4, $5, $6, $7, $8, $9, $10, $11 FROM exchange e WHERE e.symbol = $2
+		ON CONFLICT (broker_id, exchange_id) DO UPDATE
+		SET base = excluded.base,
+			base_currency = excluded.base_currency,
+			extra = excluded.extra,
+			extra_currency = excluded.extra_currency,
+			extra_apply = excluded.extra_apply,
+			maximum = excluded.maximum,
+			maximum_currency = excluded.maximum_currency,
+			change = excluded.change,
+			change_currency = excluded.change_currency
+	`
+
+	for symbol, c := range b.Commissions {
+		_, err := tx.Exec(
+			query,
+			b.ID,
+			symbol,
+			c.Base.Amount,
+			c.Base.Currency,
+			c.Extra.Amount,
+			c.Extra.Currency,
+			c.Apply,
+			c.Maximum.Amount,
+			c.Maximum.Currency,
+			c.Change.Amount,
+			c.Change.Currency,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "execCommissionUpsert")
+		}
+	}
+
+	return nil
+}
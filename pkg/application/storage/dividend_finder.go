@@ -30,6 +30,15 @@ type (
 		ChangeFromPrevYear string    `db:"change_from_prev_year"`
 		Prior12MonthsYield string    `db:"prior_12_months_yield"`
 	}
+
+	dividendChangeTuple struct {
+		StockID        uuid.UUID `db:"stock_id"`
+		Type           string    `db:"type"`
+		ExDate         time.Time `db:"ex_date"`
+		PreviousAmount string    `db:"previous_amount"`
+		Amount         string    `db:"amount"`
+		DetectedAt     time.Time `db:"detected_at"`
+	}
 )
 
 func NewStockDividendFinder(db sqlx.Queryer) *stockDividendFinder {
@@ -187,3 +196,33 @@ func (f *stockDividendFinder) FindUpcoming(ID uuid.UUID) (dividend.StockDividend
 
 	return f.hydrate(tuple), nil
 }
+
+func (f *stockDividendFinder) FindAllChangesFrom(from time.Time) ([]dividend.Change, error) {
+	var tuples []dividendChangeTuple
+
+	query := `
+		SELECT stock_id, type, ex_date, previous_amount, amount, detected_at
+		FROM stock_dividend_change
+		WHERE detected_at >= $1
+		ORDER BY detected_at DESC, id DESC
+	`
+
+	err := sqlx.Select(f.db, &tuples, query, from)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select dividend changes from %s", from)
+	}
+
+	var cs []dividend.Change
+	for _, tuple := range tuples {
+		cs = append(cs, dividend.Change{
+			StockID:    tuple.StockID,
+			Type:       dividend.ChangeType(tuple.Type),
+			ExDate:     tuple.ExDate,
+			Previous:   mm.ValueDollarFromString(tuple.PreviousAmount),
+			Amount:     mm.ValueDollarFromString(tuple.Amount),
+			DetectedAt: tuple.DetectedAt,
+		})
+	}
+
+	return cs, nil
+}
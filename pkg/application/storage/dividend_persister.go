@@ -110,3 +110,21 @@ func (p *stockDividendPersister) execDelete(tx *sqlx.Tx, stockID uuid.UUID) erro
 
 	return nil
 }
+
+func (p *stockDividendPersister) PersistChanges(cs []dividend.Change) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO stock_dividend_change(stock_id, type, ex_date, previous_amount, amount, detected_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`
+
+		for _, c := range cs {
+			_, err := tx.Exec(query, c.StockID, c.Type, c.ExDate, c.Previous.Amount, c.Amount.Amount, c.DetectedAt)
+			if err != nil {
+				return errors.Wrapf(err, "INSERT INTO stock_dividend_change stock %s type %s", c.StockID, c.Type)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/etf"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	etfFinder struct {
+		db sqlx.Queryer
+	}
+
+	etfConstituentTuple struct {
+		ID      uuid.UUID     `db:"id"`
+		Name    string        `db:"name"`
+		Symbol  string        `db:"symbol"`
+		Sector  string        `db:"sector"`
+		Country string        `db:"country"`
+		Weight  float64       `db:"weight"`
+		StockID uuid.NullUUID `db:"stock_id"`
+	}
+)
+
+var _ etf.Finder = &etfFinder{}
+
+func NewETFFinder(db sqlx.Queryer) *etfFinder {
+	return &etfFinder{
+		db: db,
+	}
+}
+
+// FindByETF returns the holdings of the ETF. Linked stocks are only loaded with their id
+func (f *etfFinder) FindByETF(stk *stock.Stock) (*etf.Holdings, error) {
+	var tuples []etfConstituentTuple
+
+	query := `
+		SELECT id, name, COALESCE(symbol, '') AS symbol, COALESCE(sector, '') AS sector,
+			COALESCE(country, '') AS country, weight, stock_id
+		FROM etf_constituent
+		WHERE etf_id = $1
+		ORDER BY weight DESC
+	`
+
+	err := sqlx.Select(f.db, &tuples, query, stk.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select etf %q constituents", stk.Symbol)
+	}
+
+	if len(tuples) == 0 {
+		return nil, mm.ErrNotFound
+	}
+
+	h := etf.NewHoldings(stk)
+	for _, tuple := range tuples {
+		c := &etf.Constituent{
+			ID:      tuple.ID,
+			Name:    tuple.Name,
+			Symbol:  tuple.Symbol,
+			Sector:  tuple.Sector,
+			Country: tuple.Country,
+			Weight:  tuple.Weight,
+		}
+
+		if tuple.StockID.Valid {
+			c.Stock = &stock.Stock{ID: tuple.StockID.UUID}
+		}
+
+		h.AddConstituent(c)
+	}
+
+	return h, nil
+}
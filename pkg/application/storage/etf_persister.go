@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/etf"
+)
+
+type (
+	// etfPersister struct to hold necessary dependencies
+	etfPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ etf.Persister = &etfPersister{}
+
+func NewETFPersister(db *sqlx.DB) *etfPersister {
+	return &etfPersister{
+		db: db,
+	}
+}
+
+// Persist stores the holdings of the ETF, replacing the previous constituents
+func (p *etfPersister) Persist(h *etf.Holdings) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `DELETE FROM etf_constituent WHERE etf_id = $1`
+
+		if _, err := tx.Exec(query, h.ETF.ID); err != nil {
+			return errors.Wrapf(err, "Persist etf %q delete constituents", h.ETF.Symbol)
+		}
+
+		query = `
+			INSERT INTO etf_constituent(id, etf_id, name, symbol, sector, country, weight, stock_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+
+		for _, c := range h.Constituents {
+			var stockID uuid.NullUUID
+			if c.Stock != nil {
+				stockID = uuid.NullUUID{UUID: c.Stock.ID, Valid: true}
+			}
+
+			_, err := tx.Exec(query, c.ID, h.ETF.ID, c.Name, c.Symbol, c.Sector, c.Country, c.Weight, stockID)
+			if err != nil {
+				return errors.Wrapf(err, "Persist etf %q insert constituent %q", h.ETF.Symbol, c.Name)
+			}
+		}
+
+		return nil
+	})
+}
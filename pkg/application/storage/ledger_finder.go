@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/ledger"
+)
+
+type (
+	ledgerFinder struct {
+		db sqlx.Queryer
+	}
+
+	ledgerEntryTuple struct {
+		ID          uuid.UUID     `db:"id"`
+		Date        time.Time     `db:"date"`
+		Kind        string        `db:"kind"`
+		Description string        `db:"description"`
+		Amount      string        `db:"amount"`
+		Balance     string        `db:"balance"`
+		OperationID uuid.NullUUID `db:"operation_id"`
+		TransferID  uuid.NullUUID `db:"transfer_id"`
+	}
+)
+
+var _ ledger.Finder = &ledgerFinder{}
+
+func NewLedgerFinder(db sqlx.Queryer) *ledgerFinder {
+	return &ledgerFinder{
+		db: db,
+	}
+}
+
+func (f *ledgerFinder) FindAllByWalletAndDateRange(walletID uuid.UUID, from, to time.Time) ([]*ledger.Entry, error) {
+	var tuples []ledgerEntryTuple
+
+	query := `SELECT id, date, kind, COALESCE(description, '') AS description, amount, balance, operation_id, transfer_id
+			FROM wallet_ledger 
+			WHERE wallet_id = $1 AND date >= $2 AND date <= $3
+			ORDER BY date, seq`
+
+	err := sqlx.Select(f.db, &tuples, query, walletID, from, to)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select ledger entries from wallet %q", walletID)
+	}
+
+	var es []*ledger.Entry
+	for _, tuple := range tuples {
+		es = append(es, &ledger.Entry{
+			ID:          tuple.ID,
+			Date:        tuple.Date,
+			Kind:        ledger.Kind(tuple.Kind),
+			Description: tuple.Description,
+			Amount:      mm.ValueEuroFromString(tuple.Amount),
+			Balance:     mm.ValueEuroFromString(tuple.Balance),
+			OperationID: tuple.OperationID.UUID,
+			TransferID:  tuple.TransferID.UUID,
+		})
+	}
+
+	return es, nil
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	operationFinder struct {
+		db sqlx.Queryer
+	}
+
+	operationTuple struct {
+		ID                    uuid.UUID `db:"id"`
+		Date                  time.Time `db:"date"`
+		StockID               uuid.UUID `db:"stock_id"`
+		Action                string    `db:"action"`
+		Amount                float64   `db:"amount"`
+		Price                 string    `db:"price"`
+		PriceChange           string    `db:"price_change"`
+		PriceChangeCommission string    `db:"price_change_commission"`
+		Value                 string    `db:"value"`
+		Commission            string    `db:"commission"`
+	}
+)
+
+var _ operation.Finder = &operationFinder{}
+
+// operationSelect selects the operations in the order they have to be replayed,
+// buys before sells before the rest when they happen at the same date
+const operationSelect = `
+	SELECT id, date, COALESCE(stock_id, '00000000-0000-0000-0000-000000000000') AS stock_id, action,
+		COALESCE(amount, 0) AS amount, price, COALESCE(price_change, 0) AS price_change,
+		COALESCE(price_change_commission, 0) AS price_change_commission, value, COALESCE(commission, 0) AS commission
+	FROM operation`
+
+const operationOrder = `
+	ORDER BY date, CASE action WHEN 'buy' THEN 0 WHEN 'sell' THEN 1 WHEN 'redemption' THEN 1 ELSE 2 END`
+
+func NewOperationFinder(db sqlx.Queryer) *operationFinder {
+	return &operationFinder{
+		db: db,
+	}
+}
+
+func (f *operationFinder) FindByID(walletID, ID uuid.UUID) (*operation.Operation, error) {
+	var tuple operationTuple
+
+	query := operationSelect + ` WHERE wallet_id = $1 AND id = $2`
+
+	err := sqlx.Get(f.db, &tuple, query, walletID, ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select operation %q from wallet %q", ID, walletID)
+	}
+
+	return f.hydrate(&tuple), nil
+}
+
+func (f *operationFinder) FindAllByWallet(walletID uuid.UUID) ([]*operation.Operation, error) {
+	var tuples []operationTuple
+
+	query := operationSelect + ` WHERE wallet_id = $1` + operationOrder
+
+	err := sqlx.Select(f.db, &tuples, query, walletID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select operations from wallet %q", walletID)
+	}
+
+	var ops []*operation.Operation
+	for _, tuple := range tuples {
+		ops = append(ops, f.hydrate(&tuple))
+	}
+
+	return ops, nil
+}
+
+func (f *operationFinder) FindAllByWalletAndStock(walletID, stockID uuid.UUID) ([]*operation.Operation, error) {
+	var tuples []operationTuple
+
+	query := operationSelect + ` WHERE wallet_id = $1 AND stock_id = $2` + operationOrder
+
+	err := sqlx.Select(f.db, &tuples, query, walletID, stockID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select operations for stock %q from wallet %q", stockID, walletID)
+	}
+
+	var ops []*operation.Operation
+	for _, tuple := range tuples {
+		ops = append(ops, f.hydrate(&tuple))
+	}
+
+	return ops, nil
+}
+
+func (f *operationFinder) hydrate(tuple *operationTuple) *operation.Operation {
+	return &operation.Operation{
+		ID:   tuple.ID,
+		Date: tuple.Date,
+		Stock: &stock.Stock{
+			ID: tuple.StockID,
+		},
+		Action:                operation.Action(tuple.Action),
+		Amount:                tuple.Amount,
+		Price:                 mm.ValueDollarFromString(tuple.Price),
+		PriceChange:           mm.ValueDollarFromString(tuple.PriceChange),
+		PriceChangeCommission: mm.ValueEuroFromString(tuple.PriceChangeCommission),
+		Value:                 mm.ValueEuroFromString(tuple.Value),
+		Commission:            mm.ValueEuroFromString(tuple.Commission),
+	}
+}
+
+func (f *operationFinder) FindTradeNumbersByWallet(walletID uuid.UUID) (map[uuid.UUID]int, error) {
+	var tuples []struct {
+		OperationID uuid.UUID `db:"operation_id"`
+		Number      int       `db:"number"`
+	}
+
+	query := `SELECT tro.operation_id, t.number
+			FROM trade_operation tro
+			INNER JOIN trade t ON t.id = tro.trade_id
+			WHERE t.wallet_id = $1`
+
+	err := sqlx.Select(f.db, &tuples, query, walletID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select trade numbers of operations from wallet %q", walletID)
+	}
+
+	ns := map[uuid.UUID]int{}
+	for _, tuple := range tuples {
+		ns[tuple.OperationID] = tuple.Number
+	}
+
+	return ns, nil
+}
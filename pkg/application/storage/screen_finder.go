@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/screen"
+)
+
+type (
+	screenFinder struct {
+		db sqlx.Queryer
+	}
+
+	screenTuple struct {
+		ID         uuid.UUID `db:"id"`
+		Name       string    `db:"name"`
+		Expression string    `db:"expression"`
+		Sort       string    `db:"sort"`
+		Descending bool      `db:"descending"`
+		MaxStocks  int       `db:"max_stocks"`
+	}
+)
+
+var _ screen.Finder = &screenFinder{}
+
+const screenSelect = `SELECT id, name, expression, COALESCE(sort, '') AS sort, descending, max_stocks FROM stock_screen`
+
+func NewScreenFinder(db sqlx.Queryer) *screenFinder {
+	return &screenFinder{
+		db: db,
+	}
+}
+
+func (f *screenFinder) FindByName(name string) (*screen.Screen, error) {
+	var tuple screenTuple
+
+	query := screenSelect + ` WHERE name ilike $1`
+
+	err := sqlx.Get(f.db, &tuple, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select screen with name %q", name)
+	}
+
+	return f.hydrate(tuple), nil
+}
+
+func (f *screenFinder) FindAll() ([]*screen.Screen, error) {
+	var tuples []screenTuple
+
+	query := screenSelect + ` ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select screens")
+	}
+
+	var ss []*screen.Screen
+	for _, tuple := range tuples {
+		ss = append(ss, f.hydrate(tuple))
+	}
+
+	return ss, nil
+}
+
+func (f *screenFinder) hydrate(tuple screenTuple) *screen.Screen {
+	return &screen.Screen{
+		ID:         tuple.ID,
+		Name:       tuple.Name,
+		Expression: tuple.Expression,
+		Sort:       tuple.Sort,
+		Desc:       tuple.Descending,
+		Limit:      tuple.MaxStocks,
+	}
+}
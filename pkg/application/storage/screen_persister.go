@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/screen"
+)
+
+type (
+	// screenPersister struct to hold necessary dependencies
+	screenPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ screen.Persister = &screenPersister{}
+
+func NewScreenPersister(db *sqlx.DB) *screenPersister {
+	return &screenPersister{
+		db: db,
+	}
+}
+
+// Persist stores the screen, replacing the one with the same name
+func (p *screenPersister) Persist(s *screen.Screen) error {
+	query := `
+		INSERT INTO stock_screen(id, name, expression, sort, descending, max_stocks)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET expression = excluded.expression,
+			sort = excluded.sort,
+			descending = excluded.descending,
+			max_stocks = excluded.max_stocks
+	`
+
+	_, err := p.db.Exec(query, s.ID, s.Name, s.Expression, s.Sort, s.Desc, s.Limit)
+	if err != nil {
+		return errors.Wrapf(err, "Persist screen %q", s.Name)
+	}
+
+	return nil
+}
+
+func (p *screenPersister) Delete(s *screen.Screen) error {
+	query := `DELETE FROM stock_screen WHERE id = $1`
+
+	_, err := p.db.Exec(query, s.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Delete screen %q", s.Name)
+	}
+
+	return nil
+}
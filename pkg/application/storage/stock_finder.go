@@ -23,6 +23,7 @@ type (
 		ID                  uuid.UUID
 		Name                string
 		Symbol              string
+		ISIN                sql.NullString `db:"isin"`
 		Value               string
 		DividendYield       string    `db:"dividend_yield"`
 		Change              string    `db:"change"`
@@ -32,6 +33,7 @@ type (
 		HighLow52WeekUpdate time.Time `db:"high_low_52_week_update"`
 		EPS                 string    `db:"eps"`
 		PER                 string    `db:"per"`
+		BookValue           string    `db:"book_value"`
 		HV20Day             string    `db:"hv_20_day"`
 		HV52Week            string    `db:"hv_52_week"`
 
@@ -42,6 +44,20 @@ type (
 		ExchangeID     uuid.UUID `db:"exchange_id"`
 		ExchangeName   string    `db:"exchange_name"`
 		ExchangeSymbol string    `db:"exchange_symbol"`
+
+		TypeID       uuid.NullUUID `db:"type"`
+		TypeName     string        `db:"type_name"`
+		SectorID     uuid.NullUUID `db:"sector"`
+		SectorName   string        `db:"sector_name"`
+		IndustryID   uuid.NullUUID `db:"industry"`
+		IndustryName string        `db:"industry_name"`
+
+		FaceValue       sql.NullFloat64 `db:"face_value"`
+		CouponRate      sql.NullFloat64 `db:"coupon_rate"`
+		CouponFrequency sql.NullInt64   `db:"coupon_frequency"`
+		IssueDate       *time.Time      `db:"issue_date"`
+		Maturity        *time.Time      `db:"maturity"`
+		CleanPrice      sql.NullFloat64 `db:"clean_price"`
 	}
 
 	stockFinder struct {
@@ -66,6 +82,7 @@ func (f *stockFinder) FindAll() ([]*stock.Stock, error) {
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 	`, strings.Join(f.selectStockColumns(), ", "))
 
 	err := sqlx.Select(f.db, &tuples, query)
@@ -91,6 +108,7 @@ func (f *stockFinder) selectStockColumns() []string {
 		"s.id",
 		"s.name",
 		"s.symbol",
+		"s.isin",
 		"s.market_id",
 		"s.exchange_id",
 		"s.value",
@@ -106,8 +124,21 @@ func (f *stockFinder) selectStockColumns() []string {
 		"e.symbol AS exchange_symbol",
 		"s.eps",
 		"s.per",
+		"s.book_value",
 		"s.hv_20_day",
 		"s.hv_52_week",
+		"s.type",
+		"COALESCE((SELECT si.name FROM stock_info si WHERE si.id = s.type), '') AS type_name",
+		"s.sector",
+		"COALESCE((SELECT si.name FROM stock_info si WHERE si.id = s.sector), '') AS sector_name",
+		"s.industry",
+		"COALESCE((SELECT si.name FROM stock_info si WHERE si.id = s.industry), '') AS industry_name",
+		"fi.face_value",
+		"fi.coupon_rate",
+		"fi.coupon_frequency",
+		"fi.issue_date",
+		"fi.maturity",
+		"fi.clean_price",
 	}
 }
 
@@ -115,10 +146,11 @@ func (f *stockFinder) hydrate(tuple *stockTuple) *stock.Stock {
 	dy, _ := strconv.ParseFloat(tuple.DividendYield, 64)
 	eps, _ := strconv.ParseFloat(tuple.EPS, 64)
 	per, _ := strconv.ParseFloat(tuple.PER, 64)
+	bv, _ := strconv.ParseFloat(tuple.BookValue, 64)
 	hv52week, _ := strconv.ParseFloat(tuple.HV52Week, 64)
 	hv20day, _ := strconv.ParseFloat(tuple.HV20Day, 64)
 
-	return &stock.Stock{
+	s := stock.Stock{
 		ID: tuple.ID,
 		Market: &market.Market{
 			ID:          tuple.MarketID,
@@ -132,6 +164,7 @@ func (f *stockFinder) hydrate(tuple *stockTuple) *stock.Stock {
 		},
 		Name:                tuple.Name,
 		Symbol:              tuple.Symbol,
+		ISIN:                tuple.ISIN.String,
 		Value:               mm.ValueFromStringAndExchange(tuple.Value, tuple.ExchangeSymbol),
 		DividendYield:       dy,
 		Change:              mm.ValueDollarFromString(tuple.Change),
@@ -141,8 +174,58 @@ func (f *stockFinder) hydrate(tuple *stockTuple) *stock.Stock {
 		HighLow52WeekUpdate: tuple.HighLow52WeekUpdate,
 		EPS:                 eps,
 		PER:                 per,
+		BookValue:           bv,
 		HV52Week:            hv52week,
 		HV20Day:             hv20day,
+		Type:                hydrateStockInfo(tuple.TypeID, tuple.TypeName, stock.StockInfoType),
+		Sector:              hydrateStockInfo(tuple.SectorID, tuple.SectorName, stock.StockInfoSector),
+		Industry:            hydrateStockInfo(tuple.IndustryID, tuple.IndustryName, stock.StockInfoIndustry),
+	}
+
+	// the price is in the quote currency, which for a cryptocurrency is the one of the pair
+	c := s.QuoteCurrency()
+	s.Value.Currency = c
+	s.High52Week.Currency = c
+	s.Low52Week.Currency = c
+
+	if tuple.FaceValue.Valid {
+		s.FixedIncome = hydrateFixedIncome(tuple, c)
+	}
+
+	return &s
+}
+
+func hydrateFixedIncome(tuple *stockTuple, c mm.Currency) *stock.FixedIncome {
+	fi := stock.FixedIncome{
+		FaceValue: mm.Value{
+			Amount:   tuple.FaceValue.Float64,
+			Currency: c,
+		},
+		CouponRate:      tuple.CouponRate.Float64,
+		CouponFrequency: int(tuple.CouponFrequency.Int64),
+		CleanPrice:      tuple.CleanPrice.Float64,
+	}
+
+	if tuple.IssueDate != nil {
+		fi.IssueDate = *tuple.IssueDate
+	}
+
+	if tuple.Maturity != nil {
+		fi.Maturity = *tuple.Maturity
+	}
+
+	return &fi
+}
+
+func hydrateStockInfo(ID uuid.NullUUID, name string, t stock.InfoType) *stock.Info {
+	if !ID.Valid {
+		return nil
+	}
+
+	return &stock.Info{
+		ID:   ID.UUID,
+		Type: t,
+		Name: name,
 	}
 }
 
@@ -155,6 +238,7 @@ func (f *stockFinder) FindBySymbol(symbol string) (*stock.Stock, error) {
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 		WHERE s.symbol LIKE upper($1)
 	`, strings.Join(f.selectStockColumns(), ", "))
 
@@ -170,6 +254,31 @@ func (f *stockFinder) FindBySymbol(symbol string) (*stock.Stock, error) {
 	return f.hydrate(&tuple), nil
 }
 
+func (f *stockFinder) FindByISIN(isin string) (*stock.Stock, error) {
+	var tuple stockTuple
+
+	query := fmt.Sprintf(`
+		SELECT 
+			%s
+		FROM stock s 
+		INNER JOIN market m ON s.market_id = m.id
+		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
+		WHERE s.isin = upper($1)
+	`, strings.Join(f.selectStockColumns(), ", "))
+
+	err := sqlx.Get(f.db, &tuple, query, isin)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select stock by isin %q", isin)
+	}
+
+	return f.hydrate(&tuple), nil
+}
+
 func (f *stockFinder) FindByName(name string) (*stock.Stock, error) {
 	var tuple stockTuple
 
@@ -179,6 +288,7 @@ func (f *stockFinder) FindByName(name string) (*stock.Stock, error) {
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 		WHERE s.name LIKE $1
 	`, strings.Join(f.selectStockColumns(), ", "))
 
@@ -203,6 +313,7 @@ func (f *stockFinder) FindByID(ID uuid.UUID) (*stock.Stock, error) {
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 		WHERE s.id = $1
 	`, strings.Join(f.selectStockColumns(), ", "))
 
@@ -227,6 +338,7 @@ func (f *stockFinder) FindAllByExchanges(exchanges []string) ([]*stock.Stock, er
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 		WHERE upper(e.symbol) IN (?)
 	`, strings.Join(f.selectStockColumns(), ", "))
 
@@ -271,6 +383,7 @@ func (f *stockFinder) FindAllByDividendAnnounceProjectYearAndMonth(year, month i
 		FROM stock s 
 		INNER JOIN market m ON s.market_id = m.id
 		INNER JOIN exchange e ON s.exchange_id = e.id
+		LEFT JOIN stock_fixed_income fi ON fi.stock_id = s.id
 		INNER JOIN stock_dividend sd ON sd.stock_id = s.id
 		WHERE sd.status IN ('announced', 'projected')
        	AND EXTRACT(YEAR FROM sd.ex_date) = $1
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -44,9 +45,15 @@ func (p *stockPersister) execInsert(tx *sqlx.Tx, s *stock.Stock) error {
 				high_low_52_week_update,
 				type,
 				sector,
-				industry
+				industry,
+				isin
 			  ) 
-			  VALUES ($1, $2, $3, $4, upper($5), $6, $7, $8, $9, $10)`
+			  VALUES ($1, $2, $3, $4, upper($5), $6, $7, $8, $9, $10, $11)`
+
+	var isin sql.NullString
+	if s.ISIN != "" {
+		isin = sql.NullString{String: s.ISIN, Valid: true}
+	}
 
 	_, err := tx.Exec(
 		query,
@@ -60,14 +67,47 @@ func (p *stockPersister) execInsert(tx *sqlx.Tx, s *stock.Stock) error {
 		s.Type.ID,
 		s.Sector.ID,
 		s.Industry.ID,
+		isin,
 	)
 	if err != nil {
 		return err
 	}
 
+	if s.IsBond() {
+		return p.execInsertFixedIncome(tx, s)
+	}
+
 	return nil
 }
 
+func (p *stockPersister) execInsertFixedIncome(tx *sqlx.Tx, s *stock.Stock) error {
+	query := `INSERT INTO stock_fixed_income(
+				stock_id,
+				face_value,
+				coupon_rate,
+				coupon_frequency,
+				issue_date,
+				maturity,
+				clean_price
+			  )
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	fi := s.FixedIncome
+
+	_, err := tx.Exec(
+		query,
+		s.ID,
+		fi.FaceValue.Amount,
+		fi.CouponRate,
+		fi.CouponFrequency,
+		fi.IssueDate,
+		fi.Maturity,
+		fi.CleanPrice,
+	)
+
+	return err
+}
+
 func (p *stockPersister) UpdatePrice(s *stock.Stock) error {
 	return transaction(p.db, func(tx *sqlx.Tx) error {
 		if err := p.execUpdatePrice(tx, s); err != nil {
@@ -170,3 +210,33 @@ func (p *stockPersister) execUpdatePriceVolatility(tx *sqlx.Tx, s *stock.Stock)
 
 	return nil
 }
+
+func (p *stockPersister) UpdateCleanPrice(s *stock.Stock) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `UPDATE stock_fixed_income SET clean_price = $1 WHERE stock_id = $2`
+
+		_, err := tx.Exec(query, s.FixedIncome.CleanPrice, s.ID)
+
+		return err
+	})
+}
+
+func (p *stockPersister) UpdateBookValue(s *stock.Stock) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `UPDATE stock SET book_value = $1 WHERE id = $2`
+
+		_, err := tx.Exec(query, s.BookValue, s.ID)
+
+		return err
+	})
+}
+
+func (p *stockPersister) UpdateISIN(s *stock.Stock) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `UPDATE stock SET isin = $1 WHERE id = $2`
+
+		_, err := tx.Exec(query, s.ISIN, s.ID)
+
+		return err
+	})
+}
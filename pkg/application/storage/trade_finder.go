@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	tradeFinder struct {
+		db sqlx.Queryer
+	}
+
+	tradeJournalTuple struct {
+		Note        string `db:"note"`
+		TargetPrice string `db:"target_price"`
+		StopLoss    string `db:"stop_loss"`
+	}
+)
+
+var _ trade.Finder = &tradeFinder{}
+
+func NewTradeFinder(db sqlx.Queryer) *tradeFinder {
+	return &tradeFinder{
+		db: db,
+	}
+}
+
+func (f *tradeFinder) FindByNumber(walletID uuid.UUID, number int) (*trade.Trade, error) {
+	var tuple walletTradeTuple
+
+	query := `SELECT * FROM trade WHERE wallet_id = $1 AND number = $2`
+
+	err := sqlx.Get(f.db, &tuple, query, walletID, number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select trade %d from wallet %q", number, walletID)
+	}
+
+	return f.hydrate(&tuple)
+}
+
+// FindAllClosedByWallet finds the trades closed within the date range, along with their journal
+func (f *tradeFinder) FindAllClosedByWallet(walletID uuid.UUID, from, to time.Time) ([]*trade.Trade, error) {
+	var tuples []walletTradeTuple
+
+	query := `SELECT * FROM trade
+			WHERE wallet_id = $1 AND status = $2 AND closed_at >= $3 AND closed_at <= $4
+			ORDER BY closed_at`
+
+	err := sqlx.Select(f.db, &tuples, query, walletID, trade.Close, from, to)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select closed trades from wallet %q", walletID)
+	}
+
+	var ts []*trade.Trade
+	for _, tuple := range tuples {
+		t, err := f.hydrate(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		ts = append(ts, t)
+	}
+
+	return ts, nil
+}
+
+func (f *tradeFinder) hydrate(tuple *walletTradeTuple) (*trade.Trade, error) {
+	t := &trade.Trade{
+		ID:     tuple.ID,
+		Number: tuple.Number,
+		Stock: &stock.Stock{
+			ID: tuple.StockID,
+		},
+
+		OpenedAt:  tuple.OpenedAt,
+		Buys:      mm.ValueEuroFromString(tuple.Buys),
+		BuyAmount: tuple.BuysAmount,
+
+		ClosedAt:   tuple.ClosedAt,
+		Sells:      mm.ValueEuroFromString(tuple.Sells),
+		SellAmount: tuple.SellsAmount,
+
+		Amount: tuple.Amount,
+
+		Dividend: mm.ValueEuroFromString(tuple.Dividend),
+		Status:   trade.Status(tuple.Status),
+
+		CloseCapital: mm.ValueEuroFromString(tuple.CloseCapital),
+		CloseNet:     mm.ValueEuroFromString(tuple.CloseNet),
+	}
+
+	if err := f.loadJournal(tuple.WalletID, t); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (f *tradeFinder) loadJournal(walletID uuid.UUID, t *trade.Trade) error {
+	var tuple tradeJournalTuple
+
+	query := `SELECT COALESCE(note, '') AS note,
+			COALESCE(target_price, 0) AS target_price,
+			COALESCE(stop_loss, 0) AS stop_loss
+			FROM trade_journal WHERE wallet_id = $1 AND number = $2`
+
+	err := sqlx.Get(f.db, &tuple, query, walletID, t.Number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		return errors.Wrapf(err, "Select journal of trade %d from wallet %q", t.Number, walletID)
+	}
+
+	var tags []string
+
+	query = `SELECT tag FROM trade_journal_tag WHERE wallet_id = $1 AND number = $2 ORDER BY tag`
+
+	err = sqlx.Select(f.db, &tags, query, walletID, t.Number)
+	if err != nil {
+		return errors.Wrapf(err, "Select journal tags of trade %d from wallet %q", t.Number, walletID)
+	}
+
+	t.Journal = trade.Journal{
+		Note:        tuple.Note,
+		Tags:        tags,
+		TargetPrice: mm.ValueDollarFromString(tuple.TargetPrice),
+		StopLoss:    mm.ValueDollarFromString(tuple.StopLoss),
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+)
+
+type (
+	// tradePersister struct to hold necessary dependencies
+	tradePersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ trade.Persister = &tradePersister{}
+
+func NewTradePersister(db *sqlx.DB) *tradePersister {
+	return &tradePersister{
+		db: db,
+	}
+}
+
+// PersistJournal stores the trade journal, replacing its tags
+func (p *tradePersister) PersistJournal(walletID uuid.UUID, t *trade.Trade) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO trade_journal(wallet_id, number, note, target_price, stop_loss)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (wallet_id, number) DO UPDATE
+			SET note = excluded.note,
+				target_price = excluded.target_price,
+				stop_loss = excluded.stop_loss
+		`
+
+		j := t.Journal
+
+		// the target price and the stop loss not set are stored as null
+		var targetPrice, stopLoss sql.NullFloat64
+		if j.TargetPrice.Amount > 0 {
+			targetPrice = sql.NullFloat64{Float64: j.TargetPrice.Amount, Valid: true}
+		}
+
+		if j.StopLoss.Amount > 0 {
+			stopLoss = sql.NullFloat64{Float64: j.StopLoss.Amount, Valid: true}
+		}
+
+		if _, err := tx.Exec(query, walletID, t.Number, j.Note, targetPrice, stopLoss); err != nil {
+			return errors.Wrapf(err, "PersistJournal")
+		}
+
+		query = `DELETE FROM trade_journal_tag WHERE wallet_id = $1 AND number = $2`
+
+		if _, err := tx.Exec(query, walletID, t.Number); err != nil {
+			return errors.Wrapf(err, "PersistJournal delete tags")
+		}
+
+		query = `INSERT INTO trade_journal_tag(wallet_id, number, tag) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+
+		for _, tag := range j.Tags {
+			if _, err := tx.Exec(query, walletID, t.Number, tag); err != nil {
+				return errors.Wrapf(err, "PersistJournal insert tag %q", tag)
+			}
+		}
+
+		return nil
+	})
+}
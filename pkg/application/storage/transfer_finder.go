@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+)
+
+type (
+	transferFinder struct {
+		db sqlx.Queryer
+	}
+
+	transferTuple struct {
+		ID        uuid.UUID       `db:"id"`
+		FromID    uuid.UUID       `db:"from_account"`
+		FromAlias string          `db:"from_alias"`
+		ToID      uuid.UUID       `db:"to_account"`
+		ToAlias   string          `db:"to_alias"`
+		Amount    float64         `db:"amount"`
+		Currency  string          `db:"currency"`
+		Rate      sql.NullFloat64 `db:"rate"`
+		Fee       float64         `db:"fee"`
+		Date      time.Time       `db:"date"`
+	}
+)
+
+var _ transfer.Finder = &transferFinder{}
+
+func NewTransferFinder(db sqlx.Queryer) *transferFinder {
+	return &transferFinder{
+		db: db,
+	}
+}
+
+// FindAllByWallet finds the transfers from or to any of the wallet bank accounts, in date order
+func (f *transferFinder) FindAllByWallet(walletID uuid.UUID) ([]*transfer.Transfer, error) {
+	var tuples []transferTuple
+
+	query := `SELECT t.id, t.from_account, fa.alias AS from_alias, t.to_account, ta.alias AS to_alias, t.amount, t.currency, t.rate, t.fee, t.date
+			FROM transfer t
+			INNER JOIN bank_account fa ON fa.id = t.from_account
+			INNER JOIN bank_account ta ON ta.id = t.to_account
+			WHERE t.from_account IN (SELECT bank_account_id FROM wallet_bank_account WHERE wallet_id = $1)
+			OR t.to_account IN (SELECT bank_account_id FROM wallet_bank_account WHERE wallet_id = $1)
+			ORDER BY t.date`
+
+	err := sqlx.Select(f.db, &tuples, query, walletID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select transfers from wallet %q", walletID)
+	}
+
+	return hydrateTransfers(tuples)
+}
+
+// FindAllByAccountsAndDate finds the transfers from and to the bank accounts given made the date given
+func (f *transferFinder) FindAllByAccountsAndDate(fromID, toID uuid.UUID, date time.Time) ([]*transfer.Transfer, error) {
+	var tuples []transferTuple
+
+	query := `SELECT t.id, t.from_account, fa.alias AS from_alias, t.to_account, ta.alias AS to_alias, t.amount, t.currency, t.rate, t.fee, t.date
+			FROM transfer t
+			INNER JOIN bank_account fa ON fa.id = t.from_account
+			INNER JOIN bank_account ta ON ta.id = t.to_account
+			WHERE t.from_account = $1 AND t.to_account = $2 AND t.date::date = $3::date`
+
+	err := sqlx.Select(f.db, &tuples, query, fromID, toID, date)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select transfers from %q to %q on %s", fromID, toID, date.Format("2006-01-02"))
+	}
+
+	return hydrateTransfers(tuples)
+}
+
+func hydrateTransfers(tuples []transferTuple) ([]*transfer.Transfer, error) {
+	var ts []*transfer.Transfer
+	for _, tuple := range tuples {
+		currency, ok := mm.CurrencyFromCode(tuple.Currency)
+		if !ok {
+			return nil, errors.Errorf("Transfer %q currency %q not supported", tuple.ID, tuple.Currency)
+		}
+
+		ts = append(ts, &transfer.Transfer{
+			ID: tuple.ID,
+			From: &bank.Account{
+				ID:    tuple.FromID,
+				Alias: tuple.FromAlias,
+			},
+			To: &bank.Account{
+				ID:    tuple.ToID,
+				Alias: tuple.ToAlias,
+			},
+			Amount: mm.Value{Amount: tuple.Amount, Currency: currency},
+			Rate:   tuple.Rate.Float64,
+			Fee:    mm.Value{Amount: tuple.Fee, Currency: currency},
+			Date:   tuple.Date,
+		})
+	}
+
+	return ts, nil
+}
@@ -1,8 +1,12 @@
 package storage
 
 import (
+	"database/sql"
+
 	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
 
+	"github.com/dohernandez/market-manager/pkg/market-manager"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
 )
 
@@ -24,7 +28,7 @@ func NewTransferPersister(db *sqlx.DB) *transferPersister {
 func (p *transferPersister) PersistAll(ts []*transfer.Transfer) error {
 	return transaction(p.db, func(tx *sqlx.Tx) error {
 		for _, t := range ts {
-			if err := p.execInsert(tx, t); err != nil {
+			if err := execTransferInsert(tx, t); err != nil {
 				return err
 			}
 		}
@@ -33,23 +37,39 @@ func (p *transferPersister) PersistAll(ts []*transfer.Transfer) error {
 	})
 }
 
-func (p *transferPersister) execInsert(tx *sqlx.Tx, t *transfer.Transfer) error {
+func execTransferInsert(tx *sqlx.Tx, t *transfer.Transfer) error {
 	query := `
 		INSERT INTO transfer(
 			id, 
 			from_account, 
 			to_account, 
 			amount, 
+			currency,
+			rate,
+			fee,
 			date 
-		) VALUES ($1, $2, $3, $4, $5)
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
+	currency, ok := mm.CurrencyCode(t.Amount.Currency)
+	if !ok {
+		return errors.Errorf("Transfer %q currency %q not supported", t.ID, t.Amount.Currency)
+	}
+
+	var rate sql.NullFloat64
+	if t.Rate > 0 {
+		rate = sql.NullFloat64{Float64: t.Rate, Valid: true}
+	}
+
 	_, err := tx.Exec(
 		query,
 		t.ID,
 		t.From.ID,
 		t.To.ID,
 		t.Amount.Amount,
+		currency,
+		rate,
+		t.Fee.Amount,
 		t.Date,
 	)
 	if err != nil {
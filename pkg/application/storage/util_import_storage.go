@@ -33,8 +33,10 @@ func (s *utilImportStorage) execInsert(tx *sqlx.Tx, r util.Resource) error {
 			id, 
 			resource, 
 			file_name, 
+			hash, 
+			mapping, 
 			created_at 
-		) VALUES ($1, $2, $3, $4)
+		) VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	_, err := tx.Exec(
@@ -42,6 +44,8 @@ func (s *utilImportStorage) execInsert(tx *sqlx.Tx, r util.Resource) error {
 		r.ID,
 		r.Resource,
 		r.FileName,
+		r.Hash,
+		r.Mapping,
 		r.CreatedAt,
 	)
 	if err != nil {
@@ -82,3 +86,34 @@ func (s *utilImportStorage) FindLastByResourceAndFilesGroup(resource, filesGroup
 
 	return r, nil
 }
+
+// PersistRows saves the rows imported. The rows already stored are kept
+func (s *utilImportStorage) PersistRows(rs []util.ResourceRow) error {
+	query := `
+		INSERT INTO import_row(resource, fingerprint, import_id, file_name, line) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (resource, fingerprint) DO NOTHING
+	`
+
+	return transaction(s.db, func(tx *sqlx.Tx) error {
+		for _, r := range rs {
+			_, err := tx.Exec(query, r.Resource, r.Fingerprint, r.ImportID, r.FileName, r.Line)
+			if err != nil {
+				return errors.Wrapf(err, "Persist import row %q line %d", r.FileName, r.Line)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *utilImportStorage) FindAllRowsByResource(resource string) ([]util.ResourceRow, error) {
+	var rs []util.ResourceRow
+	query := `SELECT * FROM import_row WHERE resource = $1`
+
+	err := sqlx.Select(s.db, &rs, query, resource)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Select import rows form resource %q", resource))
+	}
+
+	return rs, nil
+}
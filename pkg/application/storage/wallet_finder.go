@@ -10,9 +10,8 @@ import (
 
 	"time"
 
-	"github.com/almerlucke/go-iban/iban"
-
 	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
@@ -22,25 +21,27 @@ import (
 
 type (
 	walletFinder struct {
-		db sqlx.Queryer
+		db           sqlx.Queryer
+		brokerFinder broker.Finder
 	}
 
 	walletTuple struct {
-		ID         uuid.UUID `db:"id"`
-		Name       string    `db:"name"`
-		URL        string    `db:"url"`
-		Invested   string    `db:"invested"`
-		Capital    string    `db:"capital"`
-		Funds      string    `db:"funds"`
-		Dividend   string    `db:"dividend"`
-		Commission string    `db:"commission"`
-		Connection string    `db:"connection"`
-		Interest   string    `db:"interest"`
+		ID         uuid.UUID     `db:"id"`
+		Name       string        `db:"name"`
+		URL        string        `db:"url"`
+		Invested   string        `db:"invested"`
+		Capital    string        `db:"capital"`
+		Funds      string        `db:"funds"`
+		Dividend   string        `db:"dividend"`
+		Commission string        `db:"commission"`
+		Connection string        `db:"connection"`
+		Interest   string        `db:"interest"`
+		BrokerID   uuid.NullUUID `db:"broker_id"`
 	}
 
 	walletItemTuple struct {
 		ID                uuid.UUID `db:"id"`
-		Amount            int       `db:"amount"`
+		Amount            float64   `db:"amount"`
 		Invested          string    `db:"invested"`
 		Dividend          string    `db:"dividend"`
 		Buys              string    `db:"buys"`
@@ -69,22 +70,55 @@ type (
 		StockID      uuid.UUID `db:"stock_id"`
 		WalletID     uuid.UUID `db:"wallet_id"`
 	}
-
-	walletBankAccountTuple struct {
-		ID            uuid.UUID `db:"id"`
-		Name          string    `db:"name"`
-		AccountNo     string    `db:"account_no"`
-		Alias         string    `db:"alias"`
-		AccountNoType string    `db:"account_no_type"`
-	}
 )
 
 var _ wallet.Finder = &walletFinder{}
 
 func NewWalletFinder(db sqlx.Queryer) *walletFinder {
 	return &walletFinder{
-		db: db,
+		db:           db,
+		brokerFinder: NewBrokerFinder(db),
+	}
+}
+
+func (f *walletFinder) FindAll() ([]*wallet.Wallet, error) {
+	var tuples []walletTuple
+
+	query := `SELECT * FROM wallet ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select wallets")
+	}
+
+	return f.hydrateWallets(tuples)
+}
+
+func (f *walletFinder) FindAllByBroker(b *broker.Broker) ([]*wallet.Wallet, error) {
+	var tuples []walletTuple
+
+	query := `SELECT * FROM wallet WHERE broker_id = $1 ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query, b.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select wallets with broker %q", b.Name)
+	}
+
+	return f.hydrateWallets(tuples)
+}
+
+func (f *walletFinder) hydrateWallets(tuples []walletTuple) ([]*wallet.Wallet, error) {
+	var ws []*wallet.Wallet
+	for _, tuple := range tuples {
+		w, err := f.hydrateWallet(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		ws = append(ws, w)
 	}
+
+	return ws, nil
 }
 
 func (f *walletFinder) FindByName(name string) (*wallet.Wallet, error) {
@@ -101,11 +135,11 @@ func (f *walletFinder) FindByName(name string) (*wallet.Wallet, error) {
 		return nil, errors.Wrapf(err, "Select wallet with name %q", name)
 	}
 
-	return f.hydrateWallet(&tuple), nil
+	return f.hydrateWallet(&tuple)
 }
 
-func (f *walletFinder) hydrateWallet(tuple *walletTuple) *wallet.Wallet {
-	return &wallet.Wallet{
+func (f *walletFinder) hydrateWallet(tuple *walletTuple) (*wallet.Wallet, error) {
+	w := &wallet.Wallet{
 		ID:           tuple.ID,
 		Name:         tuple.Name,
 		URL:          tuple.URL,
@@ -120,6 +154,17 @@ func (f *walletFinder) hydrateWallet(tuple *walletTuple) *wallet.Wallet {
 		Interest:     mm.ValueEuroFromString(tuple.Interest),
 		Trades:       map[int]*trade.Trade{},
 	}
+
+	if tuple.BrokerID.Valid {
+		b, err := f.brokerFinder.FindByID(tuple.BrokerID.UUID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Load broker for wallet %q", tuple.Name)
+		}
+
+		w.Broker = b
+	}
+
+	return w, nil
 }
 
 func (f *walletFinder) FindByBankAccount(ba *bank.Account) (*wallet.Wallet, error) {
@@ -139,7 +184,10 @@ func (f *walletFinder) FindByBankAccount(ba *bank.Account) (*wallet.Wallet, erro
 		return nil, errors.Wrapf(err, "Select wallet with bank_account_id %q", ba.ID)
 	}
 
-	w := f.hydrateWallet(&tuple)
+	w, err := f.hydrateWallet(&tuple)
+	if err != nil {
+		return nil, err
+	}
 
 	err = w.AddBankAccount(ba)
 	if err != nil {
@@ -153,7 +201,7 @@ func (f *walletFinder) FindWithItemByStock(stk *stock.Stock) ([]*wallet.Wallet,
 	type walletWithWalletItemTuple struct {
 		walletTuple
 		ID     uuid.UUID `db:"wallet_item_id"`
-		Amount int       `db:"wallet_item_amount"`
+		Amount float64   `db:"wallet_item_amount"`
 	}
 
 	var tuples []walletWithWalletItemTuple
@@ -171,7 +219,10 @@ func (f *walletFinder) FindWithItemByStock(stk *stock.Stock) ([]*wallet.Wallet,
 
 	var ws []*wallet.Wallet
 	for _, tuple := range tuples {
-		w := f.hydrateWallet(&tuple.walletTuple)
+		w, err := f.hydrateWallet(&tuple.walletTuple)
+		if err != nil {
+			return nil, err
+		}
 
 		w.Items[stk.ID] = &wallet.Item{
 			ID:     tuple.ID,
@@ -253,7 +304,7 @@ func (f *walletFinder) LoadItemOperations(i *wallet.Item) error {
 	}
 
 	for _, tuple := range tuples {
-		a, _ := strconv.Atoi(tuple.Amount)
+		a, _ := strconv.ParseFloat(tuple.Amount, 64)
 		i.Operations = append(i.Operations, &operation.Operation{
 			ID:                    tuple.ID,
 			Stock:                 i.Stock,
@@ -262,8 +313,8 @@ func (f *walletFinder) LoadItemOperations(i *wallet.Item) error {
 			Price:                 mm.ValueDollarFromString(tuple.Price),
 			PriceChange:           mm.ValueDollarFromString(tuple.PriceChange),
 			PriceChangeCommission: mm.ValueEuroFromString(tuple.PriceChangeCommission),
-			Value:      mm.ValueEuroFromString(tuple.Value),
-			Commission: mm.ValueEuroFromString(tuple.Commission),
+			Value:                 mm.ValueEuroFromString(tuple.Value),
+			Commission:            mm.ValueEuroFromString(tuple.Commission),
 		})
 	}
 
@@ -359,6 +410,14 @@ func (f *walletFinder) LoadActiveTrades(w *wallet.Wallet) error {
 		return errors.Wrapf(err, "Select trades from wallet %q", w.ID)
 	}
 
+	// The next trade number is taken from all the trades, not only from the ones of the loaded items
+	query = `SELECT COALESCE(MAX(number), 0) FROM trade WHERE wallet_id = $1`
+
+	err = sqlx.Get(f.db, &w.LastTradeNumber, query, w.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Select last trade number from wallet %q", w.ID)
+	}
+
 	for _, tuple := range tuples {
 		t, err := f.hydrateWalletTrade(&tuple)
 		if err != nil {
@@ -387,6 +446,40 @@ func (f *walletFinder) LoadActiveTrades(w *wallet.Wallet) error {
 	return nil
 }
 
+// LoadItemTrades loads the trades of the item stock, open or closed, that are not loaded yet
+func (f *walletFinder) LoadItemTrades(w *wallet.Wallet, i *wallet.Item) error {
+	var tuples []walletTradeTuple
+
+	query := `SELECT * FROM trade WHERE wallet_id = $1 AND stock_id = $2 ORDER BY opened_at`
+
+	err := sqlx.Select(f.db, &tuples, query, w.ID, i.Stock.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Select trades of stock %q from wallet %q", i.Stock.ID, w.ID)
+	}
+
+	for _, tuple := range tuples {
+		if t, ok := w.Trades[tuple.Number]; ok {
+			if t.Stock.ID == i.Stock.ID {
+				i.Trades[t.Number] = t
+			}
+
+			continue
+		}
+
+		t, err := f.hydrateWalletTrade(&tuple)
+		if err != nil {
+			return errors.Wrapf(err, "Hydrate trades of stock %q from wallet %q", i.Stock.ID, w.ID)
+		}
+
+		t.Stock = i.Stock
+
+		i.Trades[t.Number] = t
+		w.Trades[t.Number] = t
+	}
+
+	return nil
+}
+
 func (f *walletFinder) hydrateWalletTrade(tuple *walletTradeTuple) (*trade.Trade, error) {
 	t := trade.Trade{
 		ID:     tuple.ID,
@@ -448,7 +541,7 @@ func (f *walletFinder) LoadTradeItemOperations(i *wallet.Item) error {
 }
 
 func (f *walletFinder) LoadBankAccounts(w *wallet.Wallet) error {
-	var tuples []walletBankAccountTuple
+	var tuples []bankAccountTuple
 
 	query := `SELECT ba.* 
 			FROM bank_account ba
@@ -461,23 +554,32 @@ func (f *walletFinder) LoadBankAccounts(w *wallet.Wallet) error {
 	}
 
 	for _, tuple := range tuples {
-		var AccountNo string
-		if bank.AccountNoType(tuple.AccountNoType) == bank.IBAN {
-			IBAN, err := iban.NewIBAN(tuple.AccountNo)
-			if err != nil {
-				return errors.Wrapf(err, "Select bank with wallet_id %q", w.ID)
-			}
-
-			AccountNo = IBAN.PrintCode
+		ba, err := hydrateBankAccount(&tuple)
+		if err != nil {
+			return errors.Wrapf(err, "Select bank with wallet_id %q", w.ID)
 		}
 
-		w.BankAccounts[tuple.ID] = &bank.Account{
-			ID:        tuple.ID,
-			Name:      tuple.Name,
-			AccountNo: AccountNo,
-			Alias:     tuple.Alias,
-		}
+		w.BankAccounts[tuple.ID] = ba
 	}
 
 	return nil
 }
+
+// FindImportedTransactionIDs returns the ids of the broker transactions already imported to the wallet
+func (f *walletFinder) FindImportedTransactionIDs(w *wallet.Wallet) (map[string]bool, error) {
+	var ids []string
+
+	query := `SELECT transaction_id FROM wallet_import_transaction WHERE wallet_id = $1`
+
+	err := sqlx.Select(f.db, &ids, query, w.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Select import transactions with wallet_id %q", w.ID)
+	}
+
+	imported := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		imported[id] = true
+	}
+
+	return imported, nil
+}
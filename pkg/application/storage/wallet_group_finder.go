@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+type (
+	walletGroupFinder struct {
+		db sqlx.Queryer
+	}
+
+	walletGroupTuple struct {
+		ID   uuid.UUID `db:"id"`
+		Name string    `db:"name"`
+	}
+
+	walletGroupWalletTuple struct {
+		ID   uuid.UUID `db:"id"`
+		Name string    `db:"name"`
+	}
+)
+
+var _ group.Finder = &walletGroupFinder{}
+
+func NewWalletGroupFinder(db sqlx.Queryer) *walletGroupFinder {
+	return &walletGroupFinder{
+		db: db,
+	}
+}
+
+func (f *walletGroupFinder) FindByName(name string) (*group.Group, error) {
+	var tuple walletGroupTuple
+
+	query := `SELECT id, name FROM wallet_group WHERE name ilike $1`
+
+	err := sqlx.Get(f.db, &tuple, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select wallet group with name %q", name)
+	}
+
+	return f.hydrate(&tuple)
+}
+
+func (f *walletGroupFinder) FindAll() ([]*group.Group, error) {
+	var tuples []walletGroupTuple
+
+	query := `SELECT id, name FROM wallet_group ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select wallet groups")
+	}
+
+	var gs []*group.Group
+	for _, tuple := range tuples {
+		g, err := f.hydrate(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		gs = append(gs, g)
+	}
+
+	return gs, nil
+}
+
+// hydrate builds the group with its wallets. Only the wallets id and name are loaded
+func (f *walletGroupFinder) hydrate(tuple *walletGroupTuple) (*group.Group, error) {
+	g := &group.Group{
+		ID:      tuple.ID,
+		Name:    tuple.Name,
+		Wallets: map[uuid.UUID]*wallet.Wallet{},
+	}
+
+	var tuples []walletGroupWalletTuple
+
+	query := `SELECT w.id, w.name
+			FROM wallet_group_wallet gw
+			INNER JOIN wallet w ON gw.wallet_id = w.id
+			WHERE gw.wallet_group_id = $1`
+
+	err := sqlx.Select(f.db, &tuples, query, g.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "Select wallet group wallets with wallet group id %q", g.ID)
+	}
+
+	for _, tuple := range tuples {
+		g.Wallets[tuple.ID] = &wallet.Wallet{
+			ID:   tuple.ID,
+			Name: tuple.Name,
+		}
+	}
+
+	return g, nil
+}
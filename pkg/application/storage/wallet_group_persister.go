@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/group"
+)
+
+type (
+	// walletGroupPersister struct to hold necessary dependencies
+	walletGroupPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ group.Persister = &walletGroupPersister{}
+
+func NewWalletGroupPersister(db *sqlx.DB) *walletGroupPersister {
+	return &walletGroupPersister{
+		db: db,
+	}
+}
+
+// Persist stores the wallet group, replacing its wallets
+func (p *walletGroupPersister) Persist(g *group.Group) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO wallet_group(id, name) VALUES ($1, $2)
+			ON CONFLICT (id) DO UPDATE
+			SET name = excluded.name
+		`
+
+		if _, err := tx.Exec(query, g.ID, g.Name); err != nil {
+			return errors.Wrapf(err, "Persist wallet group %q", g.Name)
+		}
+
+		query = `DELETE FROM wallet_group_wallet WHERE wallet_group_id = $1`
+
+		if _, err := tx.Exec(query, g.ID); err != nil {
+			return errors.Wrapf(err, "Persist wallet group %q delete wallets", g.Name)
+		}
+
+		query = `INSERT INTO wallet_group_wallet(wallet_group_id, wallet_id) VALUES ($1, $2)`
+
+		for _, w := range g.Wallets {
+			if _, err := tx.Exec(query, g.ID, w.ID); err != nil {
+				return errors.Wrapf(err, "Persist wallet group %q insert wallet %q", g.Name, w.Name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete removes the wallet group. The wallets are kept
+func (p *walletGroupPersister) Delete(g *group.Group) error {
+	query := `DELETE FROM wallet_group WHERE id = $1`
+
+	_, err := p.db.Exec(query, g.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Delete wallet group %q", g.Name)
+	}
+
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
 )
 
 type (
@@ -49,10 +52,33 @@ func (p *walletPersister) PersistAll(ws []*wallet.Wallet) error {
 	})
 }
 
+// PersistBankAccounts links the wallet to its bank accounts. The links already stored are kept
+func (p *walletPersister) PersistBankAccounts(w *wallet.Wallet) error {
+	query := `
+		INSERT INTO wallet_bank_account(wallet_id, bank_account_id) VALUES ($1, $2)
+		ON CONFLICT (wallet_id, bank_account_id) DO NOTHING
+	`
+
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		for _, ba := range w.BankAccounts {
+			if _, err := tx.Exec(query, w.ID, ba.ID); err != nil {
+				return errors.Wrapf(err, "Persist wallet %q bank account %q", w.Name, ba.Alias)
+			}
+		}
+
+		return nil
+	})
+}
+
 func (p *walletPersister) execInsert(tx *sqlx.Tx, w *wallet.Wallet) error {
-	query := `INSERT INTO wallet(id, name, url) VALUES ($1, $2, $3)`
+	query := `INSERT INTO wallet(id, name, url, broker_id) VALUES ($1, $2, $3, $4)`
 
-	_, err := tx.Exec(query, w.ID, w.Name, w.URL)
+	var brokerID uuid.NullUUID
+	if w.Broker != nil {
+		brokerID = uuid.NullUUID{UUID: w.Broker.ID, Valid: true}
+	}
+
+	_, err := tx.Exec(query, w.ID, w.Name, w.URL, brokerID)
 	if err != nil {
 		return errors.Wrapf(err, "execInsert")
 	}
@@ -146,32 +172,40 @@ func (p *walletPersister) execWalletItemInsert(tx *sqlx.Tx, w *wallet.Wallet) er
 
 func (p *walletPersister) PersistOperations(w *wallet.Wallet) error {
 	return transaction(p.db, func(tx *sqlx.Tx) error {
-		if err := p.execOperationInsert(tx, w); err != nil {
-			return err
-		}
+		return p.execPersistOperations(tx, w)
+	})
+}
 
-		if err := p.execWalletItemInsert(tx, w); err != nil {
-			return err
-		}
+func (p *walletPersister) execPersistOperations(tx *sqlx.Tx, w *wallet.Wallet) error {
+	if err := p.execOperationInsert(tx, w); err != nil {
+		return err
+	}
 
-		if err := p.execUpdateItemCapital(tx, w); err != nil {
-			return err
-		}
+	if err := p.execWalletItemInsert(tx, w); err != nil {
+		return err
+	}
 
-		if err := p.execUpdateCapital(tx, w); err != nil {
-			return err
-		}
+	if err := p.execUpdateItemCapital(tx, w); err != nil {
+		return err
+	}
 
-		if err := p.execUpdateAccounting(tx, w); err != nil {
-			return err
-		}
+	if err := p.execUpdateCapital(tx, w); err != nil {
+		return err
+	}
 
-		if err := p.execUpdateTrade(tx, w); err != nil {
-			return err
-		}
+	if err := p.execUpdateAccounting(tx, w); err != nil {
+		return err
+	}
 
-		return nil
-	})
+	if err := p.execUpdateTrade(tx, w); err != nil {
+		return err
+	}
+
+	if err := p.execLedgerInsert(tx, w); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (p *walletPersister) UpdateAllAccounting(ws []*wallet.Wallet) error {
@@ -180,6 +214,10 @@ func (p *walletPersister) UpdateAllAccounting(ws []*wallet.Wallet) error {
 			if err := p.execUpdateAccounting(tx, w); err != nil {
 				return err
 			}
+
+			if err := p.execLedgerInsert(tx, w); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -206,6 +244,74 @@ func (p *walletPersister) execUpdateAccounting(tx *sqlx.Tx, w *wallet.Wallet) er
 	return nil
 }
 
+// execLedgerInsert inserts the wallet ledger entries and recalculates the running balance of the wallet ledger,
+// since the entries are not always booked in date order
+func (p *walletPersister) execLedgerInsert(tx *sqlx.Tx, w *wallet.Wallet) error {
+	if len(w.Ledger) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO wallet_ledger(
+			id,
+			wallet_id,
+			date,
+			kind,
+			description,
+			amount,
+			operation_id,
+			transfer_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	for _, e := range w.Ledger {
+		var operationID, transferID uuid.NullUUID
+		if e.OperationID != uuid.Nil {
+			operationID = uuid.NullUUID{UUID: e.OperationID, Valid: true}
+		}
+
+		if e.TransferID != uuid.Nil {
+			transferID = uuid.NullUUID{UUID: e.TransferID, Valid: true}
+		}
+
+		_, err := tx.Exec(
+			query,
+			e.ID,
+			w.ID,
+			e.Date,
+			e.Kind,
+			e.Description,
+			e.Amount.Amount,
+			operationID,
+			transferID,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "execLedgerInsert")
+		}
+	}
+
+	return execUpdateLedgerBalance(tx, w)
+}
+
+func execUpdateLedgerBalance(tx *sqlx.Tx, w *wallet.Wallet) error {
+	query := `
+		UPDATE wallet_ledger wl SET balance = r.balance
+		FROM (
+			SELECT id, SUM(amount) OVER (ORDER BY date, seq) AS balance
+			FROM wallet_ledger
+			WHERE wallet_id = $1
+		) r
+		WHERE wl.id = r.id
+	`
+
+	_, err := tx.Exec(query, w.ID)
+	if err != nil {
+		return errors.Wrapf(err, "execUpdateLedgerBalance")
+	}
+
+	return nil
+}
+
 // UpdateAllItemsCapital Update the capital of all items from all wallets, along with the capital of the wallet
 func (p *walletPersister) UpdateAllItemsCapital(ws []*wallet.Wallet) error {
 	return transaction(p.db, func(tx *sqlx.Tx) error {
@@ -237,7 +343,7 @@ func (p *walletPersister) execUpdateItemCapital(tx *sqlx.Tx, w *wallet.Wallet) e
 
 func (p *walletPersister) execUpdateCapital(tx *sqlx.Tx, w *wallet.Wallet) error {
 	query := `UPDATE wallet SET capital = (
-		SELECT SUM(wi.capital)
+		SELECT COALESCE(SUM(wi.capital), 0)
 		FROM wallet w
 		INNER JOIN wallet_item wi ON w.id = wi.wallet_id
 		WHERE w.id = $1
@@ -315,6 +421,147 @@ func (p *walletPersister) execUpdateTrade(tx *sqlx.Tx, w *wallet.Wallet) error {
 	return nil
 }
 
+// UpdateOperation stores the edited operation along with the wallet item and trades of the operation stock,
+// which have to be rebuilt already, and the wallet accounting
+func (p *walletPersister) UpdateOperation(w *wallet.Wallet, o *operation.Operation) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		if err := p.execDeleteStockTrades(tx, w, o); err != nil {
+			return err
+		}
+
+		if err := p.execDeleteOperationLedger(tx, o); err != nil {
+			return err
+		}
+
+		query := `
+			UPDATE operation SET
+				date = $1,
+				amount = $2,
+				price = $3,
+				price_change = $4,
+				price_change_commission = $5,
+				value = $6,
+				commission = $7
+			WHERE id = $8 AND wallet_id = $9
+		`
+
+		if _, err := tx.Exec(
+			query,
+			o.Date,
+			o.Amount,
+			o.Price.Amount,
+			o.PriceChange.Amount,
+			o.PriceChangeCommission.Amount,
+			o.Value.Amount,
+			o.Commission.Amount,
+			o.ID,
+			w.ID,
+		); err != nil {
+			return errors.Wrapf(err, "UpdateOperation")
+		}
+
+		return p.execPersistRecompute(tx, w)
+	})
+}
+
+// DeleteOperation removes the operation along with its trade links and ledger entries, and stores the wallet item
+// and trades of the operation stock, which have to be rebuilt already, and the wallet accounting
+func (p *walletPersister) DeleteOperation(w *wallet.Wallet, o *operation.Operation) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		if err := p.execDeleteStockTrades(tx, w, o); err != nil {
+			return err
+		}
+
+		if err := p.execDeleteOperationLedger(tx, o); err != nil {
+			return err
+		}
+
+		query := `DELETE FROM operation WHERE id = $1 AND wallet_id = $2`
+
+		if _, err := tx.Exec(query, o.ID, w.ID); err != nil {
+			return errors.Wrapf(err, "DeleteOperation")
+		}
+
+		return p.execPersistRecompute(tx, w)
+	})
+}
+
+// execDeleteStockTrades deletes the trades of the operation stock, they are inserted again from the rebuilt item
+func (p *walletPersister) execDeleteStockTrades(tx *sqlx.Tx, w *wallet.Wallet, o *operation.Operation) error {
+	query := `DELETE FROM trade_operation WHERE trade_id IN (SELECT id FROM trade WHERE wallet_id = $1 AND stock_id = $2)`
+
+	if _, err := tx.Exec(query, w.ID, o.Stock.ID); err != nil {
+		return errors.Wrapf(err, "execDeleteStockTrades")
+	}
+
+	query = `DELETE FROM trade WHERE wallet_id = $1 AND stock_id = $2`
+
+	if _, err := tx.Exec(query, w.ID, o.Stock.ID); err != nil {
+		return errors.Wrapf(err, "execDeleteStockTrades")
+	}
+
+	return nil
+}
+
+func (p *walletPersister) execDeleteOperationLedger(tx *sqlx.Tx, o *operation.Operation) error {
+	query := `DELETE FROM wallet_ledger WHERE operation_id = $1`
+
+	if _, err := tx.Exec(query, o.ID); err != nil {
+		return errors.Wrapf(err, "execDeleteOperationLedger")
+	}
+
+	return nil
+}
+
+// execPersistRecompute stores the recomputed wallet items, trades, accounting and ledger
+func (p *walletPersister) execPersistRecompute(tx *sqlx.Tx, w *wallet.Wallet) error {
+	if err := p.execWalletItemInsert(tx, w); err != nil {
+		return err
+	}
+
+	if err := p.execUpdateItemCapital(tx, w); err != nil {
+		return err
+	}
+
+	if err := p.execUpdateCapital(tx, w); err != nil {
+		return err
+	}
+
+	if err := p.execUpdateAccounting(tx, w); err != nil {
+		return err
+	}
+
+	if err := p.execUpdateTrade(tx, w); err != nil {
+		return err
+	}
+
+	if len(w.Ledger) == 0 {
+		return execUpdateLedgerBalance(tx, w)
+	}
+
+	return p.execLedgerInsert(tx, w)
+}
+
+// PersistRebuild replaces the wallet items, trades, ledger and accounting with the ones of the rebuilt wallet
+func (p *walletPersister) PersistRebuild(w *wallet.Wallet) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		queries := []string{
+			`DELETE FROM trade_operation WHERE trade_id IN (SELECT id FROM trade WHERE wallet_id = $1)`,
+			`DELETE FROM trade WHERE wallet_id = $1`,
+			`DELETE FROM wallet_ledger WHERE wallet_id = $1`,
+			`DELETE FROM wallet_item WHERE wallet_id = $1`,
+		}
+
+		for _, query := range queries {
+			if _, err := tx.Exec(query, w.ID); err != nil {
+				return errors.Wrapf(err, "PersistRebuild")
+			}
+		}
+
+		return p.execPersistRecompute(tx, w)
+	})
+}
+
 // UpdateRetentions Insert the dividend retention for an stock base on the wallet operator.
 func (p *walletPersister) UpdateRetentions(w *wallet.Wallet) error {
 	return transaction(p.db, func(tx *sqlx.Tx) error {
@@ -354,3 +601,33 @@ func (p *walletPersister) execUpdateItemStockRetention(tx *sqlx.Tx, w *wallet.Wa
 
 	return nil
 }
+
+// PersistBrokerImport saves the operations added to the wallet, the transfers of the deposits and withdrawals
+// of the broker and the ids of the broker transactions booked, all or none of them. The ids already stored
+// are kept
+func (p *walletPersister) PersistBrokerImport(w *wallet.Wallet, ts []*transfer.Transfer, ids []string) error {
+	query := `
+		INSERT INTO wallet_import_transaction(wallet_id, transaction_id) VALUES ($1, $2)
+		ON CONFLICT (wallet_id, transaction_id) DO NOTHING
+	`
+
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		for _, t := range ts {
+			if err := execTransferInsert(tx, t); err != nil {
+				return errors.Wrapf(err, "Persist wallet %q transfer %q", w.Name, t.ID)
+			}
+		}
+
+		if err := p.execPersistOperations(tx, w); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if _, err := tx.Exec(query, w.ID, id); err != nil {
+				return errors.Wrapf(err, "Persist wallet %q import transaction %q", w.Name, id)
+			}
+		}
+
+		return nil
+	})
+}
@@ -24,6 +24,10 @@ func (rw *WalletReload) Reload(w *wallet.Wallet) error {
 			return err
 		}
 
+		if err := rw.execDeleteLedger(tx, w); err != nil {
+			return err
+		}
+
 		if err := rw.execDeleteOperation(tx, w); err != nil {
 			return err
 		}
@@ -48,6 +52,16 @@ func (rw *WalletReload) Reload(w *wallet.Wallet) error {
 	})
 }
 
+func (rw *WalletReload) execDeleteLedger(tx *sqlx.Tx, w *wallet.Wallet) error {
+	query := `DELETE FROM wallet_ledger WHERE wallet_id = $1 AND operation_id IS NOT NULL`
+
+	if _, err := tx.Exec(query, w.ID); err != nil {
+		return err
+	}
+
+	return execUpdateLedgerBalance(tx, w)
+}
+
 func (rw *WalletReload) execDeleteOperation(tx *sqlx.Tx, w *wallet.Wallet) error {
 	query := `DELETE FROM operation WHERE wallet_id = $1`
 
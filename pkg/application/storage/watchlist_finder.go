@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	watchlistFinder struct {
+		db sqlx.Queryer
+	}
+
+	watchlistTuple struct {
+		ID   uuid.UUID `db:"id"`
+		Name string    `db:"name"`
+	}
+
+	watchlistStockTuple struct {
+		ID     uuid.UUID `db:"id"`
+		Symbol string    `db:"symbol"`
+		Name   string    `db:"name"`
+	}
+)
+
+var _ watchlist.Finder = &watchlistFinder{}
+
+func NewWatchlistFinder(db sqlx.Queryer) *watchlistFinder {
+	return &watchlistFinder{
+		db: db,
+	}
+}
+
+func (f *watchlistFinder) FindByName(name string) (*watchlist.Watchlist, error) {
+	var tuple watchlistTuple
+
+	query := `SELECT id, name FROM watchlist WHERE name ilike $1`
+
+	err := sqlx.Get(f.db, &tuple, query, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, mm.ErrNotFound
+		}
+
+		return nil, errors.Wrapf(err, "Select watchlist with name %q", name)
+	}
+
+	return f.hydrate(&tuple)
+}
+
+func (f *watchlistFinder) FindAll() ([]*watchlist.Watchlist, error) {
+	var tuples []watchlistTuple
+
+	query := `SELECT id, name FROM watchlist ORDER BY name`
+
+	err := sqlx.Select(f.db, &tuples, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "Select watchlists")
+	}
+
+	var ws []*watchlist.Watchlist
+	for _, tuple := range tuples {
+		w, err := f.hydrate(&tuple)
+		if err != nil {
+			return nil, err
+		}
+
+		ws = append(ws, w)
+	}
+
+	return ws, nil
+}
+
+// hydrate builds the watchlist with its stocks. Only the stocks id, symbol and name are loaded
+func (f *watchlistFinder) hydrate(tuple *watchlistTuple) (*watchlist.Watchlist, error) {
+	w := &watchlist.Watchlist{
+		ID:     tuple.ID,
+		Name:   tuple.Name,
+		Stocks: map[uuid.UUID]*stock.Stock{},
+	}
+
+	var tuples []watchlistStockTuple
+
+	query := `SELECT s.id, s.symbol, s.name
+			FROM watchlist_stock ws
+			INNER JOIN stock s ON ws.stock_id = s.id
+			WHERE ws.watchlist_id = $1`
+
+	err := sqlx.Select(f.db, &tuples, query, w.ID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, errors.Wrapf(err, "Select watchlist stocks with watchlist id %q", w.ID)
+	}
+
+	for _, tuple := range tuples {
+		w.Stocks[tuple.ID] = &stock.Stock{
+			ID:     tuple.ID,
+			Symbol: tuple.Symbol,
+			Name:   tuple.Name,
+		}
+	}
+
+	return w, nil
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	// watchlistPersister struct to hold necessary dependencies
+	watchlistPersister struct {
+		db *sqlx.DB
+	}
+)
+
+var _ watchlist.Persister = &watchlistPersister{}
+
+func NewWatchlistPersister(db *sqlx.DB) *watchlistPersister {
+	return &watchlistPersister{
+		db: db,
+	}
+}
+
+// Persist stores the watchlist, replacing its stocks
+func (p *watchlistPersister) Persist(w *watchlist.Watchlist) error {
+	return transaction(p.db, func(tx *sqlx.Tx) error {
+		query := `
+			INSERT INTO watchlist(id, name) VALUES ($1, $2)
+			ON CONFLICT (id) DO UPDATE
+			SET name = excluded.name
+		`
+
+		if _, err := tx.Exec(query, w.ID, w.Name); err != nil {
+			return errors.Wrapf(err, "Persist watchlist %q", w.Name)
+		}
+
+		query = `DELETE FROM watchlist_stock WHERE watchlist_id = $1`
+
+		if _, err := tx.Exec(query, w.ID); err != nil {
+			return errors.Wrapf(err, "Persist watchlist %q delete stocks", w.Name)
+		}
+
+		query = `INSERT INTO watchlist_stock(watchlist_id, stock_id) VALUES ($1, $2)`
+
+		for _, s := range w.Stocks {
+			if _, err := tx.Exec(query, w.ID, s.ID); err != nil {
+				return errors.Wrapf(err, "Persist watchlist %q insert stock %q", w.Name, s.Symbol)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete removes the watchlist along with its stocks
+func (p *watchlistPersister) Delete(w *watchlist.Watchlist) error {
+	query := `DELETE FROM watchlist WHERE id = $1`
+
+	_, err := p.db.Exec(query, w.ID)
+	if err != nil {
+		return errors.Wrapf(err, "Delete watchlist %q", w.Name)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
@@ -24,6 +25,11 @@ func SPrintValue(value mm.Value, precision int) string {
 	return fmt.Sprintf("%.*f %s", precision, value.Amount, value.Currency)
 }
 
+// SPrintAmount prints the amount of stocks, with decimals only when it is fractional
+func SPrintAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
 func SPrintPercentage(value float64, precision int) string {
 	if value == 0 {
 		return ""
@@ -1,10 +1,16 @@
 package util
 
 import (
-	"time"
-
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/satori/go.uuid"
 )
@@ -14,13 +20,44 @@ type (
 		ID        uuid.UUID `db:"id"`
 		Resource  string    `db:"resource"`
 		FileName  string    `db:"file_name"`
+		Hash      string    `db:"hash"`
+		Mapping   string    `db:"mapping"`
 		CreatedAt time.Time `db:"created_at"`
 	}
 
+	// ResourceRow is a row imported from a file, told by its fingerprint
+	ResourceRow struct {
+		Resource    string    `db:"resource"`
+		Fingerprint string    `db:"fingerprint"`
+		ImportID    uuid.UUID `db:"import_id"`
+		FileName    string    `db:"file_name"`
+		Line        int       `db:"line"`
+	}
+
+	// ImportIssue is a problem found in a line of a file imported, line 0 when it is not of a line
+	ImportIssue struct {
+		Line    int
+		Message string
+	}
+
+	// ImportReport tells the problems found validating a file imported as a dry run, nothing is saved
+	ImportReport struct {
+		File   string
+		Rows   int
+		Issues []ImportIssue
+
+		// New, Skipped and Conflicting are the rows told apart by whether they were imported already
+		New         int
+		Skipped     int
+		Conflicting int
+	}
+
 	ResourceStorage interface {
 		Persist(r Resource) error
 		FindAllByResource(resource string) ([]Resource, error)
 		FindLastByResourceAndFilesGroup(resource, wName string) (Resource, error)
+		PersistRows(rs []ResourceRow) error
+		FindAllRowsByResource(resource string) ([]ResourceRow, error)
 	}
 )
 
@@ -33,6 +70,24 @@ func NewResource(resource, fname string) Resource {
 	}
 }
 
+// NewResourceRows returns the rows imported from the file of the resource for the lines given, line numbers
+// start at 1
+func NewResourceRows(r Resource, fingerprints []string, lines []int) []ResourceRow {
+	rs := make([]ResourceRow, len(fingerprints))
+
+	for i, f := range fingerprints {
+		rs[i] = ResourceRow{
+			Resource:    r.Resource,
+			Fingerprint: f,
+			ImportID:    r.ID,
+			FileName:    r.FileName,
+			Line:        lines[i],
+		}
+	}
+
+	return rs
+}
+
 func GeResourceNameFromFilePath(file string) string {
 	var dir = filepath.Dir(file)
 	var ext = filepath.Ext(file)
@@ -44,3 +99,52 @@ func GeResourceNameFromFilePath(file string) string {
 
 	return res
 }
+
+// FileHash returns the hash of the content of the file, the same file renamed has the same hash
+func FileHash(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Fingerprints returns the fingerprint of each line of the resource, it only depends on the content of the line
+// so the line is told apart whatever the file it is in. The fields are compared trimmed, and the same line
+// repeated gets a different fingerprint on each occurrence so two equal operations of the same day are both imported
+func Fingerprints(resource string, lines [][]string) []string {
+	fs := make([]string, len(lines))
+	seen := map[string]int{}
+
+	for i, line := range lines {
+		fields := make([]string, len(line))
+		for j, field := range line {
+			fields[j] = strings.TrimSpace(field)
+		}
+
+		row := strings.Join(fields, "\x1f")
+		seen[row]++
+
+		h := sha256.Sum256([]byte(resource + "\x1e" + row + "\x1e" + strconv.Itoa(seen[row])))
+		fs[i] = hex.EncodeToString(h[:])
+	}
+
+	return fs
+}
+
+// AddIssue adds the problem found in the line
+func (r *ImportReport) AddIssue(line int, err error) {
+	r.Issues = append(r.Issues, ImportIssue{Line: line, Message: err.Error()})
+}
+
+// AddIssuef adds the problem found in the line
+func (r *ImportReport) AddIssuef(line int, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ImportIssue{Line: line, Message: fmt.Sprintf(format, args...)})
+}
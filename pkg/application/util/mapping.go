@@ -0,0 +1,351 @@
+package util
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultDateLayout is the layout of the dates of the files imported without mapping
+const defaultDateLayout = "2/1/2006"
+
+type (
+	// Mapping is the profile the csv files of an import are read with. The columns are the ones of the line
+	// the import expects, in its order, named by the header of the file or by their position
+	//
+	//	delimiter: ";"
+	//	header: true
+	//	locale:
+	//	  date: "2006-01-02"
+	//	  decimal: ","
+	//	  thousands: "."
+	//	columns:
+	//	  - name: Trade
+	//	  - name: Date
+	//	  - name: Product
+	//	  - name: Type
+	//	    values:
+	//	      Buy: Compra
+	//	      Sell: Venta
+	//	  - value: ""
+	Mapping struct {
+		Delimiter string          `yaml:"delimiter"`
+		Header    bool            `yaml:"header"`
+		Locale    MappingLocale   `yaml:"locale"`
+		Columns   []MappingColumn `yaml:"columns"`
+
+		// name of the profile, the file it is loaded from without extension
+		name string
+	}
+
+	// MappingLocale tells how the dates and numbers of the file are written. The date is a Go time layout,
+	// day/month/year by default, and the numbers have "." as decimal separator and no thousands one by default,
+	// but the transfers that are read in Spanish, "." thousands separator and "," decimal one
+	MappingLocale struct {
+		Date      string `yaml:"date"`
+		Decimal   string `yaml:"decimal"`
+		Thousands string `yaml:"thousands"`
+	}
+
+	// MappingColumn is where a column of the line is read from: the column of the file by its header name or
+	// by its position starting at 1, or a constant value. The values read can be mapped to the ones expected
+	MappingColumn struct {
+		Name   string            `yaml:"name"`
+		Index  int               `yaml:"index"`
+		Value  *string           `yaml:"value"`
+		Values map[string]string `yaml:"values"`
+	}
+
+	// LineLayout tells the columns of the line of an import holding dates, written day/month/year, and numbers,
+	// written with "." as decimal separator
+	LineLayout struct {
+		Dates   []int
+		Numbers []int
+	}
+)
+
+// LoadMapping reads the mapping profile from the yaml file
+func LoadMapping(file string) (*Mapping, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Mapping
+	if err = yaml.UnmarshalStrict(b, &m); err != nil {
+		return nil, errors.Wrapf(err, "parsing mapping %s", file)
+	}
+
+	if err = m.validate(); err != nil {
+		return nil, errors.Wrapf(err, "mapping %s", file)
+	}
+
+	m.name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	return &m, nil
+}
+
+func (m *Mapping) validate() error {
+	if m.Delimiter != "" && utf8.RuneCountInString(m.Delimiter) != 1 {
+		return errors.Errorf("delimiter %q has to be a single character", m.Delimiter)
+	}
+
+	for i, c := range m.Columns {
+		set := 0
+		if c.Name != "" {
+			set++
+		}
+
+		if c.Index != 0 {
+			set++
+		}
+
+		if c.Value != nil {
+			set++
+		}
+
+		if set != 1 {
+			return errors.Errorf("column %d has to set one of name, index or value", i+1)
+		}
+
+		if c.Name != "" && !m.Header {
+			return errors.Errorf("column %d named %q but the file has no header", i+1, c.Name)
+		}
+
+		if c.Index < 0 {
+			return errors.Errorf("column %d index %d not valid, positions start at 1", i+1, c.Index)
+		}
+	}
+
+	return nil
+}
+
+// Name returns the name of the profile, empty without mapping
+func (m *Mapping) Name() string {
+	if m == nil {
+		return ""
+	}
+
+	return m.name
+}
+
+// HasHeader tells whether the first line of the file is the header
+func (m *Mapping) HasHeader() bool {
+	return m != nil && m.Header
+}
+
+// Comma returns the delimiter of the fields of the file
+func (m *Mapping) Comma() rune {
+	if m == nil || m.Delimiter == "" {
+		return ','
+	}
+
+	r, _ := utf8.DecodeRuneInString(m.Delimiter)
+
+	return r
+}
+
+// Date returns the date written in the locale of the mapping as day/month/year, the layout of the files
+// imported without mapping
+func (m *Mapping) Date(dt string) (string, error) {
+	dt = strings.TrimSpace(dt)
+	if m == nil || m.Locale.Date == "" || dt == "" {
+		return dt, nil
+	}
+
+	t, err := time.Parse(m.Locale.Date, dt)
+	if err != nil {
+		return "", errors.Errorf("date %q not valid, expected %s", dt, m.Locale.Date)
+	}
+
+	return t.Format(defaultDateLayout), nil
+}
+
+// Number returns the number written in the locale of the mapping with "." as decimal separator and without
+// thousands separator
+func (m *Mapping) Number(n string) string {
+	n = strings.TrimSpace(n)
+	if m == nil {
+		return n
+	}
+
+	if m.Locale.Thousands != "" {
+		n = strings.Replace(n, m.Locale.Thousands, "", -1)
+	}
+
+	if m.Locale.Decimal != "" && m.Locale.Decimal != "." {
+		n = strings.Replace(n, m.Locale.Decimal, ".", 1)
+	}
+
+	return n
+}
+
+// HasNumberLocale tells whether the mapping sets how the numbers of the file are written
+func (m *Mapping) HasNumberLocale() bool {
+	return m != nil && (m.Locale.Decimal != "" || m.Locale.Thousands != "")
+}
+
+// positions returns the position in the file of the columns of the mapping, -1 for the constant values
+func (m *Mapping) positions(header []string) ([]int, error) {
+	names := make(map[string]int, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+
+		if _, ok := names[h]; !ok {
+			names[h] = i
+		}
+	}
+
+	ps := make([]int, len(m.Columns))
+
+	for i, c := range m.Columns {
+		switch {
+		case c.Value != nil:
+			ps[i] = -1
+		case c.Name != "":
+			p, ok := names[c.Name]
+			if !ok {
+				return nil, errors.Errorf("column %q not found in the header", c.Name)
+			}
+
+			ps[i] = p
+		default:
+			ps[i] = c.Index - 1
+		}
+	}
+
+	return ps, nil
+}
+
+// line returns the line the import expects of the record read from the file
+func (m *Mapping) line(record []string, ps []int) ([]string, error) {
+	line := make([]string, len(m.Columns))
+
+	for i, c := range m.Columns {
+		if ps[i] < 0 {
+			line[i] = *c.Value
+
+			continue
+		}
+
+		if ps[i] >= len(record) {
+			return nil, errors.Errorf("column %d not found, the line has %d columns", ps[i]+1, len(record))
+		}
+
+		v := record[ps[i]]
+		if mv, ok := c.Values[strings.TrimSpace(v)]; ok {
+			v = mv
+		}
+
+		line[i] = v
+	}
+
+	return line, nil
+}
+
+// Record returns the record of the file for the line of the import, the reverse of reading it: the dates and the
+// numbers are written in the locale of the mapping, the values are mapped back to the ones of the file and the
+// columns are placed in their position, told by the header of the file for the ones read by name. The columns
+// read as a constant value are left out. Without mapping the record is the line
+func (m *Mapping) Record(line []string, header []string, layout LineLayout) ([]string, error) {
+	if m == nil || len(m.Columns) == 0 {
+		return line, nil
+	}
+
+	ps, err := m.positions(header)
+	if err != nil {
+		return nil, err
+	}
+
+	width := len(header)
+	for _, p := range ps {
+		if p >= width {
+			width = p + 1
+		}
+	}
+
+	record := make([]string, width)
+
+	for i, c := range m.Columns {
+		if ps[i] < 0 || i >= len(line) {
+			continue
+		}
+
+		v := line[i]
+
+		switch {
+		case hasColumn(layout.Dates, i):
+			v, err = m.formatDate(v)
+			if err != nil {
+				return nil, err
+			}
+		case hasColumn(layout.Numbers, i):
+			v = m.formatNumber(v)
+		}
+
+		record[ps[i]] = c.fileValue(v)
+	}
+
+	return record, nil
+}
+
+// formatDate returns the date written as day/month/year in the locale of the mapping
+func (m *Mapping) formatDate(dt string) (string, error) {
+	dt = strings.TrimSpace(dt)
+	if m.Locale.Date == "" || dt == "" {
+		return dt, nil
+	}
+
+	t, err := time.Parse(defaultDateLayout, dt)
+	if err != nil {
+		return "", errors.Errorf("date %q not valid, expected day/month/year", dt)
+	}
+
+	return t.Format(m.Locale.Date), nil
+}
+
+// formatNumber returns the number written with "." as decimal separator in the locale of the mapping, without
+// thousands separator
+func (m *Mapping) formatNumber(n string) string {
+	n = strings.TrimSpace(n)
+	if m.Locale.Decimal == "" || m.Locale.Decimal == "." {
+		return n
+	}
+
+	return strings.Replace(n, ".", m.Locale.Decimal, 1)
+}
+
+// fileValue returns the value of the file mapped to the one given, the first in order when several are.
+// The value is kept when none is mapped to it
+func (c MappingColumn) fileValue(v string) string {
+	var fvs []string
+	for fv, mv := range c.Values {
+		if mv == v {
+			fvs = append(fvs, fv)
+		}
+	}
+
+	if len(fvs) == 0 {
+		return v
+	}
+
+	sort.Strings(fvs)
+
+	return fvs[0]
+}
+
+func hasColumn(cs []int, c int) bool {
+	for _, i := range cs {
+		if i == c {
+			return true
+		}
+	}
+
+	return false
+}
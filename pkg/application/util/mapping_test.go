@@ -0,0 +1,338 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMapping(t *testing.T, dir, name, content string) string {
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+// testMapping reads the operations of a file in Spanish, with the product by its position and without status
+func testMapping() *Mapping {
+	empty := ""
+
+	return &Mapping{
+		Delimiter: ";",
+		Header:    true,
+		Locale: MappingLocale{
+			Date:      "2006-01-02",
+			Decimal:   ",",
+			Thousands: ".",
+		},
+		Columns: []MappingColumn{
+			{Name: "Trade"},
+			{Name: "Date"},
+			{Index: 3},
+			{Name: "Type", Values: map[string]string{"Compra": "Buy", "compra": "Buy", "Venta": "Sell"}},
+			{Value: &empty},
+			{Name: "Amount"},
+		},
+	}
+}
+
+func TestLoadMapping(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := writeMapping(t, dir, "degiro.yml", `delimiter: ";"
+header: true
+locale:
+  date: "2006-01-02"
+  decimal: ","
+  thousands: "."
+columns:
+  - name: Trade
+  - name: Date
+  - index: 3
+  - name: Type
+    values:
+      Compra: Buy
+      compra: Buy
+      Venta: Sell
+  - value: ""
+  - name: Amount
+`)
+
+	m, err := LoadMapping(file)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	expected := testMapping()
+	expected.name = "degiro"
+
+	assert.Equal(t, expected, m)
+	assert.Equal(t, "degiro", m.Name())
+	assert.Equal(t, ';', m.Comma())
+	assert.True(t, m.HasHeader())
+	assert.True(t, m.HasNumberLocale())
+}
+
+func TestLoadMappingError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mapping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "delimiter",
+			content: `delimiter: ";;"`,
+			err:     `delimiter ";;" has to be a single character`,
+		},
+		{
+			name:    "name and index",
+			content: "header: true\ncolumns:\n  - name: Trade\n    index: 1",
+			err:     "column 1 has to set one of name, index or value",
+		},
+		{
+			name:    "none set",
+			content: "columns:\n  - index: 1\n  - values:\n      Compra: Buy",
+			err:     "column 2 has to set one of name, index or value",
+		},
+		{
+			name:    "name without header",
+			content: "columns:\n  - name: Trade",
+			err:     `column 1 named "Trade" but the file has no header`,
+		},
+		{
+			name:    "index",
+			content: "columns:\n  - index: -1",
+			err:     "column 1 index -1 not valid, positions start at 1",
+		},
+	}
+
+	for _, tt := range tests {
+		file := writeMapping(t, dir, "mapping.yml", tt.content)
+
+		_, err := LoadMapping(file)
+		assert.EqualError(t, err, "mapping "+file+": "+tt.err, tt.name)
+	}
+
+	file := writeMapping(t, dir, "mapping.yml", `separator: ";"`)
+
+	_, err = LoadMapping(file)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "parsing mapping "+file)
+		assert.Contains(t, err.Error(), "field separator not found")
+	}
+}
+
+func TestMappingDate(t *testing.T) {
+	tests := []struct {
+		mapping *Mapping
+		date    string
+		result  string
+		err     string
+	}{
+		{nil, "5/3/2018", "5/3/2018", ""},
+		{&Mapping{}, " 5/3/2018 ", "5/3/2018", ""},
+		{testMapping(), "2018-03-05", "5/3/2018", ""},
+		{testMapping(), " 2018-03-15 ", "15/3/2018", ""},
+		{testMapping(), "", "", ""},
+		{testMapping(), "05/03/2018", "", `date "05/03/2018" not valid, expected 2006-01-02`},
+	}
+
+	for _, tt := range tests {
+		d, err := tt.mapping.Date(tt.date)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.date)
+
+			continue
+		}
+
+		assert.NoError(t, err, tt.date)
+		assert.Equal(t, tt.result, d, tt.date)
+	}
+}
+
+func TestMappingNumber(t *testing.T) {
+	tests := []struct {
+		mapping *Mapping
+		number  string
+		result  string
+	}{
+		{nil, " 1234.56 ", "1234.56"},
+		{&Mapping{}, "1234.56", "1234.56"},
+		{testMapping(), "1.234,56", "1234.56"},
+		{testMapping(), "-12,5", "-12.5"},
+		{testMapping(), "1.234.567", "1234567"},
+		{testMapping(), "", ""},
+		{&Mapping{Locale: MappingLocale{Thousands: ","}}, "1,234.56", "1234.56"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.result, tt.mapping.Number(tt.number), tt.number)
+	}
+}
+
+func TestMappingLine(t *testing.T) {
+	header := []string{"\ufeffTrade", "Date", "Product", "Type", "Amount"}
+
+	tests := []struct {
+		name   string
+		header []string
+		record []string
+		line   []string
+		err    string
+	}{
+		{
+			name:   "mapped",
+			header: header,
+			record: []string{"1", "2018-03-05", "ENAGAS", " Compra ", "1.234,5"},
+			line:   []string{"1", "2018-03-05", "ENAGAS", "Buy", "", "1.234,5"},
+		},
+		{
+			name:   "value not mapped",
+			header: []string{"Amount", "Type", "Product", "Date", "Trade"},
+			record: []string{"10", "Transfer", "ENAGAS", "2018-03-05", "1"},
+			line:   []string{"1", "2018-03-05", "ENAGAS", "Transfer", "", "10"},
+		},
+		{
+			name:   "column not in the header",
+			header: []string{"Trade", "Date", "Product"},
+			err:    `column "Type" not found in the header`,
+		},
+		{
+			name:   "line shorter",
+			header: header,
+			record: []string{"1", "2018-03-05", "ENAGAS"},
+			err:    "column 4 not found, the line has 3 columns",
+		},
+	}
+
+	m := testMapping()
+
+	for _, tt := range tests {
+		ps, err := m.positions(tt.header)
+		if err == nil {
+			var line []string
+			if line, err = m.line(tt.record, ps); err == nil {
+				assert.Equal(t, tt.line, line, tt.name)
+			}
+		}
+
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+		}
+	}
+}
+
+func TestMappingRecord(t *testing.T) {
+	header := []string{"Trade", "Date", "Product", "Type", "Amount"}
+	layout := LineLayout{Dates: []int{1}, Numbers: []int{5}}
+
+	tests := []struct {
+		name    string
+		mapping *Mapping
+		header  []string
+		line    []string
+		record  []string
+		err     string
+	}{
+		{
+			name:    "without mapping",
+			mapping: nil,
+			line:    []string{"1", "5/3/2018", "ENAGAS", "Buy", "", "1234.5"},
+			record:  []string{"1", "5/3/2018", "ENAGAS", "Buy", "", "1234.5"},
+		},
+		{
+			name:    "mapped",
+			mapping: testMapping(),
+			header:  header,
+			line:    []string{"1", "5/3/2018", "ENAGAS", "Buy", "", "1234.5"},
+			record:  []string{"1", "2018-03-05", "ENAGAS", "Compra", "1234,5"},
+		},
+		{
+			name:    "value not mapped",
+			mapping: testMapping(),
+			header:  header,
+			line:    []string{"1", "15/3/2018", "ENAGAS", "Transfer", "", "10"},
+			record:  []string{"1", "2018-03-15", "ENAGAS", "Transfer", "10"},
+		},
+		{
+			name: "position beyond the header",
+			mapping: &Mapping{Columns: []MappingColumn{
+				{Index: 2},
+				{Index: 4},
+			}},
+			line:   []string{"1", "5/3/2018"},
+			record: []string{"", "1", "", "5/3/2018"},
+		},
+		{
+			name:    "date",
+			mapping: testMapping(),
+			header:  header,
+			line:    []string{"1", "2018-03-05", "ENAGAS", "Buy", "", "1234.5"},
+			err:     `date "2018-03-05" not valid, expected day/month/year`,
+		},
+		{
+			name:    "column not in the header",
+			mapping: testMapping(),
+			header:  []string{"Trade", "Date"},
+			err:     `column "Type" not found in the header`,
+		},
+	}
+
+	for _, tt := range tests {
+		record, err := tt.mapping.Record(tt.line, tt.header, layout)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.name)
+
+			continue
+		}
+
+		if assert.NoError(t, err, tt.name) {
+			assert.Equal(t, tt.record, record, tt.name)
+		}
+	}
+}
+
+func TestMappingRecordReadBack(t *testing.T) {
+	m := testMapping()
+	header := []string{"Trade", "Date", "Product", "Type", "Amount"}
+	line := []string{"1", "5/3/2018", "ENAGAS", "Sell", "", "1234.5"}
+
+	record, err := m.Record(line, header, LineLayout{Dates: []int{1}, Numbers: []int{5}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	ps, err := m.positions(header)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	read, err := m.line(record, ps)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	read[1], err = m.Date(read[1])
+	assert.NoError(t, err)
+
+	read[5] = m.Number(read[5])
+
+	assert.Equal(t, line, read)
+}
@@ -3,7 +3,10 @@ package util
 import (
 	"bufio"
 	"encoding/csv"
+	"io"
 	"os"
+
+	"github.com/pkg/errors"
 )
 
 type Reader interface {
@@ -15,9 +18,14 @@ type Reader interface {
 
 type CsvReader struct {
 	csvFileName string
+	mapping     *Mapping
 
 	file   *os.File
 	reader *csv.Reader
+	// positions of the columns of the mapping in the file
+	positions []int
+	// err is the error reading the header of the mapping, it is returned reading the lines as well
+	err error
 }
 
 func NewCsvReader(csvFileName string) *CsvReader {
@@ -26,6 +34,15 @@ func NewCsvReader(csvFileName string) *CsvReader {
 	}
 }
 
+// NewCsvMappingReader returns the reader of the csv file with the mapping given, the lines read are the ones of
+// the columns of the mapping. Without mapping the lines are read as they are in the file
+func NewCsvMappingReader(csvFileName string, mapping *Mapping) *CsvReader {
+	return &CsvReader{
+		csvFileName: csvFileName,
+		mapping:     mapping,
+	}
+}
+
 func (r *CsvReader) Open() error {
 	csvFile, err := os.Open(r.csvFileName)
 	if err != nil {
@@ -35,6 +52,32 @@ func (r *CsvReader) Open() error {
 	r.file = csvFile
 	r.reader = csv.NewReader(bufio.NewReader(csvFile))
 
+	if r.mapping == nil {
+		return nil
+	}
+
+	r.reader.Comma = r.mapping.Comma()
+	r.reader.FieldsPerRecord = -1
+
+	var header []string
+	if r.mapping.HasHeader() {
+		header, err = r.reader.Read()
+		if err != nil && err != io.EOF {
+			r.err = errors.Wrapf(err, "reading %s header", r.csvFileName)
+
+			return r.err
+		}
+	}
+
+	if len(r.mapping.Columns) > 0 {
+		r.positions, err = r.mapping.positions(header)
+		if err != nil {
+			r.err = errors.Wrapf(err, "reading %s", r.csvFileName)
+
+			return r.err
+		}
+	}
+
 	return nil
 }
 
@@ -43,14 +86,68 @@ func (r *CsvReader) Close() error {
 
 	r.file = nil
 	r.reader = nil
+	r.positions = nil
+	r.err = nil
 
 	return err
 }
 
 func (r *CsvReader) ReadLine() (record []string, err error) {
-	return r.reader.Read()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	record, err = r.reader.Read()
+	if err != nil || r.positions == nil {
+		return record, err
+	}
+
+	return r.mapping.line(record, r.positions)
 }
 
 func (r *CsvReader) ReadAllLines() (record [][]string, err error) {
-	return r.reader.ReadAll()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.positions == nil {
+		return r.reader.ReadAll()
+	}
+
+	for {
+		line, err := r.ReadLine()
+		if err == io.EOF {
+			return record, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		record = append(record, line)
+	}
+}
+
+// ReadRecords reads the records of the csv file as they are, with the delimiter of the mapping given. The first
+// record is returned apart as the header when the mapping tells the file has header
+func ReadRecords(csvFileName string, mapping *Mapping) (header []string, records [][]string, err error) {
+	f, err := os.Open(csvFileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(bufio.NewReader(f))
+	r.Comma = mapping.Comma()
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	records, err = r.ReadAll()
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "reading %s", csvFileName)
+	}
+
+	if mapping.HasHeader() && len(records) > 0 {
+		return records[0], records[1:], nil
+	}
+
+	return nil, records, nil
 }
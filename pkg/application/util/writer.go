@@ -15,6 +15,7 @@ type Writer interface {
 
 type CsvWriter struct {
 	csvFileName string
+	comma       rune
 
 	file   *os.File
 	writer *csv.Writer
@@ -23,6 +24,15 @@ type CsvWriter struct {
 func NewCsvWriter(csvFileName string) *CsvWriter {
 	return &CsvWriter{
 		csvFileName: csvFileName,
+		comma:       ',',
+	}
+}
+
+// NewCsvMappingWriter returns the writer of the csv file with the delimiter of the mapping given
+func NewCsvMappingWriter(csvFileName string, mapping *Mapping) *CsvWriter {
+	return &CsvWriter{
+		csvFileName: csvFileName,
+		comma:       mapping.Comma(),
 	}
 }
 
@@ -38,6 +48,7 @@ func (r *CsvWriter) Open() error {
 
 	r.file = csvFile
 	r.writer = csv.NewWriter(bufio.NewWriter(csvFile))
+	r.writer.Comma = r.comma
 
 	return nil
 }
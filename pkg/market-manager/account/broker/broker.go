@@ -0,0 +1,91 @@
+package broker
+
+import (
+	"math"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+type (
+	// ImportFormat defines the format of the files exported by the broker
+	ImportFormat string
+
+	// CommissionApply defines how the extra commission is applied to an operation
+	CommissionApply string
+
+	// Commission represents the fee schedule of a broker for one exchange
+	Commission struct {
+		Base    mm.Value
+		Extra   mm.Value
+		Apply   CommissionApply
+		Maximum mm.Value
+		Change  mm.Value
+	}
+
+	// Broker represents the entity where the wallets are hold
+	Broker struct {
+		ID   uuid.UUID
+		Name string
+		// default dividend retention in percentage
+		Retention float64
+		// percentage of the net capital lent as margin
+		Margin float64
+		// yearly connectivity fee
+		Connectivity mm.Value
+		ImportFormat ImportFormat
+		// commissions by exchange symbol
+		Commissions map[string]Commission
+	}
+)
+
+const (
+	Degiro             ImportFormat    = "degiro"
+	InteractiveBrokers ImportFormat    = "interactive-brokers"
+	PerStock           CommissionApply = "PER_STOCK"
+	InvestedPercentage CommissionApply = "INVESTED_PERCENTAGE"
+)
+
+func NewBroker(name string, retention, margin float64, connectivity mm.Value, importFormat ImportFormat) *Broker {
+	return &Broker{
+		ID:           uuid.NewV4(),
+		Name:         name,
+		Retention:    retention,
+		Margin:       margin,
+		Connectivity: connectivity,
+		ImportFormat: importFormat,
+		Commissions:  map[string]Commission{},
+	}
+}
+
+// Calculate returns the commission in euro to apply to an operation of the given amount of stocks, fractional
+// for the cryptocurrencies. value is the operation value in euro and priceChange the rate used to convert the stock
+// price to euro.
+func (c Commission) Calculate(amount float64, value mm.Value, priceChange float64) mm.Value {
+	commission := mm.Value{
+		Amount:   c.Base.Amount,
+		Currency: mm.Euro,
+	}
+
+	var extra float64
+
+	switch c.Apply {
+	case PerStock:
+		extra = c.Extra.Amount * amount
+
+		if c.Extra.Currency != mm.Euro && priceChange > 0 {
+			extra = extra / priceChange
+		}
+	case InvestedPercentage:
+		extra = value.Amount * c.Extra.Amount / 100
+	}
+
+	commission = commission.Increase(mm.Value{Amount: extra, Currency: mm.Euro})
+
+	if c.Maximum.Amount > 0 {
+		commission.Amount = math.Min(commission.Amount, c.Maximum.Amount)
+	}
+
+	return commission
+}
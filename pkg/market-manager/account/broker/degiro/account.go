@@ -0,0 +1,351 @@
+package degiro
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type movementKind int
+
+const (
+	ignored movementKind = iota
+	dividendMovement
+	withholdingMovement
+	fxMovement
+	connectivityMovement
+	interestMovement
+)
+
+// movementDescriptions tells the kind of the movement by its description, the first one the description
+// contains applies
+var movementDescriptions = []struct {
+	kind  movementKind
+	texts []string
+}{
+	{withholdingMovement, []string{"dividend tax", "retención del dividendo", "retencion del dividendo", "dividendbelasting"}},
+	{dividendMovement, []string{"dividend"}},
+	{fxMovement, []string{"fx credit", "fx debit", "valuta creditering", "valuta debitering", "cambio de divisa"}},
+	{connectivityMovement, []string{"connection fee", "conectividad", "aansluitingskosten"}},
+	{interestMovement, []string{"interest", "interés", "interes", "rente"}},
+}
+
+// fxRateDays is how far from a movement in other currency than euro the conversion is looked for
+const fxRateDays = 7
+
+type (
+	movement struct {
+		line    int
+		date    time.Time
+		product string
+		isin    string
+		kind    movementKind
+		rate    float64
+		change  mm.Value
+	}
+
+	// dividend sums the dividend paid and the tax withheld the same day for a stock
+	dividend struct {
+		*movement
+		net  float64
+		tax  float64
+		paid bool
+	}
+
+	// conversion is a currency exchange (FX) of the account: the amount in other currency than euro changed into
+	// the euro amount at the rate, units of the currency per euro. The FX movements come in pairs, one per currency
+	conversion struct {
+		line    int
+		date    time.Time
+		rate    float64
+		foreign mm.Value
+		euro    mm.Value
+		used    bool
+	}
+)
+
+func movementKindFromDescription(description string) movementKind {
+	description = strings.ToLower(description)
+
+	for _, md := range movementDescriptions {
+		for _, text := range md.texts {
+			if strings.Contains(description, text) {
+				return md.kind
+			}
+		}
+	}
+
+	return ignored
+}
+
+// parseAccount creates the dividends, connectivity fees and interests of the account movements. The dividends
+// are net of the tax withheld, which is added as a retention. The movements in other currency than euro are booked
+// at the euro amount of the currency exchange (FX) they were changed with, or at the rate of the nearest one, and
+// the rate is kept as their price change. The buys, sells and their fees are read from the transactions, the
+// deposits and withdrawals are imported as transfers
+func parseAccount(h header, rows [][]string) (*Report, error) {
+	is, err := h.indexes(dateColumn, descriptionColumn, changeColumn)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading degiro account header")
+	}
+
+	iDate, iDescription, iChange := is[0], is[1], is[2]
+	iTime, iProduct, iISIN, iFX := h.index(timeColumn), h.index(productColumn), h.index(isinColumn), h.index(fxColumn)
+
+	var (
+		ms  []*movement
+		fxs []*movement
+	)
+
+	for n, row := range rows {
+		line := n + 2
+
+		kind := movementKindFromDescription(field(row, iDescription))
+		if kind == ignored || field(row, iDate) == "" {
+			continue
+		}
+
+		date, err := parseDate(field(row, iDate), field(row, iTime))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: parsing date", line)
+		}
+
+		// the change is given by the currency followed by the amount
+		currency, err := parseCurrency(field(row, iChange))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+
+		amount, err := parseNumber(field(row, iChange+1))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: parsing change %q", line, field(row, iChange+1))
+		}
+
+		rate, err := parseNumber(field(row, iFX))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: parsing fx %q", line, field(row, iFX))
+		}
+
+		m := &movement{
+			line:    line,
+			date:    date,
+			product: field(row, iProduct),
+			isin:    field(row, iISIN),
+			kind:    kind,
+			rate:    rate,
+			change:  mm.Value{Amount: amount, Currency: currency},
+		}
+
+		if kind == fxMovement {
+			fxs = append(fxs, m)
+
+			continue
+		}
+
+		ms = append(ms, m)
+	}
+
+	cs := pairConversions(fxs)
+
+	var (
+		r    = &Report{}
+		ds   = map[string]*dividend{}
+		keys []string
+	)
+
+	for _, m := range ms {
+		switch m.kind {
+		case dividendMovement, withholdingMovement:
+			key := m.isin + m.date.Format(dateLayout) + string(m.change.Currency)
+
+			d, ok := ds[key]
+			if !ok {
+				d = &dividend{movement: m}
+				ds[key] = d
+				keys = append(keys, key)
+			}
+
+			d.net += m.change.Amount
+
+			if m.kind == withholdingMovement {
+				d.tax -= m.change.Amount
+			}
+
+			// the dividend is booked at the time it was paid, not the one the tax was withheld
+			if m.kind == dividendMovement && !d.paid {
+				d.movement, d.paid = m, true
+			}
+		case connectivityMovement, interestMovement:
+			// the fees and interests are charged to the account, the value is what was paid
+			value, rate, err := toEuro(m.change.Amount, m, cs)
+			if err != nil {
+				return nil, err
+			}
+
+			action := operation.Connectivity
+			if m.kind == interestMovement {
+				action = operation.Interest
+			}
+
+			r.Operations = append(r.Operations, operation.NewOperation(
+				m.date,
+				new(stock.Stock),
+				action,
+				0,
+				mm.Value{},
+				mm.Value{Amount: rate},
+				mm.Value{},
+				mm.Value{Amount: -value.Amount, Currency: value.Currency},
+				mm.Value{},
+			))
+		}
+	}
+
+	for _, key := range keys {
+		d := ds[key]
+		if !d.paid {
+			return nil, errors.Errorf("line %d: dividend tax withheld without dividend paid", d.line)
+		}
+
+		value, rate, err := toEuro(d.net, d.movement, cs)
+		if err != nil {
+			return nil, err
+		}
+
+		stk := newStock(d.product, d.isin)
+
+		r.Operations = append(r.Operations, operation.NewOperation(
+			d.date,
+			stk,
+			operation.Dividend,
+			0,
+			mm.Value{},
+			mm.Value{Amount: rate},
+			mm.Value{},
+			value,
+			mm.Value{},
+		))
+
+		if d.tax > 0 {
+			tax := d.tax
+			if rate > 0 {
+				tax = tax / rate
+			}
+
+			r.Retentions = append(r.Retentions, &Retention{
+				Stock: stk,
+				Date:  d.date,
+				Tax:   mm.Value{Amount: tax, Currency: mm.Euro},
+			})
+		}
+	}
+
+	r.Operations = inDateOrder(r.Operations)
+
+	return r, nil
+}
+
+// pairConversions pairs the FX movements of the same time, the one in euro with the one in the other currency.
+// The rate is the one of the movements, or the one of the amounts when none tells it
+func pairConversions(fxs []*movement) []*conversion {
+	var (
+		cs      []*conversion
+		pending []*movement
+	)
+
+	for _, m := range fxs {
+		paired := false
+
+		for i, p := range pending {
+			if !p.date.Equal(m.date) || (p.change.Currency == mm.Euro) == (m.change.Currency == mm.Euro) {
+				continue
+			}
+
+			euro, foreign := p, m
+			if m.change.Currency == mm.Euro {
+				euro, foreign = m, p
+			}
+
+			rate := math.Max(euro.rate, foreign.rate)
+			if rate == 0 && euro.change.Amount != 0 {
+				rate = math.Abs(foreign.change.Amount / euro.change.Amount)
+			}
+
+			cs = append(cs, &conversion{
+				line:    euro.line,
+				date:    euro.date,
+				rate:    rate,
+				foreign: foreign.change,
+				euro:    euro.change,
+			})
+
+			pending = append(pending[:i], pending[i+1:]...)
+			paired = true
+
+			break
+		}
+
+		if !paired {
+			pending = append(pending, m)
+		}
+	}
+
+	return cs
+}
+
+// toEuro changes the amount of the movement into euro, it returns the value and the rate, 0 for the movements in
+// euro. The amount is the euro one of the conversion of the same amount of the currency, the first not used yet
+// within fxRateDays, otherwise it is changed at the rate of the nearest conversion of the currency
+func toEuro(amount float64, m *movement, cs []*conversion) (mm.Value, float64, error) {
+	if m.change.Currency == mm.Euro {
+		return mm.Value{Amount: amount, Currency: mm.Euro}, 0, nil
+	}
+
+	var (
+		nearest  *conversion
+		distance time.Duration
+	)
+
+	for _, c := range cs {
+		if c.foreign.Currency != m.change.Currency || c.rate == 0 {
+			continue
+		}
+
+		d := c.date.Sub(m.date)
+		if d < 0 {
+			d = -d
+		}
+
+		if d > fxRateDays*24*time.Hour {
+			continue
+		}
+
+		// the amount of the movement leaves the currency, changed into euro
+		if !c.used && mm.RoundAmount(c.foreign.Amount+amount) == 0 {
+			c.used = true
+
+			return mm.Value{Amount: c.euro.Amount, Currency: mm.Euro}, c.rate, nil
+		}
+
+		if nearest == nil || d < distance {
+			nearest, distance = c, d
+		}
+	}
+
+	if nearest == nil {
+		return mm.Value{}, 0, errors.Errorf(
+			"line %d: no currency exchange found to change %s into %s",
+			m.line,
+			m.change.Currency,
+			mm.Euro,
+		)
+	}
+
+	return mm.Value{Amount: amount / nearest.rate, Currency: mm.Euro}, nearest.rate, nil
+}
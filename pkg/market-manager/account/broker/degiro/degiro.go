@@ -0,0 +1,244 @@
+// Package degiro reads the files exported from the Degiro web, Transactions.csv with the buys and sells and
+// Account.csv with the rest of the movements of the account. The headers are read in English, Spanish or Dutch.
+package degiro
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+const (
+	dateLayout     = "02-01-2006"
+	dateTimeLayout = "02-01-2006 15:04"
+)
+
+type (
+	// Report holds what is read from the file exported
+	Report struct {
+		Operations []*operation.Operation
+		Retentions []*Retention
+	}
+
+	// Retention is the dividend tax withheld from the dividend paid of the stock, in euro. The exports do not tell
+	// the dividend per share, the tax is the one of the stocks held
+	Retention struct {
+		Stock *stock.Stock
+		Date  time.Time
+		Tax   mm.Value
+	}
+)
+
+// Parse reads the file exported, either Transactions.csv or Account.csv, told by its header. The stocks of the
+// operations and retentions only hold the product name and the ISIN, they have to be resolved by the caller
+func Parse(r io.Reader) (*Report, error) {
+	rows, err := readRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header := newHeader(rows[0])
+
+	if header.index(quantityColumn) >= 0 {
+		os, err := parseTransactions(header, rows[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return &Report{Operations: os}, nil
+	}
+
+	if header.index(descriptionColumn) >= 0 {
+		return parseAccount(header, rows[1:])
+	}
+
+	return nil, errors.New("file is neither a degiro transactions nor an account export")
+}
+
+// ParseTransactions reads the buys and sells of Transactions.csv
+func ParseTransactions(r io.Reader) ([]*operation.Operation, error) {
+	rows, err := readRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTransactions(newHeader(rows[0]), rows[1:])
+}
+
+// ParseAccount reads the dividends, the taxes withheld, connectivity fees and interests of Account.csv
+func ParseAccount(r io.Reader) (*Report, error) {
+	rows, err := readRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAccount(newHeader(rows[0]), rows[1:])
+}
+
+func readRows(r io.Reader) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading degiro export")
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("degiro export is empty")
+	}
+
+	return rows, nil
+}
+
+// column holds the names a column of the exports is headed with, the ones ending in * match as prefix
+type column []string
+
+var (
+	dateColumn        = column{"date", "fecha", "datum"}
+	timeColumn        = column{"time", "hora", "tijd"}
+	productColumn     = column{"product", "producto"}
+	isinColumn        = column{"isin"}
+	quantityColumn    = column{"quantity", "número", "numero", "aantal"}
+	priceColumn       = column{"price", "precio", "koers"}
+	valueColumn       = column{"value", "valor", "waarde"}
+	rateColumn        = column{"exchange rate", "tipo de cambio", "wisselkoers"}
+	autoFXColumn      = column{"autofx*"}
+	costsColumn       = column{"transaction*", "costes de transacci*", "transactiekosten*"}
+	descriptionColumn = column{"description", "descripción", "descripcion", "omschrijving"}
+	fxColumn          = column{"fx", "tipo", "fx rate"}
+	changeColumn      = column{"change", "variación", "variacion", "mutatie"}
+)
+
+type header []string
+
+func newHeader(row []string) header {
+	h := make(header, len(row))
+	for i, name := range row {
+		// the files are exported with the utf-8 byte order mark
+		h[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	return h
+}
+
+// index returns the position of the column, -1 when the file does not have it
+func (h header) index(c column) int {
+	for i, name := range h {
+		for _, n := range c {
+			if name == n || strings.HasSuffix(n, "*") && strings.HasPrefix(name, strings.TrimSuffix(n, "*")) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// indexes returns the position of the columns, an error is returned when any of them is missing
+func (h header) indexes(cs ...column) ([]int, error) {
+	var is []int
+
+	for _, c := range cs {
+		i := h.index(c)
+		if i < 0 {
+			return nil, errors.Errorf("column %q not found", c[0])
+		}
+
+		is = append(is, i)
+	}
+
+	return is, nil
+}
+
+// field returns the value of the row at the position, empty when the row is shorter or the position is -1
+func field(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[i])
+}
+
+// parseNumber reads the numbers either with comma or dot as decimal separator, the last one of the number is
+// the decimal one: 1.234,56 or 1,234.56. A single separator is the decimal one, unless it appears more than once.
+// An empty number is 0
+func parseNumber(n string) (float64, error) {
+	n = strings.Replace(strings.TrimSpace(n), " ", "", -1)
+	if n == "" {
+		return 0, nil
+	}
+
+	dot := strings.LastIndex(n, ".")
+	comma := strings.LastIndex(n, ",")
+
+	switch {
+	case dot >= 0 && comma >= 0:
+		if comma > dot {
+			n = strings.Replace(n, ".", "", -1)
+			n = strings.Replace(n, ",", ".", 1)
+		} else {
+			n = strings.Replace(n, ",", "", -1)
+		}
+	case comma >= 0:
+		if strings.Count(n, ",") > 1 {
+			n = strings.Replace(n, ",", "", -1)
+		} else {
+			n = strings.Replace(n, ",", ".", 1)
+		}
+	case dot >= 0:
+		if strings.Count(n, ".") > 1 {
+			n = strings.Replace(n, ".", "", -1)
+		}
+	}
+
+	return strconv.ParseFloat(n, 64)
+}
+
+func parseDate(d, t string) (time.Time, error) {
+	if t == "" {
+		return time.Parse(dateLayout, d)
+	}
+
+	return time.Parse(dateTimeLayout, d+" "+t)
+}
+
+func parseCurrency(code string) (mm.Currency, error) {
+	c, ok := mm.CurrencyFromCode(code)
+	if !ok {
+		return "", errors.Errorf("currency %q not supported", code)
+	}
+
+	return c, nil
+}
+
+// newStock returns the stock as it is known by the export, to be resolved by its ISIN
+func newStock(product, isin string) *stock.Stock {
+	return &stock.Stock{
+		Name: product,
+		ISIN: strings.ToUpper(isin),
+	}
+}
+
+// inDateOrder sorts the operations from the oldest, the exports come from the newest
+func inDateOrder(os []*operation.Operation) []*operation.Operation {
+	for i, j := 0, len(os)-1; i < j; i, j = i+1, j-1 {
+		os[i], os[j] = os[j], os[i]
+	}
+
+	sort.SliceStable(os, func(i, j int) bool {
+		return os[i].Date.Before(os[j].Date)
+	})
+
+	return os
+}
@@ -0,0 +1,241 @@
+package degiro
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+const (
+	transactionsHeader = "Date,Time,Product,ISIN,Reference exchange,Venue,Quantity,Price,,Local value,,Value,,Exchange rate,AutoFX Fee,Transaction and/or third,,Total,,Order ID\n"
+
+	accountHeader = "Date,Time,Value date,Product,ISIN,Description,FX,Change,,Balance,,Order Id\n"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		value  float64
+	}{
+		{"", 0},
+		{"12", 12},
+		{"-12,5", -12.5},
+		{"12.5", 12.5},
+		{"1.234,56", 1234.56},
+		{"1,234.56", 1234.56},
+		{"1.234.567", 1234567},
+		{"1,234,567", 1234567},
+		{" 1 234,56 ", 1234.56},
+	}
+
+	for _, tt := range tests {
+		n, err := parseNumber(tt.number)
+		if assert.NoError(t, err, tt.number) {
+			assert.Equal(t, tt.value, n, tt.number)
+		}
+	}
+
+	_, err := parseNumber("12a")
+	assert.Error(t, err)
+}
+
+func TestParseTransactions(t *testing.T) {
+	tests := []struct {
+		name       string
+		doc        string
+		operations []*operation.Operation
+	}{
+		{
+			name: "english",
+			doc: "\ufeff" + transactionsHeader +
+				`12-03-2018,15:30,ENAGAS,ES0130960018,MAD,XMAD,-10,"22,50",EUR,"225,00",EUR,"225,00",EUR,,,"-2,04",EUR,"222,96",EUR,b2
+05-03-2018,09:10,REALTY INCOME,us7561091049,NSY,XNYS,20,"50,00",USD,"-1000,00",USD,"-847,46",EUR,"1,18","-0,85","-0,50",EUR,"-848,81",EUR,a1
+`,
+			operations: []*operation.Operation{
+				{
+					Date:                  time.Date(2018, 3, 5, 9, 10, 0, 0, time.UTC),
+					Stock:                 &stock.Stock{Name: "REALTY INCOME", ISIN: "US7561091049"},
+					Action:                operation.Buy,
+					Amount:                20,
+					Price:                 mm.Value{Amount: 50, Currency: mm.Dollar},
+					PriceChange:           mm.Value{Amount: 1.18},
+					PriceChangeCommission: mm.Value{Amount: 0.85, Currency: mm.Euro},
+					Value:                 mm.Value{Amount: 847.46, Currency: mm.Euro},
+					Commission:            mm.Value{Amount: 0.5, Currency: mm.Euro},
+				},
+				{
+					Date:                  time.Date(2018, 3, 12, 15, 30, 0, 0, time.UTC),
+					Stock:                 &stock.Stock{Name: "ENAGAS", ISIN: "ES0130960018"},
+					Action:                operation.Sell,
+					Amount:                10,
+					Price:                 mm.Value{Amount: 22.5, Currency: mm.Euro},
+					PriceChangeCommission: mm.Value{Amount: 0, Currency: mm.Euro},
+					Value:                 mm.Value{Amount: 225, Currency: mm.Euro},
+					Commission:            mm.Value{Amount: 2.04, Currency: mm.Euro},
+				},
+			},
+		},
+		{
+			name: "spanish",
+			doc: `Fecha,Hora,Producto,ISIN,Bolsa de,Centro de ejecución,Número,Precio,,Valor local,,Valor,,Tipo de cambio,Costes de transacción,,Total,,ID Orden
+12-03-2018,15:30,ENAGAS,ES0130960018,MAD,XMAD,10,"22,50",EUR,"-225,00",EUR,"-225,00",EUR,,"-2,04",EUR,"-227,04",EUR,b2
+`,
+			operations: []*operation.Operation{
+				{
+					Date:                  time.Date(2018, 3, 12, 15, 30, 0, 0, time.UTC),
+					Stock:                 &stock.Stock{Name: "ENAGAS", ISIN: "ES0130960018"},
+					Action:                operation.Buy,
+					Amount:                10,
+					Price:                 mm.Value{Amount: 22.5, Currency: mm.Euro},
+					PriceChangeCommission: mm.Value{Amount: 0, Currency: mm.Euro},
+					Value:                 mm.Value{Amount: 225, Currency: mm.Euro},
+					Commission:            mm.Value{Amount: 2.04, Currency: mm.Euro},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		r, err := Parse(strings.NewReader(tt.doc))
+		if assert.NoError(t, err, tt.name) {
+			// the ids are generated on creation
+			for _, o := range r.Operations {
+				o.ID = uuid.Nil
+			}
+
+			assert.Equal(t, tt.operations, r.Operations, tt.name)
+			assert.Empty(t, r.Retentions, tt.name)
+		}
+	}
+}
+
+func TestParseAccount(t *testing.T) {
+	doc := accountHeader +
+		`17-03-2018,06:40,16-03-2018,,,FX Credit,"1,1800",EUR,"3,60",EUR,"3,60",
+17-03-2018,06:40,16-03-2018,,,FX Debit,,USD,"-4,25",USD,"0,00",
+16-03-2018,07:12,15-03-2018,REALTY INCOME,US7561091049,Dividend Tax,,USD,"-0,75",USD,"4,25",
+16-03-2018,07:12,15-03-2018,REALTY INCOME,US7561091049,Dividend,,USD,"5,00",USD,"5,00",
+02-03-2018,14:05,28-02-2018,,,DEGIRO Exchange Connection Fee 2018 (New York Stock Exchange - NSY),,EUR,"-2,50",EUR,"-2,50",
+01-03-2018,09:00,01-03-2018,,,Deposit,,EUR,"1000,00",EUR,"1000,00",
+28-02-2018,00:00,28-02-2018,,,Flatex Interest,,EUR,"-0,10",EUR,"-0,10",
+`
+
+	r, err := Parse(strings.NewReader(doc))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for _, o := range r.Operations {
+		o.ID = uuid.Nil
+	}
+
+	stk := &stock.Stock{Name: "REALTY INCOME", ISIN: "US7561091049"}
+
+	assert.Equal(t, []*operation.Operation{
+		{
+			Date:   time.Date(2018, 2, 28, 0, 0, 0, 0, time.UTC),
+			Stock:  new(stock.Stock),
+			Action: operation.Interest,
+			Value:  mm.Value{Amount: 0.1, Currency: mm.Euro},
+		},
+		{
+			Date:   time.Date(2018, 3, 2, 14, 5, 0, 0, time.UTC),
+			Stock:  new(stock.Stock),
+			Action: operation.Connectivity,
+			Value:  mm.Value{Amount: 2.5, Currency: mm.Euro},
+		},
+		{
+			Date:        time.Date(2018, 3, 16, 7, 12, 0, 0, time.UTC),
+			Stock:       stk,
+			Action:      operation.Dividend,
+			PriceChange: mm.Value{Amount: 1.18},
+			Value:       mm.Value{Amount: 3.6, Currency: mm.Euro},
+		},
+	}, r.Operations)
+
+	if assert.Len(t, r.Retentions, 1) {
+		assert.Equal(t, stk, r.Retentions[0].Stock)
+		assert.Equal(t, time.Date(2018, 3, 16, 7, 12, 0, 0, time.UTC), r.Retentions[0].Date)
+		assert.Equal(t, mm.Euro, r.Retentions[0].Tax.Currency)
+		assert.InDelta(t, 0.6356, r.Retentions[0].Tax.Amount, 0.0001)
+	}
+}
+
+func TestParseAccountNearestRate(t *testing.T) {
+	// the dividend is not the amount changed, it is changed at the rate of the conversion
+	doc := accountHeader +
+		`20-03-2018,06:40,19-03-2018,,,FX Credit,,EUR,"10,00",EUR,"10,00",
+20-03-2018,06:40,19-03-2018,,,FX Debit,,USD,"-12,50",USD,"0,00",
+16-03-2018,07:12,15-03-2018,REALTY INCOME,US7561091049,Dividend,,USD,"5,00",USD,"5,00",
+`
+
+	r, err := ParseAccount(strings.NewReader(doc))
+	if !assert.NoError(t, err) || !assert.Len(t, r.Operations, 1) {
+		return
+	}
+
+	assert.Equal(t, mm.Value{Amount: 1.25}, r.Operations[0].PriceChange)
+	assert.Equal(t, mm.Value{Amount: 4, Currency: mm.Euro}, r.Operations[0].Value)
+	assert.Empty(t, r.Retentions)
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		err  string
+	}{
+		{
+			name: "empty",
+			doc:  "",
+			err:  "degiro export is empty",
+		},
+		{
+			name: "unknown export",
+			doc:  "Date,Product,Amount\n",
+			err:  "file is neither a degiro transactions nor an account export",
+		},
+		{
+			name: "transactions column",
+			doc:  "Date,Product,Quantity,Price,,Value\n",
+			err:  `reading degiro transactions header: column "isin" not found`,
+		},
+		{
+			name: "transactions date",
+			doc:  transactionsHeader + `2018-03-12,15:30,ENAGAS,ES0130960018,MAD,XMAD,10,"22,50",EUR,,,"-225,00",EUR` + "\n",
+			err:  `line 2: parsing date: parsing time "2018-03-12 15:30" as "02-01-2006 15:04": cannot parse "18-03-12 15:30" as "-"`,
+		},
+		{
+			name: "transactions value not in euro",
+			doc:  transactionsHeader + `12-03-2018,15:30,ENAGAS,ES0130960018,MAD,XMAD,10,"22,50",EUR,,,"-225,00",USD` + "\n",
+			err:  "line 2: value in USD, only accounts in euro are supported",
+		},
+		{
+			name: "account currency",
+			doc:  accountHeader + `16-03-2018,07:12,15-03-2018,NESTLE,CH0038863350,Dividend,,CHF,"5,00",CHF,"5,00",` + "\n",
+			err:  `line 2: currency "CHF" not supported`,
+		},
+		{
+			name: "account tax without dividend",
+			doc:  accountHeader + `16-03-2018,07:12,15-03-2018,ENAGAS,ES0130960018,Dividend Tax,,EUR,"-0,19",EUR,"-0,19",` + "\n",
+			err:  "line 2: dividend tax withheld without dividend paid",
+		},
+		{
+			name: "account without conversion",
+			doc:  accountHeader + `16-03-2018,07:12,15-03-2018,REALTY INCOME,US7561091049,Dividend,,USD,"5,00",USD,"5,00",` + "\n",
+			err:  "line 2: no currency exchange found to change $ into €",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.doc))
+		assert.EqualError(t, err, tt.err, tt.name)
+	}
+}
@@ -0,0 +1,75 @@
+package degiro
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+)
+
+// parseTransactions creates the buys and sells of the transactions. The value and the commissions are in euro,
+// the price in the currency of the stock and the price change is the rate the euro was changed at
+func parseTransactions(h header, rows [][]string) ([]*operation.Operation, error) {
+	is, err := h.indexes(dateColumn, productColumn, isinColumn, quantityColumn, priceColumn, valueColumn)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading degiro transactions header")
+	}
+
+	iDate, iProduct, iISIN, iQuantity, iPrice, iValue := is[0], is[1], is[2], is[3], is[4], is[5]
+	iTime, iRate, iAutoFX, iCosts := h.index(timeColumn), h.index(rateColumn), h.index(autoFXColumn), h.index(costsColumn)
+
+	var os []*operation.Operation
+
+	for n, row := range rows {
+		// the header is the line 1
+		line := n + 2
+
+		if field(row, iDate) == "" {
+			continue
+		}
+
+		date, err := parseDate(field(row, iDate), field(row, iTime))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d: parsing date", line)
+		}
+
+		var vs [6]float64
+		for i, c := range []int{iQuantity, iPrice, iValue, iRate, iAutoFX, iCosts} {
+			if vs[i], err = parseNumber(field(row, c)); err != nil {
+				return nil, errors.Wrapf(err, "line %d: parsing number %q", line, field(row, c))
+			}
+		}
+
+		quantity, price, value, rate, autoFX, costs := vs[0], vs[1], vs[2], vs[3], vs[4], vs[5]
+
+		if c := field(row, iValue+1); c != "" && c != "EUR" {
+			return nil, errors.Errorf("line %d: value in %s, only accounts in euro are supported", line, c)
+		}
+
+		action := operation.Buy
+		if quantity < 0 {
+			action = operation.Sell
+		}
+
+		priceValue := mm.Value{Amount: price}
+		if c, err := parseCurrency(field(row, iPrice+1)); err == nil {
+			priceValue.Currency = c
+		}
+
+		os = append(os, operation.NewOperation(
+			date,
+			newStock(field(row, iProduct), field(row, iISIN)),
+			action,
+			math.Abs(quantity),
+			priceValue,
+			mm.Value{Amount: rate},
+			mm.Value{Amount: math.Abs(autoFX), Currency: mm.Euro},
+			mm.Value{Amount: math.Abs(value), Currency: mm.Euro},
+			mm.Value{Amount: math.Abs(costs), Currency: mm.Euro},
+		))
+	}
+
+	return inDateOrder(os), nil
+}
@@ -0,0 +1,193 @@
+package ib
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// perShareRegexp reads the dividend per share of the description: AAPL(US0378331005) Cash Dividend USD 0.73 per Share
+var perShareRegexp = regexp.MustCompile(`(?i)[A-Z]{3} ([0-9]+(?:\.[0-9]+)?) PER SHARE`)
+
+// income sums the income paid, dividend or bond interest, and the tax withheld the same day for a stock
+type income struct {
+	action   operation.Action
+	ids      []string
+	paid     *flexCashTransaction
+	withheld *flexCashTransaction
+	gross    float64
+	tax      float64
+	fx       float64
+}
+
+// addCashTransactions adds the dividends and coupons net of the tax withheld, the interests, the fees and the
+// deposits and withdrawals
+func (r *Report) addCashTransactions(cts []flexCashTransaction) error {
+	var (
+		is   = map[string]*income{}
+		keys []string
+	)
+
+	for i := range cts {
+		ct := &cts[i]
+
+		if ct.LevelOfDetail == "SUMMARY" {
+			continue
+		}
+
+		date, err := parseDateTime(ct.DateTime)
+		if err != nil {
+			return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+		}
+
+		ns, err := parseNumbers(ct.Amount, ct.FXRateToBase)
+		if err != nil {
+			return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+		}
+
+		amount, fx := ns[0], ns[1]
+
+		switch ct.Type {
+		case "Dividends", "Payment In Lieu Of Dividends", "Withholding Tax", "Bond Interest Received", "Bond Interest Paid":
+			action := operation.Dividend
+			if ct.Type == "Bond Interest Received" || ct.Type == "Bond Interest Paid" {
+				action = operation.Coupon
+			}
+
+			key := fmt.Sprintf("%s%s%s%s%s", action, ct.ISIN, ct.Symbol, date.Format("20060102"), ct.Currency)
+
+			in, ok := is[key]
+			if !ok {
+				in = &income{action: action, fx: fx}
+				is[key] = in
+				keys = append(keys, key)
+			}
+
+			in.ids = append(in.ids, ct.TransactionID)
+
+			if ct.Type == "Withholding Tax" {
+				in.tax += amount
+				in.withheld = ct
+			} else {
+				in.gross += amount
+				in.paid = ct
+			}
+		case "Broker Interest Paid", "Broker Interest Received", "Other Fees":
+			value, err := toEuro(-amount, ct.Currency, fx)
+			if err != nil {
+				return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+			}
+
+			// the interests are booked as paid, the ones received are negative
+			action := operation.Interest
+			if ct.Type == "Other Fees" {
+				action = operation.Connectivity
+			}
+
+			r.addEntry(ct, action, date, value)
+		case "Deposits/Withdrawals", "Deposits & Withdrawals":
+			c, ok := mm.CurrencyFromCode(ct.Currency)
+			if !ok {
+				return errors.Errorf("cash transaction %s currency %q not supported", ct.TransactionID, ct.Currency)
+			}
+
+			rate, err := rateToEuro(ct.Currency, fx)
+			if err != nil {
+				return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+			}
+
+			r.Transfers = append(r.Transfers, &Transfer{
+				TransactionID: ct.TransactionID,
+				Date:          date,
+				Amount:        mm.Value{Amount: amount, Currency: c},
+				Rate:          rate,
+			})
+		default:
+			r.Unsupported = append(r.Unsupported, fmt.Sprintf("cash transaction %s %s %s: %s", ct.TransactionID, ct.Type, ct.Symbol, ct.Description))
+		}
+	}
+
+	for _, key := range keys {
+		if err := r.addIncome(is[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Report) addEntry(ct *flexCashTransaction, action operation.Action, date time.Time, value mm.Value) {
+	o := operation.NewOperation(date, new(stock.Stock), action, 0, mm.Value{}, mm.Value{}, mm.Value{}, value, mm.Value{})
+
+	r.Entries = append(r.Entries, &Entry{TransactionIDs: []string{ct.TransactionID}, Operation: o})
+}
+
+// addIncome adds the income net of the tax withheld. The tax withheld without income paid the same day, as
+// a later adjustment, is booked as a negative income. The tax withheld per share is added as a retention
+func (r *Report) addIncome(in *income) error {
+	ct := in.paid
+	if ct == nil {
+		ct = in.withheld
+	}
+
+	date, err := parseDateTime(ct.DateTime)
+	if err != nil {
+		return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+	}
+
+	stk := newStock(ct.Description, ct.Symbol, ct.ISIN)
+
+	value, err := toEuro(in.gross+in.tax, ct.Currency, in.fx)
+	if err != nil {
+		return errors.Wrapf(err, "cash transaction %s", ct.TransactionID)
+	}
+
+	o := operation.NewOperation(
+		date,
+		stk,
+		in.action,
+		0,
+		mm.Value{},
+		mm.Value{},
+		mm.Value{},
+		value,
+		mm.Value{},
+	)
+
+	r.Entries = append(r.Entries, &Entry{TransactionIDs: in.ids, Operation: o})
+
+	if in.paid == nil || in.withheld == nil || in.tax >= 0 || in.gross <= 0 {
+		return nil
+	}
+
+	m := perShareRegexp.FindStringSubmatch(in.paid.Description)
+	if m == nil {
+		return nil
+	}
+
+	perShare, err := parseNumber(m[1])
+	if err != nil || perShare == 0 {
+		return nil
+	}
+
+	shares := in.gross / perShare
+
+	tax, err := toEuro(-in.tax/shares, ct.Currency, in.fx)
+	if err != nil {
+		return errors.Wrapf(err, "cash transaction %s", in.withheld.TransactionID)
+	}
+
+	r.Retentions = append(r.Retentions, &Retention{
+		TransactionID: in.withheld.TransactionID,
+		Stock:         stk,
+		PerShare:      tax,
+	})
+
+	return nil
+}
@@ -0,0 +1,333 @@
+// Package ib reads the Interactive Brokers Flex Query XML reports: trades, cash transactions and corporate actions.
+// The values are booked in euro with the rate to the base currency of the report, the account base currency has to be euro.
+package ib
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	// Report holds what is read from the flex query report
+	Report struct {
+		Entries    []*Entry
+		Transfers  []*Transfer
+		Retentions []*Retention
+		// Unsupported describes the transactions of the report that could not be mapped
+		Unsupported []string
+	}
+
+	// Entry is an operation along with the ids of the IB transactions it was created from
+	Entry struct {
+		TransactionIDs []string
+		Operation      *operation.Operation
+	}
+
+	// Transfer is a deposit, positive amount, or a withdrawal of the account. The rate is the one the
+	// currency was changed at, units of the currency per euro
+	Transfer struct {
+		TransactionID string
+		Date          time.Time
+		Amount        mm.Value
+		Rate          float64
+	}
+
+	// Retention is the dividend tax withheld per stock held, in euro
+	Retention struct {
+		TransactionID string
+		Stock         *stock.Stock
+		PerShare      mm.Value
+	}
+)
+
+// TransactionIDs returns the ids of the IB transactions of the report mapped. The rows without id, as the
+// ORDER level ones, are left out
+func (r *Report) TransactionIDs() []string {
+	var ids []string
+
+	for _, e := range r.Entries {
+		ids = append(ids, e.IDs()...)
+	}
+
+	for _, t := range r.Transfers {
+		if t.TransactionID != "" {
+			ids = append(ids, t.TransactionID)
+		}
+	}
+
+	return ids
+}
+
+// IDs returns the ids of the IB transactions of the entry, the empty ones left out
+func (e *Entry) IDs() []string {
+	var ids []string
+
+	for _, id := range e.TransactionIDs {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Exclude removes from the report the transactions already imported. A transaction without id is never taken
+// as imported
+func (r *Report) Exclude(imported map[string]bool) {
+	var es []*Entry
+
+	for _, e := range r.Entries {
+		var found bool
+		for _, id := range e.IDs() {
+			if imported[id] {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			es = append(es, e)
+		}
+	}
+
+	var ts []*Transfer
+
+	for _, t := range r.Transfers {
+		if t.TransactionID == "" || !imported[t.TransactionID] {
+			ts = append(ts, t)
+		}
+	}
+
+	var rs []*Retention
+
+	for _, rt := range r.Retentions {
+		if rt.TransactionID == "" || !imported[rt.TransactionID] {
+			rs = append(rs, rt)
+		}
+	}
+
+	r.Entries, r.Transfers, r.Retentions = es, ts, rs
+}
+
+// Operations returns the operations of the entries
+func (r *Report) Operations() []*operation.Operation {
+	var os []*operation.Operation
+
+	for _, e := range r.Entries {
+		os = append(os, e.Operation)
+	}
+
+	return os
+}
+
+type (
+	flexResponse struct {
+		Statements []flexStatement `xml:"FlexStatements>FlexStatement"`
+	}
+
+	flexStatement struct {
+		AccountID        string                 `xml:"accountId,attr"`
+		Information      flexAccountInformation `xml:"AccountInformation"`
+		Trades           []flexTrade            `xml:"Trades>Trade"`
+		CashTransactions []flexCashTransaction  `xml:"CashTransactions>CashTransaction"`
+		CorporateActions []flexCorporateAction  `xml:"CorporateActions>CorporateAction"`
+	}
+
+	flexAccountInformation struct {
+		Currency string `xml:"currency,attr"`
+	}
+
+	flexTrade struct {
+		TransactionID        string `xml:"transactionID,attr"`
+		AssetCategory        string `xml:"assetCategory,attr"`
+		Symbol               string `xml:"symbol,attr"`
+		Description          string `xml:"description,attr"`
+		ISIN                 string `xml:"isin,attr"`
+		Currency             string `xml:"currency,attr"`
+		FXRateToBase         string `xml:"fxRateToBase,attr"`
+		DateTime             string `xml:"dateTime,attr"`
+		TradeDate            string `xml:"tradeDate,attr"`
+		Quantity             string `xml:"quantity,attr"`
+		TradePrice           string `xml:"tradePrice,attr"`
+		TradeMoney           string `xml:"tradeMoney,attr"`
+		IBCommission         string `xml:"ibCommission,attr"`
+		IBCommissionCurrency string `xml:"ibCommissionCurrency,attr"`
+		BuySell              string `xml:"buySell,attr"`
+		LevelOfDetail        string `xml:"levelOfDetail,attr"`
+	}
+
+	flexCashTransaction struct {
+		TransactionID string `xml:"transactionID,attr"`
+		Type          string `xml:"type,attr"`
+		Symbol        string `xml:"symbol,attr"`
+		Description   string `xml:"description,attr"`
+		ISIN          string `xml:"isin,attr"`
+		Currency      string `xml:"currency,attr"`
+		FXRateToBase  string `xml:"fxRateToBase,attr"`
+		DateTime      string `xml:"dateTime,attr"`
+		Amount        string `xml:"amount,attr"`
+		LevelOfDetail string `xml:"levelOfDetail,attr"`
+	}
+
+	flexCorporateAction struct {
+		TransactionID string `xml:"transactionID,attr"`
+		Type          string `xml:"type,attr"`
+		Symbol        string `xml:"symbol,attr"`
+		Description   string `xml:"description,attr"`
+		ISIN          string `xml:"isin,attr"`
+		DateTime      string `xml:"dateTime,attr"`
+		Quantity      string `xml:"quantity,attr"`
+		Proceeds      string `xml:"proceeds,attr"`
+		Value         string `xml:"value,attr"`
+	}
+)
+
+// Parse reads the flex query report. The stocks of the operations and retentions only hold the description,
+// the symbol and the ISIN, they have to be resolved by the caller
+func Parse(r io.Reader) (*Report, error) {
+	var resp flexResponse
+
+	if err := xml.NewDecoder(r).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "decoding flex query report")
+	}
+
+	report := &Report{}
+
+	for _, s := range resp.Statements {
+		if c := s.Information.Currency; c != "" && c != "EUR" {
+			return nil, errors.Errorf("account %s base currency %s, only accounts in euro are supported", s.AccountID, c)
+		}
+
+		// the trades are given by execution, by order or both, the executions are the ones taken when there are
+		hasExecutions := false
+		for _, t := range s.Trades {
+			if t.LevelOfDetail == "EXECUTION" {
+				hasExecutions = true
+
+				break
+			}
+		}
+
+		for _, t := range s.Trades {
+			if err := report.addTrade(t, hasExecutions); err != nil {
+				return nil, errors.Wrapf(err, "trade %s", t.TransactionID)
+			}
+		}
+
+		if err := report.addCashTransactions(s.CashTransactions); err != nil {
+			return nil, err
+		}
+
+		for _, ca := range s.CorporateActions {
+			if err := report.addCorporateAction(ca); err != nil {
+				return nil, errors.Wrapf(err, "corporate action %s", ca.TransactionID)
+			}
+		}
+	}
+
+	// the operations are added to the wallet in the order they happened
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		return report.Entries[i].Operation.Date.Before(report.Entries[j].Operation.Date)
+	})
+
+	return report, nil
+}
+
+// parseNumber reads the numbers of the report, an empty number is 0
+func parseNumber(n string) (float64, error) {
+	n = strings.Replace(strings.TrimSpace(n), ",", "", -1)
+	if n == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(n, 64)
+}
+
+// parseNumbers reads the numbers given in order, the first error is returned
+func parseNumbers(ns ...string) ([]float64, error) {
+	fs := make([]float64, len(ns))
+
+	for i, n := range ns {
+		f, err := parseNumber(n)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing number %q", n)
+		}
+
+		fs[i] = f
+	}
+
+	return fs, nil
+}
+
+// parseDateTime reads the date and time of the report, either yyyyMMdd or yyyy-MM-dd, optionally followed
+// by the time, HHmmss or HH:mm:ss, after a semicolon, comma or space
+func parseDateTime(dt string) (time.Time, error) {
+	dt = strings.TrimSpace(dt)
+
+	var date, clock string
+	if i := strings.IndexAny(dt, ";, "); i >= 0 {
+		date, clock = dt[:i], strings.TrimLeft(dt[i+1:], " ")
+	} else {
+		date = dt
+	}
+
+	date = strings.Replace(date, "-", "", -1)
+	clock = strings.Replace(clock, ":", "", -1)
+
+	if clock == "" {
+		t, err := time.Parse("20060102", date)
+
+		return t, errors.Wrapf(err, "parsing date %q", dt)
+	}
+
+	t, err := time.Parse("20060102150405", date+clock)
+
+	return t, errors.Wrapf(err, "parsing date time %q", dt)
+}
+
+// rateToEuro returns the rate the currency is changed at, units per euro, from the rate to the base currency.
+// An error is returned for other currency than euro without rate
+func rateToEuro(currency string, fxRateToBase float64) (float64, error) {
+	if currency == "EUR" {
+		return 1, nil
+	}
+
+	if fxRateToBase <= 0 {
+		return 0, errors.Errorf("no rate to the base currency to change %s into EUR", currency)
+	}
+
+	return 1 / fxRateToBase, nil
+}
+
+// toEuro changes the amount into euro with the rate to the base currency. An error is returned for other
+// currency than euro without rate
+func toEuro(amount float64, currency string, fxRateToBase float64) (mm.Value, error) {
+	if currency == "EUR" {
+		return mm.Value{Amount: amount, Currency: mm.Euro}, nil
+	}
+
+	if fxRateToBase <= 0 {
+		return mm.Value{}, errors.Errorf("no rate to the base currency to change %s into EUR", currency)
+	}
+
+	return mm.Value{Amount: amount * fxRateToBase, Currency: mm.Euro}, nil
+}
+
+func newStock(description, symbol, isin string) *stock.Stock {
+	return &stock.Stock{
+		Name:   description,
+		Symbol: strings.ToUpper(symbol),
+		ISIN:   strings.ToUpper(isin),
+	}
+}
@@ -0,0 +1,280 @@
+package ib
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+const flexSample = `<FlexQueryResponse queryName="market-manager" type="AF">
+<FlexStatements count="1">
+<FlexStatement accountId="U1234567" fromDate="20180101" toDate="20181231">
+<AccountInformation accountId="U1234567" currency="EUR"/>
+<Trades>
+<Trade transactionID="T1" assetCategory="STK" symbol="aapl" description="APPLE INC" isin="US0378331005" currency="USD" fxRateToBase="0.5" dateTime="20180305;153000" quantity="10" tradePrice="175" tradeMoney="1,750" ibCommission="-1" ibCommissionCurrency="USD" buySell="BUY" levelOfDetail="EXECUTION"/>
+<Trade transactionID="" assetCategory="STK" symbol="AAPL" description="APPLE INC" isin="US0378331005" currency="USD" fxRateToBase="0.5" dateTime="20180305;153000" quantity="10" tradePrice="175" tradeMoney="1750" ibCommission="-1" buySell="BUY" levelOfDetail="ORDER"/>
+<Trade transactionID="T2" assetCategory="STK" symbol="ENG" description="ENAGAS" isin="ES0130960018" currency="EUR" fxRateToBase="1" dateTime="2018-03-01, 10:15:00" quantity="-20" tradePrice="22.5" tradeMoney="-450" ibCommission="-4" ibCommissionCurrency="EUR" buySell="SELL" levelOfDetail="EXECUTION"/>
+<Trade transactionID="T3" assetCategory="CASH" symbol="EUR.USD" description="EUR.USD" currency="USD" fxRateToBase="0.5" dateTime="20180305;150000" quantity="1000" levelOfDetail="EXECUTION"/>
+<Trade transactionID="T4" assetCategory="OPT" symbol="AAPL 180420C00180000" description="AAPL 20APR18 180 C" levelOfDetail="EXECUTION"/>
+</Trades>
+<CashTransactions>
+<CashTransaction transactionID="C1" type="Dividends" symbol="AAPL" description="AAPL(US0378331005) Cash Dividend USD 0.50 per Share (Ordinary Dividend)" isin="US0378331005" currency="USD" fxRateToBase="0.5" dateTime="20180517" amount="5" levelOfDetail="DETAIL"/>
+<CashTransaction transactionID="C2" type="Withholding Tax" symbol="AAPL" description="AAPL(US0378331005) Cash Dividend USD 0.50 per Share - US Tax" isin="US0378331005" currency="USD" fxRateToBase="0.5" dateTime="20180517" amount="-0.75" levelOfDetail="DETAIL"/>
+<CashTransaction type="Dividends" currency="USD" dateTime="20180517" amount="5" levelOfDetail="SUMMARY"/>
+<CashTransaction transactionID="C3" type="Broker Interest Paid" description="EUR DEBIT INT FOR MAR-2018" currency="EUR" fxRateToBase="1" dateTime="20180403" amount="-1.25"/>
+<CashTransaction transactionID="C4" type="Other Fees" description="Market data" currency="USD" fxRateToBase="0.5" dateTime="20180403" amount="-10"/>
+<CashTransaction transactionID="C5" type="Deposits/Withdrawals" description="CASH RECEIPTS" currency="EUR" fxRateToBase="1" dateTime="20180228" amount="5000"/>
+<CashTransaction transactionID="C6" type="Commission Adjustments" description="adjustment" currency="EUR" dateTime="20180228" amount="1"/>
+</CashTransactions>
+<CorporateActions>
+<CorporateAction transactionID="A1" type="FS" symbol="AAPL" description="AAPL SPLIT 4 FOR 1" isin="US0378331005" dateTime="20180601;000000" quantity="30" proceeds="0" value="0"/>
+<CorporateAction transactionID="A2" type="TC" symbol="X" description="merger" dateTime="20180601" quantity="-10" proceeds="100"/>
+</CorporateActions>
+</FlexStatement>
+</FlexStatements>
+</FlexQueryResponse>`
+
+func parseSample(t *testing.T) *Report {
+	r, err := Parse(strings.NewReader(flexSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
+
+func TestParse(t *testing.T) {
+	r := parseSample(t)
+
+	// the ids are generated on creation
+	for _, e := range r.Entries {
+		e.Operation.ID = uuid.Nil
+	}
+
+	aapl := &stock.Stock{Name: "AAPL(US0378331005) Cash Dividend USD 0.50 per Share (Ordinary Dividend)", Symbol: "AAPL", ISIN: "US0378331005"}
+
+	assert.Equal(t, []*Entry{
+		{
+			TransactionIDs: []string{"T2"},
+			Operation: &operation.Operation{
+				Date:                  time.Date(2018, 3, 1, 10, 15, 0, 0, time.UTC),
+				Stock:                 &stock.Stock{Name: "ENAGAS", Symbol: "ENG", ISIN: "ES0130960018"},
+				Action:                operation.Sell,
+				Amount:                20,
+				Price:                 mm.Value{Amount: 22.5, Currency: mm.Euro},
+				PriceChange:           mm.Value{Amount: 1},
+				PriceChangeCommission: mm.Value{Amount: 0, Currency: mm.Euro},
+				Value:                 mm.Value{Amount: 450, Currency: mm.Euro},
+				Commission:            mm.Value{Amount: 4, Currency: mm.Euro},
+			},
+		},
+		{
+			TransactionIDs: []string{"T1"},
+			Operation: &operation.Operation{
+				Date:                  time.Date(2018, 3, 5, 15, 30, 0, 0, time.UTC),
+				Stock:                 &stock.Stock{Name: "APPLE INC", Symbol: "AAPL", ISIN: "US0378331005"},
+				Action:                operation.Buy,
+				Amount:                10,
+				Price:                 mm.Value{Amount: 175, Currency: mm.Dollar},
+				PriceChange:           mm.Value{Amount: 2},
+				PriceChangeCommission: mm.Value{Amount: 0, Currency: mm.Euro},
+				Value:                 mm.Value{Amount: 875, Currency: mm.Euro},
+				Commission:            mm.Value{Amount: 0.5, Currency: mm.Euro},
+			},
+		},
+		{
+			TransactionIDs: []string{"C3"},
+			Operation: &operation.Operation{
+				Date:   time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+				Stock:  new(stock.Stock),
+				Action: operation.Interest,
+				Value:  mm.Value{Amount: 1.25, Currency: mm.Euro},
+			},
+		},
+		{
+			TransactionIDs: []string{"C4"},
+			Operation: &operation.Operation{
+				Date:   time.Date(2018, 4, 3, 0, 0, 0, 0, time.UTC),
+				Stock:  new(stock.Stock),
+				Action: operation.Connectivity,
+				Value:  mm.Value{Amount: 5, Currency: mm.Euro},
+			},
+		},
+		{
+			TransactionIDs: []string{"C1", "C2"},
+			Operation: &operation.Operation{
+				Date:   time.Date(2018, 5, 17, 0, 0, 0, 0, time.UTC),
+				Stock:  aapl,
+				Action: operation.Dividend,
+				Value:  mm.Value{Amount: 2.125, Currency: mm.Euro},
+			},
+		},
+		{
+			TransactionIDs: []string{"A1"},
+			Operation: &operation.Operation{
+				Date:                  time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC),
+				Stock:                 &stock.Stock{Name: "AAPL SPLIT 4 FOR 1", Symbol: "AAPL", ISIN: "US0378331005"},
+				Action:                operation.Buy,
+				Amount:                30,
+				PriceChangeCommission: mm.Value{Amount: 0, Currency: mm.Euro},
+				Value:                 mm.Value{Amount: 0, Currency: mm.Euro},
+				Commission:            mm.Value{Amount: 0, Currency: mm.Euro},
+			},
+		},
+	}, r.Entries)
+
+	assert.Equal(t, []*Transfer{
+		{TransactionID: "C5", Date: time.Date(2018, 2, 28, 0, 0, 0, 0, time.UTC), Amount: mm.Value{Amount: 5000, Currency: mm.Euro}, Rate: 1},
+	}, r.Transfers)
+
+	assert.Equal(t, []*Retention{
+		{TransactionID: "C2", Stock: aapl, PerShare: mm.Value{Amount: 0.0375, Currency: mm.Euro}},
+	}, r.Retentions)
+
+	assert.Equal(t, []string{
+		"trade T4 OPT AAPL 180420C00180000: AAPL 20APR18 180 C",
+		"cash transaction C6 Commission Adjustments : adjustment",
+		"corporate action A2 TC X: merger",
+	}, r.Unsupported)
+}
+
+func TestParseOrders(t *testing.T) {
+	// the orders are taken when the report does not give the executions, without transaction id
+	doc := `<FlexQueryResponse><FlexStatements><FlexStatement accountId="U1234567"><Trades>
+<Trade assetCategory="STK" symbol="ENG" description="ENAGAS" currency="EUR" tradeDate="20180301" quantity="20" tradePrice="22.5" tradeMoney="450" ibCommission="-4" buySell="BUY" levelOfDetail="ORDER"/>
+<Trade assetCategory="STK" symbol="ENG" description="ENAGAS" currency="EUR" tradeDate="20180301" levelOfDetail="CLOSED_LOT"/>
+</Trades></FlexStatement></FlexStatements></FlexQueryResponse>`
+
+	r, err := Parse(strings.NewReader(doc))
+	if !assert.NoError(t, err) || !assert.Len(t, r.Entries, 1) {
+		return
+	}
+
+	assert.Equal(t, operation.Buy, r.Entries[0].Operation.Action)
+	assert.Equal(t, time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), r.Entries[0].Operation.Date)
+	assert.Empty(t, r.Entries[0].IDs())
+	assert.Empty(t, r.TransactionIDs())
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		err  string
+	}{
+		{
+			name: "base currency",
+			doc:  `<AccountInformation currency="USD"/>`,
+			err:  "account U1234567 base currency USD, only accounts in euro are supported",
+		},
+		{
+			name: "trade without rate",
+			doc:  `<Trades><Trade transactionID="T1" assetCategory="STK" currency="USD" dateTime="20180305" quantity="10" tradeMoney="1750"/></Trades>`,
+			err:  "trade T1: no rate to the base currency to change USD into EUR",
+		},
+		{
+			name: "trade commission without rate",
+			doc:  `<Trades><Trade transactionID="T1" assetCategory="STK" currency="EUR" dateTime="20180305" quantity="10" tradeMoney="1750" ibCommission="-1" ibCommissionCurrency="USD"/></Trades>`,
+			err:  "trade T1: commission: no rate to the base currency to change USD into EUR",
+		},
+		{
+			name: "trade number",
+			doc:  `<Trades><Trade transactionID="T1" assetCategory="STK" currency="EUR" dateTime="20180305" quantity="ten"/></Trades>`,
+			err:  `trade T1: parsing number "ten": strconv.ParseFloat: parsing "ten": invalid syntax`,
+		},
+		{
+			name: "dividend without rate",
+			doc:  `<CashTransactions><CashTransaction transactionID="C1" type="Dividends" currency="USD" dateTime="20180517" amount="5"/></CashTransactions>`,
+			err:  "cash transaction C1: no rate to the base currency to change USD into EUR",
+		},
+		{
+			name: "fee without rate",
+			doc:  `<CashTransactions><CashTransaction transactionID="C4" type="Other Fees" currency="USD" dateTime="20180403" amount="-10"/></CashTransactions>`,
+			err:  "cash transaction C4: no rate to the base currency to change USD into EUR",
+		},
+		{
+			name: "deposit currency",
+			doc:  `<CashTransactions><CashTransaction transactionID="C5" type="Deposits/Withdrawals" currency="GBP" fxRateToBase="1.1" dateTime="20180228" amount="5000"/></CashTransactions>`,
+			err:  `cash transaction C5 currency "GBP" not supported`,
+		},
+		{
+			name: "deposit without rate",
+			doc:  `<CashTransactions><CashTransaction transactionID="C5" type="Deposits/Withdrawals" currency="USD" dateTime="20180228" amount="5000"/></CashTransactions>`,
+			err:  "cash transaction C5: no rate to the base currency to change USD into EUR",
+		},
+	}
+
+	for _, tt := range tests {
+		doc := `<FlexQueryResponse><FlexStatements><FlexStatement accountId="U1234567">` + tt.doc + `</FlexStatement></FlexStatements></FlexQueryResponse>`
+
+		_, err := Parse(strings.NewReader(doc))
+		assert.EqualError(t, err, tt.err, tt.name)
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	tests := []struct {
+		dt   string
+		date time.Time
+	}{
+		{"20180305", time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"2018-03-05", time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"20180305;153000", time.Date(2018, 3, 5, 15, 30, 0, 0, time.UTC)},
+		{"2018-03-05, 15:30:00", time.Date(2018, 3, 5, 15, 30, 0, 0, time.UTC)},
+		{"2018-03-05 15:30:00", time.Date(2018, 3, 5, 15, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		d, err := parseDateTime(tt.dt)
+		if assert.NoError(t, err, tt.dt) {
+			assert.Equal(t, tt.date, d, tt.dt)
+		}
+	}
+}
+
+func TestReportExclude(t *testing.T) {
+	tests := []struct {
+		name       string
+		imported   map[string]bool
+		ids        []string
+		retentions int
+	}{
+		{
+			name:       "nothing imported",
+			ids:        []string{"T2", "T1", "C3", "C4", "C1", "C2", "A1", "C5"},
+			retentions: 1,
+		},
+		{
+			name:       "trade and transfer imported",
+			imported:   map[string]bool{"T1": true, "C5": true},
+			ids:        []string{"T2", "C3", "C4", "C1", "C2", "A1"},
+			retentions: 1,
+		},
+		{
+			name:     "tax withheld imported",
+			imported: map[string]bool{"C2": true},
+			ids:      []string{"T2", "T1", "C3", "C4", "A1", "C5"},
+		},
+		{
+			name:       "other report",
+			imported:   map[string]bool{"U1": true},
+			ids:        []string{"T2", "T1", "C3", "C4", "C1", "C2", "A1", "C5"},
+			retentions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		r := parseSample(t)
+		r.Exclude(tt.imported)
+
+		assert.Equal(t, tt.ids, r.TransactionIDs(), tt.name)
+		assert.Len(t, r.Retentions, tt.retentions, tt.name)
+	}
+}
@@ -0,0 +1,139 @@
+package ib
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+)
+
+// addTrade adds the buy or sell of the trade. The currency conversions (CASH) are not operations, the value
+// of the trades is already in euro. Only stocks, ETFs included, and bonds are supported
+func (r *Report) addTrade(t flexTrade, hasExecutions bool) error {
+	switch t.LevelOfDetail {
+	case "", "EXECUTION":
+	case "ORDER":
+		if hasExecutions {
+			return nil
+		}
+	default:
+		// closed lots and summaries
+		return nil
+	}
+
+	switch t.AssetCategory {
+	case "STK", "BOND":
+	case "CASH":
+		return nil
+	default:
+		r.Unsupported = append(r.Unsupported, fmt.Sprintf("trade %s %s %s: %s", t.TransactionID, t.AssetCategory, t.Symbol, t.Description))
+
+		return nil
+	}
+
+	dt := t.DateTime
+	if dt == "" {
+		dt = t.TradeDate
+	}
+
+	date, err := parseDateTime(dt)
+	if err != nil {
+		return err
+	}
+
+	ns, err := parseNumbers(t.Quantity, t.TradePrice, t.TradeMoney, t.IBCommission, t.FXRateToBase)
+	if err != nil {
+		return err
+	}
+
+	quantity, price, money, commission, fx := ns[0], ns[1], ns[2], ns[3], ns[4]
+
+	action := operation.Buy
+	if strings.HasPrefix(t.BuySell, "SELL") || t.BuySell == "" && quantity < 0 {
+		action = operation.Sell
+	}
+
+	priceValue := mm.Value{Amount: price}
+	if c, ok := mm.CurrencyFromCode(t.Currency); ok {
+		priceValue.Currency = c
+	}
+
+	// the commission is charged either in the currency of the trade or in the base one
+	commissionCurrency := t.IBCommissionCurrency
+	if commissionCurrency == "" {
+		commissionCurrency = t.Currency
+	}
+
+	rate, err := rateToEuro(t.Currency, fx)
+	if err != nil {
+		return err
+	}
+
+	value, err := toEuro(math.Abs(money), t.Currency, fx)
+	if err != nil {
+		return err
+	}
+
+	commissionValue, err := toEuro(math.Abs(commission), commissionCurrency, fx)
+	if err != nil {
+		return errors.Wrap(err, "commission")
+	}
+
+	o := operation.NewOperation(
+		date,
+		newStock(t.Description, t.Symbol, t.ISIN),
+		action,
+		math.Abs(quantity),
+		priceValue,
+		mm.Value{Amount: rate},
+		mm.Value{Currency: mm.Euro},
+		value,
+		commissionValue,
+	)
+
+	r.Entries = append(r.Entries, &Entry{TransactionIDs: []string{t.TransactionID}, Operation: o})
+
+	return nil
+}
+
+// addCorporateAction adds the stocks received for free, as a stock dividend, a split or a spin-off, as a buy
+// without value. The rest of corporate actions are not supported, they have to be added by hand
+func (r *Report) addCorporateAction(ca flexCorporateAction) error {
+	ns, err := parseNumbers(ca.Quantity, ca.Proceeds)
+	if err != nil {
+		return err
+	}
+
+	quantity, proceeds := ns[0], ns[1]
+
+	if quantity <= 0 || proceeds != 0 {
+		r.Unsupported = append(r.Unsupported, fmt.Sprintf("corporate action %s %s %s: %s", ca.TransactionID, ca.Type, ca.Symbol, ca.Description))
+
+		return nil
+	}
+
+	date, err := parseDateTime(ca.DateTime)
+	if err != nil {
+		return err
+	}
+
+	o := operation.NewOperation(
+		date,
+		newStock(ca.Description, ca.Symbol, ca.ISIN),
+		operation.Buy,
+		quantity,
+		mm.Value{},
+		mm.Value{},
+		mm.Value{Currency: mm.Euro},
+		mm.Value{Currency: mm.Euro},
+		mm.Value{Currency: mm.Euro},
+	)
+
+	r.Entries = append(r.Entries, &Entry{TransactionIDs: []string{ca.TransactionID}, Operation: o})
+
+	return nil
+}
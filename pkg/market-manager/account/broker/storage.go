@@ -0,0 +1,15 @@
+package broker
+
+import uuid "github.com/satori/go.uuid"
+
+type (
+	Finder interface {
+		FindByName(name string) (*Broker, error)
+		FindByID(ID uuid.UUID) (*Broker, error)
+		FindAll() ([]*Broker, error)
+	}
+
+	Persister interface {
+		Persist(b *Broker) error
+	}
+)
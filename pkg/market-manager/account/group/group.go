@@ -0,0 +1,184 @@
+package group
+
+import (
+	"sort"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/wallet"
+)
+
+// Group represents a named set of wallets reported together, e.g. the wallets of a household
+type Group struct {
+	ID      uuid.UUID
+	Name    string
+	Wallets map[uuid.UUID]*wallet.Wallet
+}
+
+func NewGroup(name string) *Group {
+	return &Group{
+		ID:      uuid.NewV4(),
+		Name:    name,
+		Wallets: map[uuid.UUID]*wallet.Wallet{},
+	}
+}
+
+// AddWallet adds the wallet to the group, returns false when the wallet was already in
+func (g *Group) AddWallet(w *wallet.Wallet) bool {
+	if _, ok := g.Wallets[w.ID]; ok {
+		return false
+	}
+
+	g.Wallets[w.ID] = w
+
+	return true
+}
+
+// RemoveWallet removes the wallet from the group, returns false when the wallet was not in
+func (g *Group) RemoveWallet(w *wallet.Wallet) bool {
+	if _, ok := g.Wallets[w.ID]; !ok {
+		return false
+	}
+
+	delete(g.Wallets, w.ID)
+
+	return true
+}
+
+// Consolidate builds a wallet named after the group summing up the figures of its wallets. The items holding the
+// same stock in several wallets are merged into one. The trades keep the number they have in their own wallet.
+//
+// The wallets should be loaded with their items and capital rate before consolidating them.
+func (g *Group) Consolidate() *wallet.Wallet {
+	c := wallet.NewWallet(g.Name, "")
+	c.ID = g.ID
+	c.Invested = mm.Value{Currency: mm.Euro}
+	c.Capital = mm.Value{Currency: mm.Euro}
+	c.Funds = mm.Value{Currency: mm.Euro}
+	c.Dividend = mm.Value{Currency: mm.Euro}
+	c.Commission = mm.Value{Currency: mm.Euro}
+	c.Connection = mm.Value{Currency: mm.Euro}
+	c.Interest = mm.Value{Currency: mm.Euro}
+
+	ws := g.sortedWallets()
+	if len(ws) == 0 {
+		return c
+	}
+
+	// the broker is kept only when all the wallets are hold by the same one, its retention and commissions apply then
+	c.Broker = ws[0].Broker
+
+	// stocks merged whose every item has its own dividend retention
+	retained := map[uuid.UUID]bool{}
+
+	for _, w := range ws {
+		if c.Broker != nil && (w.Broker == nil || w.Broker.ID != c.Broker.ID) {
+			c.Broker = nil
+		}
+
+		c.Invested = c.Invested.Increase(w.Invested)
+		c.Capital = c.Capital.Increase(w.Capital)
+		c.Funds = c.Funds.Increase(w.Funds)
+		c.Dividend = c.Dividend.Increase(w.Dividend)
+		c.Commission = c.Commission.Increase(w.Commission)
+		c.Connection = c.Connection.Increase(w.Connection)
+		c.Interest = c.Interest.Increase(w.Interest)
+		c.Operations = append(c.Operations, w.Operations...)
+		c.Ledger = append(c.Ledger, w.Ledger...)
+
+		for id, ba := range w.BankAccounts {
+			c.BankAccounts[id] = ba
+		}
+
+		for _, t := range sortedTrades(w.Trades) {
+			c.LastTradeNumber++
+			c.Trades[c.LastTradeNumber] = t
+		}
+
+		for _, i := range w.Items {
+			ci, ok := c.Items[i.Stock.ID]
+			if !ok {
+				ci = newConsolidatedItem(i)
+				c.Items[i.Stock.ID] = ci
+				retained[i.Stock.ID] = true
+			}
+
+			mergeItem(ci, i)
+
+			if i.DividendRetention.Amount == 0 {
+				retained[i.Stock.ID] = false
+			}
+		}
+	}
+
+	for id, ci := range c.Items {
+		if !retained[id] {
+			ci.DividendRetention.Amount = 0
+		}
+	}
+
+	c.SetCapitalRate(ws[0].CurrentCapitalRate())
+
+	return c
+}
+
+func newConsolidatedItem(i *wallet.Item) *wallet.Item {
+	ci := wallet.NewItem(i.Stock)
+	ci.Invested.Currency = i.Invested.Currency
+	ci.Dividend.Currency = i.Dividend.Currency
+	ci.Buys.Currency = i.Buys.Currency
+	ci.Sells.Currency = i.Sells.Currency
+	ci.DividendRetention.Currency = i.DividendRetention.Currency
+
+	return ci
+}
+
+// mergeItem adds the figures of the item into the consolidated one. The dividend retention per stock is
+// averaged by the amount of stocks each item holds.
+func mergeItem(ci, i *wallet.Item) {
+	if amount := ci.Amount + i.Amount; amount > 0 {
+		ci.DividendRetention.Amount = (ci.DividendRetention.Amount*ci.Amount + i.DividendRetention.Amount*i.Amount) / amount
+	}
+
+	ci.Amount += i.Amount
+	ci.Invested = ci.Invested.Increase(i.Invested)
+	ci.Dividend = ci.Dividend.Increase(i.Dividend)
+	ci.Buys = ci.Buys.Increase(i.Buys)
+	ci.Sells = ci.Sells.Increase(i.Sells)
+	ci.Operations = append(ci.Operations, i.Operations...)
+
+	for _, t := range sortedTrades(i.Trades) {
+		ci.Trades[len(ci.Trades)+1] = t
+	}
+}
+
+func (g *Group) sortedWallets() []*wallet.Wallet {
+	var ws []*wallet.Wallet
+	for _, w := range g.Wallets {
+		ws = append(ws, w)
+	}
+
+	sort.Slice(ws, func(i, j int) bool {
+		return ws[i].Name < ws[j].Name
+	})
+
+	return ws
+}
+
+func sortedTrades(trades map[int]*trade.Trade) []*trade.Trade {
+	var numbers []int
+	for n := range trades {
+		numbers = append(numbers, n)
+	}
+
+	sort.Ints(numbers)
+
+	var ts []*trade.Trade
+	for _, n := range numbers {
+		ts = append(ts, trades[n])
+	}
+
+	return ts
+}
@@ -0,0 +1,13 @@
+package group
+
+type (
+	Finder interface {
+		FindByName(name string) (*Group, error)
+		FindAll() ([]*Group, error)
+	}
+
+	Persister interface {
+		Persist(g *Group) error
+		Delete(g *Group) error
+	}
+)
@@ -0,0 +1,111 @@
+package ledger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+)
+
+type (
+	Kind string
+
+	// Entry represents a cash movement of a wallet
+	Entry struct {
+		ID          uuid.UUID
+		Date        time.Time
+		Kind        Kind
+		Description string
+		// Amount is positive when the cash of the wallet increases, negative otherwise
+		Amount mm.Value
+		// Balance is the cash of the wallet after applying the entry
+		Balance mm.Value
+
+		OperationID uuid.UUID
+		TransferID  uuid.UUID
+	}
+)
+
+const (
+	Transfer   Kind = "transfer"
+	Buy        Kind = "buy"
+	Sell       Kind = "sell"
+	Dividend   Kind = "dividend"
+	Fee        Kind = "fee"
+	Interest   Kind = "interest"
+	Coupon     Kind = "coupon"
+	Redemption Kind = "redemption"
+)
+
+func NewEntry(date time.Time, kind Kind, description string, amount mm.Value) *Entry {
+	return &Entry{
+		ID:          uuid.NewV4(),
+		Date:        date,
+		Kind:        kind,
+		Description: description,
+		Amount:      amount,
+	}
+}
+
+// NewEntriesFromOperation returns the entries that the operation books in the ledger.
+// Buys and sells book the commissions as a separate fee entry.
+func NewEntriesFromOperation(o *operation.Operation) []*Entry {
+	var es []*Entry
+
+	switch o.Action {
+	case operation.Buy:
+		es = append(es, NewEntry(o.Date, Buy, o.Stock.Name, negative(o.Value)))
+	case operation.Sell:
+		es = append(es, NewEntry(o.Date, Sell, o.Stock.Name, o.Value))
+	case operation.Dividend:
+		es = append(es, NewEntry(o.Date, Dividend, o.Stock.Name, o.Value))
+	case operation.Coupon:
+		es = append(es, NewEntry(o.Date, Coupon, o.Stock.Name, o.Value))
+	case operation.Redemption:
+		es = append(es, NewEntry(o.Date, Redemption, o.Stock.Name, o.Value))
+	case operation.Interest:
+		es = append(es, NewEntry(o.Date, Interest, "Interest", negative(o.Value)))
+	case operation.Connectivity:
+		es = append(es, NewEntry(o.Date, Fee, "Connectivity", negative(o.Value)))
+	}
+
+	if o.Action == operation.Buy || o.Action.IsDisposal() {
+		if fc := o.FinalCommission(); fc.Amount != 0 {
+			es = append(es, NewEntry(o.Date, Fee, fmt.Sprintf("Commission %s", o.Stock.Name), negative(fc)))
+		}
+	}
+
+	for _, e := range es {
+		e.OperationID = o.ID
+	}
+
+	return es
+}
+
+// NewEntryFromTransfer returns the entry that the transfer books in the ledger.
+// in tells whether the money comes into the wallet or goes out.
+func NewEntryFromTransfer(t *transfer.Transfer, in bool) *Entry {
+	amount := t.Received()
+	description := fmt.Sprintf("From %s", t.From.Alias)
+
+	if !in {
+		amount = negative(t.Sent())
+		description = fmt.Sprintf("To %s", t.To.Alias)
+	}
+
+	e := NewEntry(t.Date, Transfer, description, amount)
+	e.TransferID = t.ID
+
+	return e
+}
+
+func negative(v mm.Value) mm.Value {
+	return mm.Value{
+		Amount:   v.Amount * -1,
+		Currency: v.Currency,
+	}
+}
@@ -0,0 +1,166 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+func TestNewEntriesFromOperation(t *testing.T) {
+	date := time.Date(2018, 3, 12, 0, 0, 0, 0, time.UTC)
+	stk := &stock.Stock{ID: uuid.NewV4(), Name: "ENAGAS"}
+	opID := uuid.NewV4()
+
+	tests := []struct {
+		name    string
+		o       *operation.Operation
+		entries []*Entry
+	}{
+		{
+			name: "buy with commissions",
+			o: &operation.Operation{
+				Action:                operation.Buy,
+				Value:                 mm.Value{Amount: 225, Currency: mm.Euro},
+				PriceChangeCommission: mm.Value{Amount: 0.5, Currency: mm.Euro},
+				Commission:            mm.Value{Amount: 2, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Buy, Description: "ENAGAS", Amount: mm.Value{Amount: -225, Currency: mm.Euro}, OperationID: opID},
+				{Date: date, Kind: Fee, Description: "Commission ENAGAS", Amount: mm.Value{Amount: -2.5, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "sell without commissions",
+			o: &operation.Operation{
+				Action: operation.Sell,
+				Value:  mm.Value{Amount: 225, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Sell, Description: "ENAGAS", Amount: mm.Value{Amount: 225, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "redemption with commission",
+			o: &operation.Operation{
+				Action:     operation.Redemption,
+				Value:      mm.Value{Amount: 1000, Currency: mm.Euro},
+				Commission: mm.Value{Amount: 1, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Redemption, Description: "ENAGAS", Amount: mm.Value{Amount: 1000, Currency: mm.Euro}, OperationID: opID},
+				{Date: date, Kind: Fee, Description: "Commission ENAGAS", Amount: mm.Value{Amount: -1, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "dividend",
+			o: &operation.Operation{
+				Action: operation.Dividend,
+				Value:  mm.Value{Amount: 3.6, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Dividend, Description: "ENAGAS", Amount: mm.Value{Amount: 3.6, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "coupon",
+			o: &operation.Operation{
+				Action: operation.Coupon,
+				Value:  mm.Value{Amount: 25, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Coupon, Description: "ENAGAS", Amount: mm.Value{Amount: 25, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "interest",
+			o: &operation.Operation{
+				Action: operation.Interest,
+				Value:  mm.Value{Amount: 0.1, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Interest, Description: "Interest", Amount: mm.Value{Amount: -0.1, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+		{
+			name: "connectivity",
+			o: &operation.Operation{
+				Action: operation.Connectivity,
+				Value:  mm.Value{Amount: 2.5, Currency: mm.Euro},
+			},
+			entries: []*Entry{
+				{Date: date, Kind: Fee, Description: "Connectivity", Amount: mm.Value{Amount: -2.5, Currency: mm.Euro}, OperationID: opID},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.o.ID = opID
+		tt.o.Date = date
+		tt.o.Stock = stk
+
+		es := NewEntriesFromOperation(tt.o)
+		// the ids are generated on creation
+		for _, e := range es {
+			e.ID = uuid.Nil
+		}
+
+		assert.Equal(t, tt.entries, es, tt.name)
+	}
+}
+
+func TestNewEntryFromTransfer(t *testing.T) {
+	date := time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC)
+	from := &bank.Account{ID: uuid.NewV4(), Alias: "bank"}
+	to := &bank.Account{ID: uuid.NewV4(), Alias: "broker"}
+	trID := uuid.NewV4()
+
+	tests := []struct {
+		name  string
+		t     *transfer.Transfer
+		in    bool
+		entry *Entry
+	}{
+		{
+			name:  "in",
+			t:     &transfer.Transfer{Amount: mm.Value{Amount: 1000, Currency: mm.Euro}},
+			in:    true,
+			entry: &Entry{Date: date, Kind: Transfer, Description: "From bank", Amount: mm.Value{Amount: 1000, Currency: mm.Euro}, TransferID: trID},
+		},
+		{
+			name:  "out",
+			t:     &transfer.Transfer{Amount: mm.Value{Amount: 1000, Currency: mm.Euro}},
+			entry: &Entry{Date: date, Kind: Transfer, Description: "To broker", Amount: mm.Value{Amount: -1000, Currency: mm.Euro}, TransferID: trID},
+		},
+		{
+			name:  "in changed into euro, fee charged",
+			t:     &transfer.Transfer{Amount: mm.Value{Amount: 1250, Currency: mm.Dollar}, Rate: 1.25, Fee: mm.Value{Amount: 25, Currency: mm.Dollar}},
+			in:    true,
+			entry: &Entry{Date: date, Kind: Transfer, Description: "From bank", Amount: mm.Value{Amount: 980, Currency: mm.Euro}, TransferID: trID},
+		},
+		{
+			name:  "out changed into euro, fee included",
+			t:     &transfer.Transfer{Amount: mm.Value{Amount: 1250, Currency: mm.Dollar}, Rate: 1.25, Fee: mm.Value{Amount: 25, Currency: mm.Dollar}},
+			entry: &Entry{Date: date, Kind: Transfer, Description: "To broker", Amount: mm.Value{Amount: -1000, Currency: mm.Euro}, TransferID: trID},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.t.ID = trID
+		tt.t.From = from
+		tt.t.To = to
+		tt.t.Date = date
+
+		e := NewEntryFromTransfer(tt.t, tt.in)
+		e.ID = uuid.Nil
+
+		assert.Equal(t, tt.entry, e, tt.name)
+	}
+}
@@ -0,0 +1,13 @@
+package ledger
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type (
+	Finder interface {
+		FindAllByWalletAndDateRange(walletID uuid.UUID, from, to time.Time) ([]*Entry, error)
+	}
+)
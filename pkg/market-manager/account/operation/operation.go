@@ -19,7 +19,7 @@ type (
 		Date   time.Time
 		Stock  *stock.Stock
 		Action Action
-		Amount int
+		Amount float64
 		// Price in dollar
 		Price                 mm.Value
 		PriceChange           mm.Value
@@ -35,6 +35,8 @@ const (
 	Connectivity Action = "connectivity"
 	Dividend     Action = "dividend"
 	Interest     Action = "interest"
+	Coupon       Action = "coupon"
+	Redemption   Action = "redemption"
 
 	Active   Status = "open"
 	Inactive Status = "close"
@@ -45,7 +47,7 @@ func NewOperation(
 	date time.Time,
 	stock *stock.Stock,
 	action Action,
-	amount int,
+	amount float64,
 	price,
 	priceChange,
 	priceChangeCommission,
@@ -66,6 +68,16 @@ func NewOperation(
 	}
 }
 
+// IsIncome tells whether the action is an income paid by the stock held, a dividend or a bond coupon
+func (a Action) IsIncome() bool {
+	return a == Dividend || a == Coupon
+}
+
+// IsDisposal tells whether the action takes the stocks out of the wallet, a sell or a bond redemption
+func (a Action) IsDisposal() bool {
+	return a == Sell || a == Redemption
+}
+
 func (o *Operation) Capital() mm.Value {
 	if o.Stock.ID == uuid.Nil {
 		return mm.Value{}
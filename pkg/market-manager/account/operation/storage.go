@@ -0,0 +1,12 @@
+package operation
+
+import uuid "github.com/satori/go.uuid"
+
+type (
+	Finder interface {
+		FindByID(walletID, ID uuid.UUID) (*Operation, error)
+		FindAllByWallet(walletID uuid.UUID) ([]*Operation, error)
+		FindAllByWalletAndStock(walletID, stockID uuid.UUID) ([]*Operation, error)
+		FindTradeNumbersByWallet(walletID uuid.UUID) (map[uuid.UUID]int, error)
+	}
+)
@@ -0,0 +1,28 @@
+package trade
+
+import (
+	"strings"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+// Journal holds why the trade was entered and how it is planned to be exited
+type Journal struct {
+	Note string
+	// Tags name the strategies followed by the trade
+	Tags []string
+	// TargetPrice and StopLoss are the planned exit prices, in the stock exchange currency
+	TargetPrice mm.Value
+	StopLoss    mm.Value
+}
+
+// HasTag tells whether the journal is tagged with the tag, case insensitive
+func (j Journal) HasTag(tag string) bool {
+	for _, t := range j.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package trade
+
+import (
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+// Stats summarises the performance of closed trades
+type Stats struct {
+	Trades int
+	Wins   int
+	Losses int
+
+	// WinRate percentage of trades closed with benefits
+	WinRate float64
+
+	AverageGain mm.Value
+	AverageLoss mm.Value
+	// AverageHoldingDays days between the trade was opened and closed
+	AverageHoldingDays float64
+	// Expectancy net expected per trade: win rate * average gain + loss rate * average loss
+	Expectancy mm.Value
+
+	Best  *Trade
+	Worst *Trade
+}
+
+// NewStats calculates the statistics of the trades. Trades not closed are ignored.
+func NewStats(ts []*Trade) Stats {
+	s := Stats{
+		AverageGain: mm.Value{Currency: mm.Euro},
+		AverageLoss: mm.Value{Currency: mm.Euro},
+		Expectancy:  mm.Value{Currency: mm.Euro},
+	}
+
+	var holdingDays float64
+
+	for _, t := range ts {
+		if t.Status != Close {
+			continue
+		}
+
+		s.Trades++
+
+		net := t.CloseNet
+		if net.Amount > 0 {
+			s.Wins++
+			s.AverageGain = s.AverageGain.Increase(net)
+		} else if net.Amount < 0 {
+			s.Losses++
+			s.AverageLoss = s.AverageLoss.Increase(net)
+		}
+
+		holdingDays += t.ClosedAt.Sub(t.OpenedAt).Hours() / 24
+
+		if s.Best == nil || net.Amount > s.Best.CloseNet.Amount {
+			s.Best = t
+		}
+
+		if s.Worst == nil || net.Amount < s.Worst.CloseNet.Amount {
+			s.Worst = t
+		}
+	}
+
+	if s.Trades == 0 {
+		return s
+	}
+
+	if s.Wins > 0 {
+		s.AverageGain.Amount = s.AverageGain.Amount / float64(s.Wins)
+	}
+
+	if s.Losses > 0 {
+		s.AverageLoss.Amount = s.AverageLoss.Amount / float64(s.Losses)
+	}
+
+	winRate := float64(s.Wins) / float64(s.Trades)
+	lossRate := float64(s.Losses) / float64(s.Trades)
+
+	s.WinRate = winRate * 100
+	s.AverageHoldingDays = holdingDays / float64(s.Trades)
+	s.Expectancy.Amount = winRate*s.AverageGain.Amount + lossRate*s.AverageLoss.Amount
+
+	return s
+}
@@ -0,0 +1,18 @@
+package trade
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+type (
+	Finder interface {
+		FindByNumber(walletID uuid.UUID, number int) (*Trade, error)
+		FindAllClosedByWallet(walletID uuid.UUID, from, to time.Time) ([]*Trade, error)
+	}
+
+	Persister interface {
+		PersistJournal(walletID uuid.UUID, t *Trade) error
+	}
+)
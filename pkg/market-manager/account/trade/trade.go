@@ -38,6 +38,8 @@ type (
 
 		// Rate currency conversion
 		CapitalRate float64
+
+		Journal Journal
 	}
 )
 
@@ -104,8 +106,8 @@ func (t *Trade) Sold(op *operation.Operation) {
 
 	t.Sells = t.Sells.Increase(op.FinalPricePaid())
 
-	t.SellAmount += float64(op.Amount)
-	t.Amount = t.BuyAmount - t.SellAmount
+	t.SellAmount = mm.RoundAmount(t.SellAmount + float64(op.Amount))
+	t.Amount = mm.RoundAmount(t.BuyAmount - t.SellAmount)
 
 	if t.Amount == 0 {
 		t.closeTrade(op.Date)
@@ -117,15 +119,15 @@ func (t *Trade) Bought(op *operation.Operation) {
 
 	t.Buys = t.Buys.Increase(op.FinalPricePaid())
 
-	t.BuyAmount += float64(op.Amount)
-	t.Amount = t.BuyAmount - t.SellAmount
+	t.BuyAmount = mm.RoundAmount(t.BuyAmount + float64(op.Amount))
+	t.Amount = mm.RoundAmount(t.BuyAmount - t.SellAmount)
 }
 
 func (t *Trade) Close(op *operation.Operation) {
 	t.Operations = append(t.Operations, op)
 
 	t.Sells = t.Sells.Increase(op.FinalPricePaid())
-	t.SellAmount += float64(op.Amount)
+	t.SellAmount = mm.RoundAmount(t.SellAmount + float64(op.Amount))
 	t.Amount = 0
 
 	t.closeTrade(op.Date)
@@ -143,19 +145,55 @@ func (t *Trade) closeTrade(closeAt time.Time) {
 
 func (t *Trade) PayedDividend(d mm.Value) {
 	t.Dividend = t.Dividend.Increase(d)
+
+	// dividends with ex-date before the trade was closed can be paid after
+	if t.Status == Close {
+		t.CloseNet = t.CloseNet.Increase(d)
+	}
+}
+
+// AmountAt returns the amount of stocks the trade held at the beginning of the date
+func (t *Trade) AmountAt(date time.Time) float64 {
+	var amount float64
+
+	for _, o := range t.Operations {
+		if !o.Date.Before(date) {
+			continue
+		}
+
+		switch o.Action {
+		case operation.Buy:
+			amount += float64(o.Amount)
+		case operation.Sell, operation.Redemption:
+			amount -= float64(o.Amount)
+		}
+	}
+
+	return mm.RoundAmount(amount)
+}
+
+// HasOperation tells whether the operation belongs to the trade
+func (t *Trade) HasOperation(ID uuid.UUID) bool {
+	for _, o := range t.Operations {
+		if o.ID == ID {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (t *Trade) WeightedAverageBuyPrice() mm.Value {
 	return t.weightedAveragePrice(operation.Buy)
 }
 
-func (t *Trade) weightedAveragePrice(action operation.Action) mm.Value {
+func (t *Trade) weightedAveragePrice(actions ...operation.Action) mm.Value {
 	var asPrice float64
 
-	eSymbol := t.Stock.Exchange.Symbol
+	currency := t.Stock.QuoteCurrency()
 
 	for _, o := range t.Operations {
-		if o.Action != action {
+		if !hasAction(o, actions) {
 			continue
 		}
 
@@ -163,7 +201,7 @@ func (t *Trade) weightedAveragePrice(action operation.Action) mm.Value {
 
 		sPrice := o.Price.Amount * float64(o.Amount)
 
-		if mm.ExchangeCurrency(eSymbol) == mm.Dollar {
+		if currency == mm.Dollar {
 			sPrice = sPrice + commissions.Amount*o.PriceChange.Amount
 		} else {
 			sPrice = sPrice + commissions.Amount
@@ -173,7 +211,7 @@ func (t *Trade) weightedAveragePrice(action operation.Action) mm.Value {
 	}
 
 	wAPrice := mm.Value{
-		Currency: mm.ExchangeCurrency(eSymbol),
+		Currency: currency,
 	}
 
 	if t.BuyAmount > 0 {
@@ -184,5 +222,15 @@ func (t *Trade) weightedAveragePrice(action operation.Action) mm.Value {
 }
 
 func (t *Trade) WeightedAverageSellPrice() mm.Value {
-	return t.weightedAveragePrice(operation.Sell)
+	return t.weightedAveragePrice(operation.Sell, operation.Redemption)
+}
+
+func hasAction(o *operation.Operation, actions []operation.Action) bool {
+	for _, a := range actions {
+		if o.Action == a {
+			return true
+		}
+	}
+
+	return false
 }
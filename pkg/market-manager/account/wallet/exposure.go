@@ -0,0 +1,90 @@
+package wallet
+
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/etf"
+)
+
+// ExposureUnknown groups the capital which sector or country is not known
+const ExposureUnknown = "UNKNOWN"
+
+// Exposure represents the capital of the wallet grouped by sector, country and stock
+type Exposure struct {
+	Capital   mm.Value
+	Sectors   map[string]mm.Value
+	Countries map[string]mm.Value
+	Stocks    map[string]mm.Value
+}
+
+func newExposure() *Exposure {
+	return &Exposure{
+		Capital:   mm.Value{Currency: mm.Euro},
+		Sectors:   map[string]mm.Value{},
+		Countries: map[string]mm.Value{},
+		Stocks:    map[string]mm.Value{},
+	}
+}
+
+func (e *Exposure) add(sector, country, stk string, capital mm.Value) {
+	if sector == "" {
+		sector = ExposureUnknown
+	}
+
+	if country == "" {
+		country = ExposureUnknown
+	}
+
+	e.Capital = e.Capital.Increase(capital)
+
+	increaseExposure(e.Sectors, sector, capital)
+	increaseExposure(e.Countries, country, capital)
+	increaseExposure(e.Stocks, stk, capital)
+}
+
+func increaseExposure(group map[string]mm.Value, key string, capital mm.Value) {
+	v, ok := group[key]
+	if !ok {
+		v = mm.Value{Currency: capital.Currency}
+	}
+
+	group[key] = v.Increase(capital)
+}
+
+// Exposure decomposes the capital of the wallet items into sectors, countries and stocks.
+// The items of an ETF with holdings are looked through, their capital is spread over the
+// constituents based on their weights.
+func (w *Wallet) Exposure(holdings map[uuid.UUID]*etf.Holdings) *Exposure {
+	e := newExposure()
+
+	for _, i := range w.Items {
+		if i.Amount <= 0 {
+			continue
+		}
+
+		capital := i.Capital()
+
+		h, ok := holdings[i.Stock.ID]
+		if !ok || h.TotalWeight() <= 0 {
+			var sector string
+			if i.Stock.Sector != nil {
+				sector = i.Stock.Sector.Name
+			}
+
+			e.add(sector, i.Stock.Country(), i.Stock.Name, capital)
+
+			continue
+		}
+
+		total := h.TotalWeight()
+		for _, c := range h.Constituents {
+			e.add(c.SectorName(), c.CountryName(), c.StockName(), mm.Value{
+				Amount:   capital.Amount * c.Weight / total,
+				Currency: capital.Currency,
+			})
+		}
+	}
+
+	return e
+}
@@ -0,0 +1,208 @@
+package wallet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+)
+
+// Difference is a value of the stored wallet that does not match the value replayed from the transfers and operations
+type Difference struct {
+	// Scope is wallet, item or trade
+	Scope   string
+	Name    string
+	Field   string
+	Stored  string
+	Rebuilt string
+}
+
+// Rebuild replays the transfers and the operations, in date order, into a new wallet with the identity, broker and
+// bank accounts of the wallet. Transfers from any of the wallet bank accounts are booked out, the rest in.
+// Trade numbers are looked up by operation ID. The wallet items and trades keep the ID of the stored ones.
+func Rebuild(w *Wallet, ts []*transfer.Transfer, ops []*operation.Operation, trades map[uuid.UUID]int) (*Wallet, error) {
+	rw := NewWallet(w.Name, w.URL)
+	rw.ID = w.ID
+	rw.Broker = w.Broker
+	rw.BankAccounts = w.BankAccounts
+
+	var i int
+	for _, o := range ops {
+		for ; i < len(ts) && !ts[i].Date.After(o.Date); i++ {
+			if err := rw.transfer(ts[i]); err != nil {
+				return nil, errors.Wrapf(err, "Replaying transfer %q at %s", ts[i].ID, ts[i].Date.Format("2/1/2006"))
+			}
+		}
+
+		if err := rw.AddOperation(o); err != nil {
+			return nil, errors.Wrapf(err, "Replaying operation %q %s at %s", o.ID, o.Action, o.Date.Format("2/1/2006"))
+		}
+
+		if n, ok := trades[o.ID]; ok {
+			if err := rw.AddTrade(n, o); err != nil {
+				return nil, errors.Wrapf(err, "Replaying trade of operation %q", o.ID)
+			}
+		} else if o.Action.IsIncome() {
+			rw.AddTrade(0, o)
+		}
+	}
+
+	for ; i < len(ts); i++ {
+		if err := rw.transfer(ts[i]); err != nil {
+			return nil, errors.Wrapf(err, "Replaying transfer %q at %s", ts[i].ID, ts[i].Date.Format("2/1/2006"))
+		}
+	}
+
+	for stkID, item := range rw.Items {
+		if item.Amount < 0 {
+			return nil, errors.Errorf("Replaying operations on stock %s sells %v stocks more than bought", item.Stock.Symbol, -item.Amount)
+		}
+
+		if old, ok := w.Items[stkID]; ok {
+			item.ID = old.ID
+			item.DividendRetention = old.DividendRetention
+		}
+	}
+
+	for n, t := range rw.Trades {
+		if old, ok := w.Trades[n]; ok && old.Stock.ID == t.Stock.ID {
+			t.ID = old.ID
+		}
+	}
+
+	rw.SetCapitalRate(w.capitalRate)
+
+	// The wallet capital is the sum of the items capital as it is done when it is stored
+	rw.Capital = mm.Value{Currency: mm.Euro}
+	for _, item := range rw.Items {
+		rw.Capital = rw.Capital.Increase(item.Capital())
+	}
+
+	return rw, nil
+}
+
+func (w *Wallet) transfer(t *transfer.Transfer) error {
+	if _, ok := w.BankAccounts[t.From.ID]; ok {
+		return w.TransferOut(t)
+	}
+
+	return w.TransferIn(t)
+}
+
+// Diff compares the stored wallet with the rebuilt one, along with their items and trades.
+// Values are compared with the precision they are stored with.
+func (w *Wallet) Diff(rw *Wallet) []Difference {
+	var ds []Difference
+
+	diffValue := func(scope, name, field string, stored, rebuilt float64) {
+		if math.Abs(stored-rebuilt) < 0.005 {
+			return
+		}
+
+		ds = append(ds, Difference{
+			Scope:   scope,
+			Name:    name,
+			Field:   field,
+			Stored:  fmt.Sprintf("%.2f", stored),
+			Rebuilt: fmt.Sprintf("%.2f", rebuilt),
+		})
+	}
+
+	diffValue("wallet", w.Name, "invested", w.Invested.Amount, rw.Invested.Amount)
+	diffValue("wallet", w.Name, "funds", w.Funds.Amount, rw.Funds.Amount)
+	diffValue("wallet", w.Name, "capital", w.Capital.Amount, rw.Capital.Amount)
+	diffValue("wallet", w.Name, "dividend", w.Dividend.Amount, rw.Dividend.Amount)
+	diffValue("wallet", w.Name, "commission", w.Commission.Amount, rw.Commission.Amount)
+	diffValue("wallet", w.Name, "connection", w.Connection.Amount, rw.Connection.Amount)
+	diffValue("wallet", w.Name, "interest", w.Interest.Amount, rw.Interest.Amount)
+
+	items := map[uuid.UUID]*Item{}
+	for stkID, item := range w.Items {
+		items[stkID] = item
+	}
+	for stkID, item := range rw.Items {
+		items[stkID] = item
+	}
+
+	var itemNames []string
+	itemIDs := map[string]uuid.UUID{}
+	for stkID, item := range items {
+		itemNames = append(itemNames, item.Stock.Symbol)
+		itemIDs[item.Stock.Symbol] = stkID
+	}
+
+	sort.Strings(itemNames)
+
+	for _, name := range itemNames {
+		stkID := itemIDs[name]
+
+		stored, ok := w.Items[stkID]
+		if !ok {
+			stored = NewItem(items[stkID].Stock)
+		}
+
+		rebuilt, ok := rw.Items[stkID]
+		if !ok {
+			rebuilt = NewItem(items[stkID].Stock)
+		}
+
+		diffValue("item", name, "amount", float64(stored.Amount), float64(rebuilt.Amount))
+		diffValue("item", name, "invested", stored.Invested.Amount, rebuilt.Invested.Amount)
+		diffValue("item", name, "dividend", stored.Dividend.Amount, rebuilt.Dividend.Amount)
+		diffValue("item", name, "buys", stored.Buys.Amount, rebuilt.Buys.Amount)
+		diffValue("item", name, "sells", stored.Sells.Amount, rebuilt.Sells.Amount)
+	}
+
+	numbers := map[int]bool{}
+	for n := range w.Trades {
+		numbers[n] = true
+	}
+	for n := range rw.Trades {
+		numbers[n] = true
+	}
+
+	var tradeNumbers []int
+	for n := range numbers {
+		tradeNumbers = append(tradeNumbers, n)
+	}
+
+	sort.Ints(tradeNumbers)
+
+	for _, n := range tradeNumbers {
+		stored, ok := w.Trades[n]
+		if !ok {
+			stored = trade.NewTrade(n)
+		}
+
+		rebuilt, ok := rw.Trades[n]
+		if !ok {
+			rebuilt = trade.NewTrade(n)
+		}
+
+		name := fmt.Sprintf("%d", n)
+
+		if stored.Status != rebuilt.Status {
+			ds = append(ds, Difference{
+				Scope:   "trade",
+				Name:    name,
+				Field:   "status",
+				Stored:  string(stored.Status),
+				Rebuilt: string(rebuilt.Status),
+			})
+		}
+
+		diffValue("trade", name, "amount", stored.Amount, rebuilt.Amount)
+		diffValue("trade", name, "buys", stored.Buys.Amount, rebuilt.Buys.Amount)
+		diffValue("trade", name, "sells", stored.Sells.Amount, rebuilt.Sells.Amount)
+		diffValue("trade", name, "dividend", stored.Dividend.Amount, rebuilt.Dividend.Amount)
+	}
+
+	return ds
+}
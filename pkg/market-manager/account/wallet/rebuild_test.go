@@ -0,0 +1,172 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+func TestRebuild(t *testing.T) {
+	bankAccount := &bank.Account{ID: uuid.NewV4(), Alias: "bank"}
+	brokerAccount := &bank.Account{ID: uuid.NewV4(), Alias: "broker"}
+	stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG"}
+
+	w := NewWallet("ourwallet", "")
+	w.ID = uuid.NewV4()
+	w.AddBankAccount(brokerAccount)
+
+	itemID := uuid.NewV4()
+	w.Items[stk.ID] = &Item{ID: itemID, Stock: stk, DividendRetention: mm.Value{Amount: 19}}
+
+	tradeID := uuid.NewV4()
+	w.Trades[1] = &trade.Trade{ID: tradeID, Number: 1, Stock: stk}
+
+	in, err := transfer.NewCurrencyTransfer(bankAccount, brokerAccount, mm.Value{Amount: 1000}, mm.Value{}, 0, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out, err := transfer.NewCurrencyTransfer(brokerAccount, bankAccount, mm.Value{Amount: 100}, mm.Value{}, 0, time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	buy := testOperation(stk, operation.Buy, time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC), 10)
+	dividend := testOperation(stk, operation.Dividend, time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC), 0)
+	dividend.Value = mm.Value{Amount: 5, Currency: mm.Euro}
+	sell := testOperation(stk, operation.Sell, time.Date(2018, 2, 15, 0, 0, 0, 0, time.UTC), 4)
+
+	rw, err := Rebuild(
+		w,
+		[]*transfer.Transfer{in, out},
+		[]*operation.Operation{buy, dividend, sell},
+		map[uuid.UUID]int{buy.ID: 1, sell.ID: 1},
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, w.ID, rw.ID)
+	assert.Equal(t, w.BankAccounts, rw.BankAccounts)
+
+	assert.Equal(t, mm.Value{Amount: 900, Currency: mm.Euro}, rw.Invested)
+	assert.Equal(t, mm.Value{Amount: 845, Currency: mm.Euro}, rw.Funds)
+	assert.Equal(t, 5.0, rw.Dividend.Amount)
+	// the transfers are booked in date order along the operations
+	assert.Len(t, rw.Ledger, 5)
+
+	item := rw.Items[stk.ID]
+	if assert.NotNil(t, item) {
+		assert.Equal(t, itemID, item.ID)
+		assert.Equal(t, mm.Value{Amount: 19}, item.DividendRetention)
+		assert.Equal(t, 6.0, item.Amount)
+		assert.Equal(t, 100.0, item.Buys.Amount)
+		assert.Equal(t, 40.0, item.Sells.Amount)
+		assert.Equal(t, 5.0, item.Dividend.Amount)
+	}
+
+	tr := rw.Trades[1]
+	if assert.NotNil(t, tr) {
+		assert.Equal(t, tradeID, tr.ID)
+		assert.Equal(t, trade.Open, tr.Status)
+		assert.Equal(t, 6.0, tr.Amount)
+		assert.Equal(t, 5.0, tr.Dividend.Amount)
+	}
+}
+
+func TestRebuildError(t *testing.T) {
+	bankAccount := &bank.Account{ID: uuid.NewV4(), Alias: "bank"}
+	brokerAccount := &bank.Account{ID: uuid.NewV4(), Alias: "broker"}
+	stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG"}
+
+	jan := time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2018, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	dividend := testOperation(stk, operation.Dividend, feb, 0)
+	dollars := &transfer.Transfer{
+		ID:     uuid.NewV4(),
+		From:   bankAccount,
+		To:     brokerAccount,
+		Amount: mm.Value{Amount: 100, Currency: mm.Dollar},
+		Date:   jan,
+	}
+
+	tests := []struct {
+		name string
+		ts   []*transfer.Transfer
+		ops  []*operation.Operation
+		err  string
+	}{
+		{
+			name: "sells more than bought",
+			ops: []*operation.Operation{
+				testOperation(stk, operation.Buy, jan, 5),
+				testOperation(stk, operation.Sell, feb, 10),
+			},
+			err: "Replaying operations on stock ENG sells 5 stocks more than bought",
+		},
+		{
+			name: "dividend of a stock not bought",
+			ops:  []*operation.Operation{dividend},
+			err:  `Replaying operation "` + dividend.ID.String() + `" dividend at 10/2/2018: can not add operation wallet item not found`,
+		},
+		{
+			name: "transfer not changed into euro",
+			ts:   []*transfer.Transfer{dollars},
+			err:  `Replaying transfer "` + dollars.ID.String() + `" at 10/1/2018: Value 100.00 $ not changed into the wallet base currency €`,
+		},
+	}
+
+	for _, tt := range tests {
+		w := NewWallet("ourwallet", "")
+		w.AddBankAccount(brokerAccount)
+
+		_, err := Rebuild(w, tt.ts, tt.ops, map[uuid.UUID]int{})
+		assert.EqualError(t, err, tt.err, tt.name)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG"}
+	rep := &stock.Stock{ID: uuid.NewV4(), Symbol: "REP"}
+
+	w := NewWallet("ourwallet", "")
+	w.Invested = mm.Value{Amount: 1000, Currency: mm.Euro}
+	w.Funds = mm.Value{Amount: 500, Currency: mm.Euro}
+	w.Items[stk.ID] = &Item{Stock: stk, Amount: 10, Invested: mm.Value{Amount: 100}, Buys: mm.Value{Amount: 100}}
+	w.Trades[1] = &trade.Trade{Number: 1, Status: trade.Open, Amount: 10, Buys: mm.Value{Amount: 100}}
+
+	rw := NewWallet("ourwallet", "")
+	rw.Invested = mm.Value{Amount: 1000, Currency: mm.Euro}
+	// under the precision the values are stored with
+	rw.Funds = mm.Value{Amount: 500.004, Currency: mm.Euro}
+	rw.Items[stk.ID] = &Item{Stock: stk, Amount: 6, Invested: mm.Value{Amount: 60}, Buys: mm.Value{Amount: 100}, Sells: mm.Value{Amount: 40}}
+	rw.Items[rep.ID] = &Item{Stock: rep, Amount: 1, Invested: mm.Value{Amount: 10}, Buys: mm.Value{Amount: 10}}
+	rw.Trades[1] = &trade.Trade{Number: 1, Status: trade.Open, Amount: 6, Buys: mm.Value{Amount: 100}, Sells: mm.Value{Amount: 40}}
+	rw.Trades[2] = &trade.Trade{Number: 2, Status: trade.Close, Buys: mm.Value{Amount: 10}, Sells: mm.Value{Amount: 12}}
+
+	assert.Equal(t, []Difference{
+		{Scope: "item", Name: "ENG", Field: "amount", Stored: "10.00", Rebuilt: "6.00"},
+		{Scope: "item", Name: "ENG", Field: "invested", Stored: "100.00", Rebuilt: "60.00"},
+		{Scope: "item", Name: "ENG", Field: "sells", Stored: "0.00", Rebuilt: "40.00"},
+		{Scope: "item", Name: "REP", Field: "amount", Stored: "0.00", Rebuilt: "1.00"},
+		{Scope: "item", Name: "REP", Field: "invested", Stored: "0.00", Rebuilt: "10.00"},
+		{Scope: "item", Name: "REP", Field: "buys", Stored: "0.00", Rebuilt: "10.00"},
+		{Scope: "trade", Name: "1", Field: "amount", Stored: "10.00", Rebuilt: "6.00"},
+		{Scope: "trade", Name: "1", Field: "sells", Stored: "0.00", Rebuilt: "40.00"},
+		{Scope: "trade", Name: "2", Field: "status", Stored: "initial", Rebuilt: "close"},
+		{Scope: "trade", Name: "2", Field: "buys", Stored: "0.00", Rebuilt: "10.00"},
+		{Scope: "trade", Name: "2", Field: "sells", Stored: "0.00", Rebuilt: "12.00"},
+	}, w.Diff(rw))
+
+	assert.Empty(t, w.Diff(w))
+}
@@ -1,12 +1,17 @@
 package wallet
 
 import (
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 )
 
 type (
 	Finder interface {
+		FindAll() ([]*Wallet, error)
+		FindAllByBroker(b *broker.Broker) ([]*Wallet, error)
 		FindByName(name string) (*Wallet, error)
 		FindByBankAccount(ba *bank.Account) (*Wallet, error)
 		LoadBankAccounts(w *Wallet) error
@@ -17,14 +22,21 @@ type (
 		LoadItemByStock(w *Wallet, stk *stock.Stock) error
 		LoadItemOperations(i *Item) error
 		LoadActiveTrades(w *Wallet) error
+		LoadItemTrades(w *Wallet, i *Item) error
 		LoadTradeItemOperations(i *Item) error
+		FindImportedTransactionIDs(w *Wallet) (map[string]bool, error)
 	}
 
 	Persister interface {
 		PersistAll(ws []*Wallet) error
+		PersistBankAccounts(w *Wallet) error
 		PersistOperations(w *Wallet) error
 		UpdateAllAccounting(ws []*Wallet) error
 		UpdateAllItemsCapital(ws []*Wallet) error
 		UpdateRetentions(w *Wallet) error
+		UpdateOperation(w *Wallet, o *operation.Operation) error
+		DeleteOperation(w *Wallet, o *operation.Operation) error
+		PersistRebuild(w *Wallet) error
+		PersistBrokerImport(w *Wallet, ts []*transfer.Transfer, ids []string) error
 	}
 )
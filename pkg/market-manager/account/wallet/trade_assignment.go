@@ -0,0 +1,129 @@
+package wallet
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+)
+
+// TradeMatch is the rule followed by a sell to choose the open trade it goes to,
+// when the stock has more than one open trade
+type TradeMatch string
+
+const (
+	// FIFO sells from the oldest open trade
+	FIFO TradeMatch = "fifo"
+	// LIFO sells from the newest open trade
+	LIFO TradeMatch = "lifo"
+	// Exact sells from the open trade holding exactly the amount sold, the oldest otherwise
+	Exact TradeMatch = "exact"
+)
+
+// ParseTradeMatch returns the trade match rule, FIFO when empty
+func ParseTradeMatch(s string) (TradeMatch, error) {
+	switch TradeMatch(s) {
+	case "":
+		return FIFO, nil
+	case FIFO, LIFO, Exact:
+		return TradeMatch(s), nil
+	}
+
+	return "", errors.Errorf("trade match %q not supported, use fifo, lifo or exact", s)
+}
+
+// AssignTrade adds the buy or sell operation to a trade chosen automatically.
+// A buy scales into the newest open trade of the stock, or opens a new trade when there is none.
+// A sell goes to the open trade of the stock chosen by the match rule, see SellTrade.
+func (w *Wallet) AssignTrade(o *operation.Operation, match TradeMatch) error {
+	switch o.Action {
+	case operation.Buy:
+		if t := w.openTrade(o, LIFO); t != nil {
+			return w.AddTrade(t.Number, o)
+		}
+
+		return w.AddTrade(w.LastTradeNumber+1, o)
+	case operation.Sell, operation.Redemption:
+		t, err := w.SellTrade(o, match)
+		if err != nil {
+			return err
+		}
+
+		return w.AddTrade(t.Number, o)
+	}
+
+	return errors.Errorf("Operation %s can not be assigned to a trade", o.Action)
+}
+
+// SellTrade returns the open trade of the stock the sell or redemption goes to, chosen by the match rule among
+// the ones holding enough stocks. A sell is never split across trades, it is rejected when no single open trade
+// holds the amount sold.
+func (w *Wallet) SellTrade(o *operation.Operation, match TradeMatch) (*trade.Trade, error) {
+	t := w.openTrade(o, match)
+	if t == nil {
+		return nil, errors.Errorf(
+			"No open trade of stock %s holds %v stocks to sell in wallet %q",
+			o.Stock.Symbol,
+			o.Amount,
+			w.Name,
+		)
+	}
+
+	return t, nil
+}
+
+// openTrade returns the open trade of the operation stock chosen by the match rule.
+// For sells, only the trades holding enough stocks are considered.
+func (w *Wallet) openTrade(o *operation.Operation, match TradeMatch) *trade.Trade {
+	var candidate, exact *trade.Trade
+
+	for _, t := range w.Trades {
+		if t.Stock.ID != o.Stock.ID || t.Status != trade.Open {
+			continue
+		}
+
+		amount := mm.RoundAmount(o.Amount)
+
+		if o.Action.IsDisposal() && mm.RoundAmount(t.Amount) < amount {
+			continue
+		}
+
+		if match == Exact && mm.RoundAmount(t.Amount) == amount && (exact == nil || isOlder(t, exact)) {
+			exact = t
+		}
+
+		if candidate == nil {
+			candidate = t
+
+			continue
+		}
+
+		switch match {
+		case LIFO:
+			if isOlder(candidate, t) {
+				candidate = t
+			}
+		default:
+			if isOlder(t, candidate) {
+				candidate = t
+			}
+		}
+	}
+
+	if exact != nil {
+		return exact
+	}
+
+	return candidate
+}
+
+// isOlder tells whether trade a was opened before trade b, by trade number when opened at the same time
+func isOlder(a, b *trade.Trade) bool {
+	if a.OpenedAt.Equal(b.OpenedAt) {
+		return a.Number < b.Number
+	}
+
+	return a.OpenedAt.Before(b.OpenedAt)
+}
@@ -0,0 +1,200 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// testOperation returns the operation of the amount of stocks at 10 euro each without commissions
+func testOperation(stk *stock.Stock, action operation.Action, date time.Time, amount float64) *operation.Operation {
+	return operation.NewOperation(
+		date,
+		stk,
+		action,
+		amount,
+		mm.Value{Amount: 10, Currency: mm.Euro},
+		mm.Value{Amount: 1},
+		mm.Value{Currency: mm.Euro},
+		mm.Value{Amount: amount * 10, Currency: mm.Euro},
+		mm.Value{Currency: mm.Euro},
+	)
+}
+
+func TestParseTradeMatch(t *testing.T) {
+	tests := []struct {
+		match  string
+		result TradeMatch
+		err    string
+	}{
+		{"", FIFO, ""},
+		{"fifo", FIFO, ""},
+		{"lifo", LIFO, ""},
+		{"exact", Exact, ""},
+		{"FIFO", "", `trade match "FIFO" not supported, use fifo, lifo or exact`},
+		{"average", "", `trade match "average" not supported, use fifo, lifo or exact`},
+	}
+
+	for _, tt := range tests {
+		m, err := ParseTradeMatch(tt.match)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.match)
+
+			continue
+		}
+
+		assert.NoError(t, err, tt.match)
+		assert.Equal(t, tt.result, m, tt.match)
+	}
+}
+
+func TestSellTrade(t *testing.T) {
+	jan := time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2018, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	type openTrade struct {
+		number int
+		date   time.Time
+		amount float64
+	}
+
+	tests := []struct {
+		name   string
+		trades []openTrade
+		amount float64
+		match  TradeMatch
+		number int
+		err    string
+	}{
+		{
+			name:   "fifo",
+			trades: []openTrade{{1, jan, 10}, {2, feb, 10}},
+			amount: 5,
+			match:  FIFO,
+			number: 1,
+		},
+		{
+			name:   "lifo",
+			trades: []openTrade{{1, jan, 10}, {2, feb, 10}},
+			amount: 5,
+			match:  LIFO,
+			number: 2,
+		},
+		{
+			name:   "exact",
+			trades: []openTrade{{1, jan, 10}, {2, feb, 5}},
+			amount: 5,
+			match:  Exact,
+			number: 2,
+		},
+		{
+			name:   "exact without trade of the amount",
+			trades: []openTrade{{1, jan, 10}, {2, feb, 8}},
+			amount: 5,
+			match:  Exact,
+			number: 1,
+		},
+		{
+			name:   "trade holding enough",
+			trades: []openTrade{{1, jan, 3}, {2, feb, 10}},
+			amount: 5,
+			match:  FIFO,
+			number: 2,
+		},
+		{
+			name:   "opened at the same time",
+			trades: []openTrade{{2, jan, 10}, {1, jan, 10}},
+			amount: 5,
+			match:  FIFO,
+			number: 1,
+		},
+		{
+			name:   "fractional amount",
+			trades: []openTrade{{1, jan, 1}, {2, feb, 0.3}},
+			amount: 0.1 + 0.2,
+			match:  Exact,
+			number: 2,
+		},
+		{
+			name:   "split across trades",
+			trades: []openTrade{{1, jan, 3}, {2, feb, 4}},
+			amount: 5,
+			match:  FIFO,
+			err:    `No open trade of stock ENG holds 5 stocks to sell in wallet "ourwallet"`,
+		},
+	}
+
+	for _, tt := range tests {
+		stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG"}
+		w := NewWallet("ourwallet", "")
+
+		for _, ot := range tt.trades {
+			o := testOperation(stk, operation.Buy, ot.date, ot.amount)
+
+			if !assert.NoError(t, w.AddOperation(o), tt.name) || !assert.NoError(t, w.AddTrade(ot.number, o), tt.name) {
+				break
+			}
+		}
+
+		tr, err := w.SellTrade(testOperation(stk, operation.Sell, feb.AddDate(0, 1, 0), tt.amount), tt.match)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.name)
+
+			continue
+		}
+
+		if assert.NoError(t, err, tt.name) {
+			assert.Equal(t, tt.number, tr.Number, tt.name)
+		}
+	}
+}
+
+func TestAssignTrade(t *testing.T) {
+	stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "BTC-EUR"}
+	w := NewWallet("ourwallet", "")
+
+	tests := []struct {
+		action operation.Action
+		amount float64
+		number int
+		status trade.Status
+		held   float64
+	}{
+		// the first buy opens a trade, the next ones scale into it
+		{operation.Buy, 0.1, 1, trade.Open, 0.1},
+		{operation.Buy, 0.2, 1, trade.Open, 0.3},
+		// the sell of the fractional amount held closes the trade
+		{operation.Sell, 0.3, 1, trade.Close, 0},
+		// once closed, a buy opens a new trade
+		{operation.Buy, 1.5, 2, trade.Open, 1.5},
+		{operation.Sell, 0.5, 2, trade.Open, 1},
+	}
+
+	date := time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	for i, tt := range tests {
+		o := testOperation(stk, tt.action, date.AddDate(0, 0, i), tt.amount)
+
+		if !assert.NoError(t, w.AddOperation(o)) || !assert.NoError(t, w.AssignTrade(o, FIFO)) {
+			return
+		}
+
+		tr := w.Trades[tt.number]
+		if assert.NotNil(t, tr, "%s %v", tt.action, tt.amount) {
+			assert.Equal(t, tt.status, tr.Status, "%s %v", tt.action, tt.amount)
+			assert.Equal(t, tt.held, tr.Amount, "%s %v", tt.action, tt.amount)
+		}
+
+		assert.Equal(t, tt.held, w.Items[stk.ID].Amount, "%s %v", tt.action, tt.amount)
+	}
+
+	err := w.AssignTrade(testOperation(stk, operation.Dividend, date, 0), FIFO)
+	assert.EqualError(t, err, "Operation dividend can not be assigned to a trade")
+}
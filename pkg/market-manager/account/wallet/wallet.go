@@ -7,9 +7,12 @@ import (
 	"github.com/satori/go.uuid"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/broker"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/ledger"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
 	"github.com/dohernandez/market-manager/pkg/market-manager/account/trade"
 	"github.com/dohernandez/market-manager/pkg/market-manager/banking/bank"
+	"github.com/dohernandez/market-manager/pkg/market-manager/banking/transfer"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
 	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
 )
@@ -17,7 +20,7 @@ import (
 type Item struct {
 	ID                uuid.UUID
 	Stock             *stock.Stock
-	Amount            int
+	Amount            float64
 	Invested          mm.Value
 	Dividend          mm.Value
 	Buys              mm.Value
@@ -40,8 +43,8 @@ func NewItem(stock *stock.Stock) *Item {
 	}
 }
 
-func (i *Item) increaseInvestment(amount int, invested, priceChangeCommission, commission mm.Value) mm.Value {
-	i.Amount = i.Amount + amount
+func (i *Item) increaseInvestment(amount float64, invested, priceChangeCommission, commission mm.Value) mm.Value {
+	i.Amount = mm.RoundAmount(i.Amount + amount)
 
 	invested = invested.Increase(priceChangeCommission)
 	invested = invested.Increase(commission)
@@ -52,8 +55,8 @@ func (i *Item) increaseInvestment(amount int, invested, priceChangeCommission, c
 	return invested
 }
 
-func (i *Item) decreaseInvestment(amount int, buyout, priceChangeCommission, commission mm.Value) mm.Value {
-	i.Amount = i.Amount - amount
+func (i *Item) decreaseInvestment(amount float64, buyout, priceChangeCommission, commission mm.Value) mm.Value {
+	i.Amount = mm.RoundAmount(i.Amount - amount)
 
 	buyout = buyout.Decrease(priceChangeCommission)
 	buyout = buyout.Decrease(commission)
@@ -73,16 +76,93 @@ func (i *Item) increaseDividend(dividend mm.Value) {
 	i.Dividend = i.Dividend.Increase(dividend)
 }
 
-func (i *Item) Capital() mm.Value {
-	capital := float64(i.Amount) * i.Stock.Value.Amount
+// allocateDividend spreads the dividend over the trades by the stocks each one held at the dividend ex-date,
+// closed trades included. When the ex-date is unknown, it is spread over the open trades by their current amount.
+func (i *Item) allocateDividend(o *operation.Operation) {
+	held := map[int]float64{}
+	var total float64
+
+	if exDate, ok := dividendExDate(o); ok {
+		for n, t := range i.Trades {
+			if amount := t.AmountAt(exDate); amount > 0 {
+				held[n] = amount
+				total += amount
+			}
+		}
+	}
+
+	if total == 0 {
+		for n, t := range i.Trades {
+			if t.Status == trade.Close {
+				continue
+			}
+
+			held[n] = t.Amount
+			total += t.Amount
+		}
+	}
+
+	if total == 0 {
+		return
+	}
+
+	dividendPayPerStock := o.FinalPricePaid().Amount / total
+
+	for n, amount := range held {
+		i.Trades[n].PayedDividend(mm.Value{
+			Amount:   dividendPayPerStock * amount,
+			Currency: mm.Euro,
+		})
+	}
+}
+
+// dividendExDate returns the ex-date of the stock dividend paid by the operation. It is the dividend with ex-date
+// before the operation whose payment date is the closest to the operation date.
+func dividendExDate(o *operation.Operation) (time.Time, bool) {
+	var (
+		found    *dividend.StockDividend
+		distance time.Duration
+	)
+
+	for k, d := range o.Stock.Dividends {
+		if d.ExDate.IsZero() || d.ExDate.After(o.Date) {
+			continue
+		}
+
+		dd := o.Date.Sub(d.ExDate)
+		if !d.PaymentDate.IsZero() {
+			dd = o.Date.Sub(d.PaymentDate)
+			if dd < 0 {
+				dd = -dd
+			}
+		}
 
-	if i.CapitalRate > 0 {
-		if i.Stock.Exchange.Symbol == "NASDAQ" || i.Stock.Exchange.Symbol == "NYSE" ||
-			i.Stock.Exchange.Symbol == "TSX" {
-			capital = capital / i.CapitalRate
+		if found == nil || dd < distance || (dd == distance && d.ExDate.After(found.ExDate)) {
+			found = &o.Stock.Dividends[k]
+			distance = dd
 		}
 	}
 
+	if found == nil {
+		return time.Time{}, false
+	}
+
+	return found.ExDate, true
+}
+
+func (i *Item) Capital() mm.Value {
+	price := i.Stock.Value.Amount
+	if i.Stock.IsBond() {
+		// the bonds are valued at the dirty price, the interest accrued since the last coupon belongs to the holder
+		price = i.Stock.FixedIncome.DirtyValue(time.Now()).Amount
+	}
+
+	capital := float64(i.Amount) * price
+
+	if i.CapitalRate > 0 && i.Stock.QuoteCurrency() != mm.Euro {
+		capital = capital / i.CapitalRate
+	}
+
 	return mm.Value{
 		Amount:   capital,
 		Currency: mm.Euro,
@@ -115,8 +195,7 @@ func (i *Item) PercentageBenefits() float64 {
 func (i *Item) Change() mm.Value {
 	change := float64(i.Amount) * i.Stock.Change.Amount
 
-	if i.Stock.Exchange.Symbol == "NASDAQ" || i.Stock.Exchange.Symbol == "NYSE" ||
-		i.Stock.Exchange.Symbol == "TSX" {
+	if i.Stock.QuoteCurrency() != mm.Euro {
 		change = change / i.CapitalRate
 	}
 
@@ -129,10 +208,10 @@ func (i *Item) Change() mm.Value {
 func (i *Item) WeightedAveragePrice() mm.Value {
 	var asPrice float64
 
-	eSymbol := i.Stock.Exchange.Symbol
+	currency := i.Stock.QuoteCurrency()
 
 	for _, o := range i.Operations {
-		if o.Action != operation.Buy && o.Action != operation.Sell {
+		if o.Action != operation.Buy && !o.Action.IsDisposal() {
 			continue
 		}
 		commissions := o.Commission.Increase(o.PriceChangeCommission)
@@ -140,7 +219,7 @@ func (i *Item) WeightedAveragePrice() mm.Value {
 		sPrice := o.Price.Amount * float64(o.Amount)
 
 		if o.Action == operation.Buy {
-			if mm.ExchangeCurrency(eSymbol) == mm.Dollar {
+			if currency == mm.Dollar {
 				sPrice = sPrice + commissions.Amount*o.PriceChange.Amount
 			} else {
 				sPrice = sPrice + commissions.Amount
@@ -148,7 +227,7 @@ func (i *Item) WeightedAveragePrice() mm.Value {
 
 			asPrice = asPrice + sPrice
 		} else {
-			if mm.ExchangeCurrency(eSymbol) == mm.Dollar {
+			if currency == mm.Dollar {
 				sPrice = sPrice - commissions.Amount*o.PriceChange.Amount
 			} else {
 				sPrice = sPrice - commissions.Amount
@@ -159,7 +238,7 @@ func (i *Item) WeightedAveragePrice() mm.Value {
 	}
 
 	wAPrice := mm.Value{
-		Currency: mm.ExchangeCurrency(eSymbol),
+		Currency: currency,
 	}
 
 	if i.Amount > 0 {
@@ -173,6 +252,126 @@ func (i *Item) PercentageInvestedRepresented(invested float64) float64 {
 	return i.Invested.Amount * 100 / invested
 }
 
+// ForwardAnnualDividend returns the dividend per stock expected within the year after now, in the stock quote
+// currency. The dividends announced are annualized by the payment frequency, the dividends paid within the year
+// before now, the payments not announced yet are expected as the last one announced. When no dividend is
+// announced yet, the dividend paid within the year before now is used.
+func (i *Item) ForwardAnnualDividend(now time.Time) mm.Value {
+	var (
+		forward, trailing float64
+		announced, paid   int
+		last              *dividend.StockDividend
+	)
+
+	from := now.AddDate(-1, 0, 0)
+	to := now.AddDate(1, 0, 0)
+
+	for k, d := range i.Stock.Dividends {
+		if d.ExDate.After(now) && !d.ExDate.After(to) {
+			forward += d.Amount.Amount
+			announced++
+
+			if last == nil || d.ExDate.After(last.ExDate) {
+				last = &i.Stock.Dividends[k]
+			}
+		}
+
+		if d.ExDate.After(from) && !d.ExDate.After(now) {
+			trailing += d.Amount.Amount
+			paid++
+		}
+	}
+
+	switch {
+	case announced == 0:
+		forward = trailing
+	case paid > announced:
+		forward += float64(paid-announced) * last.Amount.Amount
+	}
+
+	return mm.Value{
+		Amount:   forward,
+		Currency: i.Stock.QuoteCurrency(),
+	}
+}
+
+// YieldOnCost returns the forward annual dividend yield over the weighted average price
+func (i *Item) YieldOnCost(now time.Time) float64 {
+	wAPrice := i.WeightedAveragePrice()
+	if wAPrice.Amount <= 0 {
+		return 0
+	}
+
+	return i.ForwardAnnualDividend(now).Amount * 100 / wAPrice.Amount
+}
+
+// FirstBuyDate returns the date of the first buy of the stock
+func (i *Item) FirstBuyDate() (time.Time, bool) {
+	var first time.Time
+
+	for _, o := range i.Operations {
+		if o.Action != operation.Buy {
+			continue
+		}
+
+		if first.IsZero() || o.Date.Before(first) {
+			first = o.Date
+		}
+	}
+
+	return first, !first.IsZero()
+}
+
+// DividendGrowth returns the percentage the dividend per stock grew since the first buy of the stock.
+// It compares the dividend paid at the first buy, or the next one when none was paid before,
+// with the last dividend paid.
+func (i *Item) DividendGrowth(now time.Time) float64 {
+	firstBuy, ok := i.FirstBuyDate()
+	if !ok {
+		return 0
+	}
+
+	var before, after, last *dividend.StockDividend
+
+	for k, d := range i.Stock.Dividends {
+		if d.Amount.Amount <= 0 || d.ExDate.IsZero() || d.ExDate.After(now) {
+			continue
+		}
+
+		if !d.ExDate.After(firstBuy) {
+			if before == nil || d.ExDate.After(before.ExDate) {
+				before = &i.Stock.Dividends[k]
+			}
+		} else if after == nil || d.ExDate.Before(after.ExDate) {
+			after = &i.Stock.Dividends[k]
+		}
+
+		if last == nil || d.ExDate.After(last.ExDate) {
+			last = &i.Stock.Dividends[k]
+		}
+	}
+
+	first := before
+	if first == nil {
+		first = after
+	}
+
+	if first == nil || first == last {
+		return 0
+	}
+
+	return (last.Amount.Amount - first.Amount.Amount) * 100 / first.Amount.Amount
+}
+
+// DividendOnCost returns the dividends received as percentage of what was payed buying the stock
+func (i *Item) DividendOnCost() float64 {
+	if i.Buys.Amount <= 0 {
+		return 0
+	}
+
+	return i.Dividend.Amount * 100 / i.Buys.Amount
+}
+
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // CapitalRate
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -182,14 +381,35 @@ type CapitalRate struct {
 	EURCAD float64
 }
 
+// For returns the rate the stock price is converted to euro with
+func (r CapitalRate) For(stk *stock.Stock) float64 {
+	switch stk.QuoteCurrency() {
+	case mm.Dollar:
+		return r.EURUSD
+	case mm.CanadianDollar:
+		return r.EURCAD
+	}
+
+	return 1
+}
+
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 // Wallet
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// defaultMargin is the margin percentage applied when the wallet is not hold by any broker
+const defaultMargin = 49
+
+// BaseCurrency is the currency the wallet figures are booked in. The wallets are booked in euro only, the values in
+// other currency are changed before they reach the wallet: the transfers at their rate, the operations at their price
+// change, and the broker reports have to be in euro
+const BaseCurrency = mm.Euro
+
 type Wallet struct {
 	ID           uuid.UUID
 	Name         string
 	URL          string
+	Broker       *broker.Broker
 	BankAccounts map[uuid.UUID]*bank.Account
 	// stocks in trade
 	Items map[uuid.UUID]*Item
@@ -203,11 +423,15 @@ type Wallet struct {
 	Commission mm.Value
 	Connection mm.Value
 	Interest   mm.Value
+	// cash movements booked since the wallet was loaded
+	Ledger []*ledger.Entry
 
 	// Rate currency conversion
 	capitalRate CapitalRate
 
 	Trades map[int]*trade.Trade
+	// LastTradeNumber highest trade number used in the wallet, open or closed
+	LastTradeNumber int
 }
 
 func NewWallet(name, url string) *Wallet {
@@ -234,12 +458,12 @@ func (w *Wallet) AddBankAccount(ba *bank.Account) error {
 func (w *Wallet) AddOperation(o *operation.Operation) error {
 	wi := new(Item)
 
-	if o.Action == operation.Dividend || o.Action == operation.Sell || o.Action == operation.Buy {
+	if o.Action.IsIncome() || o.Action.IsDisposal() || o.Action == operation.Buy {
 		var ok bool
 		// Getting the wallet item
 		wi, ok = w.Items[o.Stock.ID]
 		if !ok {
-			if o.Action == operation.Dividend || o.Action == operation.Sell {
+			if o.Action.IsIncome() || o.Action.IsDisposal() {
 				return mm.ErrCanNotAddOperation
 			}
 
@@ -258,22 +482,44 @@ func (w *Wallet) AddOperation(o *operation.Operation) error {
 
 	switch o.Action {
 	case operation.Buy:
-		invested := wi.increaseInvestment(o.Amount, o.Value, o.PriceChangeCommission, o.Commission)
+		wi.increaseInvestment(o.Amount, o.Value, o.PriceChangeCommission, o.Commission)
 
-		w.Funds = w.Funds.Decrease(invested)
 		w.Capital = w.Capital.Increase(o.Capital())
-		w.Commission = w.Commission.Increase(o.FinalCommission())
 
-	case operation.Sell:
-		buyout := wi.decreaseInvestment(o.Amount, o.Value, o.PriceChangeCommission, o.Commission)
+	case operation.Sell, operation.Redemption:
+		wi.decreaseInvestment(o.Amount, o.Value, o.PriceChangeCommission, o.Commission)
 
-		w.Funds = w.Funds.Increase(buyout)
 		w.Capital = w.Capital.Decrease(o.Capital())
-		w.Commission = w.Commission.Increase(o.FinalCommission())
 
-	case operation.Dividend:
+	case operation.Dividend, operation.Coupon:
 		wi.increaseDividend(o.Value)
+	}
+
+	w.bookOperation(o)
+
+	w.Ledger = append(w.Ledger, ledger.NewEntriesFromOperation(o)...)
 
+	return nil
+}
+
+// bookOperation books the operation into the wallet accounting
+func (w *Wallet) bookOperation(o *operation.Operation) {
+	switch o.Action {
+	case operation.Buy:
+		invested := o.Value.Increase(o.PriceChangeCommission)
+		invested = invested.Increase(o.Commission)
+
+		w.Funds = w.Funds.Decrease(invested)
+		w.Commission = w.Commission.Increase(o.FinalCommission())
+
+	case operation.Sell, operation.Redemption:
+		buyout := o.Value.Decrease(o.PriceChangeCommission)
+		buyout = buyout.Decrease(o.Commission)
+
+		w.Funds = w.Funds.Increase(buyout)
+		w.Commission = w.Commission.Increase(o.FinalCommission())
+
+	case operation.Dividend, operation.Coupon:
 		w.Dividend = w.Dividend.Increase(o.Value)
 		w.Funds = w.Funds.Increase(o.Value)
 
@@ -285,18 +531,173 @@ func (w *Wallet) AddOperation(o *operation.Operation) error {
 		w.Funds = w.Funds.Decrease(o.Value)
 		w.Connection = w.Connection.Increase(o.Value)
 	}
+}
+
+// unbookOperation reverts the operation from the wallet accounting
+func (w *Wallet) unbookOperation(o *operation.Operation) {
+	switch o.Action {
+	case operation.Buy:
+		invested := o.Value.Increase(o.PriceChangeCommission)
+		invested = invested.Increase(o.Commission)
+
+		w.Funds = w.Funds.Increase(invested)
+		w.Commission = w.Commission.Decrease(o.FinalCommission())
+
+	case operation.Sell, operation.Redemption:
+		buyout := o.Value.Decrease(o.PriceChangeCommission)
+		buyout = buyout.Decrease(o.Commission)
+
+		w.Funds = w.Funds.Decrease(buyout)
+		w.Commission = w.Commission.Decrease(o.FinalCommission())
+
+	case operation.Dividend, operation.Coupon:
+		w.Dividend = w.Dividend.Decrease(o.Value)
+		w.Funds = w.Funds.Decrease(o.Value)
+
+	case operation.Interest:
+		w.Funds = w.Funds.Increase(o.Value)
+		w.Interest = w.Interest.Decrease(o.Value)
+
+	case operation.Connectivity:
+		w.Funds = w.Funds.Increase(o.Value)
+		w.Connection = w.Connection.Decrease(o.Value)
+	}
+}
+
+// RemoveOperation reverts the operation from the wallet accounting.
+// The wallet item and trades of the operation stock are not reverted, they have to be rebuilt with RebuildItem
+// from the remaining operations.
+func (w *Wallet) RemoveOperation(o *operation.Operation) {
+	w.unbookOperation(o)
+}
+
+// ReplaceOperation reverts the old operation from the wallet accounting and books the new one in its place.
+// The wallet item and trades of the operation stock are not recomputed, they have to be rebuilt with RebuildItem.
+func (w *Wallet) ReplaceOperation(old, o *operation.Operation) {
+	w.unbookOperation(old)
+	w.bookOperation(o)
+
+	w.Ledger = append(w.Ledger, ledger.NewEntriesFromOperation(o)...)
+}
+
+// RebuildItem recomputes the wallet item of the stock and its trades replaying the stock operations in the given
+// order. Trade numbers are looked up by operation ID, dividends are spread over the open trades.
+// The wallet accounting is left untouched.
+func (w *Wallet) RebuildItem(stk *stock.Stock, ops []*operation.Operation, trades map[uuid.UUID]int) error {
+	rw := NewWallet(w.Name, w.URL)
+	rw.ID = w.ID
+
+	for _, o := range ops {
+		if o.Stock.ID != stk.ID {
+			continue
+		}
+
+		if err := rw.AddOperation(o); err != nil {
+			return errors.Wrapf(err, "Replaying operation %q on stock %s", o.ID, stk.Symbol)
+		}
+
+		if n, ok := trades[o.ID]; ok {
+			if err := rw.AddTrade(n, o); err != nil {
+				return errors.Wrapf(err, "Replaying trade of operation %q on stock %s", o.ID, stk.Symbol)
+			}
+		} else if o.Action.IsIncome() {
+			rw.AddTrade(0, o)
+		}
+	}
+
+	item, ok := rw.Items[stk.ID]
+	if !ok {
+		item = NewItem(stk)
+	}
+
+	if item.Amount < 0 {
+		return errors.Errorf("Replaying operations on stock %s sells %v stocks more than bought", stk.Symbol, -item.Amount)
+	}
+
+	if old, ok := w.Items[stk.ID]; ok {
+		// keep the stored identity and the settings not derived from the operations
+		item.ID = old.ID
+		item.DividendRetention = old.DividendRetention
+	}
+
+	item.Stock = stk
+	w.Items[stk.ID] = item
+
+	for n, t := range w.Trades {
+		if t.Stock.ID == stk.ID {
+			delete(w.Trades, n)
+		}
+	}
+
+	for n, t := range item.Trades {
+		t.Stock = stk
+		w.Trades[n] = t
+	}
+
+	w.SetCapitalRate(w.capitalRate)
 
 	return nil
 }
 
-func (w *Wallet) IncreaseInvestment(v mm.Value) {
+// IncreaseInvestment books the money put into the wallet. The value is given in the wallet base currency,
+// the transfers in other currency are changed before, see TransferIn
+func (w *Wallet) IncreaseInvestment(v mm.Value) error {
+	if err := checkBaseCurrency(v); err != nil {
+		return err
+	}
+
 	w.Invested = w.Invested.Increase(v)
+	w.Invested.Currency = BaseCurrency
 	w.Funds = w.Funds.Increase(v)
+	w.Funds.Currency = BaseCurrency
+
+	return nil
 }
 
-func (w *Wallet) DecreaseInvestment(v mm.Value) {
+// DecreaseInvestment books the money taken out from the wallet, in the wallet base currency
+func (w *Wallet) DecreaseInvestment(v mm.Value) error {
+	if err := checkBaseCurrency(v); err != nil {
+		return err
+	}
+
 	w.Invested = w.Invested.Decrease(v)
+	w.Invested.Currency = BaseCurrency
 	w.Funds = w.Funds.Decrease(v)
+	w.Funds.Currency = BaseCurrency
+
+	return nil
+}
+
+// checkBaseCurrency returns an error when the value is in other currency than the wallet base currency,
+// it has to be changed before
+func checkBaseCurrency(v mm.Value) error {
+	if v.Currency != "" && v.Currency != BaseCurrency {
+		return errors.Errorf("Value %.2f %s not changed into the wallet base currency %s", v.Amount, v.Currency, BaseCurrency)
+	}
+
+	return nil
+}
+
+// TransferIn increases the investment with the money transferred into the wallet
+func (w *Wallet) TransferIn(t *transfer.Transfer) error {
+	if err := w.IncreaseInvestment(t.Received()); err != nil {
+		return err
+	}
+
+	w.Ledger = append(w.Ledger, ledger.NewEntryFromTransfer(t, true))
+
+	return nil
+}
+
+// TransferOut decreases the investment with the money transferred out from the wallet
+func (w *Wallet) TransferOut(t *transfer.Transfer) error {
+	if err := w.DecreaseInvestment(t.Sent()); err != nil {
+		return err
+	}
+
+	w.Ledger = append(w.Ledger, ledger.NewEntryFromTransfer(t, false))
+
+	return nil
 }
 
 func (w *Wallet) UpdateCapital(v mm.Value) {
@@ -328,25 +729,11 @@ func (w *Wallet) SetCapitalRate(capitalRate CapitalRate) {
 	w.capitalRate = capitalRate
 
 	for _, item := range w.Items {
-		switch item.Stock.Exchange.Symbol {
-		case "NASDAQ", "NYSE":
-			item.CapitalRate = capitalRate.EURUSD
-		case "TSX":
-			item.CapitalRate = capitalRate.EURCAD
-		default:
-			item.CapitalRate = 1
-		}
+		item.CapitalRate = capitalRate.For(item.Stock)
 	}
 
 	for _, t := range w.Trades {
-		switch t.Stock.Exchange.Symbol {
-		case "NASDAQ", "NYSE":
-			t.CapitalRate = capitalRate.EURUSD
-		case "TSX":
-			t.CapitalRate = capitalRate.EURCAD
-		default:
-			t.CapitalRate = 1
-		}
+		t.CapitalRate = capitalRate.For(t.Stock)
 	}
 }
 
@@ -421,7 +808,12 @@ func (w *Wallet) DividendNetProjectedNextMonth(retention float64) mm.Value {
 
 func (w *Wallet) Margin() mm.Value {
 	netCapital := w.NetCapital()
-	margin := netCapital.Amount * 49 / 100
+	marginRate := float64(defaultMargin)
+	if w.Broker != nil {
+		marginRate = w.Broker.Margin
+	}
+
+	margin := netCapital.Amount * marginRate / 100
 
 	return mm.Value{
 		Amount:   margin,
@@ -507,6 +899,10 @@ func (w *Wallet) DividendNetProjectedNextYear(retention float64) mm.Value {
 func (w *Wallet) AddTrade(n int, o *operation.Operation) error {
 	t, ok := w.Trades[n]
 
+	if n > w.LastTradeNumber {
+		w.LastTradeNumber = n
+	}
+
 	if o.Action == operation.Buy {
 		if !ok {
 			t := trade.NewTrade(n)
@@ -529,7 +925,7 @@ func (w *Wallet) AddTrade(n int, o *operation.Operation) error {
 		}
 
 		return nil
-	} else if o.Action == operation.Dividend {
+	} else if o.Action.IsIncome() {
 		item, ok := w.Items[o.Stock.ID]
 		if !ok {
 			return errors.Errorf(
@@ -539,23 +935,9 @@ func (w *Wallet) AddTrade(n int, o *operation.Operation) error {
 			)
 		}
 
-		dividendPayPerStock := o.FinalPricePaid().Amount / float64(item.Amount)
-
-		for k, t := range item.Trades {
-			if t.Status == trade.Close {
-				// TODO Check if the ex-date dividend was before the trade was close
-				continue
-			}
-
-			dPerTrade := mm.Value{
-				Amount:   dividendPayPerStock * t.Amount,
-				Currency: mm.Euro,
-			}
-
-			t.PayedDividend(dPerTrade)
+		item.allocateDividend(o)
 
-			item.Trades[k] = t
-		}
+		return nil
 	}
 
 	if !ok {
@@ -0,0 +1,146 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/account/operation"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+func TestDividendExDate(t *testing.T) {
+	tests := []struct {
+		name      string
+		dividends []dividend.StockDividend
+		exDate    time.Time
+		found     bool
+	}{
+		{
+			name: "without dividends",
+		},
+		{
+			name: "ex-date after the payment",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "without ex-date",
+			dividends: []dividend.StockDividend{
+				{PaymentDate: time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			name: "without payment date",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			exDate: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name: "payment date closest",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 14, 0, 0, 0, 0, time.UTC)},
+				{ExDate: time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 4, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			exDate: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+			found:  true,
+		},
+		{
+			name: "payment dates as close, latest ex-date",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 14, 0, 0, 0, 0, time.UTC)},
+				{ExDate: time.Date(2018, 2, 10, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 16, 0, 0, 0, 0, time.UTC)},
+			},
+			exDate: time.Date(2018, 2, 10, 0, 0, 0, 0, time.UTC),
+			found:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG", Dividends: tt.dividends}
+		o := testOperation(stk, operation.Dividend, time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC), 0)
+
+		exDate, found := dividendExDate(o)
+		assert.Equal(t, tt.found, found, tt.name)
+		assert.Equal(t, tt.exDate, exDate, tt.name)
+	}
+}
+
+func TestAllocateDividend(t *testing.T) {
+	tests := []struct {
+		name      string
+		dividends []dividend.StockDividend
+		allocated map[int]float64
+	}{
+		{
+			name: "by the stocks held at the ex-date, closed trade included",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 2, 15, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC)},
+			},
+			allocated: map[int]float64{1: 30, 2: 0},
+		},
+		{
+			name: "by the stocks held at the ex-date",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC)},
+			},
+			allocated: map[int]float64{1: 0, 2: 30},
+		},
+		{
+			name: "overlapping trades at the ex-date",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 2, 18, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC)},
+			},
+			allocated: map[int]float64{1: 20, 2: 10},
+		},
+		{
+			name:      "ex-date unknown, over the open trades",
+			allocated: map[int]float64{1: 0, 2: 30},
+		},
+		{
+			name: "no stocks held at the ex-date, over the open trades",
+			dividends: []dividend.StockDividend{
+				{ExDate: time.Date(2018, 1, 5, 0, 0, 0, 0, time.UTC), PaymentDate: time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC)},
+			},
+			allocated: map[int]float64{1: 0, 2: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		stk := &stock.Stock{ID: uuid.NewV4(), Symbol: "ENG", Dividends: tt.dividends}
+		w := NewWallet("ourwallet", "")
+
+		// trade 1 holds 10 stocks from January until sold in February, trade 2 holds 5 stocks since the middle
+		// of February
+		trades := []struct {
+			number int
+			o      *operation.Operation
+		}{
+			{1, testOperation(stk, operation.Buy, time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC), 10)},
+			{2, testOperation(stk, operation.Buy, time.Date(2018, 2, 16, 0, 0, 0, 0, time.UTC), 5)},
+			{1, testOperation(stk, operation.Sell, time.Date(2018, 2, 20, 0, 0, 0, 0, time.UTC), 10)},
+		}
+
+		for _, tr := range trades {
+			if !assert.NoError(t, w.AddOperation(tr.o), tt.name) || !assert.NoError(t, w.AddTrade(tr.number, tr.o), tt.name) {
+				return
+			}
+		}
+
+		o := testOperation(stk, operation.Dividend, time.Date(2018, 3, 15, 0, 0, 0, 0, time.UTC), 0)
+		o.Value = mm.Value{Amount: 30, Currency: mm.Euro}
+
+		w.Items[stk.ID].allocateDividend(o)
+
+		for n, amount := range tt.allocated {
+			assert.InDelta(t, amount, w.Trades[n].Dividend.Amount, 0.001, "%s trade %d", tt.name, n)
+		}
+	}
+}
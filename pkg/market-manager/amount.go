@@ -0,0 +1,12 @@
+package mm
+
+import "math"
+
+// amountPrecision is the precision the amount of stocks is stored with, 8 decimals
+const amountPrecision = 1e8
+
+// RoundAmount rounds the amount of stocks to the precision it is stored with, so the fractional amounts
+// bought and sold add up exactly and can be compared
+func RoundAmount(a float64) float64 {
+	return math.Round(a*amountPrecision) / amountPrecision
+}
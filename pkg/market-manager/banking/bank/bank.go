@@ -1,18 +1,88 @@
 package bank
 
 import (
+	"github.com/almerlucke/go-iban/iban"
+	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 )
 
 type AccountNoType string
 
+// AccountType tells what the account is used for
+type AccountType string
+
 type Account struct {
-	ID        uuid.UUID
-	Name      string
-	AccountNo string
-	Alias     string
+	ID            uuid.UUID
+	Name          string
+	AccountNo     string
+	Alias         string
+	AccountNoType AccountNoType
+	Type          AccountType
 }
 
 const (
 	IBAN AccountNoType = "iban"
+	Hash AccountNoType = "hash"
 )
+
+const (
+	Checking  AccountType = "checking"
+	Savings   AccountType = "savings"
+	Brokerage AccountType = "brokerage"
+	External  AccountType = "external"
+)
+
+// NewAccount creates a bank account. The IBAN account numbers are validated and kept in their print format
+func NewAccount(name, alias, accountNo string, accountNoType AccountNoType, accountType AccountType) (*Account, error) {
+	a := &Account{
+		ID:    uuid.NewV4(),
+		Name:  name,
+		Alias: alias,
+	}
+
+	if err := a.SetType(accountType); err != nil {
+		return nil, err
+	}
+
+	if err := a.SetAccountNo(accountNo, accountNoType); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// SetAccountNo changes the account number, validating it when it is an IBAN
+func (a *Account) SetAccountNo(accountNo string, accountNoType AccountNoType) error {
+	switch accountNoType {
+	case IBAN:
+		IBAN, err := iban.NewIBAN(accountNo)
+		if err != nil {
+			return errors.Wrapf(err, "invalid IBAN %q", accountNo)
+		}
+
+		accountNo = IBAN.PrintCode
+	case Hash:
+		if accountNo == "" {
+			return errors.New("missing account number")
+		}
+	default:
+		return errors.Errorf("account number type %q not supported", accountNoType)
+	}
+
+	a.AccountNo = accountNo
+	a.AccountNoType = accountNoType
+
+	return nil
+}
+
+// SetType changes the account type
+func (a *Account) SetType(accountType AccountType) error {
+	switch accountType {
+	case Checking, Savings, Brokerage, External:
+		a.Type = accountType
+
+		return nil
+	}
+
+	return errors.Errorf("account type %q not supported", accountType)
+}
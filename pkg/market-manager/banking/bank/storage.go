@@ -2,6 +2,12 @@ package bank
 
 type (
 	Finder interface {
+		FindAll() ([]*Account, error)
 		FindByAlias(alias string) (*Account, error)
+		FindByAccountNo(accountNo string) (*Account, error)
+	}
+
+	Persister interface {
+		Persist(a *Account) error
 	}
 )
@@ -0,0 +1,169 @@
+package statement
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+type (
+	camtDocument struct {
+		Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	}
+
+	camtStatement struct {
+		IBAN     string      `xml:"Acct>Id>IBAN"`
+		Other    string      `xml:"Acct>Id>Othr>Id"`
+		Currency string      `xml:"Acct>Ccy"`
+		Entries  []camtEntry `xml:"Ntry"`
+	}
+
+	camtEntry struct {
+		Amount       camtAmount  `xml:"Amt"`
+		CreditDebit  string      `xml:"CdtDbtInd"`
+		BookingDate  camtDate    `xml:"BookgDt"`
+		ValueDate    camtDate    `xml:"ValDt"`
+		Info         string      `xml:"AddtlNtryInf"`
+		Transactions []camtTxDtl `xml:"NtryDtls>TxDtls"`
+	}
+
+	camtAmount struct {
+		Currency string `xml:"Ccy,attr"`
+		Value    string `xml:",chardata"`
+	}
+
+	camtDate struct {
+		Date     string `xml:"Dt"`
+		DateTime string `xml:"DtTm"`
+	}
+
+	camtTxDtl struct {
+		DebtorName     string   `xml:"RltdPties>Dbtr>Nm"`
+		DebtorIBAN     string   `xml:"RltdPties>DbtrAcct>Id>IBAN"`
+		CreditorName   string   `xml:"RltdPties>Cdtr>Nm"`
+		CreditorIBAN   string   `xml:"RltdPties>CdtrAcct>Id>IBAN"`
+		Unstructured   []string `xml:"RmtInf>Ustrd"`
+		AdditionalInfo string   `xml:"AddtlTxInf"`
+	}
+)
+
+// ParseCAMT053 reads the ISO 20022 bank to customer statements (camt.053). The counterpart of an entry is the
+// debtor when the money comes in and the creditor when it goes out
+func ParseCAMT053(r io.Reader) ([]*Statement, error) {
+	var doc camtDocument
+
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "decoding camt.053")
+	}
+
+	var ss []*Statement
+	for _, cs := range doc.Statements {
+		currency, err := currencyFromCode(cs.Currency)
+		if err != nil {
+			return nil, err
+		}
+
+		accountNo := cs.IBAN
+		if accountNo == "" {
+			accountNo = cs.Other
+		}
+
+		s := &Statement{
+			AccountNo: NormalizeAccountNo(accountNo),
+			Currency:  currency,
+		}
+
+		for _, ce := range cs.Entries {
+			e, err := ce.entry(s)
+			if err != nil {
+				return nil, err
+			}
+
+			s.Entries = append(s.Entries, e)
+		}
+
+		ss = append(ss, s)
+	}
+
+	return ss, nil
+}
+
+func (ce camtEntry) entry(s *Statement) (*Entry, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(ce.Amount.Value), 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing camt.053 amount %q", ce.Amount.Value)
+	}
+
+	currency := s.Currency
+	if ce.Amount.Currency != "" {
+		if currency, err = currencyFromCode(ce.Amount.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	debit := ce.CreditDebit == "DBIT"
+	if debit {
+		amount = -amount
+	}
+
+	date, err := ce.BookingDate.time()
+	if err != nil || date.IsZero() {
+		if date, err = ce.ValueDate.time(); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &Entry{
+		Date:        date,
+		Amount:      mm.Value{Amount: amount, Currency: currency},
+		Description: ce.Info,
+	}
+
+	for _, tx := range ce.Transactions {
+		if debit {
+			e.Counterpart, e.CounterpartAccountNo = tx.CreditorName, tx.CreditorIBAN
+		} else {
+			e.Counterpart, e.CounterpartAccountNo = tx.DebtorName, tx.DebtorIBAN
+		}
+
+		if len(tx.Unstructured) > 0 {
+			e.Description = strings.Join(tx.Unstructured, " ")
+		} else if tx.AdditionalInfo != "" {
+			e.Description = tx.AdditionalInfo
+		}
+
+		if e.CounterpartAccountNo != "" {
+			break
+		}
+	}
+
+	if e.CounterpartAccountNo == "" {
+		e.CounterpartAccountNo = findIBAN(e.Description, s.AccountNo)
+	}
+
+	e.CounterpartAccountNo = NormalizeAccountNo(e.CounterpartAccountNo)
+
+	return e, nil
+}
+
+func (d camtDate) time() (time.Time, error) {
+	if d.Date != "" {
+		t, err := time.Parse("2006-01-02", d.Date)
+
+		return t, errors.Wrapf(err, "parsing camt.053 date %q", d.Date)
+	}
+
+	if len(d.DateTime) >= 19 {
+		t, err := time.Parse("2006-01-02T15:04:05", d.DateTime[:19])
+
+		return t, errors.Wrapf(err, "parsing camt.053 date time %q", d.DateTime)
+	}
+
+	return time.Time{}, nil
+}
@@ -0,0 +1,195 @@
+package statement
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+var (
+	mt940TagRegexp  = regexp.MustCompile(`^:([0-9]{2}[A-Z]?):(.*)$`)
+	mt940LineRegexp = regexp.MustCompile(`^([0-9]{6})([0-9]{4})?(R?[CD])([A-Z])?([0-9]+,[0-9]*)(.*)$`)
+)
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// ParseMT940 reads the SWIFT MT940 customer statements. The counterpart of an entry is looked for in the
+// information to account owner (:86:), either in the structured sub fields or as an IBAN in the free text
+func ParseMT940(r io.Reader) ([]*Statement, error) {
+	fields, err := readMT940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ss []*Statement
+		s  *Statement
+		e  *Entry
+	)
+
+	for _, f := range fields {
+		switch f.tag {
+		case "20":
+			s = &Statement{Currency: mm.Euro}
+			ss = append(ss, s)
+			e = nil
+		case "25":
+			if s == nil {
+				return nil, errors.New("mt940 account identification (:25:) out of a statement")
+			}
+
+			s.AccountNo = mt940AccountNo(f.value)
+		case "60F", "60M":
+			if s == nil || len(f.value) < 10 {
+				return nil, errors.Errorf("mt940 opening balance %q not valid", f.value)
+			}
+
+			if s.Currency, err = currencyFromCode(f.value[7:10]); err != nil {
+				return nil, err
+			}
+		case "61":
+			if s == nil {
+				return nil, errors.New("mt940 statement line (:61:) out of a statement")
+			}
+
+			if e, err = mt940Entry(s, f.value); err != nil {
+				return nil, err
+			}
+
+			s.Entries = append(s.Entries, e)
+		case "86":
+			if e == nil {
+				// information of the whole statement
+				continue
+			}
+
+			mt940Information(s, e, f.value)
+		}
+	}
+
+	return ss, nil
+}
+
+// readMT940Fields splits the statement in its fields, a field goes on in the lines without tag
+func readMT940Fields(r io.Reader) ([]*mt940Field, error) {
+	var (
+		fields []*mt940Field
+		last   *mt940Field
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if m := mt940TagRegexp.FindStringSubmatch(line); m != nil {
+			last = &mt940Field{tag: m[1], value: m[2]}
+			fields = append(fields, last)
+
+			continue
+		}
+
+		if last != nil && line != "-" && line != "" {
+			last.value += "\n" + line
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading mt940")
+	}
+
+	return fields, nil
+}
+
+// mt940AccountNo returns the account of the statement, the IBAN when it is given along with the currency or the bank code
+func mt940AccountNo(value string) string {
+	accountNo := NormalizeAccountNo(value)
+
+	// the currency of the account is written right after the IBAN by some banks
+	if len(accountNo) > 3 {
+		if IBAN := validIBAN(accountNo[:len(accountNo)-3]); IBAN != "" {
+			return IBAN
+		}
+	}
+
+	if IBAN := validIBAN(accountNo); IBAN != "" {
+		return IBAN
+	}
+
+	return accountNo
+}
+
+func mt940Entry(s *Statement, value string) (*Entry, error) {
+	m := mt940LineRegexp.FindStringSubmatch(strings.Replace(value, "\n", "", -1))
+	if m == nil {
+		return nil, errors.Errorf("mt940 statement line %q not valid", value)
+	}
+
+	date, err := time.Parse("060102", m[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing mt940 date %q", m[1])
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(m[5], ",", ".", 1), 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing mt940 amount %q", m[5])
+	}
+
+	// D debit, C credit, RD reversal of debit, RC reversal of credit
+	if m[3] == "D" || m[3] == "RC" {
+		amount = -amount
+	}
+
+	return &Entry{
+		Date:        date,
+		Amount:      mm.Value{Amount: amount, Currency: s.Currency},
+		Description: strings.TrimSpace(m[6]),
+	}, nil
+}
+
+// mt940Information fills the counterpart and description of the entry with the information to account owner.
+// The structured information splits in sub fields ?NN, ?20-?29 and ?60-?63 description, ?31 account and ?32-?33 name
+func mt940Information(s *Statement, e *Entry, value string) {
+	value = strings.Replace(value, "\n", "", -1)
+
+	if strings.Contains(value, "?") {
+		var description, name []string
+
+		for _, sf := range strings.Split(value, "?")[1:] {
+			if len(sf) < 2 {
+				continue
+			}
+
+			code, text := sf[:2], strings.TrimSpace(sf[2:])
+
+			switch {
+			case code >= "20" && code <= "29", code >= "60" && code <= "63":
+				description = append(description, text)
+			case code == "31":
+				e.CounterpartAccountNo = NormalizeAccountNo(text)
+			case code == "32", code == "33":
+				name = append(name, text)
+			}
+		}
+
+		e.Description = strings.Join(description, "")
+		e.Counterpart = strings.Join(name, "")
+	} else {
+		e.Description = value
+	}
+
+	if e.CounterpartAccountNo == "" {
+		if iban := findIBAN(value, s.AccountNo); iban != "" {
+			e.CounterpartAccountNo = iban
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package statement
+
+import (
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+// ParseOFX reads the Open Financial Exchange bank statements, either in the SGML (1.x) or the XML (2.x) flavour.
+// Since the SGML flavour does not close the elements holding values the file is read tag by tag
+func ParseOFX(r io.Reader) ([]*Statement, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading ofx")
+	}
+
+	var (
+		ss []*Statement
+		s  *Statement
+		e  *Entry
+		// aggregate holding the account read, BANKACCTFROM for the statement and BANKACCTTO for the entry
+		account string
+	)
+
+	for _, t := range ofxTokens(string(b)) {
+		switch t.tag {
+		case "STMTRS", "CCSTMTRS":
+			s = &Statement{Currency: mm.Euro}
+			ss = append(ss, s)
+		case "/STMTRS", "/CCSTMTRS":
+			s = nil
+		case "BANKACCTFROM", "CCACCTFROM", "BANKACCTTO", "CCACCTTO":
+			account = t.tag
+		case "/BANKACCTFROM", "/CCACCTFROM", "/BANKACCTTO", "/CCACCTTO":
+			account = ""
+		case "STMTTRN":
+			if s == nil {
+				return nil, errors.New("ofx transaction (STMTTRN) out of a statement")
+			}
+
+			e = &Entry{Amount: mm.Value{Currency: s.Currency}}
+		case "/STMTTRN":
+			if e == nil {
+				continue
+			}
+
+			if e.CounterpartAccountNo == "" {
+				e.CounterpartAccountNo = findIBAN(e.Counterpart+" "+e.Description, s.AccountNo)
+			}
+
+			s.Entries = append(s.Entries, e)
+			e = nil
+		case "CURDEF":
+			if s == nil {
+				continue
+			}
+
+			if s.Currency, err = currencyFromCode(t.value); err != nil {
+				return nil, err
+			}
+		case "ACCTID":
+			switch {
+			case e != nil && (account == "BANKACCTTO" || account == "CCACCTTO"):
+				e.CounterpartAccountNo = NormalizeAccountNo(t.value)
+			case s != nil && e == nil:
+				s.AccountNo = NormalizeAccountNo(t.value)
+			}
+		case "DTPOSTED":
+			if e == nil {
+				continue
+			}
+
+			if e.Date, err = parseOFXDate(t.value); err != nil {
+				return nil, err
+			}
+		case "TRNAMT":
+			if e == nil {
+				continue
+			}
+
+			if e.Amount.Amount, err = strconv.ParseFloat(strings.Replace(t.value, ",", ".", 1), 64); err != nil {
+				return nil, errors.Wrapf(err, "parsing ofx amount %q", t.value)
+			}
+		case "CURRENCY", "ORIGCURRENCY":
+			// the transaction was made in other currency than the account one, the amount keeps
+			// being in the currency of the account
+		case "NAME", "PAYEE":
+			if e != nil {
+				e.Counterpart = t.value
+			}
+		case "MEMO":
+			if e != nil {
+				e.Description = t.value
+			}
+		}
+	}
+
+	return ss, nil
+}
+
+type ofxToken struct {
+	tag   string
+	value string
+}
+
+// ofxTokens splits the document in its tags along with the value written right after them
+func ofxTokens(doc string) []ofxToken {
+	var tokens []ofxToken
+
+	for {
+		start := strings.Index(doc, "<")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(doc[start:], ">")
+		if end < 0 {
+			break
+		}
+
+		tag := strings.ToUpper(strings.TrimSpace(doc[start+1 : start+end]))
+		doc = doc[start+end+1:]
+
+		value := doc
+		if next := strings.Index(doc, "<"); next >= 0 {
+			value = doc[:next]
+		}
+
+		// skip the xml declaration and the processing instructions of the OFX 2.x header
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+
+		tokens = append(tokens, ofxToken{tag: tag, value: strings.TrimSpace(value)})
+	}
+
+	return tokens
+}
+
+// parseOFXDate reads the OFX datetime YYYYMMDD[HHMMSS[.XXX]][[gmt offset:tz name]], only the date is kept
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.Errorf("ofx date %q not valid", value)
+	}
+
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "parsing ofx date %q", value)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,133 @@
+package statement
+
+import (
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/almerlucke/go-iban/iban"
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+// Format of the bank statement files
+type Format string
+
+const (
+	CAMT053 Format = "camt053"
+	MT940   Format = "mt940"
+	OFX     Format = "ofx"
+)
+
+type (
+	// Statement holds the movements booked in a bank account
+	Statement struct {
+		AccountNo string
+		Currency  mm.Currency
+		Entries   []*Entry
+	}
+
+	// Entry is a movement of the statement. The amount is negative when the money goes out from the account
+	Entry struct {
+		Date                 time.Time
+		Amount               mm.Value
+		Counterpart          string
+		CounterpartAccountNo string
+		Description          string
+	}
+)
+
+// IsDebit tells whether the money goes out from the account
+func (e *Entry) IsDebit() bool {
+	return e.Amount.Amount < 0
+}
+
+// FormatFromFile returns the format of the statement file by its extension
+func FormatFromFile(file string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xml":
+		return CAMT053, nil
+	case ".sta", ".mt940", ".940":
+		return MT940, nil
+	case ".ofx", ".qfx":
+		return OFX, nil
+	}
+
+	return "", errors.Errorf("statement format of file %q unknown", file)
+}
+
+// Extensions returns the file extensions of the statement formats
+func Extensions() []string {
+	return []string{".xml", ".sta", ".mt940", ".940", ".ofx", ".qfx"}
+}
+
+// Parse reads the statements of the file in the format given
+func Parse(r io.Reader, format Format) ([]*Statement, error) {
+	switch format {
+	case CAMT053:
+		return ParseCAMT053(r)
+	case MT940:
+		return ParseMT940(r)
+	case OFX:
+		return ParseOFX(r)
+	}
+
+	return nil, errors.Errorf("statement format %q not supported", format)
+}
+
+// NormalizeAccountNo returns the account number without spaces and in upper case, as the IBAN electronic format
+func NormalizeAccountNo(accountNo string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(accountNo), ""))
+}
+
+// ibanRegexp matches the IBAN either in the print format, in groups of four characters, or in the electronic one
+var ibanRegexp = regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: [A-Z0-9]{4}){2,7}(?: [A-Z0-9]{1,3})?\b|\b[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}\b`)
+
+// findIBAN looks for an IBAN in a free text other than the one given, the statements formats without
+// a counterpart account field usually write it along with the name of the counterpart
+func findIBAN(text, exclude string) string {
+	exclude = NormalizeAccountNo(exclude)
+
+	for _, match := range ibanRegexp.FindAllString(strings.ToUpper(text), -1) {
+		accountNo := validIBAN(match)
+		if accountNo != "" && accountNo != exclude {
+			return accountNo
+		}
+	}
+
+	return ""
+}
+
+// validIBAN returns the IBAN of the match in its electronic format. The print format may take the word written
+// right after the IBAN as one more group, those are dropped until the IBAN is valid
+func validIBAN(match string) string {
+	for {
+		if IBAN, err := iban.NewIBAN(NormalizeAccountNo(match)); err == nil {
+			return IBAN.Code
+		}
+
+		i := strings.LastIndex(match, " ")
+		if i < 0 {
+			return ""
+		}
+
+		match = match[:i]
+	}
+}
+
+// currencyFromCode returns the currency of the ISO 4217 code, euro when it is empty
+func currencyFromCode(code string) (mm.Currency, error) {
+	if code == "" {
+		return mm.Euro, nil
+	}
+
+	c, ok := mm.CurrencyFromCode(code)
+	if !ok {
+		return "", errors.Errorf("currency %q not supported", code)
+	}
+
+	return c, nil
+}
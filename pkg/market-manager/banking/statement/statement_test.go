@@ -0,0 +1,295 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+const (
+	camt053Sample = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt>
+<Stmt>
+<Acct><Id><IBAN>ES91 2100 0418 4502 0005 1332</IBAN></Id><Ccy>EUR</Ccy></Acct>
+<Ntry>
+<Amt Ccy="EUR">1500.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><BookgDt><Dt>2018-03-01</Dt></BookgDt>
+<NtryDtls><TxDtls>
+<RltdPties><Cdtr><Nm>DEGIRO</Nm></Cdtr><CdtrAcct><Id><IBAN>NL91ABNA0417164300</IBAN></Id></CdtrAcct></RltdPties>
+<RmtInf><Ustrd>Deposit</Ustrd><Ustrd>march</Ustrd></RmtInf>
+</TxDtls></NtryDtls>
+</Ntry>
+<Ntry>
+<Amt Ccy="EUR">200.50</Amt><CdtDbtInd>CRDT</CdtDbtInd><ValDt><DtTm>2018-03-05T10:00:00+01:00</DtTm></ValDt>
+<AddtlNtryInf>Transfer from DE89 3704 0044 0532 0130 00 savings</AddtlNtryInf>
+</Ntry>
+</Stmt>
+</BkToCstmrStmt>
+</Document>`
+
+	mt940Sample = `:20:STARTUMSE
+:25:DE89370400440532013000EUR
+:28C:00001/001
+:60F:C180301EUR1000,00
+:61:1803010301D1500,00NTRFNONREF//123
+:86:166?00SEPA?20Deposit mar
+?21ch?31NL91ABNA0417164300?32DEGIRO
+:61:180305C200,50NTRFNONREF
+:86:Transfer from ES91 2100 0418 4502 0005 1332
+:62F:C180305EUR-299,50
+-`
+
+	ofxSGMLSample = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>EUR
+<BANKACCTFROM><BANKID>2100<ACCTID>ES9121000418450200051332</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20180301120000[+1:CET]<TRNAMT>-1500,00<NAME>DEGIRO<MEMO>Deposit march
+<BANKACCTTO><BANKID>ABNA<ACCTID>NL91ABNA0417164300</BANKACCTTO></STMTTRN>
+<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20180305<TRNAMT>200.50<NAME>Savings<MEMO>Transfer DE89 3704 0044 0532 0130 00</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+	ofxXMLSample = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+<CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+<CURDEF>USD</CURDEF>
+<CCACCTFROM><ACCTID>4111 1111 1111 1111</ACCTID></CCACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20180310</DTPOSTED><TRNAMT>-25.99</TRNAMT><PAYEE>Bookshop</PAYEE></STMTTRN>
+</BANKTRANLIST>
+</CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>`
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     Format
+		doc        string
+		statements []*Statement
+	}{
+		{
+			name:   "camt.053",
+			format: CAMT053,
+			doc:    camt053Sample,
+			statements: []*Statement{
+				{
+					AccountNo: "ES9121000418450200051332",
+					Currency:  mm.Euro,
+					Entries: []*Entry{
+						{
+							Date:                 time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: -1500, Currency: mm.Euro},
+							Counterpart:          "DEGIRO",
+							CounterpartAccountNo: "NL91ABNA0417164300",
+							Description:          "Deposit march",
+						},
+						{
+							Date:                 time.Date(2018, 3, 5, 10, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: 200.5, Currency: mm.Euro},
+							CounterpartAccountNo: "DE89370400440532013000",
+							Description:          "Transfer from DE89 3704 0044 0532 0130 00 savings",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "mt940",
+			format: MT940,
+			doc:    mt940Sample,
+			statements: []*Statement{
+				{
+					AccountNo: "DE89370400440532013000",
+					Currency:  mm.Euro,
+					Entries: []*Entry{
+						{
+							Date:                 time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: -1500, Currency: mm.Euro},
+							Counterpart:          "DEGIRO",
+							CounterpartAccountNo: "NL91ABNA0417164300",
+							Description:          "Deposit march",
+						},
+						{
+							Date:                 time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: 200.5, Currency: mm.Euro},
+							CounterpartAccountNo: "ES9121000418450200051332",
+							Description:          "Transfer from ES91 2100 0418 4502 0005 1332",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "ofx sgml",
+			format: OFX,
+			doc:    ofxSGMLSample,
+			statements: []*Statement{
+				{
+					AccountNo: "ES9121000418450200051332",
+					Currency:  mm.Euro,
+					Entries: []*Entry{
+						{
+							Date:                 time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: -1500, Currency: mm.Euro},
+							Counterpart:          "DEGIRO",
+							CounterpartAccountNo: "NL91ABNA0417164300",
+							Description:          "Deposit march",
+						},
+						{
+							Date:                 time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC),
+							Amount:               mm.Value{Amount: 200.5, Currency: mm.Euro},
+							Counterpart:          "Savings",
+							CounterpartAccountNo: "DE89370400440532013000",
+							Description:          "Transfer DE89 3704 0044 0532 0130 00",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:   "ofx xml",
+			format: OFX,
+			doc:    ofxXMLSample,
+			statements: []*Statement{
+				{
+					AccountNo: "4111111111111111",
+					Currency:  mm.Dollar,
+					Entries: []*Entry{
+						{
+							Date:        time.Date(2018, 3, 10, 0, 0, 0, 0, time.UTC),
+							Amount:      mm.Value{Amount: -25.99, Currency: mm.Dollar},
+							Counterpart: "Bookshop",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		ss, err := Parse(strings.NewReader(tt.doc), tt.format)
+		if assert.NoError(t, err, tt.name) {
+			assert.Equal(t, tt.statements, ss, tt.name)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		doc    string
+		err    string
+	}{
+		{
+			name:   "camt.053 currency",
+			format: CAMT053,
+			doc:    `<Document><BkToCstmrStmt><Stmt><Acct><Ccy>GBP</Ccy></Acct></Stmt></BkToCstmrStmt></Document>`,
+			err:    `currency "GBP" not supported`,
+		},
+		{
+			name:   "camt.053 amount",
+			format: CAMT053,
+			doc:    `<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1.500,00</Amt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+			err:    `parsing camt.053 amount "1.500,00": strconv.ParseFloat: parsing "1.500,00": invalid syntax`,
+		},
+		{
+			name:   "mt940 line out of a statement",
+			format: MT940,
+			doc:    ":61:180305C200,50NTRFNONREF",
+			err:    "mt940 statement line (:61:) out of a statement",
+		},
+		{
+			name:   "mt940 line",
+			format: MT940,
+			doc:    ":20:STARTUMSE\n:61:1803XXC200,50",
+			err:    `mt940 statement line "1803XXC200,50" not valid`,
+		},
+		{
+			name:   "mt940 opening balance",
+			format: MT940,
+			doc:    ":20:STARTUMSE\n:60F:C1803",
+			err:    `mt940 opening balance "C1803" not valid`,
+		},
+		{
+			name:   "ofx transaction out of a statement",
+			format: OFX,
+			doc:    "<OFX><STMTTRN><TRNAMT>1.00</STMTTRN></OFX>",
+			err:    "ofx transaction (STMTTRN) out of a statement",
+		},
+		{
+			name:   "ofx date",
+			format: OFX,
+			doc:    "<OFX><STMTRS><STMTTRN><DTPOSTED>201803</STMTTRN></STMTRS></OFX>",
+			err:    `ofx date "201803" not valid`,
+		},
+		{
+			name:   "format",
+			format: Format("qif"),
+			err:    `statement format "qif" not supported`,
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.doc), tt.format)
+		assert.EqualError(t, err, tt.err, tt.name)
+	}
+}
+
+func TestMT940Entry(t *testing.T) {
+	s := &Statement{Currency: mm.Euro}
+
+	tests := []struct {
+		line   string
+		amount float64
+	}{
+		{"180305C200,50NTRFNONREF", 200.5},
+		{"180305D200,NTRFNONREF", -200},
+		{"180305RC200,50NTRFNONREF", -200.5},
+		{"180305RD200,50NTRFNONREF", 200.5},
+		{"1803050306CR200,50NTRFNONREF", 200.5},
+	}
+
+	for _, tt := range tests {
+		e, err := mt940Entry(s, tt.line)
+		if assert.NoError(t, err, tt.line) {
+			assert.Equal(t, mm.Value{Amount: tt.amount, Currency: mm.Euro}, e.Amount, tt.line)
+			assert.Equal(t, time.Date(2018, 3, 5, 0, 0, 0, 0, time.UTC), e.Date, tt.line)
+		}
+	}
+}
+
+func TestFormatFromFile(t *testing.T) {
+	tests := []struct {
+		file   string
+		format Format
+	}{
+		{"statement.xml", CAMT053},
+		{"statement.STA", MT940},
+		{"statement.mt940", MT940},
+		{"statement.940", MT940},
+		{"statement.ofx", OFX},
+		{"statement.qfx", OFX},
+	}
+
+	for _, tt := range tests {
+		format, err := FormatFromFile(tt.file)
+		assert.NoError(t, err, tt.file)
+		assert.Equal(t, tt.format, format, tt.file)
+	}
+
+	_, err := FormatFromFile("statement.csv")
+	assert.EqualError(t, err, `statement format of file "statement.csv" unknown`)
+}
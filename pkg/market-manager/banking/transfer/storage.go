@@ -1,6 +1,17 @@
 package transfer
 
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
 type (
+	Finder interface {
+		FindAllByWallet(walletID uuid.UUID) ([]*Transfer, error)
+		FindAllByAccountsAndDate(fromID, toID uuid.UUID, date time.Time) ([]*Transfer, error)
+	}
+
 	Persister interface {
 		PersistAll(ts []*Transfer) error
 	}
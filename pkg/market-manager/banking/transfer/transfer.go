@@ -3,6 +3,7 @@ package transfer
 import (
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
@@ -10,11 +11,16 @@ import (
 )
 
 type (
+	// Transfer of money between two bank accounts. The amount is the money that leaves the from account, in its currency.
+	// The transfers in a currency other than euro hold the rate it was changed at, units of the currency per euro, and the
+	// fee charged for it, in the currency of the transfer.
 	Transfer struct {
 		ID     uuid.UUID
 		From   *bank.Account
 		To     *bank.Account
 		Amount mm.Value
+		Rate   float64
+		Fee    mm.Value
 		Date   time.Time
 	}
 )
@@ -24,7 +30,52 @@ func NewTransfer(From, To *bank.Account, amount float64, date time.Time) *Transf
 		ID:     uuid.NewV4(),
 		From:   From,
 		To:     To,
-		Amount: mm.Value{Amount: amount},
+		Amount: mm.Value{Amount: amount, Currency: mm.Euro},
+		Fee:    mm.Value{Currency: mm.Euro},
 		Date:   date,
 	}
 }
+
+// NewCurrencyTransfer creates a transfer in the currency given. The rate is required when the currency is not euro
+func NewCurrencyTransfer(From, To *bank.Account, amount, fee mm.Value, rate float64, date time.Time) (*Transfer, error) {
+	if amount.Currency == "" {
+		amount.Currency = mm.Euro
+	}
+
+	if amount.Currency != mm.Euro && rate <= 0 {
+		return nil, errors.Errorf("missing rate to change %s into %s", amount.Currency, mm.Euro)
+	}
+
+	t := NewTransfer(From, To, amount.Amount, date)
+	t.Amount = amount
+	t.Rate = rate
+	t.Fee = mm.Value{Amount: fee.Amount, Currency: amount.Currency}
+
+	return t, nil
+}
+
+// Sent returns in euro the money that leaves the from account
+func (t *Transfer) Sent() mm.Value {
+	return t.toEuro(t.Amount.Amount)
+}
+
+// Received returns in euro the money that reaches the to account, once the fee is charged
+func (t *Transfer) Received() mm.Value {
+	return t.toEuro(t.Amount.Amount - t.Fee.Amount)
+}
+
+// toEuro changes the amount into euro at the rate of the transfer, it is left in its currency without rate
+func (t *Transfer) toEuro(amount float64) mm.Value {
+	if t.Amount.Currency == "" || t.Amount.Currency == mm.Euro {
+		return mm.Value{Amount: amount, Currency: mm.Euro}
+	}
+
+	if t.Rate <= 0 {
+		return mm.Value{Amount: amount, Currency: t.Amount.Currency}
+	}
+
+	return mm.Value{
+		Amount:   amount / t.Rate,
+		Currency: mm.Euro,
+	}
+}
@@ -0,0 +1,158 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/watchlist"
+)
+
+type (
+	// Condition defines when a rule is triggered
+	Condition string
+
+	// Rule represents an alert rule applied to a stock or to every stock of a watchlist
+	Rule struct {
+		ID        uuid.UUID
+		Stock     *stock.Stock
+		Watchlist *watchlist.Watchlist
+		Condition Condition
+		// Value is the price for PriceBelow and the percentage for Near52WeekLow and DailyChangeAbove
+		Value float64
+		// LastTriggered is when the rule was last triggered, by stock id
+		LastTriggered map[uuid.UUID]time.Time
+	}
+
+	// Alert represents a notification about a stock
+	Alert struct {
+		Date    time.Time
+		Stock   *stock.Stock
+		Subject string
+		Message string
+	}
+)
+
+const (
+	PriceBelow       Condition = "price_below"
+	BelowBuyUnder    Condition = "below_buy_under"
+	Near52WeekLow    Condition = "near_52_week_low"
+	DailyChangeAbove Condition = "daily_change_above"
+
+	// DefaultNear52WeekLow is the percentage above the 52 week low considered near when the rule does not define one
+	DefaultNear52WeekLow = 5.0
+)
+
+// ParseCondition returns the condition, error when it is not one of the supported
+func ParseCondition(s string) (Condition, error) {
+	switch c := Condition(s); c {
+	case PriceBelow, BelowBuyUnder, Near52WeekLow, DailyChangeAbove:
+		return c, nil
+	}
+
+	return "", fmt.Errorf("alert condition %q not supported", s)
+}
+
+// NewRule creates a rule for a stock or for a watchlist, one of them must be nil
+func NewRule(stk *stock.Stock, w *watchlist.Watchlist, c Condition, value float64) *Rule {
+	if c == Near52WeekLow && value == 0 {
+		value = DefaultNear52WeekLow
+	}
+
+	return &Rule{
+		ID:            uuid.NewV4(),
+		Stock:         stk,
+		Watchlist:     w,
+		Condition:     c,
+		Value:         value,
+		LastTriggered: map[uuid.UUID]time.Time{},
+	}
+}
+
+// LastTriggeredAt returns when the rule was last triggered by any stock, zero when it never was
+func (r *Rule) LastTriggeredAt() time.Time {
+	var last time.Time
+
+	for _, t := range r.LastTriggered {
+		if t.After(last) {
+			last = t
+		}
+	}
+
+	return last
+}
+
+// Evaluate checks the rule against the current stock price. A rule is triggered at most once a day per stock
+func (r *Rule) Evaluate(s *stock.Stock, now time.Time) (Alert, bool) {
+	if s.Value.Amount <= 0 || sameDay(r.LastTriggered[s.ID], now) {
+		return Alert{}, false
+	}
+
+	var msg string
+
+	switch r.Condition {
+	case PriceBelow:
+		if s.Value.Amount >= r.Value {
+			return Alert{}, false
+		}
+
+		msg = fmt.Sprintf("%s price %.2f %s is below %.2f", s.Symbol, s.Value.Amount, s.Value.Currency, r.Value)
+	case BelowBuyUnder:
+		bu := s.BuyUnder()
+		if s.High52Week.Amount <= 0 || s.Value.Amount >= bu.Amount {
+			return Alert{}, false
+		}
+
+		msg = fmt.Sprintf("%s price %.2f %s is below buy under %.2f", s.Symbol, s.Value.Amount, s.Value.Currency, bu.Amount)
+	case Near52WeekLow:
+		if s.Low52Week.Amount <= 0 || s.Value.Amount > s.Low52Week.Amount*(1+r.Value/100) {
+			return Alert{}, false
+		}
+
+		msg = fmt.Sprintf(
+			"%s price %.2f %s is within %.2f%% of the 52 week low %.2f",
+			s.Symbol,
+			s.Value.Amount,
+			s.Value.Currency,
+			r.Value,
+			s.Low52Week.Amount,
+		)
+	case DailyChangeAbove:
+		prev := s.Value.Amount - s.Change.Amount
+		if prev <= 0 {
+			return Alert{}, false
+		}
+
+		change := s.Change.Amount * 100 / prev
+		if math.Abs(change) <= r.Value {
+			return Alert{}, false
+		}
+
+		msg = fmt.Sprintf("%s price %.2f %s changed %.2f%% today", s.Symbol, s.Value.Amount, s.Value.Currency, change)
+	default:
+		return Alert{}, false
+	}
+
+	if r.LastTriggered == nil {
+		r.LastTriggered = map[uuid.UUID]time.Time{}
+	}
+
+	r.LastTriggered[s.ID] = now
+
+	return Alert{
+		Date:    now,
+		Stock:   s,
+		Subject: fmt.Sprintf("%s %s", s.Symbol, r.Condition),
+		Message: msg,
+	}, true
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+
+	return ay == by && am == bm && ad == bd
+}
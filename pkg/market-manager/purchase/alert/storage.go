@@ -0,0 +1,19 @@
+package alert
+
+import uuid "github.com/satori/go.uuid"
+
+type (
+	Finder interface {
+		FindByID(ID uuid.UUID) (*Rule, error)
+		FindAll() ([]*Rule, error)
+		// FindAllByStock returns the rules of the stock and of the watchlists holding the stock
+		FindAllByStock(stockID uuid.UUID) ([]*Rule, error)
+	}
+
+	Persister interface {
+		Persist(r *Rule) error
+		Delete(r *Rule) error
+		// UpdateLastTriggered saves when the rule was last triggered by the stock
+		UpdateLastTriggered(r *Rule, stockID uuid.UUID) error
+	}
+)
@@ -0,0 +1,92 @@
+package etf
+
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	// Constituent represents a holding of an ETF and its weight (percentage) in the fund
+	Constituent struct {
+		ID      uuid.UUID
+		Name    string
+		Symbol  string
+		Sector  string
+		Country string
+		Weight  float64
+		Stock   *stock.Stock
+	}
+
+	// Holdings represents the constituents of an ETF
+	Holdings struct {
+		ETF          *stock.Stock
+		Constituents []*Constituent
+	}
+)
+
+func NewConstituent(name, symbol, sector, country string, weight float64) *Constituent {
+	return &Constituent{
+		ID:      uuid.NewV4(),
+		Name:    name,
+		Symbol:  symbol,
+		Sector:  sector,
+		Country: country,
+		Weight:  weight,
+	}
+}
+
+func NewHoldings(etf *stock.Stock) *Holdings {
+	return &Holdings{
+		ETF: etf,
+	}
+}
+
+// AddConstituent adds the constituent to the holdings
+func (h *Holdings) AddConstituent(c *Constituent) {
+	h.Constituents = append(h.Constituents, c)
+}
+
+// TotalWeight returns the sum of the weights of the constituents. The weights published by the
+// issuers rarely sum exactly 100, the total is used to spread the ETF position over the constituents
+func (h *Holdings) TotalWeight() float64 {
+	var total float64
+
+	for _, c := range h.Constituents {
+		total += c.Weight
+	}
+
+	return total
+}
+
+// SectorName returns the sector of the constituent, falling back to the sector of the linked stock
+func (c *Constituent) SectorName() string {
+	if c.Sector == "" && c.Stock != nil && c.Stock.Sector != nil {
+		return c.Stock.Sector.Name
+	}
+
+	return c.Sector
+}
+
+// CountryName returns the country of the constituent, falling back to the country of the linked stock
+func (c *Constituent) CountryName() string {
+	if c.Country == "" && c.Stock != nil {
+		return c.Stock.Country()
+	}
+
+	return c.Country
+}
+
+// StockName returns the name the constituent is reported with. Linked constituents use the name of
+// the stock so they are merged with the direct positions of the same stock
+func (c *Constituent) StockName() string {
+	if c.Stock != nil {
+		return c.Stock.Name
+	}
+
+	if c.Name == "" {
+		return c.Symbol
+	}
+
+	return c.Name
+}
@@ -0,0 +1,13 @@
+package etf
+
+import "github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+
+type (
+	Finder interface {
+		FindByETF(etf *stock.Stock) (*Holdings, error)
+	}
+
+	Persister interface {
+		Persist(h *Holdings) error
+	}
+)
@@ -18,3 +18,17 @@ func NewExchange(name, symbol string) *Exchange {
 		Symbol: symbol,
 	}
 }
+
+var exchangeCountry = map[string]string{
+	"NASDAQ": "US",
+	"NYSE":   "US",
+	"BME":    "ES",
+	"FRA":    "DE",
+	"BIT":    "IT",
+	"TSX":    "CA",
+}
+
+// Country returns the ISO 3166 code of the country where the exchange is, empty when it is not bound to a country
+func (e *Exchange) Country() string {
+	return exchangeCountry[e.Symbol]
+}
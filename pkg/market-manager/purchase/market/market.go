@@ -5,6 +5,7 @@ import "github.com/satori/go.uuid"
 const (
 	Stock          = "stock"
 	Cryptocurrency = "cryptocurrency"
+	Bond           = "bond"
 )
 
 // Market represents market struct
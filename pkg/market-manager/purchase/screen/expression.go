@@ -0,0 +1,343 @@
+package screen
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// Expression filters the stocks. It is built by Parse from a text like
+//
+//	dyield > 4 and per < 15 and (sector = "UTILITIES" or not industry ~ "gas")
+//
+// Number fields support = != < <= > >=, text fields = != and ~ (contains). Text comparisons are case insensitive.
+type Expression interface {
+	Match(s *stock.Stock) bool
+}
+
+type (
+	andExpression struct {
+		left, right Expression
+	}
+
+	orExpression struct {
+		left, right Expression
+	}
+
+	notExpression struct {
+		expr Expression
+	}
+
+	comparison struct {
+		field  field
+		op     string
+		number float64
+		text   string
+	}
+
+	tokenKind int
+
+	token struct {
+		kind tokenKind
+		text string
+		pos  int
+	}
+
+	parser struct {
+		tokens []token
+		pos    int
+	}
+)
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+func (e andExpression) Match(s *stock.Stock) bool {
+	return e.left.Match(s) && e.right.Match(s)
+}
+
+func (e orExpression) Match(s *stock.Stock) bool {
+	return e.left.Match(s) || e.right.Match(s)
+}
+
+func (e notExpression) Match(s *stock.Stock) bool {
+	return !e.expr.Match(s)
+}
+
+func (c comparison) Match(s *stock.Stock) bool {
+	if c.field.kind == textField {
+		v := strings.ToLower(c.field.text(s))
+
+		switch c.op {
+		case "=":
+			return v == c.text
+		case "!=":
+			return v != c.text
+		case "~":
+			return strings.Contains(v, c.text)
+		}
+
+		return false
+	}
+
+	v := c.field.number(s)
+
+	switch c.op {
+	case "=":
+		return v == c.number
+	case "!=":
+		return v != c.number
+	case "<":
+		return v < c.number
+	case "<=":
+		return v <= c.number
+	case ">":
+		return v > c.number
+	case ">=":
+		return v >= c.number
+	}
+
+	return false
+}
+
+// Parse builds the expression from the text. The error tells the position where the text is not valid
+func Parse(input string) (Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errors.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") {
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = orExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") {
+		p.next()
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = andExpression{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot() (Expression, error) {
+	if p.isKeyword("not") {
+		p.next()
+
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		return notExpression{expr: expr}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if r := p.next(); r.kind != tokenRParen {
+			return nil, errors.Errorf("expected \")\" at position %d", r.pos)
+		}
+
+		return expr, nil
+	case tokenIdent:
+		return p.parseComparison(t)
+	case tokenEOF:
+		return nil, errors.New("unexpected end of expression")
+	}
+
+	return nil, errors.Errorf("expected field at position %d, got %q", t.pos, t.text)
+}
+
+func (p *parser) parseComparison(ident token) (Expression, error) {
+	f, ok := fields[strings.ToLower(ident.text)]
+	if !ok {
+		return nil, errors.Errorf("unknown field %q at position %d", ident.text, ident.pos)
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, errors.Errorf("expected operator after %q at position %d", ident.text, op.pos)
+	}
+
+	value := p.next()
+
+	if f.kind == textField {
+		if op.text != "=" && op.text != "!=" && op.text != "~" {
+			return nil, errors.Errorf("operator %q not supported by text field %q at position %d", op.text, ident.text, op.pos)
+		}
+
+		if value.kind != tokenString {
+			return nil, errors.Errorf("expected quoted text for field %q at position %d", ident.text, value.pos)
+		}
+
+		return comparison{field: f, op: op.text, text: strings.ToLower(value.text)}, nil
+	}
+
+	if op.text == "~" {
+		return nil, errors.Errorf("operator \"~\" not supported by number field %q at position %d", ident.text, op.pos)
+	}
+
+	if value.kind != tokenNumber {
+		return nil, errors.Errorf("expected number for field %q at position %d", ident.text, value.pos)
+	}
+
+	n, err := strconv.ParseFloat(value.text, 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid number %q at position %d", value.text, value.pos)
+	}
+
+	return comparison{field: f, op: op.text, number: n}, nil
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	rs := []rune(input)
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for j < len(rs) && rs[j] != r {
+				j++
+			}
+
+			if j == len(rs) {
+				return nil, errors.Errorf("unterminated text at position %d", i)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: string(rs[i+1 : j]), pos: i})
+			i = j + 1
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if r != '~' && i+1 < len(rs) && rs[i+1] == '=' {
+				op += "="
+			}
+
+			switch op {
+			case "==":
+				op = "="
+			case "!":
+				return nil, errors.Errorf("unexpected \"!\" at position %d", i)
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+			if op == "=" && i < len(rs) && rs[i] == '=' {
+				i++
+			}
+		case unicode.IsDigit(r) || r == '-' || r == '.':
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: string(rs[i:j]), pos: i})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: string(rs[i:j]), pos: i})
+			i = j
+		default:
+			return nil, errors.Errorf("unexpected %q at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(rs)}), nil
+}
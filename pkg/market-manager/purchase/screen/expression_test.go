@@ -0,0 +1,135 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+func testStock() *stock.Stock {
+	return &stock.Stock{
+		Name:          "Enagas",
+		Symbol:        "ENG",
+		Value:         mm.Value{Amount: 21.5, Currency: mm.Euro},
+		DividendYield: 6.8,
+		PER:           12.4,
+		Sector:        &stock.Info{Name: "Utilities"},
+		Industry:      &stock.Info{Name: "Gas Utilities"},
+	}
+}
+
+func TestParseMatch(t *testing.T) {
+	tests := []struct {
+		expression string
+		match      bool
+	}{
+		{"dyield > 4", true},
+		{"dyield >= 6.8", true},
+		{"dyield < 6.8", false},
+		{"dyield <= 6.8", true},
+		{"per = 12.4", true},
+		{"per == 12.4", true},
+		{"per != 12.4", false},
+		{"price > -1", true},
+		{"symbol = \"eng\"", true},
+		{"name != 'Enagas'", false},
+		{"industry ~ \"GAS\"", true},
+		{"sector = \"UTILITIES\" and per < 10", false},
+		{"per < 10 or sector = \"utilities\"", true},
+		{"not industry ~ \"gas\"", false},
+		{"not not industry ~ \"gas\"", true},
+		{"dyield > 4 and per < 15 and (sector = \"ENERGY\" or not industry ~ \"oil\")", true},
+		{"dyield > 10 or per < 15 and sector = \"ENERGY\"", false},
+		{"(dyield > 10 or per < 15) and sector = \"UTILITIES\"", true},
+		{"DYIELD > 4 AND Per < 15", true},
+		{"exchange = \"\" and type = \"\"", true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.expression)
+		if !assert.NoError(t, err, tt.expression) {
+			continue
+		}
+
+		assert.Equal(t, tt.match, expr.Match(testStock()), tt.expression)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		expression string
+		err        string
+	}{
+		{"", "unexpected end of expression"},
+		{"dyield >", "expected number for field \"dyield\" at position 8"},
+		{"dividend > 4", "unknown field \"dividend\" at position 0"},
+		{"dyield 4", "expected operator after \"dyield\" at position 7"},
+		{"dyield ~ 4", "operator \"~\" not supported by number field \"dyield\" at position 7"},
+		{"dyield > \"4\"", "expected number for field \"dyield\" at position 9"},
+		{"dyield > 4.2.1", "invalid number \"4.2.1\" at position 9"},
+		{"sector > \"a\"", "operator \">\" not supported by text field \"sector\" at position 7"},
+		{"sector = utilities", "expected quoted text for field \"sector\" at position 9"},
+		{"sector = \"utilities", "unterminated text at position 9"},
+		{"(dyield > 4", "expected \")\" at position 11"},
+		{"dyield > 4)", "unexpected \")\" at position 10"},
+		{"dyield > 4 per < 15", "unexpected \"per\" at position 11"},
+		{"and dyield > 4", "unknown field \"and\" at position 0"},
+		{"> 4", "expected field at position 0, got \">\""},
+		{"dyield ! 4", "unexpected \"!\" at position 7"},
+		{"dyield > 4 & per < 15", "unexpected '&' at position 11"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.expression)
+		if assert.Error(t, err, tt.expression) {
+			assert.Equal(t, tt.err, err.Error(), tt.expression)
+		}
+	}
+}
+
+func TestScreenRun(t *testing.T) {
+	stks := []*stock.Stock{
+		{Name: "Red Electrica", DividendYield: 5.1},
+		{Name: "abertis", DividendYield: 4.3},
+		{Name: "Enagas", DividendYield: 6.8},
+		{Name: "Inditex", DividendYield: 2.5},
+	}
+
+	names := func(stks []*stock.Stock) []string {
+		var ns []string
+		for _, s := range stks {
+			ns = append(ns, s.Name)
+		}
+
+		return ns
+	}
+
+	tests := []struct {
+		sort  string
+		desc  bool
+		limit int
+		names []string
+	}{
+		{"", false, 0, []string{"abertis", "Enagas", "Red Electrica"}},
+		{"dyield", false, 0, []string{"abertis", "Red Electrica", "Enagas"}},
+		{"dyield", true, 2, []string{"Enagas", "Red Electrica"}},
+		{"NAME", true, 0, []string{"Red Electrica", "Enagas", "abertis"}},
+	}
+
+	for _, tt := range tests {
+		s, err := NewScreen("yield", "dyield > 4", tt.sort, tt.desc, tt.limit)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		mstks, err := s.Run(stks)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.names, names(mstks), tt.sort)
+	}
+
+	_, err := NewScreen("yield", "dyield > 4", "dividend", false, 0)
+	assert.EqualError(t, err, "unknown sort field \"dividend\"")
+}
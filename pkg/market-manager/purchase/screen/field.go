@@ -0,0 +1,112 @@
+package screen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+type (
+	fieldKind int
+
+	// field is a stored stock value the expressions can filter and sort by
+	field struct {
+		kind   fieldKind
+		number func(s *stock.Stock) float64
+		text   func(s *stock.Stock) string
+	}
+)
+
+const (
+	numberField fieldKind = iota
+	textField
+)
+
+var fields = map[string]field{
+	"price":      numberFn(func(s *stock.Stock) float64 { return s.Value.Amount }),
+	"change":     numberFn(func(s *stock.Stock) float64 { return s.Change.Amount }),
+	"high52week": numberFn(func(s *stock.Stock) float64 { return s.High52Week.Amount }),
+	"low52week":  numberFn(func(s *stock.Stock) float64 { return s.Low52Week.Amount }),
+	"buyunder":   numberFn(func(s *stock.Stock) float64 { return s.BuyUnder().Amount }),
+	"dyield":     numberFn(func(s *stock.Stock) float64 { return s.DividendYield }),
+	"eps":        numberFn(func(s *stock.Stock) float64 { return s.EPS }),
+	"per":        numberFn(func(s *stock.Stock) float64 { return s.PER }),
+	"hv20day":    numberFn(func(s *stock.Stock) float64 { return s.HV20Day }),
+	"hv52week":   numberFn(func(s *stock.Stock) float64 { return s.HV52Week }),
+	"symbol":     textFn(func(s *stock.Stock) string { return s.Symbol }),
+	"name":       textFn(func(s *stock.Stock) string { return s.Name }),
+	"exchange": textFn(func(s *stock.Stock) string {
+		if s.Exchange == nil {
+			return ""
+		}
+
+		return s.Exchange.Symbol
+	}),
+	"market": textFn(func(s *stock.Stock) string {
+		if s.Market == nil {
+			return ""
+		}
+
+		return s.Market.Name
+	}),
+	"type":     textFn(func(s *stock.Stock) string { return infoName(s.Type) }),
+	"sector":   textFn(func(s *stock.Stock) string { return infoName(s.Sector) }),
+	"industry": textFn(func(s *stock.Stock) string { return infoName(s.Industry) }),
+}
+
+func numberFn(fn func(s *stock.Stock) float64) field {
+	return field{kind: numberField, number: fn}
+}
+
+func textFn(fn func(s *stock.Stock) string) field {
+	return field{kind: textField, text: fn}
+}
+
+func infoName(i *stock.Info) string {
+	if i == nil {
+		return ""
+	}
+
+	return i.Name
+}
+
+// Fields returns the name of the fields the expressions can use, sorted
+func Fields() []string {
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// SortStocks sorts the stocks by the field, text fields are compared case insensitive
+func SortStocks(stks []*stock.Stock, by string, desc bool) error {
+	f, ok := fields[strings.ToLower(by)]
+	if !ok {
+		return errors.Errorf("unknown sort field %q", by)
+	}
+
+	less := func(i, j int) bool {
+		if f.kind == numberField {
+			return f.number(stks[i]) < f.number(stks[j])
+		}
+
+		return strings.ToLower(f.text(stks[i])) < strings.ToLower(f.text(stks[j]))
+	}
+
+	if desc {
+		sort.SliceStable(stks, func(i, j int) bool { return less(j, i) })
+
+		return nil
+	}
+
+	sort.SliceStable(stks, less)
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package screen
+
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// Screen represents a stock screen stored by name to be run again
+type Screen struct {
+	ID         uuid.UUID
+	Name       string
+	Expression string
+	// Sort is the field the stocks are sorted by, by name when empty
+	Sort string
+	// Desc sorts the stocks in descending order
+	Desc bool
+	// Limit is the maximum number of stocks, no limit when 0
+	Limit int
+}
+
+// NewScreen creates a screen, the expression is validated
+func NewScreen(name, expression, sort string, desc bool, limit int) (*Screen, error) {
+	s := &Screen{
+		ID:         uuid.NewV4(),
+		Name:       name,
+		Expression: expression,
+		Sort:       sort,
+		Desc:       desc,
+		Limit:      limit,
+	}
+
+	if _, err := s.Run(nil); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Run returns the stocks matching the expression sorted and limited
+func (s *Screen) Run(stks []*stock.Stock) ([]*stock.Stock, error) {
+	expr, err := Parse(s.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var mstks []*stock.Stock
+	for _, stk := range stks {
+		if expr.Match(stk) {
+			mstks = append(mstks, stk)
+		}
+	}
+
+	by := s.Sort
+	if by == "" {
+		by = "name"
+	}
+
+	if err := SortStocks(mstks, by, s.Desc); err != nil {
+		return nil, err
+	}
+
+	if s.Limit > 0 && len(mstks) > s.Limit {
+		mstks = mstks[:s.Limit]
+	}
+
+	return mstks, nil
+}
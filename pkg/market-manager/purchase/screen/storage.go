@@ -0,0 +1,13 @@
+package screen
+
+type (
+	Finder interface {
+		FindByName(name string) (*Screen, error)
+		FindAll() ([]*Screen, error)
+	}
+
+	Persister interface {
+		Persist(s *Screen) error
+		Delete(s *Screen) error
+	}
+)
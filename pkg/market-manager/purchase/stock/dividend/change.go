@@ -0,0 +1,170 @@
+package dividend
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+)
+
+type (
+	ChangeType string
+
+	// Change represents a difference between the stored and the scraped dividends of a stock
+	Change struct {
+		StockID    uuid.UUID
+		Type       ChangeType
+		ExDate     time.Time
+		Previous   mm.Value
+		Amount     mm.Value
+		DetectedAt time.Time
+	}
+)
+
+const (
+	NewAnnouncement ChangeType = "new"
+	Raised          ChangeType = "raised"
+	Cut             ChangeType = "cut"
+	Suspended       ChangeType = "suspended"
+)
+
+// Compare detects the changes between the stored and the scraped dividends of a stock:
+//   - a scraped announcement not stored before is new, raised or cut compared with the previous dividend
+//   - an announcement stored with a different amount is raised or cut
+//   - the upcoming stored dividends missing from the scraped ones are suspended, only when withFuture is true
+//     because otherwise the upcoming dividends could not be scraped
+//
+// Nothing is detected when there are no stored dividends, it is the first time the dividends are scraped.
+func Compare(stockID uuid.UUID, stored, scraped []StockDividend, withFuture bool, now time.Time) []Change {
+	if len(stored) == 0 {
+		return nil
+	}
+
+	var cs []Change
+
+	storedByExDate := map[time.Time]StockDividend{}
+	for _, d := range stored {
+		storedByExDate[d.ExDate] = d
+	}
+
+	for _, d := range scraped {
+		if d.Status != Announced {
+			continue
+		}
+
+		if s, ok := storedByExDate[d.ExDate]; ok && s.Status != Projected {
+			if c, ok := compareAmount(stockID, s.Amount, d, now); ok {
+				cs = append(cs, c)
+			}
+
+			continue
+		}
+
+		prev, ok := previous(stored, d.ExDate)
+		if !ok {
+			cs = append(cs, Change{
+				StockID:    stockID,
+				Type:       NewAnnouncement,
+				ExDate:     d.ExDate,
+				Amount:     d.Amount,
+				DetectedAt: now,
+			})
+
+			continue
+		}
+
+		c, ok := compareAmount(stockID, prev.Amount, d, now)
+		if !ok {
+			c = Change{
+				StockID:    stockID,
+				Type:       NewAnnouncement,
+				ExDate:     d.ExDate,
+				Previous:   prev.Amount,
+				Amount:     d.Amount,
+				DetectedAt: now,
+			}
+		}
+
+		cs = append(cs, c)
+	}
+
+	if !withFuture {
+		return cs
+	}
+
+	for _, d := range scraped {
+		if !d.ExDate.Before(now) {
+			return cs
+		}
+	}
+
+	var upcoming []StockDividend
+	for _, d := range stored {
+		if !d.ExDate.Before(now) {
+			upcoming = append(upcoming, d)
+		}
+	}
+
+	if len(upcoming) == 0 {
+		return cs
+	}
+
+	sort.Slice(upcoming, func(i, j int) bool {
+		return upcoming[i].ExDate.Before(upcoming[j].ExDate)
+	})
+
+	return append(cs, Change{
+		StockID:    stockID,
+		Type:       Suspended,
+		ExDate:     upcoming[0].ExDate,
+		Previous:   upcoming[0].Amount,
+		Amount:     mm.Value{Currency: upcoming[0].Amount.Currency},
+		DetectedAt: now,
+	})
+}
+
+func compareAmount(stockID uuid.UUID, prev mm.Value, d StockDividend, now time.Time) (Change, bool) {
+	// amounts are stored with 4 decimals
+	diff := d.Amount.Amount - prev.Amount
+	if math.Abs(diff) < 0.00005 {
+		return Change{}, false
+	}
+
+	t := Raised
+	if diff < 0 {
+		t = Cut
+	}
+
+	return Change{
+		StockID:    stockID,
+		Type:       t,
+		ExDate:     d.ExDate,
+		Previous:   prev,
+		Amount:     d.Amount,
+		DetectedAt: now,
+	}, true
+}
+
+// previous returns the latest announced or payed dividend before the ex-date
+func previous(ds []StockDividend, exDate time.Time) (StockDividend, bool) {
+	var (
+		prev  StockDividend
+		found bool
+	)
+
+	for _, d := range ds {
+		if d.Status == Projected || !d.ExDate.Before(exDate) {
+			continue
+		}
+
+		if !found || d.ExDate.After(prev.ExDate) {
+			prev = d
+			found = true
+		}
+	}
+
+	return prev, found
+}
@@ -14,11 +14,14 @@ type (
 		FindUpcoming(ID uuid.UUID) (StockDividend, error)
 		FindAllDividendsFromThisYearOn(ID uuid.UUID, year int) ([]StockDividend, error)
 		FindAllDividendsFromThisYearAndMontOn(ID uuid.UUID, year, month int) ([]StockDividend, error)
+		// Find the dividend changes detected since the date
+		FindAllChangesFrom(from time.Time) ([]Change, error)
 	}
 
 	Persister interface {
 		PersistAll(stockID uuid.UUID, ds []StockDividend) error
 		DeleteAllFromStatus(stockID uuid.UUID, status Status) error
 		DeleteAll(stockID uuid.UUID) error
+		PersistChanges(cs []Change) error
 	}
 )
@@ -0,0 +1,152 @@
+package stock
+
+import (
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+// FixedIncome represents the terms of a fixed income instrument, a bond or a treasury bill.
+// The prices are quoted as percentage of the face value.
+type FixedIncome struct {
+	FaceValue mm.Value
+	// CouponRate annual percentage of the face value paid as coupons
+	CouponRate float64
+	// CouponFrequency number of coupons paid per year. Zero for the zero coupon instruments as the treasury bills
+	CouponFrequency int
+	IssueDate       time.Time
+	Maturity        time.Time
+	CleanPrice      float64
+}
+
+// IsZeroCoupon tells whether the instrument pays no coupon, the return comes from buying below the face value
+func (f *FixedIncome) IsZeroCoupon() bool {
+	return f.CouponFrequency <= 0 || f.CouponRate <= 0
+}
+
+// Coupon returns the amount of each coupon paid per instrument
+func (f *FixedIncome) Coupon() mm.Value {
+	if f.IsZeroCoupon() {
+		return mm.Value{Currency: f.FaceValue.Currency}
+	}
+
+	return mm.Value{
+		Amount:   f.FaceValue.Amount * f.CouponRate / 100 / float64(f.CouponFrequency),
+		Currency: f.FaceValue.Currency,
+	}
+}
+
+// CouponDates returns the schedule of the coupons, from the first one after the issue date to the maturity.
+// The dates are computed back from the maturity every 12 / frequency months, the ones of the month end are
+// kept at the month end. Without issue date the schedule is unknown and no coupon is returned.
+func (f *FixedIncome) CouponDates() []time.Time {
+	if f.IsZeroCoupon() || f.IssueDate.IsZero() {
+		return nil
+	}
+
+	months := 12 / f.CouponFrequency
+	if months < 1 {
+		months = 1
+	}
+
+	var dates []time.Time
+	for n := 0; ; n++ {
+		dt := addMonths(f.Maturity, -months*n)
+		if !dt.After(f.IssueDate) {
+			break
+		}
+
+		dates = append([]time.Time{dt}, dates...)
+	}
+
+	return dates
+}
+
+// addMonths adds the months to the date keeping the day, or the last day of the month when the month is shorter
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+
+	first := time.Date(y, m+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+
+	return first.AddDate(0, 0, d-1)
+}
+
+// Coupons returns the coupon schedule as the dividends paid by the instrument
+func (f *FixedIncome) Coupons() []dividend.StockDividend {
+	var ds []dividend.StockDividend
+
+	c := f.Coupon()
+	for _, dt := range f.CouponDates() {
+		ds = append(ds, dividend.StockDividend{
+			ExDate:      dt,
+			PaymentDate: dt,
+			RecordDate:  dt,
+			// the coupons are known since the issue
+			Status: dividend.Announced,
+			Amount: c,
+		})
+	}
+
+	return ds
+}
+
+// AccruedInterest returns the interest accrued per instrument at the date since the last coupon paid
+func (f *FixedIncome) AccruedInterest(date time.Time) mm.Value {
+	accrued := mm.Value{Currency: f.FaceValue.Currency}
+
+	if f.IsZeroCoupon() || f.IssueDate.IsZero() || date.Before(f.IssueDate) || !date.Before(f.Maturity) {
+		return accrued
+	}
+
+	prev := f.IssueDate
+	next := f.Maturity
+
+	for _, dt := range f.CouponDates() {
+		if !dt.After(date) {
+			prev = dt
+
+			continue
+		}
+
+		next = dt
+
+		break
+	}
+
+	period := next.Sub(prev).Hours()
+	if period <= 0 {
+		return accrued
+	}
+
+	accrued.Amount = f.Coupon().Amount * date.Sub(prev).Hours() / period
+
+	return accrued
+}
+
+// CleanValue returns the clean price per instrument
+func (f *FixedIncome) CleanValue() mm.Value {
+	return mm.Value{
+		Amount:   f.FaceValue.Amount * f.CleanPrice / 100,
+		Currency: f.FaceValue.Currency,
+	}
+}
+
+// DirtyValue returns the price per instrument paid at the date, the clean price plus the accrued interest
+func (f *FixedIncome) DirtyValue(date time.Time) mm.Value {
+	v := f.CleanValue()
+
+	return v.Increase(f.AccruedInterest(date))
+}
+
+// DirtyPrice returns the dirty price at the date as percentage of the face value
+func (f *FixedIncome) DirtyPrice(date time.Time) float64 {
+	if f.FaceValue.Amount == 0 {
+		return 0
+	}
+
+	return f.DirtyValue(date).Amount * 100 / f.FaceValue.Amount
+}
@@ -1,8 +1,10 @@
 package stock
 
 import (
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 
 	"github.com/dohernandez/market-manager/pkg/market-manager"
@@ -27,6 +29,7 @@ type (
 		Exchange              *exchange.Exchange
 		Name                  string
 		Symbol                string
+		ISIN                  string
 		Value                 mm.Value
 		Dividends             []dividend.StockDividend
 		DividendYield         float64
@@ -40,10 +43,12 @@ type (
 		Industry              *Info
 		EPS                   float64
 		PER                   float64
+		BookValue             float64
 		Description           string
 		PriceVolatilityUpdate time.Time
 		HV20Day               float64
 		HV52Week              float64
+		FixedIncome           *FixedIncome
 	}
 
 	// Price represents stock's price struct
@@ -85,6 +90,14 @@ type (
 )
 
 const (
+	// CryptocurrencyPairSeparator separates the base and the quote currency in the symbol of a cryptocurrency
+	CryptocurrencyPairSeparator = "-"
+
+	// ETFType is the stock type name of the exchange traded funds
+	ETFType = "ETF"
+	// BondType is the stock type name of the fixed income instruments
+	BondType = "BOND"
+
 	StockInfoType     InfoType = "type"
 	StockInfoSector            = "sector"
 	StockInfoIndustry          = "industry"
@@ -156,6 +169,146 @@ func (s *Stock) BuyUnder() mm.Value {
 	}
 }
 
+// NewCryptocurrency creates a cryptocurrency instance quoted in the quote currency, the symbol is the pair BASE-QUOTE
+func NewCryptocurrency(market *market.Market, exchange *exchange.Exchange, name, base, quote string) *Stock {
+	return &Stock{
+		ID:                  uuid.NewV4(),
+		Market:              market,
+		Exchange:            exchange,
+		Name:                name,
+		Symbol:              strings.ToUpper(base + CryptocurrencyPairSeparator + quote),
+		Type:                NewStockInfo("CRYPTOCURRENCY", StockInfoType),
+		Sector:              NewStockInfo("", StockInfoSector),
+		Industry:            NewStockInfo("", StockInfoIndustry),
+		LastPriceUpdate:     time.Time{},
+		HighLow52WeekUpdate: time.Time{},
+	}
+}
+
+// NewBond creates a fixed income instrument instance, a bond or a treasury bill, valued at the clean price
+func NewBond(market *market.Market, exchange *exchange.Exchange, name, symbol string, fi *FixedIncome) *Stock {
+	s := &Stock{
+		ID:                  uuid.NewV4(),
+		Market:              market,
+		Exchange:            exchange,
+		Name:                name,
+		Symbol:              strings.ToUpper(symbol),
+		Type:                NewStockInfo(BondType, StockInfoType),
+		Sector:              NewStockInfo("", StockInfoSector),
+		Industry:            NewStockInfo("", StockInfoIndustry),
+		FixedIncome:         fi,
+		LastPriceUpdate:     time.Time{},
+		HighLow52WeekUpdate: time.Time{},
+	}
+
+	fi.FaceValue.Currency = s.QuoteCurrency()
+	s.Value = fi.CleanValue()
+
+	return s
+}
+
+// SetISIN sets the ISIN of the stock, the code is validated with its check digit
+func (s *Stock) SetISIN(isin string) error {
+	isin = strings.ToUpper(strings.TrimSpace(isin))
+	if !IsISIN(isin) {
+		return errors.Errorf("ISIN %q not valid", isin)
+	}
+
+	s.ISIN = isin
+
+	return nil
+}
+
+// IsISIN tells whether the code is a valid ISIN, two letters country code, nine alphanumeric characters
+// and the check digit computed with the Luhn algorithm over the digits of the code, letters as 10 to 35
+func IsISIN(code string) bool {
+	if len(code) != 12 {
+		return false
+	}
+
+	var digits []int
+	for i, c := range code {
+		switch {
+		case c >= '0' && c <= '9':
+			if i < 2 {
+				return false
+			}
+
+			digits = append(digits, int(c-'0'))
+		case c >= 'A' && c <= 'Z':
+			if i == 11 {
+				return false
+			}
+
+			n := int(c-'A') + 10
+			digits = append(digits, n/10, n%10)
+		default:
+			return false
+		}
+	}
+
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+	}
+
+	return sum%10 == 0
+}
+
+// IsBond returns whether the stock is a fixed income instrument
+func (s *Stock) IsBond() bool {
+	return s.FixedIncome != nil
+}
+
+// IsCryptocurrency returns whether the stock is a cryptocurrency pair
+func (s *Stock) IsCryptocurrency() bool {
+	return s.Market != nil && s.Market.Name == market.Cryptocurrency
+}
+
+// QuoteCurrency returns the currency the stock is priced in. For a cryptocurrency it is the quote currency of the pair.
+func (s *Stock) QuoteCurrency() mm.Currency {
+	if s.IsCryptocurrency() {
+		i := strings.LastIndex(s.Symbol, CryptocurrencyPairSeparator)
+		if c, ok := mm.CurrencyFromCode(s.Symbol[i+1:]); ok {
+			return c
+		}
+
+		return mm.Euro
+	}
+
+	if s.Exchange == nil {
+		return mm.Euro
+	}
+
+	if s.Exchange.Symbol == "TSX" {
+		return mm.CanadianDollar
+	}
+
+	return mm.ExchangeCurrency(s.Exchange.Symbol)
+}
+
+// IsETF returns whether the stock is an exchange traded fund
+func (s *Stock) IsETF() bool {
+	return s.Type != nil && strings.ToUpper(s.Type.Name) == ETFType
+}
+
+// Country returns the country of the stock based on the exchange it is listed on
+func (s *Stock) Country() string {
+	if s.IsCryptocurrency() || s.Exchange == nil {
+		return ""
+	}
+
+	return s.Exchange.Country()
+}
+
 func (s *Stock) Equals(stk *Stock) bool {
 	return s.Symbol == stk.Symbol
 }
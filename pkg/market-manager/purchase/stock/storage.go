@@ -8,6 +8,7 @@ type (
 		FindByID(ID uuid.UUID) (*Stock, error)
 		FindBySymbol(symbol string) (*Stock, error)
 		FindByName(name string) (*Stock, error)
+		FindByISIN(isin string) (*Stock, error)
 		FindAllByExchanges(exchanges []string) ([]*Stock, error)
 		FindAllByDividendAnnounceProjectYearAndMonth(year, month int) ([]*Stock, error)
 	}
@@ -18,6 +19,9 @@ type (
 		UpdateDividendYield(s *Stock) error
 		UpdateHighLow52WeekPrice(s *Stock) error
 		UpdatePriceVolatility(s *Stock) error
+		UpdateBookValue(s *Stock) error
+		UpdateCleanPrice(s *Stock) error
+		UpdateISIN(s *Stock) error
 	}
 
 	InfoFinder interface {
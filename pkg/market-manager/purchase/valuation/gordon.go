@@ -0,0 +1,43 @@
+package valuation
+
+import (
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// gordon values the stock with the Gordon dividend discount model, D1 / (r - g),
+// where D1 is the dividend of the last year grown one year
+type gordon struct {
+	discountRate float64
+	growth       float64
+}
+
+// NewGordon creates the Gordon dividend discount model. The discount rate and the growth are percentages
+func NewGordon(discountRate, growth float64) Model {
+	return &gordon{
+		discountRate: discountRate / 100,
+		growth:       growth / 100,
+	}
+}
+
+func (m *gordon) Name() string {
+	return "DDM"
+}
+
+func (m *gordon) FairValue(s *stock.Stock, now time.Time) (mm.Value, bool) {
+	if m.discountRate <= m.growth {
+		return mm.Value{}, false
+	}
+
+	d0 := annualDividend(s, now)
+	if d0 <= 0 {
+		return mm.Value{}, false
+	}
+
+	return mm.Value{
+		Amount:   d0 * (1 + m.growth) / (m.discountRate - m.growth),
+		Currency: s.Value.Currency,
+	}, true
+}
@@ -0,0 +1,32 @@
+package valuation
+
+import (
+	"math"
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// graham values the stock with the Graham number, sqrt(22.5 * EPS * book value per share)
+type graham struct{}
+
+// NewGraham creates the Graham number model
+func NewGraham() Model {
+	return &graham{}
+}
+
+func (m *graham) Name() string {
+	return "Graham"
+}
+
+func (m *graham) FairValue(s *stock.Stock, now time.Time) (mm.Value, bool) {
+	if s.EPS <= 0 || s.BookValue <= 0 {
+		return mm.Value{}, false
+	}
+
+	return mm.Value{
+		Amount:   math.Sqrt(22.5 * s.EPS * s.BookValue),
+		Currency: s.Value.Currency,
+	}, true
+}
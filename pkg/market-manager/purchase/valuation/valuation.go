@@ -0,0 +1,75 @@
+package valuation
+
+import (
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock/dividend"
+)
+
+type (
+	// Model estimates the fair value of a stock
+	Model interface {
+		Name() string
+		// FairValue returns the fair value of the stock, false when the model can not value the stock
+		FairValue(s *stock.Stock, now time.Time) (mm.Value, bool)
+	}
+
+	// Valuation represents the fair value of a stock given by a model
+	Valuation struct {
+		Model     string
+		FairValue mm.Value
+		// MarginOfSafety percentage the price is below the fair value, negative when it is above
+		MarginOfSafety float64
+		// Valid is false when the model can not value the stock
+		Valid bool
+	}
+)
+
+// Value values the stock with every model, in the models order
+func Value(s *stock.Stock, models []Model, now time.Time) []Valuation {
+	var vs []Valuation
+
+	for _, m := range models {
+		v := Valuation{Model: m.Name()}
+
+		fv, ok := m.FairValue(s, now)
+		if ok {
+			v.FairValue = fv
+			v.MarginOfSafety = MarginOfSafety(fv, s.Value)
+			v.Valid = true
+		}
+
+		vs = append(vs, v)
+	}
+
+	return vs
+}
+
+// MarginOfSafety returns the percentage the price is below the fair value
+func MarginOfSafety(fairValue, price mm.Value) float64 {
+	if fairValue.Amount <= 0 {
+		return 0
+	}
+
+	return (fairValue.Amount - price.Amount) * 100 / fairValue.Amount
+}
+
+// annualDividend sums the dividends of the stock with ex date within the year before now
+func annualDividend(s *stock.Stock, now time.Time) float64 {
+	from := now.AddDate(-1, 0, 0)
+
+	var amount float64
+	for _, d := range s.Dividends {
+		if d.Status == dividend.Projected {
+			continue
+		}
+
+		if d.ExDate.After(from) && !d.ExDate.After(now) {
+			amount += d.Amount.Amount
+		}
+	}
+
+	return amount
+}
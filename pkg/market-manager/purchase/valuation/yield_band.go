@@ -0,0 +1,56 @@
+package valuation
+
+import (
+	"time"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager"
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// yieldBand values the stock at the price its dividend of the last year yields the average
+// of the prior 12 months yields recorded with the dividends paid within the years
+type yieldBand struct {
+	years int
+}
+
+// NewYieldBand creates the historical yield band model over the years
+func NewYieldBand(years int) Model {
+	return &yieldBand{
+		years: years,
+	}
+}
+
+func (m *yieldBand) Name() string {
+	return "Yield Band"
+}
+
+func (m *yieldBand) FairValue(s *stock.Stock, now time.Time) (mm.Value, bool) {
+	from := now.AddDate(-m.years, 0, 0)
+
+	var (
+		sum float64
+		n   int
+	)
+	for _, d := range s.Dividends {
+		if d.Prior12MonthsYield <= 0 || d.ExDate.Before(from) || d.ExDate.After(now) {
+			continue
+		}
+
+		sum += d.Prior12MonthsYield
+		n++
+	}
+
+	if n == 0 {
+		return mm.Value{}, false
+	}
+
+	d0 := annualDividend(s, now)
+	if d0 <= 0 {
+		return mm.Value{}, false
+	}
+
+	return mm.Value{
+		Amount:   d0 / (sum / float64(n) / 100),
+		Currency: s.Value.Currency,
+	}, true
+}
@@ -0,0 +1,13 @@
+package watchlist
+
+type (
+	Finder interface {
+		FindByName(name string) (*Watchlist, error)
+		FindAll() ([]*Watchlist, error)
+	}
+
+	Persister interface {
+		Persist(w *Watchlist) error
+		Delete(w *Watchlist) error
+	}
+)
@@ -0,0 +1,44 @@
+package watchlist
+
+import (
+	"github.com/satori/go.uuid"
+
+	"github.com/dohernandez/market-manager/pkg/market-manager/purchase/stock"
+)
+
+// Watchlist represents a named list of stocks we follow without necessarily holding them
+type Watchlist struct {
+	ID     uuid.UUID
+	Name   string
+	Stocks map[uuid.UUID]*stock.Stock
+}
+
+func NewWatchlist(name string) *Watchlist {
+	return &Watchlist{
+		ID:     uuid.NewV4(),
+		Name:   name,
+		Stocks: map[uuid.UUID]*stock.Stock{},
+	}
+}
+
+// AddStock adds the stock to the watchlist, returns false when the stock was already in
+func (w *Watchlist) AddStock(s *stock.Stock) bool {
+	if _, ok := w.Stocks[s.ID]; ok {
+		return false
+	}
+
+	w.Stocks[s.ID] = s
+
+	return true
+}
+
+// RemoveStock removes the stock from the watchlist, returns false when the stock was not in
+func (w *Watchlist) RemoveStock(s *stock.Stock) bool {
+	if _, ok := w.Stocks[s.ID]; !ok {
+		return false
+	}
+
+	delete(w.Stocks, s.ID)
+
+	return true
+}
@@ -2,6 +2,7 @@ package mm
 
 import (
 	"strconv"
+	"strings"
 )
 
 type Value struct {
@@ -94,6 +95,35 @@ func ValueFromStringAndExchange(s, e string) Value {
 	return a
 }
 
+// CurrencyFromCode returns the currency of the ISO 4217 code
+func CurrencyFromCode(code string) (Currency, bool) {
+	switch strings.ToUpper(code) {
+	case "EUR":
+		return Euro, true
+	case "USD":
+		return Dollar, true
+	case "CAD":
+		return CanadianDollar, true
+	}
+
+	return "", false
+}
+
+// CurrencyCode returns the ISO 4217 code of the currency, a value without currency is in euro. The bool is false
+// when the currency is not supported
+func CurrencyCode(c Currency) (string, bool) {
+	switch c {
+	case "", Euro:
+		return "EUR", true
+	case Dollar:
+		return "USD", true
+	case CanadianDollar:
+		return "CAD", true
+	}
+
+	return "", false
+}
+
 func ExchangeCurrency(e string) Currency {
 	ec, ok := exchangeCurrency[e]
 	if !ok {
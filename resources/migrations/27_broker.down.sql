@@ -0,0 +1,5 @@
+ALTER TABLE wallet DROP broker_id;
+DROP TABLE IF EXISTS broker_commission;
+DROP TYPE IF EXISTS ecommissionapply;
+DROP TABLE IF EXISTS broker;
+DROP TYPE IF EXISTS eimportformat;
@@ -0,0 +1,52 @@
+-- broker Table
+CREATE TYPE eimportformat AS ENUM ('degiro', 'interactive-brokers');
+
+CREATE TABLE broker (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    retention NUMERIC(5, 2) DEFAULT 0,
+    margin NUMERIC(5, 2) DEFAULT 0,
+    connectivity NUMERIC(7, 2) DEFAULT 0,
+    import_format eimportformat NOT NULL,
+    UNIQUE (name)
+);
+
+-- broker_commission Table
+CREATE TYPE ecommissionapply AS ENUM ('PER_STOCK', 'INVESTED_PERCENTAGE');
+
+CREATE TABLE broker_commission (
+    broker_id UUID REFERENCES broker(id),
+    exchange_id UUID REFERENCES exchange(id),
+    base NUMERIC(11, 4) DEFAULT 0,
+    base_currency VARCHAR(3) NOT NULL,
+    extra NUMERIC(11, 4) DEFAULT 0,
+    extra_currency VARCHAR(3) NOT NULL,
+    extra_apply ecommissionapply NOT NULL,
+    maximum NUMERIC(11, 4) DEFAULT 0,
+    maximum_currency VARCHAR(3) NOT NULL,
+    change NUMERIC(11, 4) DEFAULT 0,
+    change_currency VARCHAR(3) NOT NULL,
+    PRIMARY KEY (broker_id, exchange_id)
+);
+
+-- Add new column.
+ALTER TABLE wallet ADD broker_id UUID REFERENCES broker(id);
+
+-- Every wallet so far was hold at Degiro, keep it as default broker with the previous hardcoded values.
+INSERT INTO broker (id, name, retention, margin, connectivity, import_format) VALUES
+(uuid_generate_v4(), 'Degiro', 15, 49, 2.5, 'degiro');
+
+INSERT INTO broker_commission (broker_id, exchange_id, base, base_currency, extra, extra_currency, extra_apply, maximum, maximum_currency, change, change_currency)
+SELECT b.id, e.id, c.base, c.base_currency, c.extra, c.extra_currency, c.extra_apply::ecommissionapply, c.maximum, c.maximum_currency, c.change, c.change_currency
+FROM broker b, exchange e
+INNER JOIN (VALUES
+    ('NASDAQ', 0.50, '€', 0.004, '$', 'PER_STOCK', 0, '€', 0.16, '€'),
+    ('NYSE', 0.50, '€', 0.004, '$', 'PER_STOCK', 0, '€', 0.16, '€'),
+    ('BME', 2, '€', 0.04, '€', 'INVESTED_PERCENTAGE', 60, '€', 0, '€'),
+    ('FRA', 7.5, '€', 0.08, '€', 'INVESTED_PERCENTAGE', 0, '€', 0, '€'),
+    ('BIT', 4, '€', 0.04, '€', 'INVESTED_PERCENTAGE', 60, '€', 0, '€')
+) AS c (symbol, base, base_currency, extra, extra_currency, extra_apply, maximum, maximum_currency, change, change_currency)
+ON e.symbol = c.symbol
+WHERE b.name = 'Degiro';
+
+UPDATE wallet SET broker_id = (SELECT id FROM broker WHERE name = 'Degiro');
@@ -0,0 +1,71 @@
+-- wallet_ledger Table
+CREATE TYPE eledgerkind AS ENUM ('transfer', 'buy', 'sell', 'dividend', 'fee', 'interest');
+
+CREATE TABLE wallet_ledger (
+    id UUID PRIMARY KEY NOT NULL,
+    seq BIGSERIAL,
+    wallet_id UUID REFERENCES wallet(id) ON DELETE CASCADE,
+    date TIMESTAMP NOT NULL,
+    kind eledgerkind NOT NULL,
+    description TEXT,
+    amount NUMERIC(11, 2) NOT NULL,
+    balance NUMERIC(11, 2) DEFAULT 0,
+    operation_id UUID REFERENCES operation(id) ON DELETE CASCADE,
+    transfer_id UUID REFERENCES transfer(id) ON DELETE CASCADE
+);
+
+CREATE INDEX wallet_ledger_wallet_date_idx ON wallet_ledger (wallet_id, date);
+
+-- Book the existing transfers. Same as import transfer, money goes out from the wallet owning the from account,
+-- otherwise goes into the wallet owning the to account.
+INSERT INTO wallet_ledger (id, wallet_id, date, kind, description, amount, transfer_id)
+SELECT uuid_generate_v4(), wba.wallet_id, t.date, 'transfer', 'To ' || ba.alias, -t.amount, t.id
+FROM transfer t
+INNER JOIN wallet_bank_account wba ON wba.bank_account_id = t.from_account
+INNER JOIN bank_account ba ON ba.id = t.to_account
+ORDER BY t.date;
+
+INSERT INTO wallet_ledger (id, wallet_id, date, kind, description, amount, transfer_id)
+SELECT uuid_generate_v4(), wba.wallet_id, t.date, 'transfer', 'From ' || ba.alias, t.amount, t.id
+FROM transfer t
+INNER JOIN wallet_bank_account wba ON wba.bank_account_id = t.to_account
+INNER JOIN bank_account ba ON ba.id = t.from_account
+WHERE NOT EXISTS (SELECT 1 FROM wallet_bank_account wbaf WHERE wbaf.bank_account_id = t.from_account)
+ORDER BY t.date;
+
+-- Book the existing operations
+INSERT INTO wallet_ledger (id, wallet_id, date, kind, description, amount, operation_id)
+SELECT uuid_generate_v4(), o.wallet_id, o.date,
+    CASE o.action
+        WHEN 'buy' THEN 'buy'::eledgerkind
+        WHEN 'sell' THEN 'sell'::eledgerkind
+        WHEN 'dividend' THEN 'dividend'::eledgerkind
+        WHEN 'interest' THEN 'interest'::eledgerkind
+        ELSE 'fee'::eledgerkind
+    END,
+    CASE o.action
+        WHEN 'interest' THEN 'Interest'
+        WHEN 'connectivity' THEN 'Connectivity'
+        ELSE s.name
+    END,
+    CASE WHEN o.action IN ('sell', 'dividend') THEN o.value ELSE -o.value END,
+    o.id
+FROM operation o
+LEFT JOIN stock s ON s.id = o.stock_id
+ORDER BY o.date;
+
+INSERT INTO wallet_ledger (id, wallet_id, date, kind, description, amount, operation_id)
+SELECT uuid_generate_v4(), o.wallet_id, o.date, 'fee', 'Commission ' || s.name,
+    -(COALESCE(o.commission, 0) + COALESCE(o.price_change_commission, 0)), o.id
+FROM operation o
+INNER JOIN stock s ON s.id = o.stock_id
+WHERE o.action IN ('buy', 'sell') AND COALESCE(o.commission, 0) + COALESCE(o.price_change_commission, 0) <> 0
+ORDER BY o.date;
+
+-- Running balance
+UPDATE wallet_ledger wl SET balance = r.balance
+FROM (
+    SELECT id, SUM(amount) OVER (PARTITION BY wallet_id ORDER BY date, seq) AS balance
+    FROM wallet_ledger
+) r
+WHERE wl.id = r.id;
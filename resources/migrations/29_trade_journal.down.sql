@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trade_journal_tag;
+DROP TABLE IF EXISTS trade_journal;
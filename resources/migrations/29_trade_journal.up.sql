@@ -0,0 +1,18 @@
+-- trade_journal Table
+-- The journal is keyed by the trade number, trades are recreated when the wallet is rebuilt
+CREATE TABLE trade_journal (
+    wallet_id UUID REFERENCES wallet(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    note TEXT,
+    target_price NUMERIC(11, 2),
+    stop_loss NUMERIC(11, 2),
+    PRIMARY KEY (wallet_id, number)
+);
+
+CREATE TABLE trade_journal_tag (
+    wallet_id UUID NOT NULL,
+    number INTEGER NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    PRIMARY KEY (wallet_id, number, tag),
+    FOREIGN KEY (wallet_id, number) REFERENCES trade_journal(wallet_id, number) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS watchlist_stock;
+DROP TABLE IF EXISTS watchlist;
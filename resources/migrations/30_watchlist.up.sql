@@ -0,0 +1,13 @@
+-- watchlist Table
+CREATE TABLE watchlist (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (name)
+);
+
+CREATE TABLE watchlist_stock (
+    watchlist_id UUID REFERENCES watchlist(id) ON DELETE CASCADE,
+    stock_id UUID REFERENCES stock(id) ON DELETE CASCADE,
+    PRIMARY KEY (watchlist_id, stock_id)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS alert_rule_trigger;
+DROP TABLE IF EXISTS alert_rule;
+DROP TYPE IF EXISTS ealertcondition;
@@ -0,0 +1,21 @@
+-- alert_rule Table
+CREATE TYPE ealertcondition AS ENUM ('price_below', 'below_buy_under', 'near_52_week_low', 'daily_change_above');
+
+CREATE TABLE alert_rule (
+    id UUID PRIMARY KEY NOT NULL,
+    stock_id UUID REFERENCES stock(id) ON DELETE CASCADE,
+    watchlist_id UUID REFERENCES watchlist(id) ON DELETE CASCADE,
+    condition ealertcondition NOT NULL,
+    value NUMERIC(11, 4) DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK ((stock_id IS NULL) <> (watchlist_id IS NULL))
+);
+
+-- alert_rule_trigger Table, when the rule was last triggered by each stock, so a stock of a watchlist does not
+-- hold back the alerts of the rest
+CREATE TABLE alert_rule_trigger (
+    alert_rule_id UUID REFERENCES alert_rule(id) ON DELETE CASCADE,
+    stock_id UUID REFERENCES stock(id) ON DELETE CASCADE,
+    last_triggered_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (alert_rule_id, stock_id)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_dividend_change;
+DROP TYPE IF EXISTS edividendchange;
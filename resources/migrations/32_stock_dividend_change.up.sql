@@ -0,0 +1,14 @@
+-- stock_dividend_change Table
+CREATE TYPE edividendchange AS ENUM ('new', 'raised', 'cut', 'suspended');
+
+CREATE TABLE stock_dividend_change (
+    id SERIAL PRIMARY KEY,
+    stock_id UUID REFERENCES stock(id) ON DELETE CASCADE,
+    type edividendchange NOT NULL,
+    ex_date TIMESTAMP NOT NULL,
+    previous_amount NUMERIC(7, 4) DEFAULT 0,
+    amount NUMERIC(7, 4) DEFAULT 0,
+    detected_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX stock_dividend_change_detected_at ON stock_dividend_change (detected_at);
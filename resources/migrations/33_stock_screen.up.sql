@@ -0,0 +1,10 @@
+-- stock_screen Table
+CREATE TABLE stock_screen (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    expression TEXT NOT NULL,
+    sort VARCHAR(20),
+    descending BOOLEAN NOT NULL DEFAULT FALSE,
+    max_stocks INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (name)
+);
@@ -0,0 +1,2 @@
+-- Add new column.
+ALTER TABLE stock ADD book_value NUMERIC(10, 2) DEFAULT 0;
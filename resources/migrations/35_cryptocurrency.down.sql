@@ -0,0 +1,9 @@
+ALTER TABLE stock ALTER COLUMN value TYPE NUMERIC(7, 3);
+
+ALTER TABLE trade ALTER COLUMN amount TYPE NUMERIC(11, 2);
+ALTER TABLE trade ALTER COLUMN sell_amount TYPE NUMERIC(11, 2);
+ALTER TABLE trade ALTER COLUMN buy_amount TYPE NUMERIC(11, 2);
+ALTER TABLE wallet_item ALTER COLUMN amount TYPE INTEGER;
+ALTER TABLE operation ALTER COLUMN amount TYPE INTEGER;
+
+DELETE FROM exchange WHERE symbol = 'CRYPTO';
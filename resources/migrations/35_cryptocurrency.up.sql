@@ -0,0 +1,12 @@
+-- Exchange of the cryptocurrency pairs
+INSERT INTO exchange (id, name, symbol) VALUES(uuid_generate_v4(), 'Cryptocurrency', 'CRYPTO');
+
+-- Fractional amounts
+ALTER TABLE operation ALTER COLUMN amount TYPE NUMERIC(18, 8);
+ALTER TABLE wallet_item ALTER COLUMN amount TYPE NUMERIC(18, 8);
+ALTER TABLE trade ALTER COLUMN buy_amount TYPE NUMERIC(18, 8);
+ALTER TABLE trade ALTER COLUMN sell_amount TYPE NUMERIC(18, 8);
+ALTER TABLE trade ALTER COLUMN amount TYPE NUMERIC(18, 8);
+
+-- Prices above 9999.999
+ALTER TABLE stock ALTER COLUMN value TYPE NUMERIC(14, 6);
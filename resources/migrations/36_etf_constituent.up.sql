@@ -0,0 +1,13 @@
+-- etf_constituent Table
+CREATE TABLE etf_constituent (
+    id UUID PRIMARY KEY NOT NULL,
+    etf_id UUID NOT NULL REFERENCES stock(id),
+    name VARCHAR(120) NOT NULL,
+    symbol VARCHAR(20),
+    sector VARCHAR(120),
+    country VARCHAR(5),
+    weight NUMERIC(7,4) NOT NULL,
+    stock_id UUID REFERENCES stock(id)
+);
+
+CREATE INDEX etf_constituent_etf_id_idx ON etf_constituent (etf_id);
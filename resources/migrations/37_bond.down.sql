@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS stock_fixed_income;
+
+DELETE FROM market WHERE name = 'bond';
+
+DELETE FROM wallet_ledger WHERE kind IN ('coupon', 'redemption');
+DELETE FROM trade_operation WHERE operation_id IN (SELECT id FROM operation WHERE action IN ('coupon', 'redemption'));
+DELETE FROM operation WHERE action IN ('coupon', 'redemption');
+
+ALTER TYPE eaction RENAME TO eaction_old;
+CREATE TYPE eaction AS ENUM ('buy', 'sell', 'connectivity', 'dividend', 'interest');
+ALTER TABLE operation ALTER COLUMN action TYPE eaction USING action::text::eaction;
+DROP TYPE eaction_old;
+
+ALTER TYPE eledgerkind RENAME TO eledgerkind_old;
+CREATE TYPE eledgerkind AS ENUM ('transfer', 'buy', 'sell', 'dividend', 'fee', 'interest');
+ALTER TABLE wallet_ledger ALTER COLUMN kind TYPE eledgerkind USING kind::text::eledgerkind;
+DROP TYPE eledgerkind_old;
@@ -0,0 +1,24 @@
+-- Market of the fixed income instruments
+INSERT INTO market (id, name, display_name) VALUES(uuid_generate_v4(), 'bond', 'Bond');
+
+-- stock_fixed_income Table
+CREATE TABLE stock_fixed_income (
+    stock_id UUID PRIMARY KEY NOT NULL REFERENCES stock(id),
+    face_value NUMERIC(12,2) NOT NULL,
+    coupon_rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    coupon_frequency INTEGER NOT NULL DEFAULT 0,
+    issue_date DATE,
+    maturity DATE NOT NULL,
+    clean_price NUMERIC(8,4) NOT NULL DEFAULT 100
+);
+
+-- Bond coupons and redemptions. The enum types are recreated, values can not be added within a transaction
+ALTER TYPE eaction RENAME TO eaction_old;
+CREATE TYPE eaction AS ENUM ('buy', 'sell', 'connectivity', 'dividend', 'interest', 'coupon', 'redemption');
+ALTER TABLE operation ALTER COLUMN action TYPE eaction USING action::text::eaction;
+DROP TYPE eaction_old;
+
+ALTER TYPE eledgerkind RENAME TO eledgerkind_old;
+CREATE TYPE eledgerkind AS ENUM ('transfer', 'buy', 'sell', 'dividend', 'fee', 'interest', 'coupon', 'redemption');
+ALTER TABLE wallet_ledger ALTER COLUMN kind TYPE eledgerkind USING kind::text::eledgerkind;
+DROP TYPE eledgerkind_old;
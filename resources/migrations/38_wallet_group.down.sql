@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS wallet_group_wallet;
+DROP TABLE IF EXISTS wallet_group;
@@ -0,0 +1,13 @@
+-- wallet_group Table
+CREATE TABLE wallet_group (
+    id UUID PRIMARY KEY NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (name)
+);
+
+CREATE TABLE wallet_group_wallet (
+    wallet_group_id UUID REFERENCES wallet_group(id) ON DELETE CASCADE,
+    wallet_id UUID REFERENCES wallet(id) ON DELETE CASCADE,
+    PRIMARY KEY (wallet_group_id, wallet_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE bank_account DROP COLUMN IF EXISTS account_type;
+
+DROP TYPE IF EXISTS ebankaccounttype;
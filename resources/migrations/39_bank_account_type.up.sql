@@ -0,0 +1,8 @@
+-- bank_account type
+CREATE TYPE ebankaccounttype AS ENUM ('checking', 'savings', 'brokerage', 'external');
+
+ALTER TABLE bank_account ADD COLUMN account_type ebankaccounttype NOT NULL DEFAULT 'checking';
+
+-- the accounts linked to a wallet are the cash accounts at the broker
+UPDATE bank_account SET account_type = 'brokerage'
+WHERE id IN (SELECT bank_account_id FROM wallet_bank_account);
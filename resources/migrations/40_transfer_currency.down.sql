@@ -0,0 +1,3 @@
+ALTER TABLE transfer DROP COLUMN IF EXISTS fee;
+ALTER TABLE transfer DROP COLUMN IF EXISTS rate;
+ALTER TABLE transfer DROP COLUMN IF EXISTS currency;
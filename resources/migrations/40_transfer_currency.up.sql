@@ -0,0 +1,4 @@
+-- transfer currency, the rate it was changed at into euro and the fee charged for it
+ALTER TABLE transfer ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'EUR';
+ALTER TABLE transfer ADD COLUMN rate NUMERIC(10, 5);
+ALTER TABLE transfer ADD COLUMN fee NUMERIC(11, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS stock_isin_idx;
+ALTER TABLE stock DROP COLUMN IF EXISTS isin;
@@ -0,0 +1,3 @@
+-- ISIN of the stock, the brokers statements refer the stocks by it
+ALTER TABLE stock ADD COLUMN isin VARCHAR(12) NULL;
+CREATE UNIQUE INDEX stock_isin_idx ON stock (isin);
@@ -0,0 +1,7 @@
+-- wallet_import_transaction Table, ids of the broker transactions imported to the wallet
+CREATE TABLE wallet_import_transaction (
+    wallet_id UUID REFERENCES wallet(id) ON DELETE CASCADE,
+    transaction_id VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (wallet_id, transaction_id)
+);
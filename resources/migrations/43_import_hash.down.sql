@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS import_row;
+ALTER TABLE import DROP COLUMN IF EXISTS hash;
@@ -0,0 +1,14 @@
+-- hash of the content of the file imported, a file renamed is not imported again
+ALTER TABLE import ADD COLUMN hash VARCHAR(64) NOT NULL DEFAULT '';
+
+-- import_row Table, fingerprint of the rows imported, the rows already imported are skipped
+CREATE TABLE import_row (
+    resource VARCHAR(64) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    import_id UUID REFERENCES import(id) ON DELETE CASCADE,
+    file_name VARCHAR(120) NOT NULL,
+    line INTEGER NOT NULL,
+    PRIMARY KEY (resource, fingerprint)
+);
+
+CREATE INDEX import_row_file_name ON import_row (resource, file_name);
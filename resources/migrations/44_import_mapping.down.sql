@@ -0,0 +1 @@
+ALTER TABLE import DROP COLUMN IF EXISTS mapping;
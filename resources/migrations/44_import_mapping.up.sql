@@ -0,0 +1,2 @@
+-- name of the mapping profile the file was imported with, the lines registered are written in its layout
+ALTER TABLE import ADD COLUMN mapping VARCHAR(64) NOT NULL DEFAULT '';